@@ -3,16 +3,28 @@ package main
 import (
 	"context"
 	"log/slog"
+	"math"
 	"net/http"
 	"os"
+	"strconv"
+	"strings"
 
 	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
 	"github.com/vantutran2k1/rwe/config"
 	authv1 "github.com/vantutran2k1/rwe/gen/go/auth/v1"
+	eventv1 "github.com/vantutran2k1/rwe/gen/go/event/v1"
 	tenantv1 "github.com/vantutran2k1/rwe/gen/go/tenant/v1"
+	usagev1 "github.com/vantutran2k1/rwe/gen/go/usage/v1"
+	workerv1 "github.com/vantutran2k1/rwe/gen/go/worker/v1"
 	workflowv1 "github.com/vantutran2k1/rwe/gen/go/workflow/v1"
+	"github.com/vantutran2k1/rwe/internal/common/token"
+	"github.com/vantutran2k1/rwe/internal/ratelimit"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
 )
 
 func main() {
@@ -25,7 +37,12 @@ func main() {
 
 	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
 
-	mux := runtime.NewServeMux()
+	mux := runtime.NewServeMux(
+		runtime.WithIncomingHeaderMatcher(headerMatcher),
+		runtime.WithOutgoingHeaderMatcher(outgoingHeaderMatcher),
+		runtime.WithErrorHandler(errorHandler),
+		runtime.WithForwardResponseOption(redirectOidcLogin),
+	)
 
 	grpcEndpoint := "localhost" + cfg.Server.GRPCPort
 	opts := []grpc.DialOption{
@@ -37,6 +54,29 @@ func main() {
 		os.Exit(1)
 	}
 
+	if err := workflowv1.RegisterScheduleServiceHandlerFromEndpoint(ctx, mux, grpcEndpoint, opts); err != nil {
+		logger.Error("failed to register schedule gateway", "error", err)
+		os.Exit(1)
+	}
+
+	if err := workflowv1.RegisterWebhookServiceHandlerFromEndpoint(ctx, mux, grpcEndpoint, opts); err != nil {
+		logger.Error("failed to register webhook gateway", "error", err)
+		os.Exit(1)
+	}
+
+	webhookConn, err := grpc.NewClient(grpcEndpoint, opts...)
+	if err != nil {
+		logger.Error("failed to connect to webhook service", "error", err)
+		os.Exit(1)
+	}
+	defer webhookConn.Close()
+
+	webhookClient := workflowv1.NewWebhookServiceClient(webhookConn)
+	if err := mux.HandlePath(http.MethodPost, "/v1/hooks/{token}", deliverWebhook(mux, webhookClient, cfg.Webhooks.MaxBodyBytes)); err != nil {
+		logger.Error("failed to register webhook delivery route", "error", err)
+		os.Exit(1)
+	}
+
 	if err := authv1.RegisterAuthServiceHandlerFromEndpoint(ctx, mux, grpcEndpoint, opts); err != nil {
 		logger.Error("failed to register auth gateway", "error", err)
 		os.Exit(1)
@@ -47,6 +87,21 @@ func main() {
 		os.Exit(1)
 	}
 
+	if err := workerv1.RegisterWorkerServiceHandlerFromEndpoint(ctx, mux, grpcEndpoint, opts); err != nil {
+		logger.Error("failed to register worker gateway", "error", err)
+		os.Exit(1)
+	}
+
+	if err := eventv1.RegisterEventServiceHandlerFromEndpoint(ctx, mux, grpcEndpoint, opts); err != nil {
+		logger.Error("failed to register event gateway", "error", err)
+		os.Exit(1)
+	}
+
+	if err := usagev1.RegisterUsageServiceHandlerFromEndpoint(ctx, mux, grpcEndpoint, opts); err != nil {
+		logger.Error("failed to register usage gateway", "error", err)
+		os.Exit(1)
+	}
+
 	logger.Info("starting rest gateway", "port", cfg.Server.HTTPPort)
 
 	if err := http.ListenAndServe(cfg.Server.HTTPPort, mux); err != nil {
@@ -54,3 +109,48 @@ func main() {
 		os.Exit(1)
 	}
 }
+
+func headerMatcher(key string) (string, bool) {
+	switch k := strings.ToLower(key); k {
+	case token.TenantHeader, token.ApiKeyHeader:
+		return k, true
+	}
+
+	return runtime.DefaultHeaderMatcher(key)
+}
+
+func outgoingHeaderMatcher(key string) (string, bool) {
+	if strings.ToLower(key) == ratelimit.RetryAfterHeader {
+		return "Retry-After", true
+	}
+
+	return runtime.DefaultHeaderMatcher(key)
+}
+
+// errorHandler adds a Retry-After header to throttled responses that carry
+// the delay only as a RetryInfo detail, such as quota errors.
+func errorHandler(ctx context.Context, mux *runtime.ServeMux, marshaler runtime.Marshaler, w http.ResponseWriter, r *http.Request, err error) {
+	md, _ := runtime.ServerMetadataFromContext(ctx)
+	if st, ok := status.FromError(err); ok && st.Code() == codes.ResourceExhausted && len(md.HeaderMD.Get(ratelimit.RetryAfterHeader)) == 0 {
+		for _, detail := range st.Details() {
+			if info, ok := detail.(*errdetails.RetryInfo); ok && info.GetRetryDelay() != nil {
+				seconds := int64(math.Ceil(info.GetRetryDelay().AsDuration().Seconds()))
+				w.Header().Set("Retry-After", strconv.FormatInt(seconds, 10))
+				break
+			}
+		}
+	}
+
+	runtime.DefaultHTTPErrorHandler(ctx, mux, marshaler, w, r, err)
+}
+
+// redirectOidcLogin sends browsers starting an SSO login straight to the
+// identity provider instead of returning the authorization URL as JSON.
+func redirectOidcLogin(ctx context.Context, w http.ResponseWriter, resp proto.Message) error {
+	if r, ok := resp.(*authv1.StartOidcLoginResponse); ok {
+		w.Header().Set("Location", r.AuthorizationUrl)
+		w.WriteHeader(http.StatusFound)
+	}
+
+	return nil
+}
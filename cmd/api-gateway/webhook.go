@@ -0,0 +1,61 @@
+package main
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	workflowv1 "github.com/vantutran2k1/rwe/gen/go/workflow/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// deliverWebhook forwards POST /v1/hooks/{token} to the workflow service
+// with the raw body and headers, which the signature is verified against.
+func deliverWebhook(mux *runtime.ServeMux, client workflowv1.WebhookServiceClient, maxBodyBytes int64) runtime.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request, params map[string]string) {
+		_, outbound := runtime.MarshalerForRequest(mux, r)
+
+		ctx, err := runtime.AnnotateContext(r.Context(), mux, r, workflowv1.WebhookService_DeliverWebhook_FullMethodName)
+		if err != nil {
+			runtime.HTTPError(r.Context(), mux, outbound, w, r, err)
+			return
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxBodyBytes))
+		if err != nil {
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				err = status.Errorf(codes.InvalidArgument, "webhook body exceeds %d bytes", maxBodyBytes)
+			} else {
+				err = status.Errorf(codes.InvalidArgument, "error reading webhook body: %v", err)
+			}
+			runtime.HTTPError(ctx, mux, outbound, w, r, err)
+			return
+		}
+
+		headers := make(map[string]string, len(r.Header))
+		for key, values := range r.Header {
+			if len(values) > 0 {
+				headers[strings.ToLower(key)] = values[0]
+			}
+		}
+
+		var md runtime.ServerMetadata
+		resp, err := client.DeliverWebhook(ctx, &workflowv1.DeliverWebhookRequest{
+			Token:   params["token"],
+			Body:    body,
+			Headers: headers,
+		}, grpc.Header(&md.HeaderMD), grpc.Trailer(&md.TrailerMD))
+		ctx = runtime.NewServerMetadataContext(ctx, md)
+		if err != nil {
+			runtime.HTTPError(ctx, mux, outbound, w, r, err)
+			return
+		}
+
+		runtime.ForwardResponseMessage(ctx, mux, outbound, w, r, resp)
+	}
+}
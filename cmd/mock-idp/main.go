@@ -0,0 +1,38 @@
+package main
+
+import (
+	"flag"
+	"log/slog"
+	"net/http"
+	"os"
+
+	"github.com/vantutran2k1/rwe/internal/auth/oidctest"
+)
+
+func main() {
+	addr := flag.String("addr", ":9999", "listen address")
+	issuer := flag.String("issuer", "http://localhost:9999", "issuer url, must match the listen address")
+	clientID := flag.String("client-id", "rwe", "accepted client id")
+	clientSecret := flag.String("client-secret", "secret", "accepted client secret")
+	email := flag.String("email", "dev@example.com", "email of the signed in user")
+	flag.Parse()
+
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+
+	provider, err := oidctest.NewProvider(*issuer, *clientID, *clientSecret, oidctest.User{
+		Subject:       *email,
+		Email:         *email,
+		EmailVerified: true,
+		Name:          "Dev User",
+	})
+	if err != nil {
+		logger.Error("failed to create provider", "error", err)
+		os.Exit(1)
+	}
+
+	logger.Info("starting mock identity provider", "addr", *addr, "issuer", *issuer)
+	if err := http.ListenAndServe(*addr, provider.Handler()); err != nil {
+		logger.Error("mock identity provider failed", "error", err)
+		os.Exit(1)
+	}
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"log/slog"
 	"net"
 	"os"
@@ -10,13 +11,22 @@ import (
 
 	"github.com/vantutran2k1/rwe/config"
 	authv1 "github.com/vantutran2k1/rwe/gen/go/auth/v1"
+	eventv1 "github.com/vantutran2k1/rwe/gen/go/event/v1"
 	tenantv1 "github.com/vantutran2k1/rwe/gen/go/tenant/v1"
+	usagev1 "github.com/vantutran2k1/rwe/gen/go/usage/v1"
+	workerv1 "github.com/vantutran2k1/rwe/gen/go/worker/v1"
 	workflowv1 "github.com/vantutran2k1/rwe/gen/go/workflow/v1"
 	"github.com/vantutran2k1/rwe/internal/auth"
 	"github.com/vantutran2k1/rwe/internal/auth/cache"
 	"github.com/vantutran2k1/rwe/internal/common/db"
+	"github.com/vantutran2k1/rwe/internal/event"
+	"github.com/vantutran2k1/rwe/internal/mailer"
 	"github.com/vantutran2k1/rwe/internal/middlewares"
+	"github.com/vantutran2k1/rwe/internal/quota"
+	"github.com/vantutran2k1/rwe/internal/ratelimit"
 	"github.com/vantutran2k1/rwe/internal/tenant"
+	"github.com/vantutran2k1/rwe/internal/usage"
+	"github.com/vantutran2k1/rwe/internal/worker"
 	"github.com/vantutran2k1/rwe/internal/workflow"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/reflection"
@@ -45,27 +55,169 @@ func main() {
 	}
 	defer authRedis.Close()
 
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
 	tokenDuration := time.Duration(cfg.Auth.TokenDurationHours) * time.Hour
-	tokenMaker, _ := auth.NewPasetoMaker(cfg.Auth.TokenSymmetricKey, tokenDuration)
+	var tokenMaker auth.TokenMaker
+	var keyRing *auth.KeyRing
+	switch cfg.Auth.TokenType {
+	case "v2.local":
+		tokenMaker, err = auth.NewPasetoMaker(cfg.Auth.TokenSymmetricKey, tokenDuration)
+	default:
+		keyRing, err = auth.NewKeyRing(
+			pool,
+			cfg.Auth.SigningKeyEncryptionKey,
+			time.Duration(cfg.Auth.SigningKeyRotationHours)*time.Hour,
+			time.Duration(cfg.Auth.SigningKeyRefreshSeconds)*time.Second,
+			tokenDuration,
+		)
+		if err == nil {
+			err = keyRing.Rotate(ctx)
+		}
+		tokenMaker = auth.NewPasetoPublicMaker(keyRing, tokenDuration)
+	}
+	if err != nil {
+		logger.Error("failed to create token maker", "error", err)
+		os.Exit(1)
+	}
+
+	var blocklist cache.Blocklist
+	switch cfg.Auth.BlocklistBackend {
+	case "memory":
+		blocklist = cache.NewMemoryBlocklist()
+	default:
+		blocklist = cache.NewRedisBlocklist(authRedis)
+	}
+	blocklist = cache.NewCachedBlocklist(
+		blocklist,
+		time.Duration(cfg.Auth.BlocklistCacheTTLSeconds)*time.Second,
+		int(cfg.Auth.BlocklistCacheSize),
+	)
+
+	var mail mailer.Mailer
+	switch cfg.Mail.Backend {
+	case "smtp":
+		mail = mailer.NewSMTPMailer(cfg.Mail.SMTPHost, cfg.Mail.SMTPPort, cfg.Mail.SMTPUsername, cfg.Mail.SMTPPassword, cfg.Mail.From)
+	case "file":
+		mail = mailer.NewFileMailer(cfg.Mail.FilePath)
+	default:
+		mail = mailer.NewLogMailer()
+	}
+
+	mfaBox, err := auth.NewSecretBox(cfg.Auth.MfaEncryptionKey)
+	if err != nil {
+		logger.Error("failed to create mfa secret box", "error", err)
+		os.Exit(1)
+	}
 
-	blocklist := cache.NewRedisBlocklist(authRedis)
+	oidcClient, err := auth.NewOidcClient(cfg.Auth.OidcEncryptionKey, cfg.Auth.OidcRedirectURL)
+	if err != nil {
+		logger.Error("failed to create oidc client", "error", err)
+		os.Exit(1)
+	}
+
+	webhookBox, err := auth.NewSecretBox(cfg.Webhooks.SecretEncryptionKey)
+	if err != nil {
+		logger.Error("failed to create webhook secret box", "error", err)
+		os.Exit(1)
+	}
+
+	trustedProxies, err := auth.ParseCIDRs(cfg.Auth.TrustedProxies)
+	if err != nil {
+		logger.Error("invalid trusted proxies", "error", err)
+		os.Exit(1)
+	}
 
-	authInterceptor := middlewares.NewAuthInterceptor(tokenMaker)
+	loginGuard := auth.NewLoginGuard(cache.NewRedisLoginThrottle(authRedis), trustedProxies)
 
-	workflowSvc := workflow.NewService(pool)
-	authSvc := auth.NewService(pool, tokenMaker, blocklist)
-	tenantSvc := tenant.NewService(pool)
+	authInterceptor := middlewares.NewAuthInterceptor(tokenMaker, pool, blocklist, cfg.Auth.BlocklistFailOpen, trustedProxies)
+
+	meter := usage.NewMeter(pool, time.Duration(cfg.Usage.FlushIntervalSeconds)*time.Second)
+	usageInterceptor := middlewares.NewUsageInterceptor(meter)
+
+	quotas := quota.NewStore(pool, time.Duration(cfg.Tenant.QuotaCacheSeconds)*time.Second)
+	quotaInterceptor := middlewares.NewQuotaInterceptor(quotas, quota.NewRedisRequestCounter(authRedis))
+
+	unaryInterceptors := []grpc.UnaryServerInterceptor{authInterceptor.Unary()}
+	streamInterceptors := []grpc.StreamServerInterceptor{authInterceptor.Stream()}
+	if cfg.RateLimit.Enabled {
+		rateLimitInterceptor := middlewares.NewRateLimitInterceptor(
+			ratelimit.NewRedisLimiter(authRedis),
+			rateLimitRules(cfg.RateLimit),
+			trustedProxies,
+			cfg.RateLimit.FailOpen,
+		)
+		unaryInterceptors = append(unaryInterceptors, rateLimitInterceptor.Unary())
+		streamInterceptors = append(streamInterceptors, rateLimitInterceptor.Stream())
+	}
+	unaryInterceptors = append(unaryInterceptors, quotaInterceptor.Unary(), usageInterceptor.Unary())
+	streamInterceptors = append(streamInterceptors, quotaInterceptor.Stream(), usageInterceptor.Stream())
+
+	workflowSvc := workflow.NewService(pool, quotas)
+	scheduleSvc := workflow.NewScheduleService(pool)
+	webhookSvc := workflow.NewWebhookService(pool, quotas, webhookBox, cfg.Webhooks.BaseURL)
+	refreshTokenDuration := time.Duration(cfg.Auth.RefreshTokenDurationHours) * time.Hour
+	authSvc := auth.NewService(pool, tokenMaker, keyRing, blocklist, mail, mfaBox, cfg.Auth.MfaIssuer, oidcClient, loginGuard, trustedProxies, refreshTokenDuration, cfg.Mail.LinkBaseURL)
+	tenantSvc := tenant.NewService(pool, mail, cfg.Mail.LinkBaseURL, time.Duration(cfg.Tenant.DeletionGraceHours)*time.Hour)
+	workerSvc := worker.NewService(pool, time.Duration(cfg.Worker.LeaseSeconds)*time.Second)
+	usageSvc := usage.NewService(pool)
+	eventSvc := event.NewService(pool, time.Duration(cfg.Events.PollIntervalMillis)*time.Millisecond)
+
+	schedulerInterval := time.Duration(cfg.Workflow.SchedulerIntervalMillis) * time.Millisecond
+	scheduler := workflow.NewScheduler(pool, schedulerInterval, cfg.Workflow.SchedulerBatchSize, time.Duration(cfg.Workflow.SchedulerLeaseSeconds)*time.Second)
+	scheduleTicker := workflow.NewScheduleTicker(pool, quotas, time.Duration(cfg.Workflow.ScheduleIntervalMillis)*time.Millisecond, cfg.Workflow.ScheduleBatchSize)
+
+	aggregator := usage.NewAggregator(pool, time.Duration(cfg.Usage.AggregationIntervalSeconds)*time.Second)
+	purger := tenant.NewPurger(pool, time.Duration(cfg.Tenant.PurgeIntervalSeconds)*time.Second, int(cfg.Tenant.PurgeBatchSize))
+	deliveryPruner := workflow.NewDeliveryPruner(
+		pool,
+		time.Duration(cfg.Webhooks.DeliveryRetentionHours)*time.Hour,
+		time.Duration(cfg.Webhooks.PruneIntervalSeconds)*time.Second,
+		cfg.Webhooks.PruneBatchSize,
+	)
+
+	if keyRing != nil {
+		go keyRing.Run(ctx)
+	}
+
+	go func() {
+		logger.Info("starting workflow scheduler", "interval", schedulerInterval)
+		scheduler.Run(ctx)
+	}()
+
+	go scheduleTicker.Run(ctx)
+	go purger.Run(ctx)
+	go deliveryPruner.Run(ctx)
+
+	meterDone := make(chan struct{})
+	go func() {
+		defer close(meterDone)
+		meter.Run(ctx)
+	}()
+	go aggregator.Run(ctx)
 
 	grpcServer := grpc.NewServer(
-		grpc.UnaryInterceptor(authInterceptor.Unary()),
+		grpc.ChainUnaryInterceptor(unaryInterceptors...),
+		grpc.ChainStreamInterceptor(streamInterceptors...),
 	)
 
 	workflowv1.RegisterWorkflowServiceServer(grpcServer, workflowSvc)
+	workflowv1.RegisterScheduleServiceServer(grpcServer, scheduleSvc)
+	workflowv1.RegisterWebhookServiceServer(grpcServer, webhookSvc)
 	authv1.RegisterAuthServiceServer(grpcServer, authSvc)
 	tenantv1.RegisterTenantServiceServer(grpcServer, tenantSvc)
+	workerv1.RegisterWorkerServiceServer(grpcServer, workerSvc)
+	eventv1.RegisterEventServiceServer(grpcServer, eventSvc)
+	usagev1.RegisterUsageServiceServer(grpcServer, usageSvc)
 
 	reflection.Register(grpcServer)
 
+	if err := authInterceptor.LoadPolicies(grpcServer); err != nil {
+		logger.Error("failed to load access policies", "error", err)
+		os.Exit(1)
+	}
+
 	lis, err := net.Listen("tcp", cfg.Server.GRPCPort)
 	if err != nil {
 		logger.Error("failed to listen", "error", err)
@@ -84,6 +236,23 @@ func main() {
 	<-quit
 
 	logger.Info("shutting down grpc Server...")
+	cancel()
 	grpcServer.GracefulStop()
+	<-meterDone
 	logger.Info("server exited")
 }
+
+func rateLimitRules(cfg config.RateLimitConfig) middlewares.RateLimitRules {
+	rules := middlewares.RateLimitRules{
+		ApiKey:    ratelimit.Limit{Rate: cfg.ApiKey.Rate, Burst: cfg.ApiKey.Burst},
+		User:      ratelimit.Limit{Rate: cfg.User.Rate, Burst: cfg.User.Burst},
+		Tenant:    ratelimit.Limit{Rate: cfg.Tenant.Rate, Burst: cfg.Tenant.Burst},
+		Anonymous: ratelimit.Limit{Rate: cfg.Anonymous.Rate, Burst: cfg.Anonymous.Burst},
+		Methods:   make(map[string]ratelimit.Limit, len(cfg.Methods)),
+	}
+	for _, m := range cfg.Methods {
+		rules.Methods[m.Method] = ratelimit.Limit{Rate: m.Rate, Burst: m.Burst}
+	}
+
+	return rules
+}
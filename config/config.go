@@ -8,9 +8,17 @@ import (
 )
 
 type Config struct {
-	Server   ServerConfig   `mapstructure:"server"`
-	Auth     AuthConfig     `mapstructure:"auth"`
-	Database DatabaseConfig `mapstructure:"database"`
+	Server    ServerConfig    `mapstructure:"server"`
+	Auth      AuthConfig      `mapstructure:"auth"`
+	Database  DatabaseConfig  `mapstructure:"database"`
+	Workflow  WorkflowConfig  `mapstructure:"workflow"`
+	Worker    WorkerConfig    `mapstructure:"worker"`
+	Events    EventsConfig    `mapstructure:"events"`
+	Mail      MailConfig      `mapstructure:"mail"`
+	Tenant    TenantConfig    `mapstructure:"tenant"`
+	Usage     UsageConfig     `mapstructure:"usage"`
+	RateLimit RateLimitConfig `mapstructure:"rate_limit"`
+	Webhooks  WebhooksConfig  `mapstructure:"webhooks"`
 }
 
 type ServerConfig struct {
@@ -19,8 +27,22 @@ type ServerConfig struct {
 }
 
 type AuthConfig struct {
-	TokenSymmetricKey  string `mapstructure:"token_symmetric_key"`
-	TokenDurationHours int32  `mapstructure:"token_duration_hours"`
+	TokenType                 string   `mapstructure:"token_type"`
+	TokenSymmetricKey         string   `mapstructure:"token_symmetric_key"`
+	SigningKeyEncryptionKey   string   `mapstructure:"signing_key_encryption_key"`
+	SigningKeyRotationHours   int32    `mapstructure:"signing_key_rotation_hours"`
+	SigningKeyRefreshSeconds  int32    `mapstructure:"signing_key_refresh_seconds"`
+	MfaEncryptionKey          string   `mapstructure:"mfa_encryption_key"`
+	MfaIssuer                 string   `mapstructure:"mfa_issuer"`
+	OidcEncryptionKey         string   `mapstructure:"oidc_encryption_key"`
+	OidcRedirectURL           string   `mapstructure:"oidc_redirect_url"`
+	TokenDurationHours        int32    `mapstructure:"token_duration_hours"`
+	RefreshTokenDurationHours int32    `mapstructure:"refresh_token_duration_hours"`
+	BlocklistBackend          string   `mapstructure:"blocklist_backend"`
+	BlocklistCacheTTLSeconds  int32    `mapstructure:"blocklist_cache_ttl_seconds"`
+	BlocklistCacheSize        int32    `mapstructure:"blocklist_cache_size"`
+	BlocklistFailOpen         bool     `mapstructure:"blocklist_fail_open"`
+	TrustedProxies            []string `mapstructure:"trusted_proxies"`
 }
 
 type DatabaseConfig struct {
@@ -28,6 +50,75 @@ type DatabaseConfig struct {
 	AuthRedisURL string `mapstructure:"auth_redis_url"`
 }
 
+type WorkflowConfig struct {
+	SchedulerIntervalMillis int32 `mapstructure:"scheduler_interval_millis"`
+	SchedulerBatchSize      int32 `mapstructure:"scheduler_batch_size"`
+	SchedulerLeaseSeconds   int32 `mapstructure:"scheduler_lease_seconds"`
+	ScheduleIntervalMillis  int32 `mapstructure:"schedule_interval_millis"`
+	ScheduleBatchSize       int32 `mapstructure:"schedule_batch_size"`
+}
+
+type WorkerConfig struct {
+	LeaseSeconds int32 `mapstructure:"lease_seconds"`
+}
+
+type EventsConfig struct {
+	PollIntervalMillis int32 `mapstructure:"poll_interval_millis"`
+}
+
+type TenantConfig struct {
+	DeletionGraceHours   int32 `mapstructure:"deletion_grace_hours"`
+	PurgeIntervalSeconds int32 `mapstructure:"purge_interval_seconds"`
+	PurgeBatchSize       int32 `mapstructure:"purge_batch_size"`
+	QuotaCacheSeconds    int32 `mapstructure:"quota_cache_seconds"`
+}
+
+type UsageConfig struct {
+	FlushIntervalSeconds       int32 `mapstructure:"flush_interval_seconds"`
+	AggregationIntervalSeconds int32 `mapstructure:"aggregation_interval_seconds"`
+}
+
+type RateLimitConfig struct {
+	Enabled   bool                `mapstructure:"enabled"`
+	FailOpen  bool                `mapstructure:"fail_open"`
+	ApiKey    LimitConfig         `mapstructure:"api_key"`
+	User      LimitConfig         `mapstructure:"user"`
+	Tenant    LimitConfig         `mapstructure:"tenant"`
+	Anonymous LimitConfig         `mapstructure:"anonymous"`
+	Methods   []MethodLimitConfig `mapstructure:"methods"`
+}
+
+type LimitConfig struct {
+	Rate  float64 `mapstructure:"rate"`
+	Burst int     `mapstructure:"burst"`
+}
+
+type MethodLimitConfig struct {
+	Method string  `mapstructure:"method"`
+	Rate   float64 `mapstructure:"rate"`
+	Burst  int     `mapstructure:"burst"`
+}
+
+type WebhooksConfig struct {
+	BaseURL                string `mapstructure:"base_url"`
+	SecretEncryptionKey    string `mapstructure:"secret_encryption_key"`
+	MaxBodyBytes           int64  `mapstructure:"max_body_bytes"`
+	DeliveryRetentionHours int32  `mapstructure:"delivery_retention_hours"`
+	PruneIntervalSeconds   int32  `mapstructure:"prune_interval_seconds"`
+	PruneBatchSize         int32  `mapstructure:"prune_batch_size"`
+}
+
+type MailConfig struct {
+	Backend      string `mapstructure:"backend"`
+	From         string `mapstructure:"from"`
+	SMTPHost     string `mapstructure:"smtp_host"`
+	SMTPPort     int    `mapstructure:"smtp_port"`
+	SMTPUsername string `mapstructure:"smtp_username"`
+	SMTPPassword string `mapstructure:"smtp_password"`
+	FilePath     string `mapstructure:"file_path"`
+	LinkBaseURL  string `mapstructure:"link_base_url"`
+}
+
 func Load(path string) (*Config, error) {
 	viper.AddConfigPath(path)
 	viper.SetConfigName("config")
@@ -0,0 +1,259 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: auth/v1/policy.proto
+
+package authv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	descriptorpb "google.golang.org/protobuf/types/descriptorpb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Tenant roles ordered by privilege; a role grants everything the roles below it do.
+type Role int32
+
+const (
+	Role_ROLE_UNSPECIFIED Role = 0
+	Role_ROLE_VIEWER      Role = 1
+	Role_ROLE_MEMBER      Role = 2
+	Role_ROLE_ADMIN       Role = 3
+	Role_ROLE_OWNER       Role = 4
+)
+
+// Enum value maps for Role.
+var (
+	Role_name = map[int32]string{
+		0: "ROLE_UNSPECIFIED",
+		1: "ROLE_VIEWER",
+		2: "ROLE_MEMBER",
+		3: "ROLE_ADMIN",
+		4: "ROLE_OWNER",
+	}
+	Role_value = map[string]int32{
+		"ROLE_UNSPECIFIED": 0,
+		"ROLE_VIEWER":      1,
+		"ROLE_MEMBER":      2,
+		"ROLE_ADMIN":       3,
+		"ROLE_OWNER":       4,
+	}
+)
+
+func (x Role) Enum() *Role {
+	p := new(Role)
+	*p = x
+	return p
+}
+
+func (x Role) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Role) Descriptor() protoreflect.EnumDescriptor {
+	return file_auth_v1_policy_proto_enumTypes[0].Descriptor()
+}
+
+func (Role) Type() protoreflect.EnumType {
+	return &file_auth_v1_policy_proto_enumTypes[0]
+}
+
+func (x Role) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Role.Descriptor instead.
+func (Role) EnumDescriptor() ([]byte, []int) {
+	return file_auth_v1_policy_proto_rawDescGZIP(), []int{0}
+}
+
+type Policy struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The method can be called without credentials.
+	Public bool `protobuf:"varint,1,opt,name=public,proto3" json:"public,omitempty"`
+	// The method requires an authenticated caller but no active tenant.
+	Tenantless bool `protobuf:"varint,2,opt,name=tenantless,proto3" json:"tenantless,omitempty"`
+	// Minimum role the caller must hold in the active tenant.
+	Role Role `protobuf:"varint,3,opt,name=role,proto3,enum=auth.v1.Role" json:"role,omitempty"`
+	// API key scope required to call the method, written as <resource>:<read|write>.
+	// Keys restricted to scopes cannot call methods without one.
+	Scope string `protobuf:"bytes,4,opt,name=scope,proto3" json:"scope,omitempty"`
+	// The method stays callable with a user token while the tenant is suspended
+	// or archived, e.g. to reactivate it. API keys of such tenants are always
+	// rejected.
+	AllowInactiveTenant bool `protobuf:"varint,5,opt,name=allow_inactive_tenant,json=allowInactiveTenant,proto3" json:"allow_inactive_tenant,omitempty"`
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *Policy) Reset() {
+	*x = Policy{}
+	mi := &file_auth_v1_policy_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Policy) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Policy) ProtoMessage() {}
+
+func (x *Policy) ProtoReflect() protoreflect.Message {
+	mi := &file_auth_v1_policy_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Policy.ProtoReflect.Descriptor instead.
+func (*Policy) Descriptor() ([]byte, []int) {
+	return file_auth_v1_policy_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Policy) GetPublic() bool {
+	if x != nil {
+		return x.Public
+	}
+	return false
+}
+
+func (x *Policy) GetTenantless() bool {
+	if x != nil {
+		return x.Tenantless
+	}
+	return false
+}
+
+func (x *Policy) GetRole() Role {
+	if x != nil {
+		return x.Role
+	}
+	return Role_ROLE_UNSPECIFIED
+}
+
+func (x *Policy) GetScope() string {
+	if x != nil {
+		return x.Scope
+	}
+	return ""
+}
+
+func (x *Policy) GetAllowInactiveTenant() bool {
+	if x != nil {
+		return x.AllowInactiveTenant
+	}
+	return false
+}
+
+var file_auth_v1_policy_proto_extTypes = []protoimpl.ExtensionInfo{
+	{
+		ExtendedType:  (*descriptorpb.MethodOptions)(nil),
+		ExtensionType: (*Policy)(nil),
+		Field:         50001,
+		Name:          "auth.v1.policy",
+		Tag:           "bytes,50001,opt,name=policy",
+		Filename:      "auth/v1/policy.proto",
+	},
+}
+
+// Extension fields to descriptorpb.MethodOptions.
+var (
+	// optional auth.v1.Policy policy = 50001;
+	E_Policy = &file_auth_v1_policy_proto_extTypes[0]
+)
+
+var File_auth_v1_policy_proto protoreflect.FileDescriptor
+
+const file_auth_v1_policy_proto_rawDesc = "" +
+	"\n" +
+	"\x14auth/v1/policy.proto\x12\aauth.v1\x1a google/protobuf/descriptor.proto\"\xad\x01\n" +
+	"\x06Policy\x12\x16\n" +
+	"\x06public\x18\x01 \x01(\bR\x06public\x12\x1e\n" +
+	"\n" +
+	"tenantless\x18\x02 \x01(\bR\n" +
+	"tenantless\x12!\n" +
+	"\x04role\x18\x03 \x01(\x0e2\r.auth.v1.RoleR\x04role\x12\x14\n" +
+	"\x05scope\x18\x04 \x01(\tR\x05scope\x122\n" +
+	"\x15allow_inactive_tenant\x18\x05 \x01(\bR\x13allowInactiveTenant*^\n" +
+	"\x04Role\x12\x14\n" +
+	"\x10ROLE_UNSPECIFIED\x10\x00\x12\x0f\n" +
+	"\vROLE_VIEWER\x10\x01\x12\x0f\n" +
+	"\vROLE_MEMBER\x10\x02\x12\x0e\n" +
+	"\n" +
+	"ROLE_ADMIN\x10\x03\x12\x0e\n" +
+	"\n" +
+	"ROLE_OWNER\x10\x04:I\n" +
+	"\x06policy\x12\x1e.google.protobuf.MethodOptions\x18ц\x03 \x01(\v2\x0f.auth.v1.PolicyR\x06policyB3Z1github.com/vantutran2k1/rwe/gen/go/auth/v1;authv1b\x06proto3"
+
+var (
+	file_auth_v1_policy_proto_rawDescOnce sync.Once
+	file_auth_v1_policy_proto_rawDescData []byte
+)
+
+func file_auth_v1_policy_proto_rawDescGZIP() []byte {
+	file_auth_v1_policy_proto_rawDescOnce.Do(func() {
+		file_auth_v1_policy_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_auth_v1_policy_proto_rawDesc), len(file_auth_v1_policy_proto_rawDesc)))
+	})
+	return file_auth_v1_policy_proto_rawDescData
+}
+
+var file_auth_v1_policy_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_auth_v1_policy_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_auth_v1_policy_proto_goTypes = []any{
+	(Role)(0),                          // 0: auth.v1.Role
+	(*Policy)(nil),                     // 1: auth.v1.Policy
+	(*descriptorpb.MethodOptions)(nil), // 2: google.protobuf.MethodOptions
+}
+var file_auth_v1_policy_proto_depIdxs = []int32{
+	0, // 0: auth.v1.Policy.role:type_name -> auth.v1.Role
+	2, // 1: auth.v1.policy:extendee -> google.protobuf.MethodOptions
+	1, // 2: auth.v1.policy:type_name -> auth.v1.Policy
+	3, // [3:3] is the sub-list for method output_type
+	3, // [3:3] is the sub-list for method input_type
+	2, // [2:3] is the sub-list for extension type_name
+	1, // [1:2] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
+}
+
+func init() { file_auth_v1_policy_proto_init() }
+func file_auth_v1_policy_proto_init() {
+	if File_auth_v1_policy_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_auth_v1_policy_proto_rawDesc), len(file_auth_v1_policy_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   1,
+			NumExtensions: 1,
+			NumServices:   0,
+		},
+		GoTypes:           file_auth_v1_policy_proto_goTypes,
+		DependencyIndexes: file_auth_v1_policy_proto_depIdxs,
+		EnumInfos:         file_auth_v1_policy_proto_enumTypes,
+		MessageInfos:      file_auth_v1_policy_proto_msgTypes,
+		ExtensionInfos:    file_auth_v1_policy_proto_extTypes,
+	}.Build()
+	File_auth_v1_policy_proto = out.File
+	file_auth_v1_policy_proto_goTypes = nil
+	file_auth_v1_policy_proto_depIdxs = nil
+}
@@ -25,49 +25,177 @@ var File_auth_v1_services_proto protoreflect.FileDescriptor
 
 const file_auth_v1_services_proto_rawDesc = "" +
 	"\n" +
-	"\x16auth/v1/services.proto\x12\aauth.v1\x1a\x13auth/v1/types.proto\x1a\x1cgoogle/api/annotations.proto2\x97\x05\n" +
-	"\vAuthService\x12Q\n" +
-	"\x0eValidateApiKey\x12\x1e.auth.v1.ValidateApiKeyRequest\x1a\x1f.auth.v1.ValidateApiKeyResponse\x12b\n" +
-	"\vIssueApiKey\x12\x1b.auth.v1.IssueApiKeyRequest\x1a\x1c.auth.v1.IssueApiKeyResponse\"\x18\x82\xd3\xe4\x93\x02\x12:\x01*\"\r/v1/auth/keys\x12g\n" +
-	"\fRevokeApiKey\x12\x1c.auth.v1.RevokeApiKeyRequest\x1a\x1d.auth.v1.RevokeApiKeyResponse\"\x1a\x82\xd3\xe4\x93\x02\x14*\x12/v1/auth/keys/{id}\x12_\n" +
-	"\vListApiKeys\x12\x1b.auth.v1.ListApiKeysRequest\x1a\x1c.auth.v1.ListApiKeysResponse\"\x15\x82\xd3\xe4\x93\x02\x0f\x12\r/v1/auth/keys\x12]\n" +
-	"\bRegister\x12\x18.auth.v1.RegisterRequest\x1a\x19.auth.v1.RegisterResponse\"\x1c\x82\xd3\xe4\x93\x02\x16:\x01*\"\x11/v1/auth/register\x12Q\n" +
-	"\x05Login\x12\x15.auth.v1.LoginRequest\x1a\x16.auth.v1.LoginResponse\"\x19\x82\xd3\xe4\x93\x02\x13:\x01*\"\x0e/v1/auth/login\x12U\n" +
-	"\x06Logout\x12\x16.auth.v1.LogoutRequest\x1a\x17.auth.v1.LogoutResponse\"\x1a\x82\xd3\xe4\x93\x02\x14:\x01*\"\x0f/v1/auth/logoutB3Z1github.com/vantutran2k1/rwe/gen/go/auth/v1;authv1b\x06proto3"
+	"\x16auth/v1/services.proto\x12\aauth.v1\x1a\x13auth/v1/types.proto\x1a\x14auth/v1/policy.proto\x1a\x1cgoogle/api/annotations.proto2\xa6\x1e\n" +
+	"\vAuthService\x12Y\n" +
+	"\x0eValidateApiKey\x12\x1e.auth.v1.ValidateApiKeyRequest\x1a\x1f.auth.v1.ValidateApiKeyResponse\"\x06\x8a\xb5\x18\x02\x10\x01\x12x\n" +
+	"\vIssueApiKey\x12\x1b.auth.v1.IssueApiKeyRequest\x1a\x1c.auth.v1.IssueApiKeyResponse\".\x8a\xb5\x18\x12\x18\x03\"\x0eapi_keys:write\x82\xd3\xe4\x93\x02\x12:\x01*\"\r/v1/auth/keys\x12\x87\x01\n" +
+	"\fRotateApiKey\x12\x1c.auth.v1.RotateApiKeyRequest\x1a\x1d.auth.v1.RotateApiKeyResponse\":\x8a\xb5\x18\x12\x18\x03\"\x0eapi_keys:write\x82\xd3\xe4\x93\x02\x1e:\x01*\"\x19/v1/auth/keys/{id}/rotate\x12}\n" +
+	"\fRevokeApiKey\x12\x1c.auth.v1.RevokeApiKeyRequest\x1a\x1d.auth.v1.RevokeApiKeyResponse\"0\x8a\xb5\x18\x12\x18\x03\"\x0eapi_keys:write\x82\xd3\xe4\x93\x02\x14*\x12/v1/auth/keys/{id}\x12t\n" +
+	"\vListApiKeys\x12\x1b.auth.v1.ListApiKeysRequest\x1a\x1c.auth.v1.ListApiKeysResponse\"*\x8a\xb5\x18\x11\x18\x03\"\rapi_keys:read\x82\xd3\xe4\x93\x02\x0f\x12\r/v1/auth/keys\x12c\n" +
+	"\bRegister\x12\x18.auth.v1.RegisterRequest\x1a\x19.auth.v1.RegisterResponse\"\"\x8a\xb5\x18\x02\b\x01\x82\xd3\xe4\x93\x02\x16:\x01*\"\x11/v1/auth/register\x12W\n" +
+	"\x05Login\x12\x15.auth.v1.LoginRequest\x1a\x16.auth.v1.LoginResponse\"\x1f\x8a\xb5\x18\x02\b\x01\x82\xd3\xe4\x93\x02\x13:\x01*\"\x0e/v1/auth/login\x12h\n" +
+	"\tVerifyMfa\x12\x19.auth.v1.VerifyMfaRequest\x1a\x1a.auth.v1.VerifyMfaResponse\"$\x8a\xb5\x18\x02\b\x01\x82\xd3\xe4\x93\x02\x18:\x01*\"\x13/v1/auth/mfa/verify\x12h\n" +
+	"\tEnrollMfa\x12\x19.auth.v1.EnrollMfaRequest\x1a\x1a.auth.v1.EnrollMfaResponse\"$\x8a\xb5\x18\x02\x10\x01\x82\xd3\xe4\x93\x02\x18:\x01*\"\x13/v1/auth/mfa/enroll\x12l\n" +
+	"\n" +
+	"ConfirmMfa\x12\x1a.auth.v1.ConfirmMfaRequest\x1a\x1b.auth.v1.ConfirmMfaResponse\"%\x8a\xb5\x18\x02\x10\x01\x82\xd3\xe4\x93\x02\x19:\x01*\"\x14/v1/auth/mfa/confirm\x12l\n" +
+	"\n" +
+	"DisableMfa\x12\x1a.auth.v1.DisableMfaRequest\x1a\x1b.auth.v1.DisableMfaResponse\"%\x8a\xb5\x18\x02\x10\x01\x82\xd3\xe4\x93\x02\x19:\x01*\"\x14/v1/auth/mfa/disable\x12\x9a\x01\n" +
+	"\x17RegenerateRecoveryCodes\x12'.auth.v1.RegenerateRecoveryCodesRequest\x1a(.auth.v1.RegenerateRecoveryCodesResponse\",\x8a\xb5\x18\x02\x10\x01\x82\xd3\xe4\x93\x02 :\x01*\"\x1b/v1/auth/mfa/recovery-codes\x12n\n" +
+	"\fRefreshToken\x12\x1c.auth.v1.RefreshTokenRequest\x1a\x1d.auth.v1.RefreshTokenResponse\"!\x8a\xb5\x18\x02\b\x01\x82\xd3\xe4\x93\x02\x15:\x01*\"\x10/v1/auth/refresh\x12l\n" +
+	"\fListSessions\x12\x1c.auth.v1.ListSessionsRequest\x1a\x1d.auth.v1.ListSessionsResponse\"\x1f\x8a\xb5\x18\x02\x10\x01\x82\xd3\xe4\x93\x02\x13\x12\x11/v1/auth/sessions\x12t\n" +
+	"\rRevokeSession\x12\x1d.auth.v1.RevokeSessionRequest\x1a\x1e.auth.v1.RevokeSessionResponse\"$\x8a\xb5\x18\x02\x10\x01\x82\xd3\xe4\x93\x02\x18*\x16/v1/auth/sessions/{id}\x12\x95\x01\n" +
+	"\x14RequestPasswordReset\x12$.auth.v1.RequestPasswordResetRequest\x1a%.auth.v1.RequestPasswordResetResponse\"0\x8a\xb5\x18\x02\b\x01\x82\xd3\xe4\x93\x02$:\x01*\"\x1f/v1/auth/password/reset-request\x12x\n" +
+	"\rResetPassword\x12\x1d.auth.v1.ResetPasswordRequest\x1a\x1e.auth.v1.ResetPasswordResponse\"(\x8a\xb5\x18\x02\b\x01\x82\xd3\xe4\x93\x02\x1c:\x01*\"\x17/v1/auth/password/reset\x12\x94\x01\n" +
+	"\x15SendVerificationEmail\x12%.auth.v1.SendVerificationEmailRequest\x1a&.auth.v1.SendVerificationEmailResponse\",\x8a\xb5\x18\x02\x10\x01\x82\xd3\xe4\x93\x02 :\x01*\"\x1b/v1/auth/email/verification\x12p\n" +
+	"\vVerifyEmail\x12\x1b.auth.v1.VerifyEmailRequest\x1a\x1c.auth.v1.VerifyEmailResponse\"&\x8a\xb5\x18\x02\b\x01\x82\xd3\xe4\x93\x02\x1a:\x01*\"\x15/v1/auth/email/verify\x12}\n" +
+	"\x0fSetOidcProvider\x12\x1f.auth.v1.SetOidcProviderRequest\x1a .auth.v1.SetOidcProviderResponse\"'\x8a\xb5\x18\x02\x18\x04\x82\xd3\xe4\x93\x02\x1b:\x01*\x1a\x16/v1/auth/oidc/provider\x12z\n" +
+	"\x0fGetOidcProvider\x12\x1f.auth.v1.GetOidcProviderRequest\x1a .auth.v1.GetOidcProviderResponse\"$\x8a\xb5\x18\x02\x18\x03\x82\xd3\xe4\x93\x02\x18\x12\x16/v1/auth/oidc/provider\x12\x83\x01\n" +
+	"\x12DeleteOidcProvider\x12\".auth.v1.DeleteOidcProviderRequest\x1a#.auth.v1.DeleteOidcProviderResponse\"$\x8a\xb5\x18\x02\x18\x04\x82\xd3\xe4\x93\x02\x18*\x16/v1/auth/oidc/provider\x12\x82\x01\n" +
+	"\x0eStartOidcLogin\x12\x1e.auth.v1.StartOidcLoginRequest\x1a\x1f.auth.v1.StartOidcLoginResponse\"/\x8a\xb5\x18\x02\b\x01\x82\xd3\xe4\x93\x02#\x12!/v1/auth/oidc/{tenant_slug}/login\x12q\n" +
+	"\fOidcCallback\x12\x1c.auth.v1.OidcCallbackRequest\x1a\x1d.auth.v1.OidcCallbackResponse\"$\x8a\xb5\x18\x02\b\x01\x82\xd3\xe4\x93\x02\x18\x12\x16/v1/auth/oidc/callback\x12\x81\x01\n" +
+	"\rStartOidcLink\x12\x1d.auth.v1.StartOidcLinkRequest\x1a\x1e.auth.v1.StartOidcLinkResponse\"1\x8a\xb5\x18\x02\x10\x01\x82\xd3\xe4\x93\x02%:\x01*\" /v1/auth/oidc/{tenant_slug}/link\x12s\n" +
+	"\fAddSsoDomain\x12\x1c.auth.v1.AddSsoDomainRequest\x1a\x1d.auth.v1.AddSsoDomainResponse\"&\x8a\xb5\x18\x02\x18\x04\x82\xd3\xe4\x93\x02\x1a:\x01*\"\x15/v1/auth/oidc/domains\x12\x8c\x01\n" +
+	"\x0fVerifySsoDomain\x12\x1f.auth.v1.VerifySsoDomainRequest\x1a .auth.v1.VerifySsoDomainResponse\"6\x8a\xb5\x18\x02\x18\x04\x82\xd3\xe4\x93\x02*:\x01*\"%/v1/auth/oidc/domains/{domain}:verify\x12v\n" +
+	"\x0eListSsoDomains\x12\x1e.auth.v1.ListSsoDomainsRequest\x1a\x1f.auth.v1.ListSsoDomainsResponse\"#\x8a\xb5\x18\x02\x18\x03\x82\xd3\xe4\x93\x02\x17\x12\x15/v1/auth/oidc/domains\x12\x82\x01\n" +
+	"\x0fDeleteSsoDomain\x12\x1f.auth.v1.DeleteSsoDomainRequest\x1a .auth.v1.DeleteSsoDomainResponse\",\x8a\xb5\x18\x02\x18\x04\x82\xd3\xe4\x93\x02 *\x1e/v1/auth/oidc/domains/{domain}\x12u\n" +
+	"\x0eListPublicKeys\x12\x1e.auth.v1.ListPublicKeysRequest\x1a\x1f.auth.v1.ListPublicKeysResponse\"\"\x8a\xb5\x18\x02\b\x01\x82\xd3\xe4\x93\x02\x16\x12\x14/v1/auth/public-keys\x12w\n" +
+	"\n" +
+	"UnlockUser\x12\x1a.auth.v1.UnlockUserRequest\x1a\x1b.auth.v1.UnlockUserResponse\"0\x8a\xb5\x18\x02\x18\x03\x82\xd3\xe4\x93\x02$:\x01*\"\x1f/v1/auth/users/{user_id}/unlock\x12[\n" +
+	"\x06Logout\x12\x16.auth.v1.LogoutRequest\x1a\x17.auth.v1.LogoutResponse\" \x8a\xb5\x18\x02\x10\x01\x82\xd3\xe4\x93\x02\x14:\x01*\"\x0f/v1/auth/logoutB3Z1github.com/vantutran2k1/rwe/gen/go/auth/v1;authv1b\x06proto3"
 
 var file_auth_v1_services_proto_goTypes = []any{
-	(*ValidateApiKeyRequest)(nil),  // 0: auth.v1.ValidateApiKeyRequest
-	(*IssueApiKeyRequest)(nil),     // 1: auth.v1.IssueApiKeyRequest
-	(*RevokeApiKeyRequest)(nil),    // 2: auth.v1.RevokeApiKeyRequest
-	(*ListApiKeysRequest)(nil),     // 3: auth.v1.ListApiKeysRequest
-	(*RegisterRequest)(nil),        // 4: auth.v1.RegisterRequest
-	(*LoginRequest)(nil),           // 5: auth.v1.LoginRequest
-	(*LogoutRequest)(nil),          // 6: auth.v1.LogoutRequest
-	(*ValidateApiKeyResponse)(nil), // 7: auth.v1.ValidateApiKeyResponse
-	(*IssueApiKeyResponse)(nil),    // 8: auth.v1.IssueApiKeyResponse
-	(*RevokeApiKeyResponse)(nil),   // 9: auth.v1.RevokeApiKeyResponse
-	(*ListApiKeysResponse)(nil),    // 10: auth.v1.ListApiKeysResponse
-	(*RegisterResponse)(nil),       // 11: auth.v1.RegisterResponse
-	(*LoginResponse)(nil),          // 12: auth.v1.LoginResponse
-	(*LogoutResponse)(nil),         // 13: auth.v1.LogoutResponse
+	(*ValidateApiKeyRequest)(nil),           // 0: auth.v1.ValidateApiKeyRequest
+	(*IssueApiKeyRequest)(nil),              // 1: auth.v1.IssueApiKeyRequest
+	(*RotateApiKeyRequest)(nil),             // 2: auth.v1.RotateApiKeyRequest
+	(*RevokeApiKeyRequest)(nil),             // 3: auth.v1.RevokeApiKeyRequest
+	(*ListApiKeysRequest)(nil),              // 4: auth.v1.ListApiKeysRequest
+	(*RegisterRequest)(nil),                 // 5: auth.v1.RegisterRequest
+	(*LoginRequest)(nil),                    // 6: auth.v1.LoginRequest
+	(*VerifyMfaRequest)(nil),                // 7: auth.v1.VerifyMfaRequest
+	(*EnrollMfaRequest)(nil),                // 8: auth.v1.EnrollMfaRequest
+	(*ConfirmMfaRequest)(nil),               // 9: auth.v1.ConfirmMfaRequest
+	(*DisableMfaRequest)(nil),               // 10: auth.v1.DisableMfaRequest
+	(*RegenerateRecoveryCodesRequest)(nil),  // 11: auth.v1.RegenerateRecoveryCodesRequest
+	(*RefreshTokenRequest)(nil),             // 12: auth.v1.RefreshTokenRequest
+	(*ListSessionsRequest)(nil),             // 13: auth.v1.ListSessionsRequest
+	(*RevokeSessionRequest)(nil),            // 14: auth.v1.RevokeSessionRequest
+	(*RequestPasswordResetRequest)(nil),     // 15: auth.v1.RequestPasswordResetRequest
+	(*ResetPasswordRequest)(nil),            // 16: auth.v1.ResetPasswordRequest
+	(*SendVerificationEmailRequest)(nil),    // 17: auth.v1.SendVerificationEmailRequest
+	(*VerifyEmailRequest)(nil),              // 18: auth.v1.VerifyEmailRequest
+	(*SetOidcProviderRequest)(nil),          // 19: auth.v1.SetOidcProviderRequest
+	(*GetOidcProviderRequest)(nil),          // 20: auth.v1.GetOidcProviderRequest
+	(*DeleteOidcProviderRequest)(nil),       // 21: auth.v1.DeleteOidcProviderRequest
+	(*StartOidcLoginRequest)(nil),           // 22: auth.v1.StartOidcLoginRequest
+	(*OidcCallbackRequest)(nil),             // 23: auth.v1.OidcCallbackRequest
+	(*StartOidcLinkRequest)(nil),            // 24: auth.v1.StartOidcLinkRequest
+	(*AddSsoDomainRequest)(nil),             // 25: auth.v1.AddSsoDomainRequest
+	(*VerifySsoDomainRequest)(nil),          // 26: auth.v1.VerifySsoDomainRequest
+	(*ListSsoDomainsRequest)(nil),           // 27: auth.v1.ListSsoDomainsRequest
+	(*DeleteSsoDomainRequest)(nil),          // 28: auth.v1.DeleteSsoDomainRequest
+	(*ListPublicKeysRequest)(nil),           // 29: auth.v1.ListPublicKeysRequest
+	(*UnlockUserRequest)(nil),               // 30: auth.v1.UnlockUserRequest
+	(*LogoutRequest)(nil),                   // 31: auth.v1.LogoutRequest
+	(*ValidateApiKeyResponse)(nil),          // 32: auth.v1.ValidateApiKeyResponse
+	(*IssueApiKeyResponse)(nil),             // 33: auth.v1.IssueApiKeyResponse
+	(*RotateApiKeyResponse)(nil),            // 34: auth.v1.RotateApiKeyResponse
+	(*RevokeApiKeyResponse)(nil),            // 35: auth.v1.RevokeApiKeyResponse
+	(*ListApiKeysResponse)(nil),             // 36: auth.v1.ListApiKeysResponse
+	(*RegisterResponse)(nil),                // 37: auth.v1.RegisterResponse
+	(*LoginResponse)(nil),                   // 38: auth.v1.LoginResponse
+	(*VerifyMfaResponse)(nil),               // 39: auth.v1.VerifyMfaResponse
+	(*EnrollMfaResponse)(nil),               // 40: auth.v1.EnrollMfaResponse
+	(*ConfirmMfaResponse)(nil),              // 41: auth.v1.ConfirmMfaResponse
+	(*DisableMfaResponse)(nil),              // 42: auth.v1.DisableMfaResponse
+	(*RegenerateRecoveryCodesResponse)(nil), // 43: auth.v1.RegenerateRecoveryCodesResponse
+	(*RefreshTokenResponse)(nil),            // 44: auth.v1.RefreshTokenResponse
+	(*ListSessionsResponse)(nil),            // 45: auth.v1.ListSessionsResponse
+	(*RevokeSessionResponse)(nil),           // 46: auth.v1.RevokeSessionResponse
+	(*RequestPasswordResetResponse)(nil),    // 47: auth.v1.RequestPasswordResetResponse
+	(*ResetPasswordResponse)(nil),           // 48: auth.v1.ResetPasswordResponse
+	(*SendVerificationEmailResponse)(nil),   // 49: auth.v1.SendVerificationEmailResponse
+	(*VerifyEmailResponse)(nil),             // 50: auth.v1.VerifyEmailResponse
+	(*SetOidcProviderResponse)(nil),         // 51: auth.v1.SetOidcProviderResponse
+	(*GetOidcProviderResponse)(nil),         // 52: auth.v1.GetOidcProviderResponse
+	(*DeleteOidcProviderResponse)(nil),      // 53: auth.v1.DeleteOidcProviderResponse
+	(*StartOidcLoginResponse)(nil),          // 54: auth.v1.StartOidcLoginResponse
+	(*OidcCallbackResponse)(nil),            // 55: auth.v1.OidcCallbackResponse
+	(*StartOidcLinkResponse)(nil),           // 56: auth.v1.StartOidcLinkResponse
+	(*AddSsoDomainResponse)(nil),            // 57: auth.v1.AddSsoDomainResponse
+	(*VerifySsoDomainResponse)(nil),         // 58: auth.v1.VerifySsoDomainResponse
+	(*ListSsoDomainsResponse)(nil),          // 59: auth.v1.ListSsoDomainsResponse
+	(*DeleteSsoDomainResponse)(nil),         // 60: auth.v1.DeleteSsoDomainResponse
+	(*ListPublicKeysResponse)(nil),          // 61: auth.v1.ListPublicKeysResponse
+	(*UnlockUserResponse)(nil),              // 62: auth.v1.UnlockUserResponse
+	(*LogoutResponse)(nil),                  // 63: auth.v1.LogoutResponse
 }
 var file_auth_v1_services_proto_depIdxs = []int32{
 	0,  // 0: auth.v1.AuthService.ValidateApiKey:input_type -> auth.v1.ValidateApiKeyRequest
 	1,  // 1: auth.v1.AuthService.IssueApiKey:input_type -> auth.v1.IssueApiKeyRequest
-	2,  // 2: auth.v1.AuthService.RevokeApiKey:input_type -> auth.v1.RevokeApiKeyRequest
-	3,  // 3: auth.v1.AuthService.ListApiKeys:input_type -> auth.v1.ListApiKeysRequest
-	4,  // 4: auth.v1.AuthService.Register:input_type -> auth.v1.RegisterRequest
-	5,  // 5: auth.v1.AuthService.Login:input_type -> auth.v1.LoginRequest
-	6,  // 6: auth.v1.AuthService.Logout:input_type -> auth.v1.LogoutRequest
-	7,  // 7: auth.v1.AuthService.ValidateApiKey:output_type -> auth.v1.ValidateApiKeyResponse
-	8,  // 8: auth.v1.AuthService.IssueApiKey:output_type -> auth.v1.IssueApiKeyResponse
-	9,  // 9: auth.v1.AuthService.RevokeApiKey:output_type -> auth.v1.RevokeApiKeyResponse
-	10, // 10: auth.v1.AuthService.ListApiKeys:output_type -> auth.v1.ListApiKeysResponse
-	11, // 11: auth.v1.AuthService.Register:output_type -> auth.v1.RegisterResponse
-	12, // 12: auth.v1.AuthService.Login:output_type -> auth.v1.LoginResponse
-	13, // 13: auth.v1.AuthService.Logout:output_type -> auth.v1.LogoutResponse
-	7,  // [7:14] is the sub-list for method output_type
-	0,  // [0:7] is the sub-list for method input_type
+	2,  // 2: auth.v1.AuthService.RotateApiKey:input_type -> auth.v1.RotateApiKeyRequest
+	3,  // 3: auth.v1.AuthService.RevokeApiKey:input_type -> auth.v1.RevokeApiKeyRequest
+	4,  // 4: auth.v1.AuthService.ListApiKeys:input_type -> auth.v1.ListApiKeysRequest
+	5,  // 5: auth.v1.AuthService.Register:input_type -> auth.v1.RegisterRequest
+	6,  // 6: auth.v1.AuthService.Login:input_type -> auth.v1.LoginRequest
+	7,  // 7: auth.v1.AuthService.VerifyMfa:input_type -> auth.v1.VerifyMfaRequest
+	8,  // 8: auth.v1.AuthService.EnrollMfa:input_type -> auth.v1.EnrollMfaRequest
+	9,  // 9: auth.v1.AuthService.ConfirmMfa:input_type -> auth.v1.ConfirmMfaRequest
+	10, // 10: auth.v1.AuthService.DisableMfa:input_type -> auth.v1.DisableMfaRequest
+	11, // 11: auth.v1.AuthService.RegenerateRecoveryCodes:input_type -> auth.v1.RegenerateRecoveryCodesRequest
+	12, // 12: auth.v1.AuthService.RefreshToken:input_type -> auth.v1.RefreshTokenRequest
+	13, // 13: auth.v1.AuthService.ListSessions:input_type -> auth.v1.ListSessionsRequest
+	14, // 14: auth.v1.AuthService.RevokeSession:input_type -> auth.v1.RevokeSessionRequest
+	15, // 15: auth.v1.AuthService.RequestPasswordReset:input_type -> auth.v1.RequestPasswordResetRequest
+	16, // 16: auth.v1.AuthService.ResetPassword:input_type -> auth.v1.ResetPasswordRequest
+	17, // 17: auth.v1.AuthService.SendVerificationEmail:input_type -> auth.v1.SendVerificationEmailRequest
+	18, // 18: auth.v1.AuthService.VerifyEmail:input_type -> auth.v1.VerifyEmailRequest
+	19, // 19: auth.v1.AuthService.SetOidcProvider:input_type -> auth.v1.SetOidcProviderRequest
+	20, // 20: auth.v1.AuthService.GetOidcProvider:input_type -> auth.v1.GetOidcProviderRequest
+	21, // 21: auth.v1.AuthService.DeleteOidcProvider:input_type -> auth.v1.DeleteOidcProviderRequest
+	22, // 22: auth.v1.AuthService.StartOidcLogin:input_type -> auth.v1.StartOidcLoginRequest
+	23, // 23: auth.v1.AuthService.OidcCallback:input_type -> auth.v1.OidcCallbackRequest
+	24, // 24: auth.v1.AuthService.StartOidcLink:input_type -> auth.v1.StartOidcLinkRequest
+	25, // 25: auth.v1.AuthService.AddSsoDomain:input_type -> auth.v1.AddSsoDomainRequest
+	26, // 26: auth.v1.AuthService.VerifySsoDomain:input_type -> auth.v1.VerifySsoDomainRequest
+	27, // 27: auth.v1.AuthService.ListSsoDomains:input_type -> auth.v1.ListSsoDomainsRequest
+	28, // 28: auth.v1.AuthService.DeleteSsoDomain:input_type -> auth.v1.DeleteSsoDomainRequest
+	29, // 29: auth.v1.AuthService.ListPublicKeys:input_type -> auth.v1.ListPublicKeysRequest
+	30, // 30: auth.v1.AuthService.UnlockUser:input_type -> auth.v1.UnlockUserRequest
+	31, // 31: auth.v1.AuthService.Logout:input_type -> auth.v1.LogoutRequest
+	32, // 32: auth.v1.AuthService.ValidateApiKey:output_type -> auth.v1.ValidateApiKeyResponse
+	33, // 33: auth.v1.AuthService.IssueApiKey:output_type -> auth.v1.IssueApiKeyResponse
+	34, // 34: auth.v1.AuthService.RotateApiKey:output_type -> auth.v1.RotateApiKeyResponse
+	35, // 35: auth.v1.AuthService.RevokeApiKey:output_type -> auth.v1.RevokeApiKeyResponse
+	36, // 36: auth.v1.AuthService.ListApiKeys:output_type -> auth.v1.ListApiKeysResponse
+	37, // 37: auth.v1.AuthService.Register:output_type -> auth.v1.RegisterResponse
+	38, // 38: auth.v1.AuthService.Login:output_type -> auth.v1.LoginResponse
+	39, // 39: auth.v1.AuthService.VerifyMfa:output_type -> auth.v1.VerifyMfaResponse
+	40, // 40: auth.v1.AuthService.EnrollMfa:output_type -> auth.v1.EnrollMfaResponse
+	41, // 41: auth.v1.AuthService.ConfirmMfa:output_type -> auth.v1.ConfirmMfaResponse
+	42, // 42: auth.v1.AuthService.DisableMfa:output_type -> auth.v1.DisableMfaResponse
+	43, // 43: auth.v1.AuthService.RegenerateRecoveryCodes:output_type -> auth.v1.RegenerateRecoveryCodesResponse
+	44, // 44: auth.v1.AuthService.RefreshToken:output_type -> auth.v1.RefreshTokenResponse
+	45, // 45: auth.v1.AuthService.ListSessions:output_type -> auth.v1.ListSessionsResponse
+	46, // 46: auth.v1.AuthService.RevokeSession:output_type -> auth.v1.RevokeSessionResponse
+	47, // 47: auth.v1.AuthService.RequestPasswordReset:output_type -> auth.v1.RequestPasswordResetResponse
+	48, // 48: auth.v1.AuthService.ResetPassword:output_type -> auth.v1.ResetPasswordResponse
+	49, // 49: auth.v1.AuthService.SendVerificationEmail:output_type -> auth.v1.SendVerificationEmailResponse
+	50, // 50: auth.v1.AuthService.VerifyEmail:output_type -> auth.v1.VerifyEmailResponse
+	51, // 51: auth.v1.AuthService.SetOidcProvider:output_type -> auth.v1.SetOidcProviderResponse
+	52, // 52: auth.v1.AuthService.GetOidcProvider:output_type -> auth.v1.GetOidcProviderResponse
+	53, // 53: auth.v1.AuthService.DeleteOidcProvider:output_type -> auth.v1.DeleteOidcProviderResponse
+	54, // 54: auth.v1.AuthService.StartOidcLogin:output_type -> auth.v1.StartOidcLoginResponse
+	55, // 55: auth.v1.AuthService.OidcCallback:output_type -> auth.v1.OidcCallbackResponse
+	56, // 56: auth.v1.AuthService.StartOidcLink:output_type -> auth.v1.StartOidcLinkResponse
+	57, // 57: auth.v1.AuthService.AddSsoDomain:output_type -> auth.v1.AddSsoDomainResponse
+	58, // 58: auth.v1.AuthService.VerifySsoDomain:output_type -> auth.v1.VerifySsoDomainResponse
+	59, // 59: auth.v1.AuthService.ListSsoDomains:output_type -> auth.v1.ListSsoDomainsResponse
+	60, // 60: auth.v1.AuthService.DeleteSsoDomain:output_type -> auth.v1.DeleteSsoDomainResponse
+	61, // 61: auth.v1.AuthService.ListPublicKeys:output_type -> auth.v1.ListPublicKeysResponse
+	62, // 62: auth.v1.AuthService.UnlockUser:output_type -> auth.v1.UnlockUserResponse
+	63, // 63: auth.v1.AuthService.Logout:output_type -> auth.v1.LogoutResponse
+	32, // [32:64] is the sub-list for method output_type
+	0,  // [0:32] is the sub-list for method input_type
 	0,  // [0:0] is the sub-list for extension type_name
 	0,  // [0:0] is the sub-list for extension extendee
 	0,  // [0:0] is the sub-list for field type_name
@@ -79,6 +207,7 @@ func file_auth_v1_services_proto_init() {
 		return
 	}
 	file_auth_v1_types_proto_init()
+	file_auth_v1_policy_proto_init()
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
@@ -0,0 +1,73 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: event/v1/services.proto
+
+package eventv1
+
+import (
+	_ "github.com/vantutran2k1/rwe/gen/go/auth/v1"
+	_ "google.golang.org/genproto/googleapis/api/annotations"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+var File_event_v1_services_proto protoreflect.FileDescriptor
+
+const file_event_v1_services_proto_rawDesc = "" +
+	"\n" +
+	"\x17event/v1/services.proto\x12\bevent.v1\x1a\x14event/v1/types.proto\x1a\x14auth/v1/policy.proto\x1a\x1cgoogle/api/annotations.proto2\xf2\x01\n" +
+	"\fEventService\x12n\n" +
+	"\fStreamEvents\x12\x1d.event.v1.StreamEventsRequest\x1a\x0f.event.v1.Event\",\x8a\xb5\x18\x0f\x18\x01\"\vevents:read\x82\xd3\xe4\x93\x02\x13\x12\x11/v1/events:stream0\x01\x12r\n" +
+	"\x10StreamUserEvents\x12!.event.v1.StreamUserEventsRequest\x1a\x0f.event.v1.Event\"(\x8a\xb5\x18\x02\x10\x01\x82\xd3\xe4\x93\x02\x1c\x12\x1a/v1/users/me/events:stream0\x01B5Z3github.com/vantutran2k1/rwe/gen/go/event/v1;eventv1b\x06proto3"
+
+var file_event_v1_services_proto_goTypes = []any{
+	(*StreamEventsRequest)(nil),     // 0: event.v1.StreamEventsRequest
+	(*StreamUserEventsRequest)(nil), // 1: event.v1.StreamUserEventsRequest
+	(*Event)(nil),                   // 2: event.v1.Event
+}
+var file_event_v1_services_proto_depIdxs = []int32{
+	0, // 0: event.v1.EventService.StreamEvents:input_type -> event.v1.StreamEventsRequest
+	1, // 1: event.v1.EventService.StreamUserEvents:input_type -> event.v1.StreamUserEventsRequest
+	2, // 2: event.v1.EventService.StreamEvents:output_type -> event.v1.Event
+	2, // 3: event.v1.EventService.StreamUserEvents:output_type -> event.v1.Event
+	2, // [2:4] is the sub-list for method output_type
+	0, // [0:2] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_event_v1_services_proto_init() }
+func file_event_v1_services_proto_init() {
+	if File_event_v1_services_proto != nil {
+		return
+	}
+	file_event_v1_types_proto_init()
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_event_v1_services_proto_rawDesc), len(file_event_v1_services_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   0,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_event_v1_services_proto_goTypes,
+		DependencyIndexes: file_event_v1_services_proto_depIdxs,
+	}.Build()
+	File_event_v1_services_proto = out.File
+	file_event_v1_services_proto_goTypes = nil
+	file_event_v1_services_proto_depIdxs = nil
+}
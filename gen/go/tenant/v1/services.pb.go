@@ -7,6 +7,7 @@
 package tenantv1
 
 import (
+	_ "github.com/vantutran2k1/rwe/gen/go/auth/v1"
 	_ "google.golang.org/genproto/googleapis/api/annotations"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
@@ -25,22 +26,92 @@ var File_tenant_v1_services_proto protoreflect.FileDescriptor
 
 const file_tenant_v1_services_proto_rawDesc = "" +
 	"\n" +
-	"\x18tenant/v1/services.proto\x12\ttenant.v1\x1a\x15tenant/v1/types.proto\x1a\x1cgoogle/api/annotations.proto2x\n" +
-	"\rTenantService\x12g\n" +
-	"\fCreateTenant\x12\x1e.tenant.v1.CreateTenantRequest\x1a\x1f.tenant.v1.CreateTenantResponse\"\x16\x82\xd3\xe4\x93\x02\x10:\x01*\"\v/v1/tenantsB7Z5github.com/vantutran2k1/rwe/gen/go/tenant/v1;tenantv1b\x06proto3"
+	"\x18tenant/v1/services.proto\x12\ttenant.v1\x1a\x15tenant/v1/types.proto\x1a\x14auth/v1/policy.proto\x1a\x1cgoogle/api/annotations.proto2\xc7\x0f\n" +
+	"\rTenantService\x12m\n" +
+	"\fCreateTenant\x12\x1e.tenant.v1.CreateTenantRequest\x1a\x1f.tenant.v1.CreateTenantResponse\"\x1c\x8a\xb5\x18\x02\x10\x01\x82\xd3\xe4\x93\x02\x10:\x01*\"\v/v1/tenants\x12m\n" +
+	"\rListMyTenants\x12\x1f.tenant.v1.ListMyTenantsRequest\x1a .tenant.v1.ListMyTenantsResponse\"\x19\x8a\xb5\x18\x02\x10\x01\x82\xd3\xe4\x93\x02\r\x12\v/v1/tenants\x12x\n" +
+	"\tGetTenant\x12\x1b.tenant.v1.GetTenantRequest\x1a\x1c.tenant.v1.GetTenantResponse\"0\x8a\xb5\x18\x11\x18\x01\"\vtenant:read(\x01\x82\xd3\xe4\x93\x02\x15\x12\x13/v1/tenants/current\x12\x8a\x01\n" +
+	"\x0eListTenantTree\x12 .tenant.v1.ListTenantTreeRequest\x1a!.tenant.v1.ListTenantTreeResponse\"3\x8a\xb5\x18\x0f\x18\x01\"\vtenant:read\x82\xd3\xe4\x93\x02\x1a\x12\x18/v1/tenants/current/tree\x12\x83\x01\n" +
+	"\fUpdateTenant\x12\x1e.tenant.v1.UpdateTenantRequest\x1a\x1f.tenant.v1.UpdateTenantResponse\"2\x8a\xb5\x18\x10\x18\x03\"\ftenant:write\x82\xd3\xe4\x93\x02\x18:\x01*2\x13/v1/tenants/current\x12\x80\x01\n" +
+	"\rSuspendTenant\x12\x1f.tenant.v1.SuspendTenantRequest\x1a .tenant.v1.SuspendTenantResponse\",\x8a\xb5\x18\x02\x18\x04\x82\xd3\xe4\x93\x02 :\x01*\"\x1b/v1/tenants/current/suspend\x12\x8e\x01\n" +
+	"\x10ReactivateTenant\x12\".tenant.v1.ReactivateTenantRequest\x1a#.tenant.v1.ReactivateTenantResponse\"1\x8a\xb5\x18\x04\x18\x04(\x01\x82\xd3\xe4\x93\x02#:\x01*\"\x1e/v1/tenants/current/reactivate\x12\x82\x01\n" +
+	"\rArchiveTenant\x12\x1f.tenant.v1.ArchiveTenantRequest\x1a .tenant.v1.ArchiveTenantResponse\".\x8a\xb5\x18\x04\x18\x04(\x01\x82\xd3\xe4\x93\x02 :\x01*\"\x1b/v1/tenants/current/archive\x12t\n" +
+	"\fDeleteTenant\x12\x1e.tenant.v1.DeleteTenantRequest\x1a\x1f.tenant.v1.DeleteTenantResponse\"#\x8a\xb5\x18\x04\x18\x04(\x01\x82\xd3\xe4\x93\x02\x15*\x13/v1/tenants/current\x12\x90\x01\n" +
+	"\fInviteMember\x12\x1e.tenant.v1.InviteMemberRequest\x1a\x1f.tenant.v1.InviteMemberResponse\"?\x8a\xb5\x18\x11\x18\x03\"\rmembers:write\x82\xd3\xe4\x93\x02$:\x01*\"\x1f/v1/tenants/members/invitations\x12\x8c\x01\n" +
+	"\x10AcceptInvitation\x12\".tenant.v1.AcceptInvitationRequest\x1a#.tenant.v1.AcceptInvitationResponse\"/\x8a\xb5\x18\x02\x10\x01\x82\xd3\xe4\x93\x02#:\x01*\"\x1e/v1/tenants/invitations/accept\x12}\n" +
+	"\vListMembers\x12\x1d.tenant.v1.ListMembersRequest\x1a\x1e.tenant.v1.ListMembersResponse\"/\x8a\xb5\x18\x10\x18\x01\"\fmembers:read\x82\xd3\xe4\x93\x02\x15\x12\x13/v1/tenants/members\x12\x9a\x01\n" +
+	"\x10UpdateMemberRole\x12\".tenant.v1.UpdateMemberRoleRequest\x1a#.tenant.v1.UpdateMemberRoleResponse\"=\x8a\xb5\x18\x11\x18\x03\"\rmembers:write\x82\xd3\xe4\x93\x02\":\x01*2\x1d/v1/tenants/members/{user_id}\x12\x8b\x01\n" +
+	"\fRemoveMember\x12\x1e.tenant.v1.RemoveMemberRequest\x1a\x1f.tenant.v1.RemoveMemberResponse\":\x8a\xb5\x18\x11\x18\x03\"\rmembers:write\x82\xd3\xe4\x93\x02\x1f*\x1d/v1/tenants/members/{user_id}\x12p\n" +
+	"\vLeaveTenant\x12\x1d.tenant.v1.LeaveTenantRequest\x1a\x1e.tenant.v1.LeaveTenantResponse\"\"\x8a\xb5\x18\x02\x18\x01\x82\xd3\xe4\x93\x02\x16:\x01*\"\x11/v1/tenants/leaveB7Z5github.com/vantutran2k1/rwe/gen/go/tenant/v1;tenantv1b\x06proto3"
 
 var file_tenant_v1_services_proto_goTypes = []any{
-	(*CreateTenantRequest)(nil),  // 0: tenant.v1.CreateTenantRequest
-	(*CreateTenantResponse)(nil), // 1: tenant.v1.CreateTenantResponse
+	(*CreateTenantRequest)(nil),      // 0: tenant.v1.CreateTenantRequest
+	(*ListMyTenantsRequest)(nil),     // 1: tenant.v1.ListMyTenantsRequest
+	(*GetTenantRequest)(nil),         // 2: tenant.v1.GetTenantRequest
+	(*ListTenantTreeRequest)(nil),    // 3: tenant.v1.ListTenantTreeRequest
+	(*UpdateTenantRequest)(nil),      // 4: tenant.v1.UpdateTenantRequest
+	(*SuspendTenantRequest)(nil),     // 5: tenant.v1.SuspendTenantRequest
+	(*ReactivateTenantRequest)(nil),  // 6: tenant.v1.ReactivateTenantRequest
+	(*ArchiveTenantRequest)(nil),     // 7: tenant.v1.ArchiveTenantRequest
+	(*DeleteTenantRequest)(nil),      // 8: tenant.v1.DeleteTenantRequest
+	(*InviteMemberRequest)(nil),      // 9: tenant.v1.InviteMemberRequest
+	(*AcceptInvitationRequest)(nil),  // 10: tenant.v1.AcceptInvitationRequest
+	(*ListMembersRequest)(nil),       // 11: tenant.v1.ListMembersRequest
+	(*UpdateMemberRoleRequest)(nil),  // 12: tenant.v1.UpdateMemberRoleRequest
+	(*RemoveMemberRequest)(nil),      // 13: tenant.v1.RemoveMemberRequest
+	(*LeaveTenantRequest)(nil),       // 14: tenant.v1.LeaveTenantRequest
+	(*CreateTenantResponse)(nil),     // 15: tenant.v1.CreateTenantResponse
+	(*ListMyTenantsResponse)(nil),    // 16: tenant.v1.ListMyTenantsResponse
+	(*GetTenantResponse)(nil),        // 17: tenant.v1.GetTenantResponse
+	(*ListTenantTreeResponse)(nil),   // 18: tenant.v1.ListTenantTreeResponse
+	(*UpdateTenantResponse)(nil),     // 19: tenant.v1.UpdateTenantResponse
+	(*SuspendTenantResponse)(nil),    // 20: tenant.v1.SuspendTenantResponse
+	(*ReactivateTenantResponse)(nil), // 21: tenant.v1.ReactivateTenantResponse
+	(*ArchiveTenantResponse)(nil),    // 22: tenant.v1.ArchiveTenantResponse
+	(*DeleteTenantResponse)(nil),     // 23: tenant.v1.DeleteTenantResponse
+	(*InviteMemberResponse)(nil),     // 24: tenant.v1.InviteMemberResponse
+	(*AcceptInvitationResponse)(nil), // 25: tenant.v1.AcceptInvitationResponse
+	(*ListMembersResponse)(nil),      // 26: tenant.v1.ListMembersResponse
+	(*UpdateMemberRoleResponse)(nil), // 27: tenant.v1.UpdateMemberRoleResponse
+	(*RemoveMemberResponse)(nil),     // 28: tenant.v1.RemoveMemberResponse
+	(*LeaveTenantResponse)(nil),      // 29: tenant.v1.LeaveTenantResponse
 }
 var file_tenant_v1_services_proto_depIdxs = []int32{
-	0, // 0: tenant.v1.TenantService.CreateTenant:input_type -> tenant.v1.CreateTenantRequest
-	1, // 1: tenant.v1.TenantService.CreateTenant:output_type -> tenant.v1.CreateTenantResponse
-	1, // [1:2] is the sub-list for method output_type
-	0, // [0:1] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	0,  // 0: tenant.v1.TenantService.CreateTenant:input_type -> tenant.v1.CreateTenantRequest
+	1,  // 1: tenant.v1.TenantService.ListMyTenants:input_type -> tenant.v1.ListMyTenantsRequest
+	2,  // 2: tenant.v1.TenantService.GetTenant:input_type -> tenant.v1.GetTenantRequest
+	3,  // 3: tenant.v1.TenantService.ListTenantTree:input_type -> tenant.v1.ListTenantTreeRequest
+	4,  // 4: tenant.v1.TenantService.UpdateTenant:input_type -> tenant.v1.UpdateTenantRequest
+	5,  // 5: tenant.v1.TenantService.SuspendTenant:input_type -> tenant.v1.SuspendTenantRequest
+	6,  // 6: tenant.v1.TenantService.ReactivateTenant:input_type -> tenant.v1.ReactivateTenantRequest
+	7,  // 7: tenant.v1.TenantService.ArchiveTenant:input_type -> tenant.v1.ArchiveTenantRequest
+	8,  // 8: tenant.v1.TenantService.DeleteTenant:input_type -> tenant.v1.DeleteTenantRequest
+	9,  // 9: tenant.v1.TenantService.InviteMember:input_type -> tenant.v1.InviteMemberRequest
+	10, // 10: tenant.v1.TenantService.AcceptInvitation:input_type -> tenant.v1.AcceptInvitationRequest
+	11, // 11: tenant.v1.TenantService.ListMembers:input_type -> tenant.v1.ListMembersRequest
+	12, // 12: tenant.v1.TenantService.UpdateMemberRole:input_type -> tenant.v1.UpdateMemberRoleRequest
+	13, // 13: tenant.v1.TenantService.RemoveMember:input_type -> tenant.v1.RemoveMemberRequest
+	14, // 14: tenant.v1.TenantService.LeaveTenant:input_type -> tenant.v1.LeaveTenantRequest
+	15, // 15: tenant.v1.TenantService.CreateTenant:output_type -> tenant.v1.CreateTenantResponse
+	16, // 16: tenant.v1.TenantService.ListMyTenants:output_type -> tenant.v1.ListMyTenantsResponse
+	17, // 17: tenant.v1.TenantService.GetTenant:output_type -> tenant.v1.GetTenantResponse
+	18, // 18: tenant.v1.TenantService.ListTenantTree:output_type -> tenant.v1.ListTenantTreeResponse
+	19, // 19: tenant.v1.TenantService.UpdateTenant:output_type -> tenant.v1.UpdateTenantResponse
+	20, // 20: tenant.v1.TenantService.SuspendTenant:output_type -> tenant.v1.SuspendTenantResponse
+	21, // 21: tenant.v1.TenantService.ReactivateTenant:output_type -> tenant.v1.ReactivateTenantResponse
+	22, // 22: tenant.v1.TenantService.ArchiveTenant:output_type -> tenant.v1.ArchiveTenantResponse
+	23, // 23: tenant.v1.TenantService.DeleteTenant:output_type -> tenant.v1.DeleteTenantResponse
+	24, // 24: tenant.v1.TenantService.InviteMember:output_type -> tenant.v1.InviteMemberResponse
+	25, // 25: tenant.v1.TenantService.AcceptInvitation:output_type -> tenant.v1.AcceptInvitationResponse
+	26, // 26: tenant.v1.TenantService.ListMembers:output_type -> tenant.v1.ListMembersResponse
+	27, // 27: tenant.v1.TenantService.UpdateMemberRole:output_type -> tenant.v1.UpdateMemberRoleResponse
+	28, // 28: tenant.v1.TenantService.RemoveMember:output_type -> tenant.v1.RemoveMemberResponse
+	29, // 29: tenant.v1.TenantService.LeaveTenant:output_type -> tenant.v1.LeaveTenantResponse
+	15, // [15:30] is the sub-list for method output_type
+	0,  // [0:15] is the sub-list for method input_type
+	0,  // [0:0] is the sub-list for extension type_name
+	0,  // [0:0] is the sub-list for extension extendee
+	0,  // [0:0] is the sub-list for field type_name
 }
 
 func init() { file_tenant_v1_services_proto_init() }
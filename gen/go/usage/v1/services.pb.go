@@ -0,0 +1,70 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: usage/v1/services.proto
+
+package usagev1
+
+import (
+	_ "github.com/vantutran2k1/rwe/gen/go/auth/v1"
+	_ "google.golang.org/genproto/googleapis/api/annotations"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+var File_usage_v1_services_proto protoreflect.FileDescriptor
+
+const file_usage_v1_services_proto_rawDesc = "" +
+	"\n" +
+	"\x17usage/v1/services.proto\x12\busage.v1\x1a\x14usage/v1/types.proto\x1a\x14auth/v1/policy.proto\x1a\x1cgoogle/api/annotations.proto2v\n" +
+	"\fUsageService\x12f\n" +
+	"\bGetUsage\x12\x19.usage.v1.GetUsageRequest\x1a\x1a.usage.v1.GetUsageResponse\"#\x8a\xb5\x18\x0e\x18\x03\"\n" +
+	"usage:read\x82\xd3\xe4\x93\x02\v\x12\t/v1/usageB5Z3github.com/vantutran2k1/rwe/gen/go/usage/v1;usagev1b\x06proto3"
+
+var file_usage_v1_services_proto_goTypes = []any{
+	(*GetUsageRequest)(nil),  // 0: usage.v1.GetUsageRequest
+	(*GetUsageResponse)(nil), // 1: usage.v1.GetUsageResponse
+}
+var file_usage_v1_services_proto_depIdxs = []int32{
+	0, // 0: usage.v1.UsageService.GetUsage:input_type -> usage.v1.GetUsageRequest
+	1, // 1: usage.v1.UsageService.GetUsage:output_type -> usage.v1.GetUsageResponse
+	1, // [1:2] is the sub-list for method output_type
+	0, // [0:1] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_usage_v1_services_proto_init() }
+func file_usage_v1_services_proto_init() {
+	if File_usage_v1_services_proto != nil {
+		return
+	}
+	file_usage_v1_types_proto_init()
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_usage_v1_services_proto_rawDesc), len(file_usage_v1_services_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   0,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_usage_v1_services_proto_goTypes,
+		DependencyIndexes: file_usage_v1_services_proto_depIdxs,
+	}.Build()
+	File_usage_v1_services_proto = out.File
+	file_usage_v1_services_proto_goTypes = nil
+	file_usage_v1_services_proto_depIdxs = nil
+}
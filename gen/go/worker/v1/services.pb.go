@@ -0,0 +1,89 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: worker/v1/services.proto
+
+package workerv1
+
+import (
+	_ "github.com/vantutran2k1/rwe/gen/go/auth/v1"
+	_ "google.golang.org/genproto/googleapis/api/annotations"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+var File_worker_v1_services_proto protoreflect.FileDescriptor
+
+const file_worker_v1_services_proto_rawDesc = "" +
+	"\n" +
+	"\x18worker/v1/services.proto\x12\tworker.v1\x1a\x15worker/v1/types.proto\x1a\x14auth/v1/policy.proto\x1a\x1cgoogle/api/annotations.proto2\xdf\x05\n" +
+	"\rWorkerService\x12\x82\x01\n" +
+	"\x0eRegisterWorker\x12 .worker.v1.RegisterWorkerRequest\x1a!.worker.v1.RegisterWorkerResponse\"+\x8a\xb5\x18\x11\x18\x02\"\rworkers:write\x82\xd3\xe4\x93\x02\x10:\x01*\"\v/v1/workers\x12\x89\x01\n" +
+	"\tHeartbeat\x12\x1b.worker.v1.HeartbeatRequest\x1a\x1c.worker.v1.HeartbeatResponse\"A\x8a\xb5\x18\x11\x18\x02\"\rworkers:write\x82\xd3\xe4\x93\x02&:\x01*\"!/v1/workers/{worker_id}/heartbeat\x12\x88\x01\n" +
+	"\tPollTasks\x12\x1b.worker.v1.PollTasksRequest\x1a\x1c.worker.v1.PollTasksResponse\"@\x8a\xb5\x18\x0f\x18\x02\"\vtasks:write\x82\xd3\xe4\x93\x02':\x01*\"\"/v1/workers/{worker_id}/tasks:poll\x12\x9f\x01\n" +
+	"\fCompleteTask\x12\x1e.worker.v1.CompleteTaskRequest\x1a\x1f.worker.v1.CompleteTaskResponse\"N\x8a\xb5\x18\x0f\x18\x02\"\vtasks:write\x82\xd3\xe4\x93\x025:\x01*\"0/v1/workers/{worker_id}/tasks/{task_id}:complete\x12\x8f\x01\n" +
+	"\bFailTask\x12\x1a.worker.v1.FailTaskRequest\x1a\x1b.worker.v1.FailTaskResponse\"J\x8a\xb5\x18\x0f\x18\x02\"\vtasks:write\x82\xd3\xe4\x93\x021:\x01*\",/v1/workers/{worker_id}/tasks/{task_id}:failB7Z5github.com/vantutran2k1/rwe/gen/go/worker/v1;workerv1b\x06proto3"
+
+var file_worker_v1_services_proto_goTypes = []any{
+	(*RegisterWorkerRequest)(nil),  // 0: worker.v1.RegisterWorkerRequest
+	(*HeartbeatRequest)(nil),       // 1: worker.v1.HeartbeatRequest
+	(*PollTasksRequest)(nil),       // 2: worker.v1.PollTasksRequest
+	(*CompleteTaskRequest)(nil),    // 3: worker.v1.CompleteTaskRequest
+	(*FailTaskRequest)(nil),        // 4: worker.v1.FailTaskRequest
+	(*RegisterWorkerResponse)(nil), // 5: worker.v1.RegisterWorkerResponse
+	(*HeartbeatResponse)(nil),      // 6: worker.v1.HeartbeatResponse
+	(*PollTasksResponse)(nil),      // 7: worker.v1.PollTasksResponse
+	(*CompleteTaskResponse)(nil),   // 8: worker.v1.CompleteTaskResponse
+	(*FailTaskResponse)(nil),       // 9: worker.v1.FailTaskResponse
+}
+var file_worker_v1_services_proto_depIdxs = []int32{
+	0, // 0: worker.v1.WorkerService.RegisterWorker:input_type -> worker.v1.RegisterWorkerRequest
+	1, // 1: worker.v1.WorkerService.Heartbeat:input_type -> worker.v1.HeartbeatRequest
+	2, // 2: worker.v1.WorkerService.PollTasks:input_type -> worker.v1.PollTasksRequest
+	3, // 3: worker.v1.WorkerService.CompleteTask:input_type -> worker.v1.CompleteTaskRequest
+	4, // 4: worker.v1.WorkerService.FailTask:input_type -> worker.v1.FailTaskRequest
+	5, // 5: worker.v1.WorkerService.RegisterWorker:output_type -> worker.v1.RegisterWorkerResponse
+	6, // 6: worker.v1.WorkerService.Heartbeat:output_type -> worker.v1.HeartbeatResponse
+	7, // 7: worker.v1.WorkerService.PollTasks:output_type -> worker.v1.PollTasksResponse
+	8, // 8: worker.v1.WorkerService.CompleteTask:output_type -> worker.v1.CompleteTaskResponse
+	9, // 9: worker.v1.WorkerService.FailTask:output_type -> worker.v1.FailTaskResponse
+	5, // [5:10] is the sub-list for method output_type
+	0, // [0:5] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_worker_v1_services_proto_init() }
+func file_worker_v1_services_proto_init() {
+	if File_worker_v1_services_proto != nil {
+		return
+	}
+	file_worker_v1_types_proto_init()
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_worker_v1_services_proto_rawDesc), len(file_worker_v1_services_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   0,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_worker_v1_services_proto_goTypes,
+		DependencyIndexes: file_worker_v1_services_proto_depIdxs,
+	}.Build()
+	File_worker_v1_services_proto = out.File
+	file_worker_v1_services_proto_goTypes = nil
+	file_worker_v1_services_proto_depIdxs = nil
+}
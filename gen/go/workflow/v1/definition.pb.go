@@ -0,0 +1,350 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: workflow/v1/definition.proto
+
+package workflowv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
+	structpb "google.golang.org/protobuf/types/known/structpb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// WorkflowDefinition is the typed schema of the definition stored with a
+// workflow. It is accepted as a JSON object on the wire.
+type WorkflowDefinition struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Steps         []*Step                `protobuf:"bytes,1,rep,name=steps,proto3" json:"steps,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WorkflowDefinition) Reset() {
+	*x = WorkflowDefinition{}
+	mi := &file_workflow_v1_definition_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WorkflowDefinition) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WorkflowDefinition) ProtoMessage() {}
+
+func (x *WorkflowDefinition) ProtoReflect() protoreflect.Message {
+	mi := &file_workflow_v1_definition_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WorkflowDefinition.ProtoReflect.Descriptor instead.
+func (*WorkflowDefinition) Descriptor() ([]byte, []int) {
+	return file_workflow_v1_definition_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *WorkflowDefinition) GetSteps() []*Step {
+	if x != nil {
+		return x.Steps
+	}
+	return nil
+}
+
+type Step struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Unique identifier of the step within the definition.
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// One of "noop", "echo" (executed by the engine) or "task" (executed by workers).
+	Type string `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
+	// Queue polled by workers. Required for "task" steps only.
+	Queue string `protobuf:"bytes,3,opt,name=queue,proto3" json:"queue,omitempty"`
+	// IDs of the steps that must succeed before this step is queued.
+	DependsOn []string        `protobuf:"bytes,4,rep,name=depends_on,json=dependsOn,proto3" json:"depends_on,omitempty"`
+	Input     *structpb.Value `protobuf:"bytes,5,opt,name=input,proto3" json:"input,omitempty"`
+	// Names of the keys this step is expected to produce in its result.
+	Outputs []string     `protobuf:"bytes,6,rep,name=outputs,proto3" json:"outputs,omitempty"`
+	Retry   *RetryPolicy `protobuf:"bytes,7,opt,name=retry,proto3" json:"retry,omitempty"`
+	// Upper bound for a single attempt of the step.
+	Timeout       *durationpb.Duration `protobuf:"bytes,8,opt,name=timeout,proto3" json:"timeout,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Step) Reset() {
+	*x = Step{}
+	mi := &file_workflow_v1_definition_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Step) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Step) ProtoMessage() {}
+
+func (x *Step) ProtoReflect() protoreflect.Message {
+	mi := &file_workflow_v1_definition_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Step.ProtoReflect.Descriptor instead.
+func (*Step) Descriptor() ([]byte, []int) {
+	return file_workflow_v1_definition_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Step) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Step) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *Step) GetQueue() string {
+	if x != nil {
+		return x.Queue
+	}
+	return ""
+}
+
+func (x *Step) GetDependsOn() []string {
+	if x != nil {
+		return x.DependsOn
+	}
+	return nil
+}
+
+func (x *Step) GetInput() *structpb.Value {
+	if x != nil {
+		return x.Input
+	}
+	return nil
+}
+
+func (x *Step) GetOutputs() []string {
+	if x != nil {
+		return x.Outputs
+	}
+	return nil
+}
+
+func (x *Step) GetRetry() *RetryPolicy {
+	if x != nil {
+		return x.Retry
+	}
+	return nil
+}
+
+func (x *Step) GetTimeout() *durationpb.Duration {
+	if x != nil {
+		return x.Timeout
+	}
+	return nil
+}
+
+type RetryPolicy struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Total number of attempts, including the first one. Defaults to 1.
+	MaxAttempts int32 `protobuf:"varint,1,opt,name=max_attempts,json=maxAttempts,proto3" json:"max_attempts,omitempty"`
+	// Delay before the first retry. Defaults to 1s.
+	InitialBackoff *durationpb.Duration `protobuf:"bytes,2,opt,name=initial_backoff,json=initialBackoff,proto3" json:"initial_backoff,omitempty"`
+	// Upper bound for the delay between attempts. Defaults to 5m.
+	MaxBackoff *durationpb.Duration `protobuf:"bytes,3,opt,name=max_backoff,json=maxBackoff,proto3" json:"max_backoff,omitempty"`
+	// Factor applied to the delay after every attempt. Defaults to 2.
+	BackoffMultiplier float64 `protobuf:"fixed64,4,opt,name=backoff_multiplier,json=backoffMultiplier,proto3" json:"backoff_multiplier,omitempty"`
+	// Fraction in [0, 1] by which each delay is randomly shortened or extended.
+	Jitter float64 `protobuf:"fixed64,5,opt,name=jitter,proto3" json:"jitter,omitempty"`
+	// Error codes reported by a failed attempt that must not be retried.
+	NonRetryableErrorCodes []string `protobuf:"bytes,6,rep,name=non_retryable_error_codes,json=nonRetryableErrorCodes,proto3" json:"non_retryable_error_codes,omitempty"`
+	unknownFields          protoimpl.UnknownFields
+	sizeCache              protoimpl.SizeCache
+}
+
+func (x *RetryPolicy) Reset() {
+	*x = RetryPolicy{}
+	mi := &file_workflow_v1_definition_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RetryPolicy) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RetryPolicy) ProtoMessage() {}
+
+func (x *RetryPolicy) ProtoReflect() protoreflect.Message {
+	mi := &file_workflow_v1_definition_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RetryPolicy.ProtoReflect.Descriptor instead.
+func (*RetryPolicy) Descriptor() ([]byte, []int) {
+	return file_workflow_v1_definition_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *RetryPolicy) GetMaxAttempts() int32 {
+	if x != nil {
+		return x.MaxAttempts
+	}
+	return 0
+}
+
+func (x *RetryPolicy) GetInitialBackoff() *durationpb.Duration {
+	if x != nil {
+		return x.InitialBackoff
+	}
+	return nil
+}
+
+func (x *RetryPolicy) GetMaxBackoff() *durationpb.Duration {
+	if x != nil {
+		return x.MaxBackoff
+	}
+	return nil
+}
+
+func (x *RetryPolicy) GetBackoffMultiplier() float64 {
+	if x != nil {
+		return x.BackoffMultiplier
+	}
+	return 0
+}
+
+func (x *RetryPolicy) GetJitter() float64 {
+	if x != nil {
+		return x.Jitter
+	}
+	return 0
+}
+
+func (x *RetryPolicy) GetNonRetryableErrorCodes() []string {
+	if x != nil {
+		return x.NonRetryableErrorCodes
+	}
+	return nil
+}
+
+var File_workflow_v1_definition_proto protoreflect.FileDescriptor
+
+const file_workflow_v1_definition_proto_rawDesc = "" +
+	"\n" +
+	"\x1cworkflow/v1/definition.proto\x12\vworkflow.v1\x1a\x1egoogle/protobuf/duration.proto\x1a\x1cgoogle/protobuf/struct.proto\"=\n" +
+	"\x12WorkflowDefinition\x12'\n" +
+	"\x05steps\x18\x01 \x03(\v2\x11.workflow.v1.StepR\x05steps\"\x8c\x02\n" +
+	"\x04Step\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04type\x18\x02 \x01(\tR\x04type\x12\x14\n" +
+	"\x05queue\x18\x03 \x01(\tR\x05queue\x12\x1d\n" +
+	"\n" +
+	"depends_on\x18\x04 \x03(\tR\tdependsOn\x12,\n" +
+	"\x05input\x18\x05 \x01(\v2\x16.google.protobuf.ValueR\x05input\x12\x18\n" +
+	"\aoutputs\x18\x06 \x03(\tR\aoutputs\x12.\n" +
+	"\x05retry\x18\a \x01(\v2\x18.workflow.v1.RetryPolicyR\x05retry\x123\n" +
+	"\atimeout\x18\b \x01(\v2\x19.google.protobuf.DurationR\atimeout\"\xb2\x02\n" +
+	"\vRetryPolicy\x12!\n" +
+	"\fmax_attempts\x18\x01 \x01(\x05R\vmaxAttempts\x12B\n" +
+	"\x0finitial_backoff\x18\x02 \x01(\v2\x19.google.protobuf.DurationR\x0einitialBackoff\x12:\n" +
+	"\vmax_backoff\x18\x03 \x01(\v2\x19.google.protobuf.DurationR\n" +
+	"maxBackoff\x12-\n" +
+	"\x12backoff_multiplier\x18\x04 \x01(\x01R\x11backoffMultiplier\x12\x16\n" +
+	"\x06jitter\x18\x05 \x01(\x01R\x06jitter\x129\n" +
+	"\x19non_retryable_error_codes\x18\x06 \x03(\tR\x16nonRetryableErrorCodesB;Z9github.com/vantutran2k1/rwe/gen/go/workflow/v1;workflowv1b\x06proto3"
+
+var (
+	file_workflow_v1_definition_proto_rawDescOnce sync.Once
+	file_workflow_v1_definition_proto_rawDescData []byte
+)
+
+func file_workflow_v1_definition_proto_rawDescGZIP() []byte {
+	file_workflow_v1_definition_proto_rawDescOnce.Do(func() {
+		file_workflow_v1_definition_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_workflow_v1_definition_proto_rawDesc), len(file_workflow_v1_definition_proto_rawDesc)))
+	})
+	return file_workflow_v1_definition_proto_rawDescData
+}
+
+var file_workflow_v1_definition_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_workflow_v1_definition_proto_goTypes = []any{
+	(*WorkflowDefinition)(nil),  // 0: workflow.v1.WorkflowDefinition
+	(*Step)(nil),                // 1: workflow.v1.Step
+	(*RetryPolicy)(nil),         // 2: workflow.v1.RetryPolicy
+	(*structpb.Value)(nil),      // 3: google.protobuf.Value
+	(*durationpb.Duration)(nil), // 4: google.protobuf.Duration
+}
+var file_workflow_v1_definition_proto_depIdxs = []int32{
+	1, // 0: workflow.v1.WorkflowDefinition.steps:type_name -> workflow.v1.Step
+	3, // 1: workflow.v1.Step.input:type_name -> google.protobuf.Value
+	2, // 2: workflow.v1.Step.retry:type_name -> workflow.v1.RetryPolicy
+	4, // 3: workflow.v1.Step.timeout:type_name -> google.protobuf.Duration
+	4, // 4: workflow.v1.RetryPolicy.initial_backoff:type_name -> google.protobuf.Duration
+	4, // 5: workflow.v1.RetryPolicy.max_backoff:type_name -> google.protobuf.Duration
+	6, // [6:6] is the sub-list for method output_type
+	6, // [6:6] is the sub-list for method input_type
+	6, // [6:6] is the sub-list for extension type_name
+	6, // [6:6] is the sub-list for extension extendee
+	0, // [0:6] is the sub-list for field type_name
+}
+
+func init() { file_workflow_v1_definition_proto_init() }
+func file_workflow_v1_definition_proto_init() {
+	if File_workflow_v1_definition_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_workflow_v1_definition_proto_rawDesc), len(file_workflow_v1_definition_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   3,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_workflow_v1_definition_proto_goTypes,
+		DependencyIndexes: file_workflow_v1_definition_proto_depIdxs,
+		MessageInfos:      file_workflow_v1_definition_proto_msgTypes,
+	}.Build()
+	File_workflow_v1_definition_proto = out.File
+	file_workflow_v1_definition_proto_goTypes = nil
+	file_workflow_v1_definition_proto_depIdxs = nil
+}
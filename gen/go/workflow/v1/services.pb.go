@@ -7,6 +7,7 @@
 package workflowv1
 
 import (
+	_ "github.com/vantutran2k1/rwe/gen/go/auth/v1"
 	_ "google.golang.org/genproto/googleapis/api/annotations"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
@@ -25,32 +26,126 @@ var File_workflow_v1_services_proto protoreflect.FileDescriptor
 
 const file_workflow_v1_services_proto_rawDesc = "" +
 	"\n" +
-	"\x1aworkflow/v1/services.proto\x12\vworkflow.v1\x1a\x17workflow/v1/types.proto\x1a\x1cgoogle/api/annotations.proto2\xe0\x02\n" +
-	"\x0fWorkflowService\x12s\n" +
-	"\x0eCreateWorkflow\x12\".workflow.v1.CreateWorkflowRequest\x1a#.workflow.v1.CreateWorkflowResponse\"\x18\x82\xd3\xe4\x93\x02\x12:\x01*\"\r/v1/workflows\x12l\n" +
-	"\vGetWorkflow\x12\x1f.workflow.v1.GetWorkflowRequest\x1a .workflow.v1.GetWorkflowResponse\"\x1a\x82\xd3\xe4\x93\x02\x14\x12\x12/v1/workflows/{id}\x12j\n" +
-	"\fGetWorkflows\x12 .workflow.v1.GetWorkflowsRequest\x1a!.workflow.v1.GetWorkflowsResponse\"\x15\x82\xd3\xe4\x93\x02\x0f\x12\r/v1/workflowsB;Z9github.com/vantutran2k1/rwe/gen/go/workflow/v1;workflowv1b\x06proto3"
+	"\x1aworkflow/v1/services.proto\x12\vworkflow.v1\x1a\x17workflow/v1/types.proto\x1a\x14auth/v1/policy.proto\x1a\x1cgoogle/api/annotations.proto2\xfb\f\n" +
+	"\x0fWorkflowService\x12\x8a\x01\n" +
+	"\x0eCreateWorkflow\x12\".workflow.v1.CreateWorkflowRequest\x1a#.workflow.v1.CreateWorkflowResponse\"/\x8a\xb5\x18\x13\x18\x02\"\x0fworkflows:write\x82\xd3\xe4\x93\x02\x12:\x01*\"\r/v1/workflows\x12\x82\x01\n" +
+	"\vGetWorkflow\x12\x1f.workflow.v1.GetWorkflowRequest\x1a .workflow.v1.GetWorkflowResponse\"0\x8a\xb5\x18\x12\x18\x01\"\x0eworkflows:read\x82\xd3\xe4\x93\x02\x14\x12\x12/v1/workflows/{id}\x12\x80\x01\n" +
+	"\fGetWorkflows\x12 .workflow.v1.GetWorkflowsRequest\x1a!.workflow.v1.GetWorkflowsResponse\"+\x8a\xb5\x18\x12\x18\x01\"\x0eworkflows:read\x82\xd3\xe4\x93\x02\x0f\x12\r/v1/workflows\x12\x9e\x01\n" +
+	"\x10StartWorkflowRun\x12$.workflow.v1.StartWorkflowRunRequest\x1a%.workflow.v1.StartWorkflowRunResponse\"=\x8a\xb5\x18\x0e\x18\x02\"\n" +
+	"runs:write\x82\xd3\xe4\x93\x02%:\x01*\" /v1/workflows/{workflow_id}/runs\x12\x81\x01\n" +
+	"\x0eGetWorkflowRun\x12\".workflow.v1.GetWorkflowRunRequest\x1a#.workflow.v1.GetWorkflowRunResponse\"&\x8a\xb5\x18\r\x18\x01\"\truns:read\x82\xd3\xe4\x93\x02\x0f\x12\r/v1/runs/{id}\x12\x8f\x01\n" +
+	"\x0eUpdateWorkflow\x12\".workflow.v1.UpdateWorkflowRequest\x1a#.workflow.v1.UpdateWorkflowResponse\"4\x8a\xb5\x18\x13\x18\x02\"\x0fworkflows:write\x82\xd3\xe4\x93\x02\x17:\x01*\x1a\x12/v1/workflows/{id}\x12\xa6\x01\n" +
+	"\x14ListWorkflowVersions\x12(.workflow.v1.ListWorkflowVersionsRequest\x1a).workflow.v1.ListWorkflowVersionsResponse\"9\x8a\xb5\x18\x12\x18\x01\"\x0eworkflows:read\x82\xd3\xe4\x93\x02\x1d\x12\x1b/v1/workflows/{id}/versions\x12\xaa\x01\n" +
+	"\x12GetWorkflowVersion\x12&.workflow.v1.GetWorkflowVersionRequest\x1a'.workflow.v1.GetWorkflowVersionResponse\"C\x8a\xb5\x18\x12\x18\x01\"\x0eworkflows:read\x82\xd3\xe4\x93\x02'\x12%/v1/workflows/{id}/versions/{version}\x12\xa2\x01\n" +
+	"\x14DiffWorkflowVersions\x12(.workflow.v1.DiffWorkflowVersionsRequest\x1a).workflow.v1.DiffWorkflowVersionsResponse\"5\x8a\xb5\x18\x12\x18\x01\"\x0eworkflows:read\x82\xd3\xe4\x93\x02\x19\x12\x17/v1/workflows/{id}/diff\x12\x98\x01\n" +
+	"\x13ListDeadLetterTasks\x12'.workflow.v1.ListDeadLetterTasksRequest\x1a(.workflow.v1.ListDeadLetterTasksResponse\".\x8a\xb5\x18\r\x18\x01\"\truns:read\x82\xd3\xe4\x93\x02\x17\x12\x15/v1/tasks/dead-letter\x12\x85\x01\n" +
+	"\vRedriveTask\x12\x1f.workflow.v1.RedriveTaskRequest\x1a .workflow.v1.RedriveTaskResponse\"3\x8a\xb5\x18\x0e\x18\x03\"\n" +
+	"runs:write\x82\xd3\xe4\x93\x02\x1b:\x01*\"\x16/v1/tasks/{id}:redrive2\xfe\x06\n" +
+	"\x0fScheduleService\x12\xa2\x01\n" +
+	"\x0eCreateSchedule\x12\".workflow.v1.CreateScheduleRequest\x1a#.workflow.v1.CreateScheduleResponse\"G\x8a\xb5\x18\x13\x18\x02\"\x0fschedules:write\x82\xd3\xe4\x93\x02*:\x01*\"%/v1/workflows/{workflow_id}/schedules\x12\x82\x01\n" +
+	"\vGetSchedule\x12\x1f.workflow.v1.GetScheduleRequest\x1a .workflow.v1.GetScheduleResponse\"0\x8a\xb5\x18\x12\x18\x01\"\x0eschedules:read\x82\xd3\xe4\x93\x02\x14\x12\x12/v1/schedules/{id}\x12\x83\x01\n" +
+	"\rListSchedules\x12!.workflow.v1.ListSchedulesRequest\x1a\".workflow.v1.ListSchedulesResponse\"+\x8a\xb5\x18\x12\x18\x01\"\x0eschedules:read\x82\xd3\xe4\x93\x02\x0f\x12\r/v1/schedules\x12\x92\x01\n" +
+	"\rPauseSchedule\x12!.workflow.v1.PauseScheduleRequest\x1a\".workflow.v1.PauseScheduleResponse\":\x8a\xb5\x18\x13\x18\x02\"\x0fschedules:write\x82\xd3\xe4\x93\x02\x1d:\x01*\"\x18/v1/schedules/{id}:pause\x12\x96\x01\n" +
+	"\x0eResumeSchedule\x12\".workflow.v1.ResumeScheduleRequest\x1a#.workflow.v1.ResumeScheduleResponse\";\x8a\xb5\x18\x13\x18\x02\"\x0fschedules:write\x82\xd3\xe4\x93\x02\x1e:\x01*\"\x19/v1/schedules/{id}:resume\x12\x8c\x01\n" +
+	"\x0eDeleteSchedule\x12\".workflow.v1.DeleteScheduleRequest\x1a#.workflow.v1.DeleteScheduleResponse\"1\x8a\xb5\x18\x13\x18\x02\"\x0fschedules:write\x82\xd3\xe4\x93\x02\x14*\x12/v1/schedules/{id}2\x9d\x04\n" +
+	"\x0eWebhookService\x12\x9d\x01\n" +
+	"\rCreateWebhook\x12!.workflow.v1.CreateWebhookRequest\x1a\".workflow.v1.CreateWebhookResponse\"E\x8a\xb5\x18\x12\x18\x02\"\x0ewebhooks:write\x82\xd3\xe4\x93\x02):\x01*\"$/v1/workflows/{workflow_id}/webhooks\x12~\n" +
+	"\fListWebhooks\x12 .workflow.v1.ListWebhooksRequest\x1a!.workflow.v1.ListWebhooksResponse\")\x8a\xb5\x18\x11\x18\x01\"\rwebhooks:read\x82\xd3\xe4\x93\x02\x0e\x12\f/v1/webhooks\x12\x87\x01\n" +
+	"\rDeleteWebhook\x12!.workflow.v1.DeleteWebhookRequest\x1a\".workflow.v1.DeleteWebhookResponse\"/\x8a\xb5\x18\x12\x18\x02\"\x0ewebhooks:write\x82\xd3\xe4\x93\x02\x13*\x11/v1/webhooks/{id}\x12a\n" +
+	"\x0eDeliverWebhook\x12\".workflow.v1.DeliverWebhookRequest\x1a#.workflow.v1.DeliverWebhookResponse\"\x06\x8a\xb5\x18\x02\b\x01B;Z9github.com/vantutran2k1/rwe/gen/go/workflow/v1;workflowv1b\x06proto3"
 
 var file_workflow_v1_services_proto_goTypes = []any{
-	(*CreateWorkflowRequest)(nil),  // 0: workflow.v1.CreateWorkflowRequest
-	(*GetWorkflowRequest)(nil),     // 1: workflow.v1.GetWorkflowRequest
-	(*GetWorkflowsRequest)(nil),    // 2: workflow.v1.GetWorkflowsRequest
-	(*CreateWorkflowResponse)(nil), // 3: workflow.v1.CreateWorkflowResponse
-	(*GetWorkflowResponse)(nil),    // 4: workflow.v1.GetWorkflowResponse
-	(*GetWorkflowsResponse)(nil),   // 5: workflow.v1.GetWorkflowsResponse
+	(*CreateWorkflowRequest)(nil),        // 0: workflow.v1.CreateWorkflowRequest
+	(*GetWorkflowRequest)(nil),           // 1: workflow.v1.GetWorkflowRequest
+	(*GetWorkflowsRequest)(nil),          // 2: workflow.v1.GetWorkflowsRequest
+	(*StartWorkflowRunRequest)(nil),      // 3: workflow.v1.StartWorkflowRunRequest
+	(*GetWorkflowRunRequest)(nil),        // 4: workflow.v1.GetWorkflowRunRequest
+	(*UpdateWorkflowRequest)(nil),        // 5: workflow.v1.UpdateWorkflowRequest
+	(*ListWorkflowVersionsRequest)(nil),  // 6: workflow.v1.ListWorkflowVersionsRequest
+	(*GetWorkflowVersionRequest)(nil),    // 7: workflow.v1.GetWorkflowVersionRequest
+	(*DiffWorkflowVersionsRequest)(nil),  // 8: workflow.v1.DiffWorkflowVersionsRequest
+	(*ListDeadLetterTasksRequest)(nil),   // 9: workflow.v1.ListDeadLetterTasksRequest
+	(*RedriveTaskRequest)(nil),           // 10: workflow.v1.RedriveTaskRequest
+	(*CreateScheduleRequest)(nil),        // 11: workflow.v1.CreateScheduleRequest
+	(*GetScheduleRequest)(nil),           // 12: workflow.v1.GetScheduleRequest
+	(*ListSchedulesRequest)(nil),         // 13: workflow.v1.ListSchedulesRequest
+	(*PauseScheduleRequest)(nil),         // 14: workflow.v1.PauseScheduleRequest
+	(*ResumeScheduleRequest)(nil),        // 15: workflow.v1.ResumeScheduleRequest
+	(*DeleteScheduleRequest)(nil),        // 16: workflow.v1.DeleteScheduleRequest
+	(*CreateWebhookRequest)(nil),         // 17: workflow.v1.CreateWebhookRequest
+	(*ListWebhooksRequest)(nil),          // 18: workflow.v1.ListWebhooksRequest
+	(*DeleteWebhookRequest)(nil),         // 19: workflow.v1.DeleteWebhookRequest
+	(*DeliverWebhookRequest)(nil),        // 20: workflow.v1.DeliverWebhookRequest
+	(*CreateWorkflowResponse)(nil),       // 21: workflow.v1.CreateWorkflowResponse
+	(*GetWorkflowResponse)(nil),          // 22: workflow.v1.GetWorkflowResponse
+	(*GetWorkflowsResponse)(nil),         // 23: workflow.v1.GetWorkflowsResponse
+	(*StartWorkflowRunResponse)(nil),     // 24: workflow.v1.StartWorkflowRunResponse
+	(*GetWorkflowRunResponse)(nil),       // 25: workflow.v1.GetWorkflowRunResponse
+	(*UpdateWorkflowResponse)(nil),       // 26: workflow.v1.UpdateWorkflowResponse
+	(*ListWorkflowVersionsResponse)(nil), // 27: workflow.v1.ListWorkflowVersionsResponse
+	(*GetWorkflowVersionResponse)(nil),   // 28: workflow.v1.GetWorkflowVersionResponse
+	(*DiffWorkflowVersionsResponse)(nil), // 29: workflow.v1.DiffWorkflowVersionsResponse
+	(*ListDeadLetterTasksResponse)(nil),  // 30: workflow.v1.ListDeadLetterTasksResponse
+	(*RedriveTaskResponse)(nil),          // 31: workflow.v1.RedriveTaskResponse
+	(*CreateScheduleResponse)(nil),       // 32: workflow.v1.CreateScheduleResponse
+	(*GetScheduleResponse)(nil),          // 33: workflow.v1.GetScheduleResponse
+	(*ListSchedulesResponse)(nil),        // 34: workflow.v1.ListSchedulesResponse
+	(*PauseScheduleResponse)(nil),        // 35: workflow.v1.PauseScheduleResponse
+	(*ResumeScheduleResponse)(nil),       // 36: workflow.v1.ResumeScheduleResponse
+	(*DeleteScheduleResponse)(nil),       // 37: workflow.v1.DeleteScheduleResponse
+	(*CreateWebhookResponse)(nil),        // 38: workflow.v1.CreateWebhookResponse
+	(*ListWebhooksResponse)(nil),         // 39: workflow.v1.ListWebhooksResponse
+	(*DeleteWebhookResponse)(nil),        // 40: workflow.v1.DeleteWebhookResponse
+	(*DeliverWebhookResponse)(nil),       // 41: workflow.v1.DeliverWebhookResponse
 }
 var file_workflow_v1_services_proto_depIdxs = []int32{
-	0, // 0: workflow.v1.WorkflowService.CreateWorkflow:input_type -> workflow.v1.CreateWorkflowRequest
-	1, // 1: workflow.v1.WorkflowService.GetWorkflow:input_type -> workflow.v1.GetWorkflowRequest
-	2, // 2: workflow.v1.WorkflowService.GetWorkflows:input_type -> workflow.v1.GetWorkflowsRequest
-	3, // 3: workflow.v1.WorkflowService.CreateWorkflow:output_type -> workflow.v1.CreateWorkflowResponse
-	4, // 4: workflow.v1.WorkflowService.GetWorkflow:output_type -> workflow.v1.GetWorkflowResponse
-	5, // 5: workflow.v1.WorkflowService.GetWorkflows:output_type -> workflow.v1.GetWorkflowsResponse
-	3, // [3:6] is the sub-list for method output_type
-	0, // [0:3] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	0,  // 0: workflow.v1.WorkflowService.CreateWorkflow:input_type -> workflow.v1.CreateWorkflowRequest
+	1,  // 1: workflow.v1.WorkflowService.GetWorkflow:input_type -> workflow.v1.GetWorkflowRequest
+	2,  // 2: workflow.v1.WorkflowService.GetWorkflows:input_type -> workflow.v1.GetWorkflowsRequest
+	3,  // 3: workflow.v1.WorkflowService.StartWorkflowRun:input_type -> workflow.v1.StartWorkflowRunRequest
+	4,  // 4: workflow.v1.WorkflowService.GetWorkflowRun:input_type -> workflow.v1.GetWorkflowRunRequest
+	5,  // 5: workflow.v1.WorkflowService.UpdateWorkflow:input_type -> workflow.v1.UpdateWorkflowRequest
+	6,  // 6: workflow.v1.WorkflowService.ListWorkflowVersions:input_type -> workflow.v1.ListWorkflowVersionsRequest
+	7,  // 7: workflow.v1.WorkflowService.GetWorkflowVersion:input_type -> workflow.v1.GetWorkflowVersionRequest
+	8,  // 8: workflow.v1.WorkflowService.DiffWorkflowVersions:input_type -> workflow.v1.DiffWorkflowVersionsRequest
+	9,  // 9: workflow.v1.WorkflowService.ListDeadLetterTasks:input_type -> workflow.v1.ListDeadLetterTasksRequest
+	10, // 10: workflow.v1.WorkflowService.RedriveTask:input_type -> workflow.v1.RedriveTaskRequest
+	11, // 11: workflow.v1.ScheduleService.CreateSchedule:input_type -> workflow.v1.CreateScheduleRequest
+	12, // 12: workflow.v1.ScheduleService.GetSchedule:input_type -> workflow.v1.GetScheduleRequest
+	13, // 13: workflow.v1.ScheduleService.ListSchedules:input_type -> workflow.v1.ListSchedulesRequest
+	14, // 14: workflow.v1.ScheduleService.PauseSchedule:input_type -> workflow.v1.PauseScheduleRequest
+	15, // 15: workflow.v1.ScheduleService.ResumeSchedule:input_type -> workflow.v1.ResumeScheduleRequest
+	16, // 16: workflow.v1.ScheduleService.DeleteSchedule:input_type -> workflow.v1.DeleteScheduleRequest
+	17, // 17: workflow.v1.WebhookService.CreateWebhook:input_type -> workflow.v1.CreateWebhookRequest
+	18, // 18: workflow.v1.WebhookService.ListWebhooks:input_type -> workflow.v1.ListWebhooksRequest
+	19, // 19: workflow.v1.WebhookService.DeleteWebhook:input_type -> workflow.v1.DeleteWebhookRequest
+	20, // 20: workflow.v1.WebhookService.DeliverWebhook:input_type -> workflow.v1.DeliverWebhookRequest
+	21, // 21: workflow.v1.WorkflowService.CreateWorkflow:output_type -> workflow.v1.CreateWorkflowResponse
+	22, // 22: workflow.v1.WorkflowService.GetWorkflow:output_type -> workflow.v1.GetWorkflowResponse
+	23, // 23: workflow.v1.WorkflowService.GetWorkflows:output_type -> workflow.v1.GetWorkflowsResponse
+	24, // 24: workflow.v1.WorkflowService.StartWorkflowRun:output_type -> workflow.v1.StartWorkflowRunResponse
+	25, // 25: workflow.v1.WorkflowService.GetWorkflowRun:output_type -> workflow.v1.GetWorkflowRunResponse
+	26, // 26: workflow.v1.WorkflowService.UpdateWorkflow:output_type -> workflow.v1.UpdateWorkflowResponse
+	27, // 27: workflow.v1.WorkflowService.ListWorkflowVersions:output_type -> workflow.v1.ListWorkflowVersionsResponse
+	28, // 28: workflow.v1.WorkflowService.GetWorkflowVersion:output_type -> workflow.v1.GetWorkflowVersionResponse
+	29, // 29: workflow.v1.WorkflowService.DiffWorkflowVersions:output_type -> workflow.v1.DiffWorkflowVersionsResponse
+	30, // 30: workflow.v1.WorkflowService.ListDeadLetterTasks:output_type -> workflow.v1.ListDeadLetterTasksResponse
+	31, // 31: workflow.v1.WorkflowService.RedriveTask:output_type -> workflow.v1.RedriveTaskResponse
+	32, // 32: workflow.v1.ScheduleService.CreateSchedule:output_type -> workflow.v1.CreateScheduleResponse
+	33, // 33: workflow.v1.ScheduleService.GetSchedule:output_type -> workflow.v1.GetScheduleResponse
+	34, // 34: workflow.v1.ScheduleService.ListSchedules:output_type -> workflow.v1.ListSchedulesResponse
+	35, // 35: workflow.v1.ScheduleService.PauseSchedule:output_type -> workflow.v1.PauseScheduleResponse
+	36, // 36: workflow.v1.ScheduleService.ResumeSchedule:output_type -> workflow.v1.ResumeScheduleResponse
+	37, // 37: workflow.v1.ScheduleService.DeleteSchedule:output_type -> workflow.v1.DeleteScheduleResponse
+	38, // 38: workflow.v1.WebhookService.CreateWebhook:output_type -> workflow.v1.CreateWebhookResponse
+	39, // 39: workflow.v1.WebhookService.ListWebhooks:output_type -> workflow.v1.ListWebhooksResponse
+	40, // 40: workflow.v1.WebhookService.DeleteWebhook:output_type -> workflow.v1.DeleteWebhookResponse
+	41, // 41: workflow.v1.WebhookService.DeliverWebhook:output_type -> workflow.v1.DeliverWebhookResponse
+	21, // [21:42] is the sub-list for method output_type
+	0,  // [0:21] is the sub-list for method input_type
+	0,  // [0:0] is the sub-list for extension type_name
+	0,  // [0:0] is the sub-list for extension extendee
+	0,  // [0:0] is the sub-list for field type_name
 }
 
 func init() { file_workflow_v1_services_proto_init() }
@@ -67,7 +162,7 @@ func file_workflow_v1_services_proto_init() {
 			NumEnums:      0,
 			NumMessages:   0,
 			NumExtensions: 0,
-			NumServices:   1,
+			NumServices:   3,
 		},
 		GoTypes:           file_workflow_v1_services_proto_goTypes,
 		DependencyIndexes: file_workflow_v1_services_proto_depIdxs,
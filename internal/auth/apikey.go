@@ -0,0 +1,140 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/netip"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	sqlc "github.com/vantutran2k1/rwe/internal/auth/db"
+)
+
+var (
+	ErrInvalidApiKey  = errors.New("api key is invalid, revoked or expired")
+	ErrTenantInactive = errors.New("tenant is not active")
+)
+
+var scopeRegex = regexp.MustCompile(`^[a-z_]+:(read|write|\*)$`)
+
+func LookupApiKey(ctx context.Context, querier sqlc.Querier, rawKey string) (sqlc.GetApiKeyByHashRow, error) {
+	if rawKey == "" || !strings.HasPrefix(rawKey, keyPrefix) {
+		return sqlc.GetApiKeyByHashRow{}, ErrInvalidApiKey
+	}
+
+	key, err := querier.GetApiKeyByHash(ctx, HashKey(rawKey))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return sqlc.GetApiKeyByHashRow{}, ErrInvalidApiKey
+		}
+
+		return sqlc.GetApiKeyByHashRow{}, err
+	}
+
+	if key.Revoked.Bool {
+		return sqlc.GetApiKeyByHashRow{}, ErrInvalidApiKey
+	}
+
+	if key.ExpiresAt.Valid && !key.ExpiresAt.Time.After(time.Now()) {
+		return sqlc.GetApiKeyByHashRow{}, ErrInvalidApiKey
+	}
+
+	if !TenantActive(key.TenantStatus) {
+		return sqlc.GetApiKeyByHashRow{}, ErrTenantInactive
+	}
+
+	return key, nil
+}
+
+// TenantActive reports whether credentials of a tenant with the given status
+// may be used. Suspended, archived and pending tenants are locked out.
+func TenantActive(status sqlc.NullTenantStatus) bool {
+	return !status.Valid || status.TenantStatus == sqlc.TenantStatusActive
+}
+
+func ValidateScope(scope string) error {
+	if scope != "*" && !scopeRegex.MatchString(scope) {
+		return fmt.Errorf("invalid scope %q: must be <resource>:<read|write|*>", scope)
+	}
+
+	return nil
+}
+
+// ScopeAllows reports whether any of the granted scopes covers required. A
+// write scope also covers read on the same resource.
+func ScopeAllows(granted []string, required string) bool {
+	if required == "" {
+		return false
+	}
+
+	return slices.ContainsFunc(granted, func(scope string) bool { return scopeCovers(scope, required) })
+}
+
+// scopeCovers reports whether held grants everything granted does.
+func scopeCovers(held, granted string) bool {
+	if held == "*" || held == granted {
+		return true
+	}
+	if granted == "*" {
+		return false
+	}
+
+	resource, action, _ := strings.Cut(granted, ":")
+	switch held {
+	case resource + ":*":
+		return true
+	case resource + ":write":
+		return action == "read"
+	}
+
+	return false
+}
+
+// NormalizeScopes validates scopes and returns them sorted without
+// duplicates.
+func NormalizeScopes(scopes []string) ([]string, error) {
+	normalized := make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		scope = strings.ToLower(strings.TrimSpace(scope))
+		if err := ValidateScope(scope); err != nil {
+			return nil, err
+		}
+		normalized = append(normalized, scope)
+	}
+
+	slices.Sort(normalized)
+	return slices.Compact(normalized), nil
+}
+
+func ParseCIDRs(cidrs []string) ([]netip.Prefix, error) {
+	prefixes := make([]netip.Prefix, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		prefix, err := netip.ParsePrefix(strings.TrimSpace(cidr))
+		if err != nil {
+			return nil, fmt.Errorf("invalid cidr %q: %w", cidr, err)
+		}
+		prefixes = append(prefixes, prefix.Masked())
+	}
+
+	return prefixes, nil
+}
+
+func IPAllowed(cidrs []string, ip netip.Addr) bool {
+	prefixes, err := ParseCIDRs(cidrs)
+	if err != nil {
+		return false
+	}
+
+	ip = ip.Unmap()
+	for _, prefix := range prefixes {
+		if prefix.Contains(ip) {
+			return true
+		}
+	}
+
+	return false
+}
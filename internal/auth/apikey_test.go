@@ -0,0 +1,103 @@
+package auth
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestScopeAllows(t *testing.T) {
+	tests := []struct {
+		name     string
+		granted  []string
+		required string
+		want     bool
+	}{
+		{name: "exact scope", granted: []string{"workflows:read"}, required: "workflows:read", want: true},
+		{name: "write covers read", granted: []string{"workflows:write"}, required: "workflows:read", want: true},
+		{name: "read does not cover write", granted: []string{"workflows:read"}, required: "workflows:write", want: false},
+		{name: "resource wildcard", granted: []string{"workflows:*"}, required: "workflows:write", want: true},
+		{name: "global wildcard", granted: []string{"*"}, required: "tenants:write", want: true},
+		{name: "other resource", granted: []string{"workflows:*"}, required: "tenants:read", want: false},
+		{name: "resource prefix is not the resource", granted: []string{"work:*"}, required: "workflows:read", want: false},
+		{name: "any granted scope", granted: []string{"tenants:read", "workflows:write"}, required: "workflows:read", want: true},
+		{name: "no scopes", required: "workflows:read", want: false},
+		{name: "empty requirement", granted: []string{"*"}, required: "", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ScopeAllows(tt.granted, tt.required); got != tt.want {
+				t.Errorf("ScopeAllows(%v, %q) = %v, want %v", tt.granted, tt.required, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScopeCovers(t *testing.T) {
+	tests := []struct {
+		held    string
+		granted string
+		want    bool
+	}{
+		{held: "*", granted: "*", want: true},
+		{held: "*", granted: "workflows:*", want: true},
+		{held: "workflows:*", granted: "*", want: false},
+		{held: "workflows:*", granted: "workflows:*", want: true},
+		{held: "workflows:*", granted: "workflows:read", want: true},
+		{held: "workflows:write", granted: "workflows:*", want: false},
+		{held: "workflows:write", granted: "workflows:write", want: true},
+		{held: "workflows:write", granted: "workflows:read", want: true},
+		{held: "workflows:read", granted: "workflows:write", want: false},
+		{held: "workflows:write", granted: "tenants:read", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.held+" "+tt.granted, func(t *testing.T) {
+			if got := scopeCovers(tt.held, tt.granted); got != tt.want {
+				t.Errorf("scopeCovers(%q, %q) = %v, want %v", tt.held, tt.granted, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeScopes(t *testing.T) {
+	tests := []struct {
+		name    string
+		scopes  []string
+		want    []string
+		wantErr bool
+	}{
+		{
+			name:   "sorted without duplicates",
+			scopes: []string{"workflows:write", " Tenants:Read ", "workflows:write"},
+			want:   []string{"tenants:read", "workflows:write"},
+		},
+		{
+			name:   "global wildcard",
+			scopes: []string{"*"},
+			want:   []string{"*"},
+		},
+		{
+			name:    "missing action",
+			scopes:  []string{"workflows"},
+			wantErr: true,
+		},
+		{
+			name:    "unknown action",
+			scopes:  []string{"workflows:delete"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NormalizeScopes(tt.scopes)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NormalizeScopes(%v) error = %v, want error %v", tt.scopes, err, tt.wantErr)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("NormalizeScopes(%v) = %v, want %v", tt.scopes, got, tt.want)
+			}
+		})
+	}
+}
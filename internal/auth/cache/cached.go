@@ -0,0 +1,107 @@
+package cache
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+)
+
+// CachedBlocklist caches lookups of a backing Blocklist in process. Negative
+// results expire after ttl so logouts on other replicas are picked up.
+type CachedBlocklist struct {
+	backend Blocklist
+	ttl     time.Duration
+	size    int
+
+	mu      sync.Mutex
+	order   *list.List
+	entries map[string]*list.Element
+}
+
+type cacheEntry struct {
+	tokenID     string
+	blocklisted bool
+	expiresAt   time.Time
+}
+
+func NewCachedBlocklist(backend Blocklist, ttl time.Duration, size int) *CachedBlocklist {
+	return &CachedBlocklist{
+		backend: backend,
+		ttl:     ttl,
+		size:    size,
+		order:   list.New(),
+		entries: make(map[string]*list.Element),
+	}
+}
+
+func (c *CachedBlocklist) AddToBlocklist(ctx context.Context, tokenID string, duration time.Duration) error {
+	if err := c.backend.AddToBlocklist(ctx, tokenID, duration); err != nil {
+		return err
+	}
+
+	c.put(tokenID, true, time.Now().Add(duration))
+	return nil
+}
+
+func (c *CachedBlocklist) IsBlocklisted(ctx context.Context, tokenID string) (bool, error) {
+	if blocklisted, ok := c.get(tokenID); ok {
+		return blocklisted, nil
+	}
+
+	blocklisted, err := c.backend.IsBlocklisted(ctx, tokenID)
+	if err != nil {
+		return false, err
+	}
+
+	if blocklisted {
+		c.put(tokenID, true, time.Time{})
+	} else {
+		c.put(tokenID, false, time.Now().Add(c.ttl))
+	}
+
+	return blocklisted, nil
+}
+
+func (c *CachedBlocklist) get(tokenID string) (bool, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.entries[tokenID]
+	if !ok {
+		return false, false
+	}
+
+	entry := el.Value.(*cacheEntry)
+	if !entry.expiresAt.IsZero() && !entry.expiresAt.After(time.Now()) {
+		c.order.Remove(el)
+		delete(c.entries, tokenID)
+		return false, false
+	}
+
+	c.order.MoveToFront(el)
+	return entry.blocklisted, true
+}
+
+func (c *CachedBlocklist) put(tokenID string, blocklisted bool, expiresAt time.Time) {
+	if c.size <= 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.entries[tokenID]; ok {
+		el.Value = &cacheEntry{tokenID: tokenID, blocklisted: blocklisted, expiresAt: expiresAt}
+		c.order.MoveToFront(el)
+		return
+	}
+
+	c.entries[tokenID] = c.order.PushFront(&cacheEntry{tokenID: tokenID, blocklisted: blocklisted, expiresAt: expiresAt})
+
+	for c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cacheEntry).tokenID)
+	}
+}
@@ -0,0 +1,89 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+type LoginStatus struct {
+	Failures    int64
+	LastFailure time.Time
+	LockedFor   time.Duration
+}
+
+// LoginThrottle tracks failed logins per key, e.g. an email or client IP.
+type LoginThrottle interface {
+	Status(ctx context.Context, key string) (LoginStatus, error)
+	RecordFailure(ctx context.Context, key string, window time.Duration) (int64, error)
+	Lock(ctx context.Context, key string, duration time.Duration) error
+	Reset(ctx context.Context, key string) error
+}
+
+type RedisLoginThrottle struct {
+	client *redis.Client
+}
+
+func NewRedisLoginThrottle(client *redis.Client) *RedisLoginThrottle {
+	return &RedisLoginThrottle{client: client}
+}
+
+func (r *RedisLoginThrottle) Status(ctx context.Context, key string) (LoginStatus, error) {
+	var failures *redis.MapStringStringCmd
+	var lockTTL *redis.DurationCmd
+	if _, err := r.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		failures = pipe.HGetAll(ctx, failuresKey(key))
+		lockTTL = pipe.PTTL(ctx, lockKey(key))
+		return nil
+	}); err != nil {
+		return LoginStatus{}, err
+	}
+
+	var status LoginStatus
+	fields := failures.Val()
+	if count, err := strconv.ParseInt(fields["count"], 10, 64); err == nil {
+		status.Failures = count
+	}
+	if last, err := strconv.ParseInt(fields["last"], 10, 64); err == nil {
+		status.LastFailure = time.UnixMilli(last)
+	}
+	// PTTL reports negative values for missing keys and keys without expiry.
+	if ttl := lockTTL.Val(); ttl > 0 {
+		status.LockedFor = ttl
+	}
+
+	return status, nil
+}
+
+func (r *RedisLoginThrottle) RecordFailure(ctx context.Context, key string, window time.Duration) (int64, error) {
+	var count *redis.IntCmd
+	if _, err := r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		count = pipe.HIncrBy(ctx, failuresKey(key), "count", 1)
+		pipe.HSet(ctx, failuresKey(key), "last", time.Now().UnixMilli())
+		pipe.PExpire(ctx, failuresKey(key), window)
+		return nil
+	}); err != nil {
+		return 0, err
+	}
+
+	return count.Val(), nil
+}
+
+func (r *RedisLoginThrottle) Lock(ctx context.Context, key string, duration time.Duration) error {
+	return r.client.Set(ctx, lockKey(key), "1", duration).Err()
+}
+
+func (r *RedisLoginThrottle) Reset(ctx context.Context, key string) error {
+	return r.client.Del(ctx, failuresKey(key), lockKey(key)).Err()
+}
+
+func failuresKey(key string) string {
+	return fmt.Sprintf("login:failures:%s", key)
+}
+
+func lockKey(key string) string {
+	return fmt.Sprintf("login:lock:%s", key)
+}
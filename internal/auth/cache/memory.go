@@ -0,0 +1,48 @@
+package cache
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type MemoryBlocklist struct {
+	mu      sync.Mutex
+	entries map[string]time.Time
+}
+
+func NewMemoryBlocklist() *MemoryBlocklist {
+	return &MemoryBlocklist{entries: make(map[string]time.Time)}
+}
+
+func (m *MemoryBlocklist) AddToBlocklist(ctx context.Context, tokenID string, duration time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	for id, expiresAt := range m.entries {
+		if !expiresAt.After(now) {
+			delete(m.entries, id)
+		}
+	}
+
+	m.entries[tokenID] = now.Add(duration)
+	return nil
+}
+
+func (m *MemoryBlocklist) IsBlocklisted(ctx context.Context, tokenID string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	expiresAt, ok := m.entries[tokenID]
+	if !ok {
+		return false, nil
+	}
+
+	if !expiresAt.After(time.Now()) {
+		delete(m.entries, tokenID)
+		return false, nil
+	}
+
+	return true, nil
+}
@@ -0,0 +1,58 @@
+package auth
+
+import (
+	"context"
+	"net/netip"
+	"strings"
+
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+// ClientIP returns the address of the caller. When the connection comes from
+// a trusted proxy, such as the REST gateway, the caller is the right-most
+// x-forwarded-for entry that is not itself a trusted proxy.
+func ClientIP(ctx context.Context, trustedProxies []netip.Prefix) (netip.Addr, bool) {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return netip.Addr{}, false
+	}
+
+	ip, ok := parseAddr(p.Addr.String())
+	if !ok {
+		return netip.Addr{}, false
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	forwarded := strings.Split(strings.Join(md.Get("x-forwarded-for"), ","), ",")
+	for i := len(forwarded) - 1; i >= 0 && trusted(ip, trustedProxies); i-- {
+		next, ok := parseAddr(strings.TrimSpace(forwarded[i]))
+		if !ok {
+			break
+		}
+		ip = next
+	}
+
+	return ip, true
+}
+
+func trusted(ip netip.Addr, trustedProxies []netip.Prefix) bool {
+	for _, prefix := range trustedProxies {
+		if prefix.Contains(ip) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func parseAddr(s string) (netip.Addr, bool) {
+	if addrPort, err := netip.ParseAddrPort(s); err == nil {
+		return addrPort.Addr().Unmap(), true
+	}
+	if addr, err := netip.ParseAddr(s); err == nil {
+		return addr.Unmap(), true
+	}
+
+	return netip.Addr{}, false
+}
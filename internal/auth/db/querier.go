@@ -11,13 +11,75 @@ import (
 )
 
 type Querier interface {
+	AddTenantMemberIfMissing(ctx context.Context, arg AddTenantMemberIfMissingParams) error
+	// Appends to one stream at a time, so that ids grow in commit order within
+	// the tenant or user stream a subscriber follows.
+	AppendEvent(ctx context.Context, arg AppendEventParams) (int64, error)
+	ConfirmUserMfa(ctx context.Context, arg ConfirmUserMfaParams) error
+	ConsumeOidcLoginState(ctx context.Context, stateHash string) (OidcLoginState, error)
+	CountUnusedRecoveryCodes(ctx context.Context, userID pgtype.UUID) (int64, error)
 	CreateApiKey(ctx context.Context, arg CreateApiKeyParams) (CreateApiKeyRow, error)
+	CreateOidcLoginState(ctx context.Context, arg CreateOidcLoginStateParams) error
+	CreateRecoveryCode(ctx context.Context, arg CreateRecoveryCodeParams) error
+	CreateRefreshToken(ctx context.Context, arg CreateRefreshTokenParams) error
+	CreateSession(ctx context.Context, arg CreateSessionParams) (Session, error)
+	CreateSigningKey(ctx context.Context, arg CreateSigningKeyParams) error
+	CreateTenantDomain(ctx context.Context, arg CreateTenantDomainParams) (TenantDomain, error)
 	CreateUser(ctx context.Context, arg CreateUserParams) (pgtype.UUID, error)
+	CreateUserIdentity(ctx context.Context, arg CreateUserIdentityParams) error
+	CreateUserToken(ctx context.Context, arg CreateUserTokenParams) error
+	DeleteOidcProvider(ctx context.Context, tenantID pgtype.UUID) (int64, error)
+	DeleteRecoveryCodes(ctx context.Context, userID pgtype.UUID) error
+	DeleteTenantDomain(ctx context.Context, arg DeleteTenantDomainParams) (int64, error)
+	DeleteUserMfa(ctx context.Context, userID pgtype.UUID) error
+	// The tenant status is that of the nearest inactive tenant on the path to the
+	// root, so keys of a tenant under a suspended parent are locked out too.
 	GetApiKeyByHash(ctx context.Context, keyHash string) (GetApiKeyByHashRow, error)
+	GetApiKeyForUpdate(ctx context.Context, arg GetApiKeyForUpdateParams) (ApiKey, error)
+	// Unlike GetTenantMembership, memberships of ancestor tenants do not count.
+	GetDirectTenantMembership(ctx context.Context, arg GetDirectTenantMembershipParams) (GetDirectTenantMembershipRow, error)
+	GetOidcProvider(ctx context.Context, tenantID pgtype.UUID) (OidcProvider, error)
+	GetOidcProviderBySlug(ctx context.Context, slug string) (GetOidcProviderBySlugRow, error)
+	GetRefreshTokenForUpdate(ctx context.Context, tokenHash string) (GetRefreshTokenForUpdateRow, error)
+	GetTenantDomain(ctx context.Context, arg GetTenantDomainParams) (TenantDomain, error)
+	// Members of an ancestor tenant are members of all of its descendants; the
+	// highest role held anywhere along the path applies. Likewise the status of
+	// the nearest inactive tenant on the path is the status of the tenant.
+	GetTenantMembership(ctx context.Context, arg GetTenantMembershipParams) (GetTenantMembershipRow, error)
 	GetUserByEmail(ctx context.Context, email string) (GetUserByEmailRow, error)
 	GetUserByEmailWithPassword(ctx context.Context, email string) (GetUserByEmailWithPasswordRow, error)
+	GetUserByID(ctx context.Context, id pgtype.UUID) (GetUserByIDRow, error)
+	GetUserIdentity(ctx context.Context, arg GetUserIdentityParams) (GetUserIdentityRow, error)
+	GetUserMfa(ctx context.Context, userID pgtype.UUID) (UserMfa, error)
+	GetUserMfaForUpdate(ctx context.Context, userID pgtype.UUID) (UserMfa, error)
+	GetUserTokenForUpdate(ctx context.Context, arg GetUserTokenForUpdateParams) (GetUserTokenForUpdateRow, error)
+	GetVerifiedTenantMemberByEmail(ctx context.Context, arg GetVerifiedTenantMemberByEmailParams) (pgtype.UUID, error)
+	IncrementUserTokenAttempts(ctx context.Context, id pgtype.UUID) (int32, error)
+	InvalidateUserTokens(ctx context.Context, arg InvalidateUserTokensParams) error
+	ListActiveSessions(ctx context.Context, userID pgtype.UUID) ([]Session, error)
 	ListApiKeys(ctx context.Context, tenantID pgtype.UUID) ([]ListApiKeysRow, error)
-	RevokeApiKey(ctx context.Context, id pgtype.UUID) error
+	ListSigningKeys(ctx context.Context) ([]SigningKey, error)
+	ListTenantDomains(ctx context.Context, tenantID pgtype.UUID) ([]TenantDomain, error)
+	// As in GetTenantMembership, an inactive ancestor makes the tenant inactive.
+	ListTenantMembershipsByUserID(ctx context.Context, arg ListTenantMembershipsByUserIDParams) ([]ListTenantMembershipsByUserIDRow, error)
+	ListVerifiedTenantDomains(ctx context.Context, tenantID pgtype.UUID) ([]string, error)
+	LockSigningKeys(ctx context.Context) error
+	MarkApiKeyRotated(ctx context.Context, arg MarkApiKeyRotatedParams) error
+	MarkRefreshTokenUsed(ctx context.Context, id pgtype.UUID) error
+	MarkTenantDomainVerified(ctx context.Context, arg MarkTenantDomainVerifiedParams) (TenantDomain, error)
+	MarkUserEmailVerified(ctx context.Context, id pgtype.UUID) error
+	MarkUserTokenUsed(ctx context.Context, id pgtype.UUID) error
+	RetireSigningKeys(ctx context.Context, arg RetireSigningKeysParams) error
+	RevokeApiKey(ctx context.Context, arg RevokeApiKeyParams) (RevokeApiKeyRow, error)
+	RevokeSession(ctx context.Context, arg RevokeSessionParams) (Session, error)
+	RevokeUserSessions(ctx context.Context, userID pgtype.UUID) ([]Session, error)
+	RotateSessionAccessToken(ctx context.Context, arg RotateSessionAccessTokenParams) error
+	TouchApiKey(ctx context.Context, id pgtype.UUID) error
+	UpdateUserMfaLastUsedStep(ctx context.Context, arg UpdateUserMfaLastUsedStepParams) error
+	UpdateUserPassword(ctx context.Context, arg UpdateUserPasswordParams) error
+	UpsertOidcProvider(ctx context.Context, arg UpsertOidcProviderParams) (OidcProvider, error)
+	UpsertPendingUserMfa(ctx context.Context, arg UpsertPendingUserMfaParams) error
+	UseRecoveryCode(ctx context.Context, arg UseRecoveryCodeParams) (int64, error)
 }
 
 var _ Querier = (*Queries)(nil)
@@ -1,21 +1,64 @@
 -- name: GetApiKeyByHash :one
-SELECT id, tenant_id, revoked, expires_at
-FROM api_keys
-WHERE key_hash = $1
+-- The tenant status is that of the nearest inactive tenant on the path to the
+-- root, so keys of a tenant under a suspended parent are locked out too.
+WITH RECURSIVE path AS (SELECT t.id, t.parent_id, t.status, 0 AS depth
+                        FROM api_keys pk
+                                 JOIN tenants t ON t.id = pk.tenant_id
+                        WHERE pk.key_hash = @key_hash
+                        UNION ALL
+                        SELECT t.id, t.parent_id, t.status, p.depth + 1
+                        FROM tenants t
+                                 JOIN path p ON t.id = p.parent_id)
+SELECT k.id,
+       k.tenant_id,
+       k.role,
+       k.revoked,
+       k.expires_at,
+       k.scopes,
+       k.allowed_cidrs,
+       coalesce((SELECT p.status
+                 FROM path p
+                 WHERE p.status <> 'active'
+                 ORDER BY p.depth
+                 LIMIT 1), t.status) AS tenant_status
+FROM api_keys k
+         JOIN tenants t ON t.id = k.tenant_id
+WHERE k.key_hash = @key_hash
 LIMIT 1;
 
 -- name: CreateApiKey :one
-INSERT INTO api_keys (tenant_id, key_hash, key_prefix, name)
-VALUES ($1, $2, $3, $4)
+INSERT INTO api_keys (tenant_id, key_hash, key_prefix, name, role, expires_at, scopes, allowed_cidrs)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
 RETURNING id, created_at;
 
--- name: RevokeApiKey :exec
+-- name: GetApiKeyForUpdate :one
+SELECT *
+FROM api_keys
+WHERE id = $1
+  AND tenant_id = $2
+    FOR UPDATE;
+
+-- name: MarkApiKeyRotated :exec
+UPDATE api_keys
+SET rotated_to = @rotated_to,
+    expires_at = @expires_at
+WHERE id = @id;
+
+-- name: RevokeApiKey :one
 UPDATE api_keys
 SET revoked = true
-WHERE id = $1;
+WHERE id = $1
+  AND tenant_id = $2
+RETURNING id, tenant_id;
+
+-- name: TouchApiKey :exec
+UPDATE api_keys
+SET last_used_at = now()
+WHERE id = $1
+  AND (last_used_at IS NULL OR last_used_at < now() - interval '1 minute');
 
 -- name: ListApiKeys :many
-SELECT id, name, key_prefix, created_at, last_used_at, revoked, expires_at
+SELECT id, name, key_prefix, role, created_at, last_used_at, revoked, expires_at, scopes, allowed_cidrs, rotated_to
 FROM api_keys
 WHERE tenant_id = $1
 ORDER BY created_at DESC;
@@ -34,3 +77,379 @@ WHERE email = $1;
 SELECT id, email, password_hash
 FROM users
 WHERE email = $1;
+
+-- name: GetUserByID :one
+SELECT id, email, full_name, email_verified_at
+FROM users
+WHERE id = $1;
+
+-- name: UpdateUserPassword :exec
+UPDATE users
+SET password_hash = $2
+WHERE id = $1;
+
+-- name: MarkUserEmailVerified :exec
+UPDATE users
+SET email_verified_at = coalesce(email_verified_at, now())
+WHERE id = $1;
+
+-- name: CreateUserToken :exec
+INSERT INTO user_tokens (user_id, purpose, token_hash, expires_at)
+VALUES ($1, $2, $3, $4);
+
+-- name: GetUserTokenForUpdate :one
+SELECT ut.id,
+       ut.user_id,
+       ut.expires_at,
+       ut.used_at,
+       ut.attempts,
+       u.email
+FROM user_tokens ut
+         JOIN users u ON u.id = ut.user_id
+WHERE ut.token_hash = $1
+  AND ut.purpose = $2
+    FOR UPDATE OF ut;
+
+-- name: MarkUserTokenUsed :exec
+UPDATE user_tokens
+SET used_at = now()
+WHERE id = $1;
+
+-- name: IncrementUserTokenAttempts :one
+UPDATE user_tokens
+SET attempts = attempts + 1
+WHERE id = $1
+RETURNING attempts;
+
+-- name: InvalidateUserTokens :exec
+UPDATE user_tokens
+SET used_at = now()
+WHERE user_id = $1
+  AND purpose = $2
+  AND used_at IS NULL;
+
+-- name: GetUserMfa :one
+SELECT *
+FROM user_mfa
+WHERE user_id = $1;
+
+-- name: GetUserMfaForUpdate :one
+SELECT *
+FROM user_mfa
+WHERE user_id = $1
+    FOR UPDATE;
+
+-- name: UpsertPendingUserMfa :exec
+INSERT INTO user_mfa (user_id, encrypted_secret)
+VALUES ($1, $2)
+ON CONFLICT (user_id) DO UPDATE
+    SET encrypted_secret = excluded.encrypted_secret,
+        last_used_step   = 0,
+        created_at       = now()
+WHERE user_mfa.confirmed_at IS NULL;
+
+-- name: ConfirmUserMfa :exec
+UPDATE user_mfa
+SET confirmed_at   = now(),
+    last_used_step = $2
+WHERE user_id = $1;
+
+-- name: UpdateUserMfaLastUsedStep :exec
+UPDATE user_mfa
+SET last_used_step = $2
+WHERE user_id = $1;
+
+-- name: DeleteUserMfa :exec
+DELETE
+FROM user_mfa
+WHERE user_id = $1;
+
+-- name: CreateRecoveryCode :exec
+INSERT INTO mfa_recovery_codes (user_id, code_hash)
+VALUES ($1, $2);
+
+-- name: DeleteRecoveryCodes :exec
+DELETE
+FROM mfa_recovery_codes
+WHERE user_id = $1;
+
+-- name: UseRecoveryCode :execrows
+UPDATE mfa_recovery_codes
+SET used_at = now()
+WHERE user_id = $1
+  AND code_hash = $2
+  AND used_at IS NULL;
+
+-- name: CountUnusedRecoveryCodes :one
+SELECT count(*)
+FROM mfa_recovery_codes
+WHERE user_id = $1
+  AND used_at IS NULL;
+
+-- name: CreateSession :one
+INSERT INTO sessions (id, user_id, user_agent, ip_address, expires_at)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING *;
+
+-- name: CreateRefreshToken :exec
+INSERT INTO refresh_tokens (session_id, token_hash)
+VALUES ($1, $2);
+
+-- name: GetRefreshTokenForUpdate :one
+SELECT rt.id,
+       rt.used_at,
+       s.id AS session_id,
+       s.user_id,
+       s.expires_at,
+       s.revoked_at,
+       s.access_token_id,
+       s.access_token_expires_at,
+       u.email
+FROM refresh_tokens rt
+         JOIN sessions s ON s.id = rt.session_id
+         JOIN users u ON u.id = s.user_id
+WHERE rt.token_hash = $1
+    FOR UPDATE OF rt, s;
+
+-- name: MarkRefreshTokenUsed :exec
+UPDATE refresh_tokens
+SET used_at = now()
+WHERE id = $1;
+
+-- name: RotateSessionAccessToken :exec
+UPDATE sessions
+SET access_token_id         = $2,
+    access_token_expires_at = $3,
+    user_agent              = coalesce(sqlc.narg(user_agent), user_agent),
+    ip_address              = coalesce(sqlc.narg(ip_address), ip_address),
+    last_used_at            = now()
+WHERE id = $1;
+
+-- name: ListActiveSessions :many
+SELECT *
+FROM sessions
+WHERE user_id = $1
+  AND revoked_at IS NULL
+  AND expires_at > now()
+ORDER BY last_used_at DESC;
+
+-- name: RevokeSession :one
+UPDATE sessions
+SET revoked_at = now()
+WHERE id = $1
+  AND user_id = $2
+  AND revoked_at IS NULL
+RETURNING *;
+
+-- name: RevokeUserSessions :many
+UPDATE sessions
+SET revoked_at = now()
+WHERE user_id = $1
+  AND revoked_at IS NULL
+RETURNING *;
+
+-- name: LockSigningKeys :exec
+SELECT pg_advisory_xact_lock(hashtext('signing_keys'));
+
+-- name: ListSigningKeys :many
+SELECT *
+FROM signing_keys
+WHERE expires_at IS NULL
+   OR expires_at > now()
+ORDER BY created_at DESC;
+
+-- name: CreateSigningKey :exec
+INSERT INTO signing_keys (kid, public_key, encrypted_private_key)
+VALUES ($1, $2, $3);
+
+-- name: RetireSigningKeys :exec
+UPDATE signing_keys
+SET retired_at = now(),
+    expires_at = @expires_at
+WHERE kid <> @active_kid
+  AND retired_at IS NULL;
+
+-- name: GetTenantMembership :one
+-- Members of an ancestor tenant are members of all of its descendants; the
+-- highest role held anywhere along the path applies. Likewise the status of
+-- the nearest inactive tenant on the path is the status of the tenant.
+WITH RECURSIVE ancestors AS (SELECT id, parent_id, status, 0 AS depth
+                             FROM tenants
+                             WHERE id = @tenant_id
+                             UNION ALL
+                             SELECT t.id, t.parent_id, t.status, a.depth + 1
+                             FROM tenants t
+                                      JOIN ancestors a ON t.id = a.parent_id)
+SELECT t.id AS tenant_id,
+       m.role,
+       coalesce((SELECT a.status
+                 FROM ancestors a
+                 WHERE a.status <> 'active'
+                 ORDER BY a.depth
+                 LIMIT 1), t.status) AS tenant_status
+FROM tenants t
+         JOIN LATERAL (SELECT tm.role
+                       FROM ancestors a
+                                JOIN tenant_members tm ON tm.tenant_id = a.id
+                       WHERE tm.user_id = @user_id
+                       ORDER BY CASE tm.role
+                                    WHEN 'owner' THEN 4
+                                    WHEN 'admin' THEN 3
+                                    WHEN 'member' THEN 2
+                                    WHEN 'viewer' THEN 1
+                                    ELSE 0 END DESC
+                       LIMIT 1) m ON true
+WHERE t.id = @tenant_id;
+
+-- name: GetDirectTenantMembership :one
+-- Unlike GetTenantMembership, memberships of ancestor tenants do not count.
+SELECT tenant_id, role
+FROM tenant_members
+WHERE tenant_id = @tenant_id
+  AND user_id = @user_id;
+
+-- name: ListTenantMembershipsByUserID :many
+-- As in GetTenantMembership, an inactive ancestor makes the tenant inactive.
+WITH RECURSIVE path AS (SELECT pm.tenant_id AS member_tenant_id, t.id, t.parent_id, t.status, 0 AS depth
+                        FROM tenant_members pm
+                                 JOIN tenants t ON t.id = pm.tenant_id
+                        WHERE pm.user_id = $1
+                        UNION ALL
+                        SELECT p.member_tenant_id, t.id, t.parent_id, t.status, p.depth + 1
+                        FROM tenants t
+                                 JOIN path p ON t.id = p.parent_id)
+SELECT tm.tenant_id,
+       tm.role,
+       coalesce((SELECT p.status
+                 FROM path p
+                 WHERE p.member_tenant_id = tm.tenant_id
+                   AND p.status <> 'active'
+                 ORDER BY p.depth
+                 LIMIT 1), t.status) AS tenant_status
+FROM tenant_members tm
+         JOIN tenants t ON t.id = tm.tenant_id
+WHERE tm.user_id = $1
+ORDER BY tm.joined_at
+LIMIT $2;
+
+-- name: UpsertOidcProvider :one
+INSERT INTO oidc_providers (tenant_id, issuer, client_id, encrypted_client_secret, allowed_domains, default_role, enabled)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+ON CONFLICT (tenant_id) DO UPDATE
+    SET issuer                  = excluded.issuer,
+        client_id               = excluded.client_id,
+        encrypted_client_secret = excluded.encrypted_client_secret,
+        allowed_domains         = excluded.allowed_domains,
+        default_role            = excluded.default_role,
+        enabled                 = excluded.enabled,
+        updated_at              = now()
+RETURNING *;
+
+-- name: GetOidcProvider :one
+SELECT p.tenant_id,
+       p.issuer,
+       p.client_id,
+       p.encrypted_client_secret,
+       p.allowed_domains,
+       p.default_role,
+       p.enabled,
+       p.created_at,
+       p.updated_at
+FROM oidc_providers p
+WHERE p.tenant_id = $1;
+
+-- name: GetOidcProviderBySlug :one
+SELECT p.tenant_id,
+       p.issuer,
+       p.client_id,
+       p.enabled
+FROM oidc_providers p
+         JOIN tenants t ON t.id = p.tenant_id
+WHERE t.slug = $1;
+
+-- name: DeleteOidcProvider :execrows
+DELETE
+FROM oidc_providers
+WHERE tenant_id = $1;
+
+-- name: CreateOidcLoginState :exec
+INSERT INTO oidc_login_states (state_hash, tenant_id, code_verifier, nonce, expires_at, link_user_id)
+VALUES (@state_hash, @tenant_id, @code_verifier, @nonce, @expires_at, sqlc.narg(link_user_id));
+
+-- name: ConsumeOidcLoginState :one
+UPDATE oidc_login_states
+SET used_at = now()
+WHERE state_hash = $1
+  AND used_at IS NULL
+  AND expires_at > now()
+RETURNING *;
+
+-- name: GetUserIdentity :one
+SELECT ui.user_id, u.email
+FROM user_identities ui
+         JOIN users u ON u.id = ui.user_id
+WHERE ui.issuer = $1
+  AND ui.subject = $2;
+
+-- name: CreateUserIdentity :exec
+INSERT INTO user_identities (issuer, subject, user_id)
+VALUES ($1, $2, $3);
+
+-- name: GetVerifiedTenantMemberByEmail :one
+SELECT u.id
+FROM users u
+         JOIN tenant_members tm ON tm.user_id = u.id
+WHERE u.email = @email
+  AND tm.tenant_id = @tenant_id
+  AND u.email_verified_at IS NOT NULL;
+
+-- name: CreateTenantDomain :one
+INSERT INTO tenant_domains (tenant_id, domain, verification_token)
+VALUES ($1, $2, $3)
+RETURNING *;
+
+-- name: GetTenantDomain :one
+SELECT *
+FROM tenant_domains
+WHERE tenant_id = $1
+  AND domain = $2;
+
+-- name: MarkTenantDomainVerified :one
+UPDATE tenant_domains
+SET verified_at = coalesce(verified_at, now())
+WHERE tenant_id = $1
+  AND domain = $2
+RETURNING *;
+
+-- name: ListTenantDomains :many
+SELECT *
+FROM tenant_domains
+WHERE tenant_id = $1
+ORDER BY domain;
+
+-- name: ListVerifiedTenantDomains :many
+SELECT domain
+FROM tenant_domains
+WHERE tenant_id = $1
+  AND verified_at IS NOT NULL;
+
+-- name: DeleteTenantDomain :execrows
+DELETE
+FROM tenant_domains
+WHERE tenant_id = $1
+  AND domain = $2;
+
+-- name: AddTenantMemberIfMissing :exec
+INSERT INTO tenant_members (tenant_id, user_id, role)
+VALUES ($1, $2, $3)
+ON CONFLICT (tenant_id, user_id) DO NOTHING;
+
+-- name: AppendEvent :one
+-- Appends to one stream at a time, so that ids grow in commit order within
+-- the tenant or user stream a subscriber follows.
+WITH ordering_lock AS (SELECT pg_advisory_xact_lock(hashtext('events:' || coalesce(sqlc.narg(tenant_id)::uuid, sqlc.narg(user_id)::uuid)::text)))
+INSERT
+INTO events (tenant_id, event_type, aggregate_id, payload, user_id)
+SELECT sqlc.narg(tenant_id)::uuid, @event_type::text, @aggregate_id::uuid, @payload::jsonb, sqlc.narg(user_id)::uuid
+FROM ordering_lock
+RETURNING id;
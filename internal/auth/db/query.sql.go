@@ -11,17 +11,126 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const addTenantMemberIfMissing = `-- name: AddTenantMemberIfMissing :exec
+INSERT INTO tenant_members (tenant_id, user_id, role)
+VALUES ($1, $2, $3)
+ON CONFLICT (tenant_id, user_id) DO NOTHING
+`
+
+type AddTenantMemberIfMissingParams struct {
+	TenantID pgtype.UUID `db:"tenant_id" json:"tenant_id"`
+	UserID   pgtype.UUID `db:"user_id" json:"user_id"`
+	Role     pgtype.Text `db:"role" json:"role"`
+}
+
+func (q *Queries) AddTenantMemberIfMissing(ctx context.Context, arg AddTenantMemberIfMissingParams) error {
+	_, err := q.db.Exec(ctx, addTenantMemberIfMissing, arg.TenantID, arg.UserID, arg.Role)
+	return err
+}
+
+const appendEvent = `-- name: AppendEvent :one
+WITH ordering_lock AS (SELECT pg_advisory_xact_lock(hashtext('events:' || coalesce($1::uuid, $5::uuid)::text)))
+INSERT
+INTO events (tenant_id, event_type, aggregate_id, payload, user_id)
+SELECT $1::uuid, $2::text, $3::uuid, $4::jsonb, $5::uuid
+FROM ordering_lock
+RETURNING id
+`
+
+type AppendEventParams struct {
+	TenantID    pgtype.UUID `db:"tenant_id" json:"tenant_id"`
+	EventType   string      `db:"event_type" json:"event_type"`
+	AggregateID pgtype.UUID `db:"aggregate_id" json:"aggregate_id"`
+	Payload     []byte      `db:"payload" json:"payload"`
+	UserID      pgtype.UUID `db:"user_id" json:"user_id"`
+}
+
+// Appends to one stream at a time, so that ids grow in commit order within
+// the tenant or user stream a subscriber follows.
+func (q *Queries) AppendEvent(ctx context.Context, arg AppendEventParams) (int64, error) {
+	row := q.db.QueryRow(ctx, appendEvent,
+		arg.TenantID,
+		arg.EventType,
+		arg.AggregateID,
+		arg.Payload,
+		arg.UserID,
+	)
+	var id int64
+	err := row.Scan(&id)
+	return id, err
+}
+
+const confirmUserMfa = `-- name: ConfirmUserMfa :exec
+UPDATE user_mfa
+SET confirmed_at   = now(),
+    last_used_step = $2
+WHERE user_id = $1
+`
+
+type ConfirmUserMfaParams struct {
+	UserID       pgtype.UUID `db:"user_id" json:"user_id"`
+	LastUsedStep int64       `db:"last_used_step" json:"last_used_step"`
+}
+
+func (q *Queries) ConfirmUserMfa(ctx context.Context, arg ConfirmUserMfaParams) error {
+	_, err := q.db.Exec(ctx, confirmUserMfa, arg.UserID, arg.LastUsedStep)
+	return err
+}
+
+const consumeOidcLoginState = `-- name: ConsumeOidcLoginState :one
+UPDATE oidc_login_states
+SET used_at = now()
+WHERE state_hash = $1
+  AND used_at IS NULL
+  AND expires_at > now()
+RETURNING state_hash, tenant_id, code_verifier, nonce, link_user_id, created_at, expires_at, used_at
+`
+
+func (q *Queries) ConsumeOidcLoginState(ctx context.Context, stateHash string) (OidcLoginState, error) {
+	row := q.db.QueryRow(ctx, consumeOidcLoginState, stateHash)
+	var i OidcLoginState
+	err := row.Scan(
+		&i.StateHash,
+		&i.TenantID,
+		&i.CodeVerifier,
+		&i.Nonce,
+		&i.LinkUserID,
+		&i.CreatedAt,
+		&i.ExpiresAt,
+		&i.UsedAt,
+	)
+	return i, err
+}
+
+const countUnusedRecoveryCodes = `-- name: CountUnusedRecoveryCodes :one
+SELECT count(*)
+FROM mfa_recovery_codes
+WHERE user_id = $1
+  AND used_at IS NULL
+`
+
+func (q *Queries) CountUnusedRecoveryCodes(ctx context.Context, userID pgtype.UUID) (int64, error) {
+	row := q.db.QueryRow(ctx, countUnusedRecoveryCodes, userID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const createApiKey = `-- name: CreateApiKey :one
-INSERT INTO api_keys (tenant_id, key_hash, key_prefix, name)
-VALUES ($1, $2, $3, $4)
+INSERT INTO api_keys (tenant_id, key_hash, key_prefix, name, role, expires_at, scopes, allowed_cidrs)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
 RETURNING id, created_at
 `
 
 type CreateApiKeyParams struct {
-	TenantID  pgtype.UUID `db:"tenant_id" json:"tenant_id"`
-	KeyHash   string      `db:"key_hash" json:"key_hash"`
-	KeyPrefix string      `db:"key_prefix" json:"key_prefix"`
-	Name      pgtype.Text `db:"name" json:"name"`
+	TenantID     pgtype.UUID        `db:"tenant_id" json:"tenant_id"`
+	KeyHash      string             `db:"key_hash" json:"key_hash"`
+	KeyPrefix    string             `db:"key_prefix" json:"key_prefix"`
+	Name         pgtype.Text        `db:"name" json:"name"`
+	Role         pgtype.Text        `db:"role" json:"role"`
+	ExpiresAt    pgtype.Timestamptz `db:"expires_at" json:"expires_at"`
+	Scopes       []string           `db:"scopes" json:"scopes"`
+	AllowedCidrs []string           `db:"allowed_cidrs" json:"allowed_cidrs"`
 }
 
 type CreateApiKeyRow struct {
@@ -35,12 +144,151 @@ func (q *Queries) CreateApiKey(ctx context.Context, arg CreateApiKeyParams) (Cre
 		arg.KeyHash,
 		arg.KeyPrefix,
 		arg.Name,
+		arg.Role,
+		arg.ExpiresAt,
+		arg.Scopes,
+		arg.AllowedCidrs,
 	)
 	var i CreateApiKeyRow
 	err := row.Scan(&i.ID, &i.CreatedAt)
 	return i, err
 }
 
+const createOidcLoginState = `-- name: CreateOidcLoginState :exec
+INSERT INTO oidc_login_states (state_hash, tenant_id, code_verifier, nonce, expires_at, link_user_id)
+VALUES ($1, $2, $3, $4, $5, $6)
+`
+
+type CreateOidcLoginStateParams struct {
+	StateHash    string             `db:"state_hash" json:"state_hash"`
+	TenantID     pgtype.UUID        `db:"tenant_id" json:"tenant_id"`
+	CodeVerifier string             `db:"code_verifier" json:"code_verifier"`
+	Nonce        string             `db:"nonce" json:"nonce"`
+	ExpiresAt    pgtype.Timestamptz `db:"expires_at" json:"expires_at"`
+	LinkUserID   pgtype.UUID        `db:"link_user_id" json:"link_user_id"`
+}
+
+func (q *Queries) CreateOidcLoginState(ctx context.Context, arg CreateOidcLoginStateParams) error {
+	_, err := q.db.Exec(ctx, createOidcLoginState,
+		arg.StateHash,
+		arg.TenantID,
+		arg.CodeVerifier,
+		arg.Nonce,
+		arg.ExpiresAt,
+		arg.LinkUserID,
+	)
+	return err
+}
+
+const createRecoveryCode = `-- name: CreateRecoveryCode :exec
+INSERT INTO mfa_recovery_codes (user_id, code_hash)
+VALUES ($1, $2)
+`
+
+type CreateRecoveryCodeParams struct {
+	UserID   pgtype.UUID `db:"user_id" json:"user_id"`
+	CodeHash string      `db:"code_hash" json:"code_hash"`
+}
+
+func (q *Queries) CreateRecoveryCode(ctx context.Context, arg CreateRecoveryCodeParams) error {
+	_, err := q.db.Exec(ctx, createRecoveryCode, arg.UserID, arg.CodeHash)
+	return err
+}
+
+const createRefreshToken = `-- name: CreateRefreshToken :exec
+INSERT INTO refresh_tokens (session_id, token_hash)
+VALUES ($1, $2)
+`
+
+type CreateRefreshTokenParams struct {
+	SessionID pgtype.UUID `db:"session_id" json:"session_id"`
+	TokenHash string      `db:"token_hash" json:"token_hash"`
+}
+
+func (q *Queries) CreateRefreshToken(ctx context.Context, arg CreateRefreshTokenParams) error {
+	_, err := q.db.Exec(ctx, createRefreshToken, arg.SessionID, arg.TokenHash)
+	return err
+}
+
+const createSession = `-- name: CreateSession :one
+INSERT INTO sessions (id, user_id, user_agent, ip_address, expires_at)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING id, user_id, user_agent, ip_address, access_token_id, access_token_expires_at, created_at, last_used_at, expires_at, revoked_at
+`
+
+type CreateSessionParams struct {
+	ID        pgtype.UUID        `db:"id" json:"id"`
+	UserID    pgtype.UUID        `db:"user_id" json:"user_id"`
+	UserAgent pgtype.Text        `db:"user_agent" json:"user_agent"`
+	IpAddress pgtype.Text        `db:"ip_address" json:"ip_address"`
+	ExpiresAt pgtype.Timestamptz `db:"expires_at" json:"expires_at"`
+}
+
+func (q *Queries) CreateSession(ctx context.Context, arg CreateSessionParams) (Session, error) {
+	row := q.db.QueryRow(ctx, createSession,
+		arg.ID,
+		arg.UserID,
+		arg.UserAgent,
+		arg.IpAddress,
+		arg.ExpiresAt,
+	)
+	var i Session
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.UserAgent,
+		&i.IpAddress,
+		&i.AccessTokenID,
+		&i.AccessTokenExpiresAt,
+		&i.CreatedAt,
+		&i.LastUsedAt,
+		&i.ExpiresAt,
+		&i.RevokedAt,
+	)
+	return i, err
+}
+
+const createSigningKey = `-- name: CreateSigningKey :exec
+INSERT INTO signing_keys (kid, public_key, encrypted_private_key)
+VALUES ($1, $2, $3)
+`
+
+type CreateSigningKeyParams struct {
+	Kid                 string `db:"kid" json:"kid"`
+	PublicKey           []byte `db:"public_key" json:"public_key"`
+	EncryptedPrivateKey []byte `db:"encrypted_private_key" json:"encrypted_private_key"`
+}
+
+func (q *Queries) CreateSigningKey(ctx context.Context, arg CreateSigningKeyParams) error {
+	_, err := q.db.Exec(ctx, createSigningKey, arg.Kid, arg.PublicKey, arg.EncryptedPrivateKey)
+	return err
+}
+
+const createTenantDomain = `-- name: CreateTenantDomain :one
+INSERT INTO tenant_domains (tenant_id, domain, verification_token)
+VALUES ($1, $2, $3)
+RETURNING tenant_id, domain, verification_token, created_at, verified_at
+`
+
+type CreateTenantDomainParams struct {
+	TenantID          pgtype.UUID `db:"tenant_id" json:"tenant_id"`
+	Domain            string      `db:"domain" json:"domain"`
+	VerificationToken string      `db:"verification_token" json:"verification_token"`
+}
+
+func (q *Queries) CreateTenantDomain(ctx context.Context, arg CreateTenantDomainParams) (TenantDomain, error) {
+	row := q.db.QueryRow(ctx, createTenantDomain, arg.TenantID, arg.Domain, arg.VerificationToken)
+	var i TenantDomain
+	err := row.Scan(
+		&i.TenantID,
+		&i.Domain,
+		&i.VerificationToken,
+		&i.CreatedAt,
+		&i.VerifiedAt,
+	)
+	return i, err
+}
+
 const createUser = `-- name: CreateUser :one
 INSERT INTO users (email, password_hash, full_name)
 VALUES ($1, $2, $3)
@@ -60,32 +308,397 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (pgtype.
 	return id, err
 }
 
+const createUserIdentity = `-- name: CreateUserIdentity :exec
+INSERT INTO user_identities (issuer, subject, user_id)
+VALUES ($1, $2, $3)
+`
+
+type CreateUserIdentityParams struct {
+	Issuer  string      `db:"issuer" json:"issuer"`
+	Subject string      `db:"subject" json:"subject"`
+	UserID  pgtype.UUID `db:"user_id" json:"user_id"`
+}
+
+func (q *Queries) CreateUserIdentity(ctx context.Context, arg CreateUserIdentityParams) error {
+	_, err := q.db.Exec(ctx, createUserIdentity, arg.Issuer, arg.Subject, arg.UserID)
+	return err
+}
+
+const createUserToken = `-- name: CreateUserToken :exec
+INSERT INTO user_tokens (user_id, purpose, token_hash, expires_at)
+VALUES ($1, $2, $3, $4)
+`
+
+type CreateUserTokenParams struct {
+	UserID    pgtype.UUID        `db:"user_id" json:"user_id"`
+	Purpose   string             `db:"purpose" json:"purpose"`
+	TokenHash string             `db:"token_hash" json:"token_hash"`
+	ExpiresAt pgtype.Timestamptz `db:"expires_at" json:"expires_at"`
+}
+
+func (q *Queries) CreateUserToken(ctx context.Context, arg CreateUserTokenParams) error {
+	_, err := q.db.Exec(ctx, createUserToken,
+		arg.UserID,
+		arg.Purpose,
+		arg.TokenHash,
+		arg.ExpiresAt,
+	)
+	return err
+}
+
+const deleteOidcProvider = `-- name: DeleteOidcProvider :execrows
+DELETE
+FROM oidc_providers
+WHERE tenant_id = $1
+`
+
+func (q *Queries) DeleteOidcProvider(ctx context.Context, tenantID pgtype.UUID) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteOidcProvider, tenantID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const deleteRecoveryCodes = `-- name: DeleteRecoveryCodes :exec
+DELETE
+FROM mfa_recovery_codes
+WHERE user_id = $1
+`
+
+func (q *Queries) DeleteRecoveryCodes(ctx context.Context, userID pgtype.UUID) error {
+	_, err := q.db.Exec(ctx, deleteRecoveryCodes, userID)
+	return err
+}
+
+const deleteTenantDomain = `-- name: DeleteTenantDomain :execrows
+DELETE
+FROM tenant_domains
+WHERE tenant_id = $1
+  AND domain = $2
+`
+
+type DeleteTenantDomainParams struct {
+	TenantID pgtype.UUID `db:"tenant_id" json:"tenant_id"`
+	Domain   string      `db:"domain" json:"domain"`
+}
+
+func (q *Queries) DeleteTenantDomain(ctx context.Context, arg DeleteTenantDomainParams) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteTenantDomain, arg.TenantID, arg.Domain)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const deleteUserMfa = `-- name: DeleteUserMfa :exec
+DELETE
+FROM user_mfa
+WHERE user_id = $1
+`
+
+func (q *Queries) DeleteUserMfa(ctx context.Context, userID pgtype.UUID) error {
+	_, err := q.db.Exec(ctx, deleteUserMfa, userID)
+	return err
+}
+
 const getApiKeyByHash = `-- name: GetApiKeyByHash :one
-SELECT id, tenant_id, revoked, expires_at
-FROM api_keys
-WHERE key_hash = $1
+WITH RECURSIVE path AS (SELECT t.id, t.parent_id, t.status, 0 AS depth
+                        FROM api_keys pk
+                                 JOIN tenants t ON t.id = pk.tenant_id
+                        WHERE pk.key_hash = $1
+                        UNION ALL
+                        SELECT t.id, t.parent_id, t.status, p.depth + 1
+                        FROM tenants t
+                                 JOIN path p ON t.id = p.parent_id)
+SELECT k.id,
+       k.tenant_id,
+       k.role,
+       k.revoked,
+       k.expires_at,
+       k.scopes,
+       k.allowed_cidrs,
+       coalesce((SELECT p.status
+                 FROM path p
+                 WHERE p.status <> 'active'
+                 ORDER BY p.depth
+                 LIMIT 1), t.status) AS tenant_status
+FROM api_keys k
+         JOIN tenants t ON t.id = k.tenant_id
+WHERE k.key_hash = $1
 LIMIT 1
 `
 
 type GetApiKeyByHashRow struct {
-	ID        pgtype.UUID        `db:"id" json:"id"`
-	TenantID  pgtype.UUID        `db:"tenant_id" json:"tenant_id"`
-	Revoked   pgtype.Bool        `db:"revoked" json:"revoked"`
-	ExpiresAt pgtype.Timestamptz `db:"expires_at" json:"expires_at"`
+	ID           pgtype.UUID        `db:"id" json:"id"`
+	TenantID     pgtype.UUID        `db:"tenant_id" json:"tenant_id"`
+	Role         pgtype.Text        `db:"role" json:"role"`
+	Revoked      pgtype.Bool        `db:"revoked" json:"revoked"`
+	ExpiresAt    pgtype.Timestamptz `db:"expires_at" json:"expires_at"`
+	Scopes       []string           `db:"scopes" json:"scopes"`
+	AllowedCidrs []string           `db:"allowed_cidrs" json:"allowed_cidrs"`
+	TenantStatus NullTenantStatus   `db:"tenant_status" json:"tenant_status"`
 }
 
+// The tenant status is that of the nearest inactive tenant on the path to the
+// root, so keys of a tenant under a suspended parent are locked out too.
 func (q *Queries) GetApiKeyByHash(ctx context.Context, keyHash string) (GetApiKeyByHashRow, error) {
 	row := q.db.QueryRow(ctx, getApiKeyByHash, keyHash)
 	var i GetApiKeyByHashRow
 	err := row.Scan(
 		&i.ID,
 		&i.TenantID,
+		&i.Role,
+		&i.Revoked,
+		&i.ExpiresAt,
+		&i.Scopes,
+		&i.AllowedCidrs,
+		&i.TenantStatus,
+	)
+	return i, err
+}
+
+const getApiKeyForUpdate = `-- name: GetApiKeyForUpdate :one
+SELECT id, tenant_id, key_hash, key_prefix, name, created_at, expires_at, revoked, last_used_at, role, scopes, allowed_cidrs, rotated_to
+FROM api_keys
+WHERE id = $1
+  AND tenant_id = $2
+    FOR UPDATE
+`
+
+type GetApiKeyForUpdateParams struct {
+	ID       pgtype.UUID `db:"id" json:"id"`
+	TenantID pgtype.UUID `db:"tenant_id" json:"tenant_id"`
+}
+
+func (q *Queries) GetApiKeyForUpdate(ctx context.Context, arg GetApiKeyForUpdateParams) (ApiKey, error) {
+	row := q.db.QueryRow(ctx, getApiKeyForUpdate, arg.ID, arg.TenantID)
+	var i ApiKey
+	err := row.Scan(
+		&i.ID,
+		&i.TenantID,
+		&i.KeyHash,
+		&i.KeyPrefix,
+		&i.Name,
+		&i.CreatedAt,
+		&i.ExpiresAt,
 		&i.Revoked,
+		&i.LastUsedAt,
+		&i.Role,
+		&i.Scopes,
+		&i.AllowedCidrs,
+		&i.RotatedTo,
+	)
+	return i, err
+}
+
+const getDirectTenantMembership = `-- name: GetDirectTenantMembership :one
+SELECT tenant_id, role
+FROM tenant_members
+WHERE tenant_id = $1
+  AND user_id = $2
+`
+
+type GetDirectTenantMembershipParams struct {
+	TenantID pgtype.UUID `db:"tenant_id" json:"tenant_id"`
+	UserID   pgtype.UUID `db:"user_id" json:"user_id"`
+}
+
+type GetDirectTenantMembershipRow struct {
+	TenantID pgtype.UUID `db:"tenant_id" json:"tenant_id"`
+	Role     pgtype.Text `db:"role" json:"role"`
+}
+
+// Unlike GetTenantMembership, memberships of ancestor tenants do not count.
+func (q *Queries) GetDirectTenantMembership(ctx context.Context, arg GetDirectTenantMembershipParams) (GetDirectTenantMembershipRow, error) {
+	row := q.db.QueryRow(ctx, getDirectTenantMembership, arg.TenantID, arg.UserID)
+	var i GetDirectTenantMembershipRow
+	err := row.Scan(&i.TenantID, &i.Role)
+	return i, err
+}
+
+const getOidcProvider = `-- name: GetOidcProvider :one
+SELECT p.tenant_id,
+       p.issuer,
+       p.client_id,
+       p.encrypted_client_secret,
+       p.allowed_domains,
+       p.default_role,
+       p.enabled,
+       p.created_at,
+       p.updated_at
+FROM oidc_providers p
+WHERE p.tenant_id = $1
+`
+
+func (q *Queries) GetOidcProvider(ctx context.Context, tenantID pgtype.UUID) (OidcProvider, error) {
+	row := q.db.QueryRow(ctx, getOidcProvider, tenantID)
+	var i OidcProvider
+	err := row.Scan(
+		&i.TenantID,
+		&i.Issuer,
+		&i.ClientID,
+		&i.EncryptedClientSecret,
+		&i.AllowedDomains,
+		&i.DefaultRole,
+		&i.Enabled,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getOidcProviderBySlug = `-- name: GetOidcProviderBySlug :one
+SELECT p.tenant_id,
+       p.issuer,
+       p.client_id,
+       p.enabled
+FROM oidc_providers p
+         JOIN tenants t ON t.id = p.tenant_id
+WHERE t.slug = $1
+`
+
+type GetOidcProviderBySlugRow struct {
+	TenantID pgtype.UUID `db:"tenant_id" json:"tenant_id"`
+	Issuer   string      `db:"issuer" json:"issuer"`
+	ClientID string      `db:"client_id" json:"client_id"`
+	Enabled  bool        `db:"enabled" json:"enabled"`
+}
+
+func (q *Queries) GetOidcProviderBySlug(ctx context.Context, slug string) (GetOidcProviderBySlugRow, error) {
+	row := q.db.QueryRow(ctx, getOidcProviderBySlug, slug)
+	var i GetOidcProviderBySlugRow
+	err := row.Scan(
+		&i.TenantID,
+		&i.Issuer,
+		&i.ClientID,
+		&i.Enabled,
+	)
+	return i, err
+}
+
+const getRefreshTokenForUpdate = `-- name: GetRefreshTokenForUpdate :one
+SELECT rt.id,
+       rt.used_at,
+       s.id AS session_id,
+       s.user_id,
+       s.expires_at,
+       s.revoked_at,
+       s.access_token_id,
+       s.access_token_expires_at,
+       u.email
+FROM refresh_tokens rt
+         JOIN sessions s ON s.id = rt.session_id
+         JOIN users u ON u.id = s.user_id
+WHERE rt.token_hash = $1
+    FOR UPDATE OF rt, s
+`
+
+type GetRefreshTokenForUpdateRow struct {
+	ID                   pgtype.UUID        `db:"id" json:"id"`
+	UsedAt               pgtype.Timestamptz `db:"used_at" json:"used_at"`
+	SessionID            pgtype.UUID        `db:"session_id" json:"session_id"`
+	UserID               pgtype.UUID        `db:"user_id" json:"user_id"`
+	ExpiresAt            pgtype.Timestamptz `db:"expires_at" json:"expires_at"`
+	RevokedAt            pgtype.Timestamptz `db:"revoked_at" json:"revoked_at"`
+	AccessTokenID        pgtype.UUID        `db:"access_token_id" json:"access_token_id"`
+	AccessTokenExpiresAt pgtype.Timestamptz `db:"access_token_expires_at" json:"access_token_expires_at"`
+	Email                string             `db:"email" json:"email"`
+}
+
+func (q *Queries) GetRefreshTokenForUpdate(ctx context.Context, tokenHash string) (GetRefreshTokenForUpdateRow, error) {
+	row := q.db.QueryRow(ctx, getRefreshTokenForUpdate, tokenHash)
+	var i GetRefreshTokenForUpdateRow
+	err := row.Scan(
+		&i.ID,
+		&i.UsedAt,
+		&i.SessionID,
+		&i.UserID,
 		&i.ExpiresAt,
+		&i.RevokedAt,
+		&i.AccessTokenID,
+		&i.AccessTokenExpiresAt,
+		&i.Email,
+	)
+	return i, err
+}
+
+const getTenantDomain = `-- name: GetTenantDomain :one
+SELECT tenant_id, domain, verification_token, created_at, verified_at
+FROM tenant_domains
+WHERE tenant_id = $1
+  AND domain = $2
+`
+
+type GetTenantDomainParams struct {
+	TenantID pgtype.UUID `db:"tenant_id" json:"tenant_id"`
+	Domain   string      `db:"domain" json:"domain"`
+}
+
+func (q *Queries) GetTenantDomain(ctx context.Context, arg GetTenantDomainParams) (TenantDomain, error) {
+	row := q.db.QueryRow(ctx, getTenantDomain, arg.TenantID, arg.Domain)
+	var i TenantDomain
+	err := row.Scan(
+		&i.TenantID,
+		&i.Domain,
+		&i.VerificationToken,
+		&i.CreatedAt,
+		&i.VerifiedAt,
 	)
 	return i, err
 }
 
+const getTenantMembership = `-- name: GetTenantMembership :one
+WITH RECURSIVE ancestors AS (SELECT id, parent_id, status, 0 AS depth
+                             FROM tenants
+                             WHERE id = $2
+                             UNION ALL
+                             SELECT t.id, t.parent_id, t.status, a.depth + 1
+                             FROM tenants t
+                                      JOIN ancestors a ON t.id = a.parent_id)
+SELECT t.id AS tenant_id,
+       m.role,
+       coalesce((SELECT a.status
+                 FROM ancestors a
+                 WHERE a.status <> 'active'
+                 ORDER BY a.depth
+                 LIMIT 1), t.status) AS tenant_status
+FROM tenants t
+         JOIN LATERAL (SELECT tm.role
+                       FROM ancestors a
+                                JOIN tenant_members tm ON tm.tenant_id = a.id
+                       WHERE tm.user_id = $1
+                       ORDER BY CASE tm.role
+                                    WHEN 'owner' THEN 4
+                                    WHEN 'admin' THEN 3
+                                    WHEN 'member' THEN 2
+                                    WHEN 'viewer' THEN 1
+                                    ELSE 0 END DESC
+                       LIMIT 1) m ON true
+WHERE t.id = $2
+`
+
+type GetTenantMembershipParams struct {
+	UserID   pgtype.UUID `db:"user_id" json:"user_id"`
+	TenantID pgtype.UUID `db:"tenant_id" json:"tenant_id"`
+}
+
+type GetTenantMembershipRow struct {
+	TenantID     pgtype.UUID      `db:"tenant_id" json:"tenant_id"`
+	Role         pgtype.Text      `db:"role" json:"role"`
+	TenantStatus NullTenantStatus `db:"tenant_status" json:"tenant_status"`
+}
+
+// Members of an ancestor tenant are members of all of its descendants; the
+// highest role held anywhere along the path applies. Likewise the status of
+// the nearest inactive tenant on the path is the status of the tenant.
+func (q *Queries) GetTenantMembership(ctx context.Context, arg GetTenantMembershipParams) (GetTenantMembershipRow, error) {
+	row := q.db.QueryRow(ctx, getTenantMembership, arg.UserID, arg.TenantID)
+	var i GetTenantMembershipRow
+	err := row.Scan(&i.TenantID, &i.Role, &i.TenantStatus)
+	return i, err
+}
+
 const getUserByEmail = `-- name: GetUserByEmail :one
 SELECT id, email, full_name
 FROM users
@@ -124,58 +737,794 @@ func (q *Queries) GetUserByEmailWithPassword(ctx context.Context, email string)
 	return i, err
 }
 
-const listApiKeys = `-- name: ListApiKeys :many
-SELECT id, name, key_prefix, created_at, last_used_at, revoked, expires_at
-FROM api_keys
-WHERE tenant_id = $1
-ORDER BY created_at DESC
+const getUserByID = `-- name: GetUserByID :one
+SELECT id, email, full_name, email_verified_at
+FROM users
+WHERE id = $1
 `
 
-type ListApiKeysRow struct {
-	ID         pgtype.UUID        `db:"id" json:"id"`
-	Name       pgtype.Text        `db:"name" json:"name"`
-	KeyPrefix  string             `db:"key_prefix" json:"key_prefix"`
-	CreatedAt  pgtype.Timestamptz `db:"created_at" json:"created_at"`
-	LastUsedAt pgtype.Timestamptz `db:"last_used_at" json:"last_used_at"`
-	Revoked    pgtype.Bool        `db:"revoked" json:"revoked"`
-	ExpiresAt  pgtype.Timestamptz `db:"expires_at" json:"expires_at"`
+type GetUserByIDRow struct {
+	ID              pgtype.UUID        `db:"id" json:"id"`
+	Email           string             `db:"email" json:"email"`
+	FullName        pgtype.Text        `db:"full_name" json:"full_name"`
+	EmailVerifiedAt pgtype.Timestamptz `db:"email_verified_at" json:"email_verified_at"`
 }
 
-func (q *Queries) ListApiKeys(ctx context.Context, tenantID pgtype.UUID) ([]ListApiKeysRow, error) {
-	rows, err := q.db.Query(ctx, listApiKeys, tenantID)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	var items []ListApiKeysRow
-	for rows.Next() {
-		var i ListApiKeysRow
-		if err := rows.Scan(
-			&i.ID,
-			&i.Name,
-			&i.KeyPrefix,
-			&i.CreatedAt,
-			&i.LastUsedAt,
-			&i.Revoked,
-			&i.ExpiresAt,
-		); err != nil {
-			return nil, err
-		}
-		items = append(items, i)
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-	return items, nil
+func (q *Queries) GetUserByID(ctx context.Context, id pgtype.UUID) (GetUserByIDRow, error) {
+	row := q.db.QueryRow(ctx, getUserByID, id)
+	var i GetUserByIDRow
+	err := row.Scan(
+		&i.ID,
+		&i.Email,
+		&i.FullName,
+		&i.EmailVerifiedAt,
+	)
+	return i, err
 }
 
-const revokeApiKey = `-- name: RevokeApiKey :exec
-UPDATE api_keys
+const getUserIdentity = `-- name: GetUserIdentity :one
+SELECT ui.user_id, u.email
+FROM user_identities ui
+         JOIN users u ON u.id = ui.user_id
+WHERE ui.issuer = $1
+  AND ui.subject = $2
+`
+
+type GetUserIdentityParams struct {
+	Issuer  string `db:"issuer" json:"issuer"`
+	Subject string `db:"subject" json:"subject"`
+}
+
+type GetUserIdentityRow struct {
+	UserID pgtype.UUID `db:"user_id" json:"user_id"`
+	Email  string      `db:"email" json:"email"`
+}
+
+func (q *Queries) GetUserIdentity(ctx context.Context, arg GetUserIdentityParams) (GetUserIdentityRow, error) {
+	row := q.db.QueryRow(ctx, getUserIdentity, arg.Issuer, arg.Subject)
+	var i GetUserIdentityRow
+	err := row.Scan(&i.UserID, &i.Email)
+	return i, err
+}
+
+const getUserMfa = `-- name: GetUserMfa :one
+SELECT user_id, encrypted_secret, confirmed_at, last_used_step, created_at
+FROM user_mfa
+WHERE user_id = $1
+`
+
+func (q *Queries) GetUserMfa(ctx context.Context, userID pgtype.UUID) (UserMfa, error) {
+	row := q.db.QueryRow(ctx, getUserMfa, userID)
+	var i UserMfa
+	err := row.Scan(
+		&i.UserID,
+		&i.EncryptedSecret,
+		&i.ConfirmedAt,
+		&i.LastUsedStep,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getUserMfaForUpdate = `-- name: GetUserMfaForUpdate :one
+SELECT user_id, encrypted_secret, confirmed_at, last_used_step, created_at
+FROM user_mfa
+WHERE user_id = $1
+    FOR UPDATE
+`
+
+func (q *Queries) GetUserMfaForUpdate(ctx context.Context, userID pgtype.UUID) (UserMfa, error) {
+	row := q.db.QueryRow(ctx, getUserMfaForUpdate, userID)
+	var i UserMfa
+	err := row.Scan(
+		&i.UserID,
+		&i.EncryptedSecret,
+		&i.ConfirmedAt,
+		&i.LastUsedStep,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getUserTokenForUpdate = `-- name: GetUserTokenForUpdate :one
+SELECT ut.id,
+       ut.user_id,
+       ut.expires_at,
+       ut.used_at,
+       ut.attempts,
+       u.email
+FROM user_tokens ut
+         JOIN users u ON u.id = ut.user_id
+WHERE ut.token_hash = $1
+  AND ut.purpose = $2
+    FOR UPDATE OF ut
+`
+
+type GetUserTokenForUpdateParams struct {
+	TokenHash string `db:"token_hash" json:"token_hash"`
+	Purpose   string `db:"purpose" json:"purpose"`
+}
+
+type GetUserTokenForUpdateRow struct {
+	ID        pgtype.UUID        `db:"id" json:"id"`
+	UserID    pgtype.UUID        `db:"user_id" json:"user_id"`
+	ExpiresAt pgtype.Timestamptz `db:"expires_at" json:"expires_at"`
+	UsedAt    pgtype.Timestamptz `db:"used_at" json:"used_at"`
+	Attempts  int32              `db:"attempts" json:"attempts"`
+	Email     string             `db:"email" json:"email"`
+}
+
+func (q *Queries) GetUserTokenForUpdate(ctx context.Context, arg GetUserTokenForUpdateParams) (GetUserTokenForUpdateRow, error) {
+	row := q.db.QueryRow(ctx, getUserTokenForUpdate, arg.TokenHash, arg.Purpose)
+	var i GetUserTokenForUpdateRow
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.ExpiresAt,
+		&i.UsedAt,
+		&i.Attempts,
+		&i.Email,
+	)
+	return i, err
+}
+
+const getVerifiedTenantMemberByEmail = `-- name: GetVerifiedTenantMemberByEmail :one
+SELECT u.id
+FROM users u
+         JOIN tenant_members tm ON tm.user_id = u.id
+WHERE u.email = $1
+  AND tm.tenant_id = $2
+  AND u.email_verified_at IS NOT NULL
+`
+
+type GetVerifiedTenantMemberByEmailParams struct {
+	Email    string      `db:"email" json:"email"`
+	TenantID pgtype.UUID `db:"tenant_id" json:"tenant_id"`
+}
+
+func (q *Queries) GetVerifiedTenantMemberByEmail(ctx context.Context, arg GetVerifiedTenantMemberByEmailParams) (pgtype.UUID, error) {
+	row := q.db.QueryRow(ctx, getVerifiedTenantMemberByEmail, arg.Email, arg.TenantID)
+	var id pgtype.UUID
+	err := row.Scan(&id)
+	return id, err
+}
+
+const incrementUserTokenAttempts = `-- name: IncrementUserTokenAttempts :one
+UPDATE user_tokens
+SET attempts = attempts + 1
+WHERE id = $1
+RETURNING attempts
+`
+
+func (q *Queries) IncrementUserTokenAttempts(ctx context.Context, id pgtype.UUID) (int32, error) {
+	row := q.db.QueryRow(ctx, incrementUserTokenAttempts, id)
+	var attempts int32
+	err := row.Scan(&attempts)
+	return attempts, err
+}
+
+const invalidateUserTokens = `-- name: InvalidateUserTokens :exec
+UPDATE user_tokens
+SET used_at = now()
+WHERE user_id = $1
+  AND purpose = $2
+  AND used_at IS NULL
+`
+
+type InvalidateUserTokensParams struct {
+	UserID  pgtype.UUID `db:"user_id" json:"user_id"`
+	Purpose string      `db:"purpose" json:"purpose"`
+}
+
+func (q *Queries) InvalidateUserTokens(ctx context.Context, arg InvalidateUserTokensParams) error {
+	_, err := q.db.Exec(ctx, invalidateUserTokens, arg.UserID, arg.Purpose)
+	return err
+}
+
+const listActiveSessions = `-- name: ListActiveSessions :many
+SELECT id, user_id, user_agent, ip_address, access_token_id, access_token_expires_at, created_at, last_used_at, expires_at, revoked_at
+FROM sessions
+WHERE user_id = $1
+  AND revoked_at IS NULL
+  AND expires_at > now()
+ORDER BY last_used_at DESC
+`
+
+func (q *Queries) ListActiveSessions(ctx context.Context, userID pgtype.UUID) ([]Session, error) {
+	rows, err := q.db.Query(ctx, listActiveSessions, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Session
+	for rows.Next() {
+		var i Session
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.UserAgent,
+			&i.IpAddress,
+			&i.AccessTokenID,
+			&i.AccessTokenExpiresAt,
+			&i.CreatedAt,
+			&i.LastUsedAt,
+			&i.ExpiresAt,
+			&i.RevokedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listApiKeys = `-- name: ListApiKeys :many
+SELECT id, name, key_prefix, role, created_at, last_used_at, revoked, expires_at, scopes, allowed_cidrs, rotated_to
+FROM api_keys
+WHERE tenant_id = $1
+ORDER BY created_at DESC
+`
+
+type ListApiKeysRow struct {
+	ID           pgtype.UUID        `db:"id" json:"id"`
+	Name         pgtype.Text        `db:"name" json:"name"`
+	KeyPrefix    string             `db:"key_prefix" json:"key_prefix"`
+	Role         pgtype.Text        `db:"role" json:"role"`
+	CreatedAt    pgtype.Timestamptz `db:"created_at" json:"created_at"`
+	LastUsedAt   pgtype.Timestamptz `db:"last_used_at" json:"last_used_at"`
+	Revoked      pgtype.Bool        `db:"revoked" json:"revoked"`
+	ExpiresAt    pgtype.Timestamptz `db:"expires_at" json:"expires_at"`
+	Scopes       []string           `db:"scopes" json:"scopes"`
+	AllowedCidrs []string           `db:"allowed_cidrs" json:"allowed_cidrs"`
+	RotatedTo    pgtype.UUID        `db:"rotated_to" json:"rotated_to"`
+}
+
+func (q *Queries) ListApiKeys(ctx context.Context, tenantID pgtype.UUID) ([]ListApiKeysRow, error) {
+	rows, err := q.db.Query(ctx, listApiKeys, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListApiKeysRow
+	for rows.Next() {
+		var i ListApiKeysRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.KeyPrefix,
+			&i.Role,
+			&i.CreatedAt,
+			&i.LastUsedAt,
+			&i.Revoked,
+			&i.ExpiresAt,
+			&i.Scopes,
+			&i.AllowedCidrs,
+			&i.RotatedTo,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listSigningKeys = `-- name: ListSigningKeys :many
+SELECT kid, algorithm, public_key, encrypted_private_key, created_at, retired_at, expires_at
+FROM signing_keys
+WHERE expires_at IS NULL
+   OR expires_at > now()
+ORDER BY created_at DESC
+`
+
+func (q *Queries) ListSigningKeys(ctx context.Context) ([]SigningKey, error) {
+	rows, err := q.db.Query(ctx, listSigningKeys)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SigningKey
+	for rows.Next() {
+		var i SigningKey
+		if err := rows.Scan(
+			&i.Kid,
+			&i.Algorithm,
+			&i.PublicKey,
+			&i.EncryptedPrivateKey,
+			&i.CreatedAt,
+			&i.RetiredAt,
+			&i.ExpiresAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTenantDomains = `-- name: ListTenantDomains :many
+SELECT tenant_id, domain, verification_token, created_at, verified_at
+FROM tenant_domains
+WHERE tenant_id = $1
+ORDER BY domain
+`
+
+func (q *Queries) ListTenantDomains(ctx context.Context, tenantID pgtype.UUID) ([]TenantDomain, error) {
+	rows, err := q.db.Query(ctx, listTenantDomains, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []TenantDomain
+	for rows.Next() {
+		var i TenantDomain
+		if err := rows.Scan(
+			&i.TenantID,
+			&i.Domain,
+			&i.VerificationToken,
+			&i.CreatedAt,
+			&i.VerifiedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTenantMembershipsByUserID = `-- name: ListTenantMembershipsByUserID :many
+WITH RECURSIVE path AS (SELECT pm.tenant_id AS member_tenant_id, t.id, t.parent_id, t.status, 0 AS depth
+                        FROM tenant_members pm
+                                 JOIN tenants t ON t.id = pm.tenant_id
+                        WHERE pm.user_id = $1
+                        UNION ALL
+                        SELECT p.member_tenant_id, t.id, t.parent_id, t.status, p.depth + 1
+                        FROM tenants t
+                                 JOIN path p ON t.id = p.parent_id)
+SELECT tm.tenant_id,
+       tm.role,
+       coalesce((SELECT p.status
+                 FROM path p
+                 WHERE p.member_tenant_id = tm.tenant_id
+                   AND p.status <> 'active'
+                 ORDER BY p.depth
+                 LIMIT 1), t.status) AS tenant_status
+FROM tenant_members tm
+         JOIN tenants t ON t.id = tm.tenant_id
+WHERE tm.user_id = $1
+ORDER BY tm.joined_at
+LIMIT $2
+`
+
+type ListTenantMembershipsByUserIDParams struct {
+	UserID pgtype.UUID `db:"user_id" json:"user_id"`
+	Limit  int32       `db:"limit" json:"limit"`
+}
+
+type ListTenantMembershipsByUserIDRow struct {
+	TenantID     pgtype.UUID      `db:"tenant_id" json:"tenant_id"`
+	Role         pgtype.Text      `db:"role" json:"role"`
+	TenantStatus NullTenantStatus `db:"tenant_status" json:"tenant_status"`
+}
+
+// As in GetTenantMembership, an inactive ancestor makes the tenant inactive.
+func (q *Queries) ListTenantMembershipsByUserID(ctx context.Context, arg ListTenantMembershipsByUserIDParams) ([]ListTenantMembershipsByUserIDRow, error) {
+	rows, err := q.db.Query(ctx, listTenantMembershipsByUserID, arg.UserID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListTenantMembershipsByUserIDRow
+	for rows.Next() {
+		var i ListTenantMembershipsByUserIDRow
+		if err := rows.Scan(&i.TenantID, &i.Role, &i.TenantStatus); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listVerifiedTenantDomains = `-- name: ListVerifiedTenantDomains :many
+SELECT domain
+FROM tenant_domains
+WHERE tenant_id = $1
+  AND verified_at IS NOT NULL
+`
+
+func (q *Queries) ListVerifiedTenantDomains(ctx context.Context, tenantID pgtype.UUID) ([]string, error) {
+	rows, err := q.db.Query(ctx, listVerifiedTenantDomains, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var domain string
+		if err := rows.Scan(&domain); err != nil {
+			return nil, err
+		}
+		items = append(items, domain)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const lockSigningKeys = `-- name: LockSigningKeys :exec
+SELECT pg_advisory_xact_lock(hashtext('signing_keys'))
+`
+
+func (q *Queries) LockSigningKeys(ctx context.Context) error {
+	_, err := q.db.Exec(ctx, lockSigningKeys)
+	return err
+}
+
+const markApiKeyRotated = `-- name: MarkApiKeyRotated :exec
+UPDATE api_keys
+SET rotated_to = $1,
+    expires_at = $2
+WHERE id = $3
+`
+
+type MarkApiKeyRotatedParams struct {
+	RotatedTo pgtype.UUID        `db:"rotated_to" json:"rotated_to"`
+	ExpiresAt pgtype.Timestamptz `db:"expires_at" json:"expires_at"`
+	ID        pgtype.UUID        `db:"id" json:"id"`
+}
+
+func (q *Queries) MarkApiKeyRotated(ctx context.Context, arg MarkApiKeyRotatedParams) error {
+	_, err := q.db.Exec(ctx, markApiKeyRotated, arg.RotatedTo, arg.ExpiresAt, arg.ID)
+	return err
+}
+
+const markRefreshTokenUsed = `-- name: MarkRefreshTokenUsed :exec
+UPDATE refresh_tokens
+SET used_at = now()
+WHERE id = $1
+`
+
+func (q *Queries) MarkRefreshTokenUsed(ctx context.Context, id pgtype.UUID) error {
+	_, err := q.db.Exec(ctx, markRefreshTokenUsed, id)
+	return err
+}
+
+const markTenantDomainVerified = `-- name: MarkTenantDomainVerified :one
+UPDATE tenant_domains
+SET verified_at = coalesce(verified_at, now())
+WHERE tenant_id = $1
+  AND domain = $2
+RETURNING tenant_id, domain, verification_token, created_at, verified_at
+`
+
+type MarkTenantDomainVerifiedParams struct {
+	TenantID pgtype.UUID `db:"tenant_id" json:"tenant_id"`
+	Domain   string      `db:"domain" json:"domain"`
+}
+
+func (q *Queries) MarkTenantDomainVerified(ctx context.Context, arg MarkTenantDomainVerifiedParams) (TenantDomain, error) {
+	row := q.db.QueryRow(ctx, markTenantDomainVerified, arg.TenantID, arg.Domain)
+	var i TenantDomain
+	err := row.Scan(
+		&i.TenantID,
+		&i.Domain,
+		&i.VerificationToken,
+		&i.CreatedAt,
+		&i.VerifiedAt,
+	)
+	return i, err
+}
+
+const markUserEmailVerified = `-- name: MarkUserEmailVerified :exec
+UPDATE users
+SET email_verified_at = coalesce(email_verified_at, now())
+WHERE id = $1
+`
+
+func (q *Queries) MarkUserEmailVerified(ctx context.Context, id pgtype.UUID) error {
+	_, err := q.db.Exec(ctx, markUserEmailVerified, id)
+	return err
+}
+
+const markUserTokenUsed = `-- name: MarkUserTokenUsed :exec
+UPDATE user_tokens
+SET used_at = now()
+WHERE id = $1
+`
+
+func (q *Queries) MarkUserTokenUsed(ctx context.Context, id pgtype.UUID) error {
+	_, err := q.db.Exec(ctx, markUserTokenUsed, id)
+	return err
+}
+
+const retireSigningKeys = `-- name: RetireSigningKeys :exec
+UPDATE signing_keys
+SET retired_at = now(),
+    expires_at = $1
+WHERE kid <> $2
+  AND retired_at IS NULL
+`
+
+type RetireSigningKeysParams struct {
+	ExpiresAt pgtype.Timestamptz `db:"expires_at" json:"expires_at"`
+	ActiveKid string             `db:"active_kid" json:"active_kid"`
+}
+
+func (q *Queries) RetireSigningKeys(ctx context.Context, arg RetireSigningKeysParams) error {
+	_, err := q.db.Exec(ctx, retireSigningKeys, arg.ExpiresAt, arg.ActiveKid)
+	return err
+}
+
+const revokeApiKey = `-- name: RevokeApiKey :one
+UPDATE api_keys
 SET revoked = true
 WHERE id = $1
+  AND tenant_id = $2
+RETURNING id, tenant_id
+`
+
+type RevokeApiKeyParams struct {
+	ID       pgtype.UUID `db:"id" json:"id"`
+	TenantID pgtype.UUID `db:"tenant_id" json:"tenant_id"`
+}
+
+type RevokeApiKeyRow struct {
+	ID       pgtype.UUID `db:"id" json:"id"`
+	TenantID pgtype.UUID `db:"tenant_id" json:"tenant_id"`
+}
+
+func (q *Queries) RevokeApiKey(ctx context.Context, arg RevokeApiKeyParams) (RevokeApiKeyRow, error) {
+	row := q.db.QueryRow(ctx, revokeApiKey, arg.ID, arg.TenantID)
+	var i RevokeApiKeyRow
+	err := row.Scan(&i.ID, &i.TenantID)
+	return i, err
+}
+
+const revokeSession = `-- name: RevokeSession :one
+UPDATE sessions
+SET revoked_at = now()
+WHERE id = $1
+  AND user_id = $2
+  AND revoked_at IS NULL
+RETURNING id, user_id, user_agent, ip_address, access_token_id, access_token_expires_at, created_at, last_used_at, expires_at, revoked_at
 `
 
-func (q *Queries) RevokeApiKey(ctx context.Context, id pgtype.UUID) error {
-	_, err := q.db.Exec(ctx, revokeApiKey, id)
+type RevokeSessionParams struct {
+	ID     pgtype.UUID `db:"id" json:"id"`
+	UserID pgtype.UUID `db:"user_id" json:"user_id"`
+}
+
+func (q *Queries) RevokeSession(ctx context.Context, arg RevokeSessionParams) (Session, error) {
+	row := q.db.QueryRow(ctx, revokeSession, arg.ID, arg.UserID)
+	var i Session
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.UserAgent,
+		&i.IpAddress,
+		&i.AccessTokenID,
+		&i.AccessTokenExpiresAt,
+		&i.CreatedAt,
+		&i.LastUsedAt,
+		&i.ExpiresAt,
+		&i.RevokedAt,
+	)
+	return i, err
+}
+
+const revokeUserSessions = `-- name: RevokeUserSessions :many
+UPDATE sessions
+SET revoked_at = now()
+WHERE user_id = $1
+  AND revoked_at IS NULL
+RETURNING id, user_id, user_agent, ip_address, access_token_id, access_token_expires_at, created_at, last_used_at, expires_at, revoked_at
+`
+
+func (q *Queries) RevokeUserSessions(ctx context.Context, userID pgtype.UUID) ([]Session, error) {
+	rows, err := q.db.Query(ctx, revokeUserSessions, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Session
+	for rows.Next() {
+		var i Session
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.UserAgent,
+			&i.IpAddress,
+			&i.AccessTokenID,
+			&i.AccessTokenExpiresAt,
+			&i.CreatedAt,
+			&i.LastUsedAt,
+			&i.ExpiresAt,
+			&i.RevokedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const rotateSessionAccessToken = `-- name: RotateSessionAccessToken :exec
+UPDATE sessions
+SET access_token_id         = $2,
+    access_token_expires_at = $3,
+    user_agent              = coalesce($4, user_agent),
+    ip_address              = coalesce($5, ip_address),
+    last_used_at            = now()
+WHERE id = $1
+`
+
+type RotateSessionAccessTokenParams struct {
+	ID                   pgtype.UUID        `db:"id" json:"id"`
+	AccessTokenID        pgtype.UUID        `db:"access_token_id" json:"access_token_id"`
+	AccessTokenExpiresAt pgtype.Timestamptz `db:"access_token_expires_at" json:"access_token_expires_at"`
+	UserAgent            pgtype.Text        `db:"user_agent" json:"user_agent"`
+	IpAddress            pgtype.Text        `db:"ip_address" json:"ip_address"`
+}
+
+func (q *Queries) RotateSessionAccessToken(ctx context.Context, arg RotateSessionAccessTokenParams) error {
+	_, err := q.db.Exec(ctx, rotateSessionAccessToken,
+		arg.ID,
+		arg.AccessTokenID,
+		arg.AccessTokenExpiresAt,
+		arg.UserAgent,
+		arg.IpAddress,
+	)
+	return err
+}
+
+const touchApiKey = `-- name: TouchApiKey :exec
+UPDATE api_keys
+SET last_used_at = now()
+WHERE id = $1
+  AND (last_used_at IS NULL OR last_used_at < now() - interval '1 minute')
+`
+
+func (q *Queries) TouchApiKey(ctx context.Context, id pgtype.UUID) error {
+	_, err := q.db.Exec(ctx, touchApiKey, id)
 	return err
 }
+
+const updateUserMfaLastUsedStep = `-- name: UpdateUserMfaLastUsedStep :exec
+UPDATE user_mfa
+SET last_used_step = $2
+WHERE user_id = $1
+`
+
+type UpdateUserMfaLastUsedStepParams struct {
+	UserID       pgtype.UUID `db:"user_id" json:"user_id"`
+	LastUsedStep int64       `db:"last_used_step" json:"last_used_step"`
+}
+
+func (q *Queries) UpdateUserMfaLastUsedStep(ctx context.Context, arg UpdateUserMfaLastUsedStepParams) error {
+	_, err := q.db.Exec(ctx, updateUserMfaLastUsedStep, arg.UserID, arg.LastUsedStep)
+	return err
+}
+
+const updateUserPassword = `-- name: UpdateUserPassword :exec
+UPDATE users
+SET password_hash = $2
+WHERE id = $1
+`
+
+type UpdateUserPasswordParams struct {
+	ID           pgtype.UUID `db:"id" json:"id"`
+	PasswordHash string      `db:"password_hash" json:"password_hash"`
+}
+
+func (q *Queries) UpdateUserPassword(ctx context.Context, arg UpdateUserPasswordParams) error {
+	_, err := q.db.Exec(ctx, updateUserPassword, arg.ID, arg.PasswordHash)
+	return err
+}
+
+const upsertOidcProvider = `-- name: UpsertOidcProvider :one
+INSERT INTO oidc_providers (tenant_id, issuer, client_id, encrypted_client_secret, allowed_domains, default_role, enabled)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+ON CONFLICT (tenant_id) DO UPDATE
+    SET issuer                  = excluded.issuer,
+        client_id               = excluded.client_id,
+        encrypted_client_secret = excluded.encrypted_client_secret,
+        allowed_domains         = excluded.allowed_domains,
+        default_role            = excluded.default_role,
+        enabled                 = excluded.enabled,
+        updated_at              = now()
+RETURNING tenant_id, issuer, client_id, encrypted_client_secret, allowed_domains, default_role, enabled, created_at, updated_at
+`
+
+type UpsertOidcProviderParams struct {
+	TenantID              pgtype.UUID `db:"tenant_id" json:"tenant_id"`
+	Issuer                string      `db:"issuer" json:"issuer"`
+	ClientID              string      `db:"client_id" json:"client_id"`
+	EncryptedClientSecret []byte      `db:"encrypted_client_secret" json:"encrypted_client_secret"`
+	AllowedDomains        []string    `db:"allowed_domains" json:"allowed_domains"`
+	DefaultRole           string      `db:"default_role" json:"default_role"`
+	Enabled               bool        `db:"enabled" json:"enabled"`
+}
+
+func (q *Queries) UpsertOidcProvider(ctx context.Context, arg UpsertOidcProviderParams) (OidcProvider, error) {
+	row := q.db.QueryRow(ctx, upsertOidcProvider,
+		arg.TenantID,
+		arg.Issuer,
+		arg.ClientID,
+		arg.EncryptedClientSecret,
+		arg.AllowedDomains,
+		arg.DefaultRole,
+		arg.Enabled,
+	)
+	var i OidcProvider
+	err := row.Scan(
+		&i.TenantID,
+		&i.Issuer,
+		&i.ClientID,
+		&i.EncryptedClientSecret,
+		&i.AllowedDomains,
+		&i.DefaultRole,
+		&i.Enabled,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const upsertPendingUserMfa = `-- name: UpsertPendingUserMfa :exec
+INSERT INTO user_mfa (user_id, encrypted_secret)
+VALUES ($1, $2)
+ON CONFLICT (user_id) DO UPDATE
+    SET encrypted_secret = excluded.encrypted_secret,
+        last_used_step   = 0,
+        created_at       = now()
+WHERE user_mfa.confirmed_at IS NULL
+`
+
+type UpsertPendingUserMfaParams struct {
+	UserID          pgtype.UUID `db:"user_id" json:"user_id"`
+	EncryptedSecret []byte      `db:"encrypted_secret" json:"encrypted_secret"`
+}
+
+func (q *Queries) UpsertPendingUserMfa(ctx context.Context, arg UpsertPendingUserMfaParams) error {
+	_, err := q.db.Exec(ctx, upsertPendingUserMfa, arg.UserID, arg.EncryptedSecret)
+	return err
+}
+
+const useRecoveryCode = `-- name: UseRecoveryCode :execrows
+UPDATE mfa_recovery_codes
+SET used_at = now()
+WHERE user_id = $1
+  AND code_hash = $2
+  AND used_at IS NULL
+`
+
+type UseRecoveryCodeParams struct {
+	UserID   pgtype.UUID `db:"user_id" json:"user_id"`
+	CodeHash string      `db:"code_hash" json:"code_hash"`
+}
+
+func (q *Queries) UseRecoveryCode(ctx context.Context, arg UseRecoveryCodeParams) (int64, error) {
+	result, err := q.db.Exec(ctx, useRecoveryCode, arg.UserID, arg.CodeHash)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
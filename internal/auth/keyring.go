@@ -0,0 +1,224 @@
+package auth
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	paseto "aidanwoods.dev/go-paseto"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	sqlc "github.com/vantutran2k1/rwe/internal/auth/db"
+	"github.com/vantutran2k1/rwe/internal/common/utils"
+)
+
+const signingKeyAlgorithm = "v4.public"
+
+var ErrUnknownKey = errors.New("unknown signing key")
+
+type signingKey struct {
+	kid       string
+	secret    paseto.V4AsymmetricSecretKey
+	public    paseto.V4AsymmetricPublicKey
+	createdAt time.Time
+	expiresAt *time.Time
+}
+
+// KeyRing holds the asymmetric keys used to sign and verify access tokens.
+// Keys are stored in Postgres with their private half encrypted, so every
+// node shares the same ring; every unexpired key verifies. A new key only
+// signs once it is published, after every node has had time to load it.
+type KeyRing struct {
+	pool             *pgxpool.Pool
+	querier          sqlc.Querier
+	box              *SecretBox
+	rotationInterval time.Duration
+	refreshInterval  time.Duration
+	tokenDuration    time.Duration
+
+	mu   sync.RWMutex
+	keys []*signingKey
+}
+
+func NewKeyRing(pool *pgxpool.Pool, encryptionKey string, rotationInterval, refreshInterval, tokenDuration time.Duration) (*KeyRing, error) {
+	box, err := NewSecretBox(encryptionKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return &KeyRing{
+		pool:             pool,
+		querier:          sqlc.New(pool),
+		box:              box,
+		rotationInterval: rotationInterval,
+		refreshInterval:  refreshInterval,
+		tokenDuration:    tokenDuration,
+	}, nil
+}
+
+// publishDelay is how long a new key only verifies. Every node reloads the
+// ring once per refresh interval, so two intervals leave a full reload to
+// spare.
+func (r *KeyRing) publishDelay() time.Duration {
+	return 2 * r.refreshInterval
+}
+
+// Rotate creates a new signing key when there is none or the newest one is
+// older than the rotation interval. Older keys are retired but stay valid
+// for verification until every token they signed has expired.
+func (r *KeyRing) Rotate(ctx context.Context) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	querier := sqlc.New(tx)
+	if err := querier.LockSigningKeys(ctx); err != nil {
+		return fmt.Errorf("error locking signing keys: %v", err)
+	}
+
+	rows, err := querier.ListSigningKeys(ctx)
+	if err != nil {
+		return fmt.Errorf("error listing signing keys: %v", err)
+	}
+	if len(rows) > 0 && !rows[0].RetiredAt.Valid && time.Since(rows[0].CreatedAt.Time) < r.rotationInterval {
+		return r.load(rows)
+	}
+
+	secret := paseto.NewV4AsymmetricSecretKey()
+	kid := uuid.NewString()
+	encrypted, err := r.box.Seal(secret.ExportBytes(), []byte(kid))
+	if err != nil {
+		return err
+	}
+
+	if err := querier.CreateSigningKey(ctx, sqlc.CreateSigningKeyParams{
+		Kid:                 kid,
+		PublicKey:           secret.Public().ExportBytes(),
+		EncryptedPrivateKey: encrypted,
+	}); err != nil {
+		return fmt.Errorf("error creating signing key: %v", err)
+	}
+
+	// The previous key signs until the new one is published, and its tokens
+	// stay verifiable for one token lifetime after that.
+	if err := querier.RetireSigningKeys(ctx, sqlc.RetireSigningKeysParams{
+		ExpiresAt: utils.TimeToPgTimestamptz(time.Now().Add(r.publishDelay() + r.tokenDuration)),
+		ActiveKid: kid,
+	}); err != nil {
+		return fmt.Errorf("error retiring signing keys: %v", err)
+	}
+
+	rows, err = querier.ListSigningKeys(ctx)
+	if err != nil {
+		return fmt.Errorf("error listing signing keys: %v", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return err
+	}
+
+	slog.Info("rotated signing key", "kid", kid)
+	return r.load(rows)
+}
+
+// Run periodically rotates and reloads the ring until ctx is cancelled.
+func (r *KeyRing) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.refreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := r.Rotate(ctx); err != nil && !errors.Is(err, context.Canceled) {
+				slog.Error("signing key rotation failed", "error", err)
+			}
+		}
+	}
+}
+
+// signingKey returns the newest published key. A fresh ring has no published
+// key yet and signs with its oldest one, which no node can be missing.
+func (r *KeyRing) signingKey() (*signingKey, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if len(r.keys) == 0 {
+		return nil, errors.New("no signing key available")
+	}
+
+	publishedBefore := time.Now().Add(-r.publishDelay())
+	for _, key := range r.keys {
+		if !key.createdAt.After(publishedBefore) {
+			return key, nil
+		}
+	}
+	return r.keys[len(r.keys)-1], nil
+}
+
+func (r *KeyRing) verificationKey(kid string) (*signingKey, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, key := range r.keys {
+		if key.kid != kid {
+			continue
+		}
+		if key.expiresAt != nil && time.Now().After(*key.expiresAt) {
+			return nil, ErrUnknownKey
+		}
+		return key, nil
+	}
+	return nil, ErrUnknownKey
+}
+
+func (r *KeyRing) publicKeys() []*signingKey {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	keys := make([]*signingKey, len(r.keys))
+	copy(keys, r.keys)
+	return keys
+}
+
+func (r *KeyRing) load(rows []sqlc.SigningKey) error {
+	keys := make([]*signingKey, 0, len(rows))
+	for _, row := range rows {
+		raw, err := r.box.Open(row.EncryptedPrivateKey, []byte(row.Kid))
+		if err != nil {
+			return fmt.Errorf("error decrypting signing key %s: %v", row.Kid, err)
+		}
+		secret, err := paseto.NewV4AsymmetricSecretKeyFromBytes(raw)
+		if err != nil {
+			return fmt.Errorf("error parsing signing key %s: %v", row.Kid, err)
+		}
+
+		key := &signingKey{
+			kid:       row.Kid,
+			secret:    secret,
+			public:    secret.Public(),
+			createdAt: row.CreatedAt.Time,
+		}
+		if row.ExpiresAt.Valid {
+			expiresAt := row.ExpiresAt.Time
+			key.expiresAt = &expiresAt
+		}
+		keys = append(keys, key)
+	}
+
+	r.mu.Lock()
+	r.keys = keys
+	r.mu.Unlock()
+	return nil
+}
+
+// paserk serializes a public key in PASERK k4.public form.
+func paserk(key paseto.V4AsymmetricPublicKey) string {
+	return "k4.public." + base64.RawURLEncoding.EncodeToString(key.ExportBytes())
+}
@@ -0,0 +1,90 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/netip"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	authv1 "github.com/vantutran2k1/rwe/gen/go/auth/v1"
+	sqlc "github.com/vantutran2k1/rwe/internal/auth/db"
+	"github.com/vantutran2k1/rwe/internal/common/events"
+	"github.com/vantutran2k1/rwe/internal/common/token"
+	"github.com/vantutran2k1/rwe/internal/common/utils"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (s *Service) UnlockUser(ctx context.Context, req *authv1.UnlockUserRequest) (*authv1.UnlockUserResponse, error) {
+	tenantID, err := tenantFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	userID, err := uuid.Parse(req.UserId)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid user id: %v", err)
+	}
+
+	// Admins can only unlock direct members of their own tenant; inheriting a
+	// membership from a parent does not put the user under this tenant.
+	if _, err := s.querier.GetDirectTenantMembership(ctx, sqlc.GetDirectTenantMembershipParams{
+		TenantID: tenantID,
+		UserID:   utils.UUIDToPgUUID(userID),
+	}); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, status.Errorf(codes.NotFound, "user with id %s not found", userID)
+		}
+
+		return nil, status.Errorf(codes.Internal, "error getting tenant membership: %v", err)
+	}
+
+	user, err := s.querier.GetUserByID(ctx, utils.UUIDToPgUUID(userID))
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "error getting user: %v", err)
+	}
+
+	if err := s.loginGuard.reset(ctx, user.Email); err != nil {
+		return nil, status.Errorf(codes.Unavailable, "error unlocking user: %v", err)
+	}
+
+	payload := map[string]any{"email": user.Email}
+	if tokenPayload := token.GetTokenPayload(ctx); tokenPayload != nil {
+		payload["unlocked_by"] = tokenPayload.UserID.String()
+	}
+	if err := appendEvent(ctx, s.querier, tenantID, user.ID, events.UserUnlocked, payload); err != nil {
+		return nil, status.Errorf(codes.Internal, "error recording unlock: %v", err)
+	}
+
+	return &authv1.UnlockUserResponse{Success: true}, nil
+}
+
+// recordLoginFailure counts a failed password or MFA code and writes an audit
+// event for every lockout it triggers. userID is unset for unknown emails.
+func (s *Service) recordLoginFailure(ctx context.Context, email string, ip netip.Addr, userID pgtype.UUID) {
+	for _, l := range s.loginGuard.recordFailure(ctx, email, ip) {
+		slog.Warn("login locked out", "kind", l.kind, "value", l.value, "failures", l.failures, "locked_until", l.until)
+
+		payload := map[string]any{
+			"kind":         l.kind,
+			"email":        email,
+			"failures":     l.failures,
+			"locked_until": l.until,
+		}
+		if ip.IsValid() {
+			payload["ip_address"] = ip.String()
+		}
+
+		aggregateID := userID
+		if l.kind != "email" {
+			aggregateID = pgtype.UUID{}
+		}
+
+		if err := appendUserEvent(ctx, s.querier, aggregateID, aggregateID, events.LoginLockedOut, payload); err != nil {
+			slog.Error("failed to record login lockout", "error", err)
+		}
+	}
+}
@@ -0,0 +1,487 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base32"
+	"errors"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/pquerna/otp"
+	"github.com/pquerna/otp/totp"
+	authv1 "github.com/vantutran2k1/rwe/gen/go/auth/v1"
+	sqlc "github.com/vantutran2k1/rwe/internal/auth/db"
+	"github.com/vantutran2k1/rwe/internal/common/events"
+	"github.com/vantutran2k1/rwe/internal/common/token"
+	"github.com/vantutran2k1/rwe/internal/common/utils"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	tokenPurposeMfaChallenge = "mfa_challenge"
+
+	mfaChallengeDuration    = 5 * time.Minute
+	maxMfaChallengeAttempts = 5
+	recoveryCodeCount       = 10
+	totpPeriod              = 30
+)
+
+func (s *Service) EnrollMfa(ctx context.Context, req *authv1.EnrollMfaRequest) (*authv1.EnrollMfaResponse, error) {
+	tokenPayload := token.GetTokenPayload(ctx)
+	if tokenPayload == nil {
+		return nil, status.Error(codes.Unauthenticated, "missing user authentication")
+	}
+	userID := utils.UUIDToPgUUID(tokenPayload.UserID)
+
+	mfa, err := s.querier.GetUserMfa(ctx, userID)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return nil, status.Errorf(codes.Internal, "error getting mfa settings: %v", err)
+	}
+	if err == nil && mfa.ConfirmedAt.Valid {
+		return nil, status.Error(codes.FailedPrecondition, "mfa is already enabled")
+	}
+
+	key, err := totp.Generate(totp.GenerateOpts{
+		Issuer:      s.mfaIssuer,
+		AccountName: tokenPayload.Email,
+		Period:      totpPeriod,
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "error generating mfa secret: %v", err)
+	}
+
+	encryptedSecret, err := s.mfaBox.Seal([]byte(key.Secret()), mfaAdditionalData(userID))
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "error encrypting mfa secret: %v", err)
+	}
+
+	// Enrolling again before confirmation replaces the pending secret.
+	if err := s.querier.UpsertPendingUserMfa(ctx, sqlc.UpsertPendingUserMfaParams{
+		UserID:          userID,
+		EncryptedSecret: encryptedSecret,
+	}); err != nil {
+		return nil, status.Errorf(codes.Internal, "error saving mfa secret: %v", err)
+	}
+
+	return &authv1.EnrollMfaResponse{
+		Secret:     key.Secret(),
+		OtpauthUri: key.URL(),
+	}, nil
+}
+
+func (s *Service) ConfirmMfa(ctx context.Context, req *authv1.ConfirmMfaRequest) (*authv1.ConfirmMfaResponse, error) {
+	tokenPayload := token.GetTokenPayload(ctx)
+	if tokenPayload == nil {
+		return nil, status.Error(codes.Unauthenticated, "missing user authentication")
+	}
+	userID := utils.UUIDToPgUUID(tokenPayload.UserID)
+
+	var reqErr error
+	var recoveryCodes []string
+	if err := s.execTx(ctx, func(querier sqlc.Querier) error {
+		mfa, err := querier.GetUserMfaForUpdate(ctx, userID)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				reqErr = status.Error(codes.FailedPrecondition, "mfa enrollment has not been started")
+			}
+			return err
+		}
+
+		if mfa.ConfirmedAt.Valid {
+			reqErr = status.Error(codes.FailedPrecondition, "mfa is already enabled")
+			return reqErr
+		}
+
+		secret, err := s.mfaSecret(mfa)
+		if err != nil {
+			return err
+		}
+
+		step, ok := validateTOTP(secret, req.Code, mfa.LastUsedStep, time.Now())
+		if !ok {
+			reqErr = status.Error(codes.InvalidArgument, "invalid mfa code")
+			return reqErr
+		}
+
+		if err := querier.ConfirmUserMfa(ctx, sqlc.ConfirmUserMfaParams{
+			UserID:       userID,
+			LastUsedStep: step,
+		}); err != nil {
+			return err
+		}
+
+		recoveryCodes, err = replaceRecoveryCodes(ctx, querier, userID)
+		if err != nil {
+			return err
+		}
+
+		return appendUserEvent(ctx, querier, userID, userID, events.MfaEnabled, nil)
+	}); err != nil {
+		if reqErr != nil {
+			return nil, reqErr
+		}
+
+		return nil, status.Errorf(codes.Internal, "error confirming mfa: %v", err)
+	}
+
+	return &authv1.ConfirmMfaResponse{RecoveryCodes: recoveryCodes}, nil
+}
+
+func (s *Service) DisableMfa(ctx context.Context, req *authv1.DisableMfaRequest) (*authv1.DisableMfaResponse, error) {
+	tokenPayload := token.GetTokenPayload(ctx)
+	if tokenPayload == nil {
+		return nil, status.Error(codes.Unauthenticated, "missing user authentication")
+	}
+	userID := utils.UUIDToPgUUID(tokenPayload.UserID)
+
+	ip := s.loginGuard.clientIP(ctx)
+	if err := s.loginGuard.check(ctx, tokenPayload.Email, ip); err != nil {
+		return nil, err
+	}
+
+	var reqErr error
+	if err := s.execTx(ctx, func(querier sqlc.Querier) error {
+		mfa, err := s.confirmedMfaForUpdate(ctx, querier, userID)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				reqErr = status.Error(codes.FailedPrecondition, "mfa is not enabled")
+			}
+			return err
+		}
+
+		ok, err := s.verifyMfaCode(ctx, querier, mfa, req.Code, req.RecoveryCode)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			s.recordLoginFailure(ctx, tokenPayload.Email, ip, userID)
+			reqErr = status.Error(codes.InvalidArgument, "invalid mfa code")
+			return reqErr
+		}
+
+		if err := querier.DeleteRecoveryCodes(ctx, userID); err != nil {
+			return err
+		}
+		if err := querier.DeleteUserMfa(ctx, userID); err != nil {
+			return err
+		}
+
+		return appendUserEvent(ctx, querier, userID, userID, events.MfaDisabled, nil)
+	}); err != nil {
+		if reqErr != nil {
+			return nil, reqErr
+		}
+
+		return nil, status.Errorf(codes.Internal, "error disabling mfa: %v", err)
+	}
+
+	return &authv1.DisableMfaResponse{Success: true}, nil
+}
+
+func (s *Service) RegenerateRecoveryCodes(ctx context.Context, req *authv1.RegenerateRecoveryCodesRequest) (*authv1.RegenerateRecoveryCodesResponse, error) {
+	tokenPayload := token.GetTokenPayload(ctx)
+	if tokenPayload == nil {
+		return nil, status.Error(codes.Unauthenticated, "missing user authentication")
+	}
+	userID := utils.UUIDToPgUUID(tokenPayload.UserID)
+
+	ip := s.loginGuard.clientIP(ctx)
+	if err := s.loginGuard.check(ctx, tokenPayload.Email, ip); err != nil {
+		return nil, err
+	}
+
+	var reqErr error
+	var recoveryCodes []string
+	if err := s.execTx(ctx, func(querier sqlc.Querier) error {
+		mfa, err := s.confirmedMfaForUpdate(ctx, querier, userID)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				reqErr = status.Error(codes.FailedPrecondition, "mfa is not enabled")
+			}
+			return err
+		}
+
+		ok, err := s.verifyMfaCode(ctx, querier, mfa, req.Code, "")
+		if err != nil {
+			return err
+		}
+		if !ok {
+			s.recordLoginFailure(ctx, tokenPayload.Email, ip, userID)
+			reqErr = status.Error(codes.InvalidArgument, "invalid mfa code")
+			return reqErr
+		}
+
+		recoveryCodes, err = replaceRecoveryCodes(ctx, querier, userID)
+		return err
+	}); err != nil {
+		if reqErr != nil {
+			return nil, reqErr
+		}
+
+		return nil, status.Errorf(codes.Internal, "error regenerating recovery codes: %v", err)
+	}
+
+	return &authv1.RegenerateRecoveryCodesResponse{RecoveryCodes: recoveryCodes}, nil
+}
+
+func (s *Service) VerifyMfa(ctx context.Context, req *authv1.VerifyMfaRequest) (*authv1.VerifyMfaResponse, error) {
+	ip := s.loginGuard.clientIP(ctx)
+
+	var reqErr error
+	var challenge sqlc.GetUserTokenForUpdateRow
+	if err := s.execTx(ctx, func(querier sqlc.Querier) error {
+		var err error
+		challenge, err = querier.GetUserTokenForUpdate(ctx, sqlc.GetUserTokenForUpdateParams{
+			TokenHash: HashKey(req.ChallengeToken),
+			Purpose:   tokenPurposeMfaChallenge,
+		})
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				reqErr = status.Error(codes.Unauthenticated, "invalid or expired mfa challenge")
+			}
+			return err
+		}
+
+		if challenge.UsedAt.Valid || time.Now().After(challenge.ExpiresAt.Time) {
+			reqErr = status.Error(codes.Unauthenticated, "invalid or expired mfa challenge")
+			return reqErr
+		}
+
+		if err := s.loginGuard.check(ctx, challenge.Email, ip); err != nil {
+			reqErr = err
+			return reqErr
+		}
+
+		mfa, err := s.confirmedMfaForUpdate(ctx, querier, challenge.UserID)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				reqErr = status.Error(codes.Unauthenticated, "invalid or expired mfa challenge")
+			}
+			return err
+		}
+
+		ok, err := s.verifyMfaCode(ctx, querier, mfa, req.Code, req.RecoveryCode)
+		if err != nil {
+			return err
+		}
+
+		if !ok {
+			// The failed attempt is committed so that the challenge cannot be
+			// used to guess codes indefinitely, and counts towards the login
+			// lockout so that new challenges cannot either.
+			s.recordLoginFailure(ctx, challenge.Email, ip, challenge.UserID)
+
+			attempts, err := querier.IncrementUserTokenAttempts(ctx, challenge.ID)
+			if err != nil {
+				return err
+			}
+			if attempts >= maxMfaChallengeAttempts {
+				if err := querier.MarkUserTokenUsed(ctx, challenge.ID); err != nil {
+					return err
+				}
+			}
+
+			reqErr = status.Error(codes.Unauthenticated, "invalid mfa code")
+			return nil
+		}
+
+		return querier.MarkUserTokenUsed(ctx, challenge.ID)
+	}); err != nil {
+		if reqErr != nil {
+			return nil, reqErr
+		}
+
+		return nil, status.Errorf(codes.Internal, "error verifying mfa: %v", err)
+	}
+	if reqErr != nil {
+		return nil, reqErr
+	}
+
+	if err := s.loginGuard.reset(ctx, challenge.Email); err != nil {
+		slog.Warn("failed to reset login failures", "error", err)
+	}
+
+	userID, err := uuid.Parse(utils.PgUUIDToString(challenge.UserID))
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "error parsing user id: %v", err)
+	}
+
+	tokens, err := s.createSession(ctx, userID, challenge.Email)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "error creating session: %v", err)
+	}
+
+	return &authv1.VerifyMfaResponse{
+		AccessToken:           tokens.accessToken,
+		ExpiresAt:             timestamppb.New(tokens.accessPayload.ExpiredAt),
+		RefreshToken:          tokens.refreshToken,
+		RefreshTokenExpiresAt: timestamppb.New(tokens.refreshExpiresAt),
+		SessionId:             tokens.accessPayload.SessionID.String(),
+	}, nil
+}
+
+// mfaChallenge reports whether the user has MFA enabled and, if so, creates
+// the challenge token Login returns in place of session tokens.
+func (s *Service) mfaChallenge(ctx context.Context, userID pgtype.UUID) (*authv1.LoginResponse, error) {
+	mfa, err := s.querier.GetUserMfa(ctx, userID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if !mfa.ConfirmedAt.Valid {
+		return nil, nil
+	}
+
+	expiresAt := time.Now().Add(mfaChallengeDuration)
+	var challengeToken string
+	if err := s.execTx(ctx, func(querier sqlc.Querier) error {
+		challengeToken, err = createUserToken(ctx, querier, userID, tokenPurposeMfaChallenge, mfaChallengeDuration)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	return &authv1.LoginResponse{
+		MfaRequired:           true,
+		MfaChallengeToken:     challengeToken,
+		MfaChallengeExpiresAt: timestamppb.New(expiresAt),
+	}, nil
+}
+
+func (s *Service) confirmedMfaForUpdate(ctx context.Context, querier sqlc.Querier, userID pgtype.UUID) (sqlc.UserMfa, error) {
+	mfa, err := querier.GetUserMfaForUpdate(ctx, userID)
+	if err != nil {
+		return mfa, err
+	}
+	if !mfa.ConfirmedAt.Valid {
+		return mfa, pgx.ErrNoRows
+	}
+
+	return mfa, nil
+}
+
+// verifyMfaCode checks a TOTP code, or a recovery code when one is given, and
+// records its use so that neither can be replayed.
+func (s *Service) verifyMfaCode(ctx context.Context, querier sqlc.Querier, mfa sqlc.UserMfa, code, recoveryCode string) (bool, error) {
+	if recoveryCode != "" {
+		rows, err := querier.UseRecoveryCode(ctx, sqlc.UseRecoveryCodeParams{
+			UserID:   mfa.UserID,
+			CodeHash: HashKey(normalizeRecoveryCode(recoveryCode)),
+		})
+		if err != nil || rows == 0 {
+			return false, err
+		}
+
+		remaining, err := querier.CountUnusedRecoveryCodes(ctx, mfa.UserID)
+		if err != nil {
+			return false, err
+		}
+
+		return true, appendUserEvent(ctx, querier, mfa.UserID, mfa.UserID, events.RecoveryCodeUsed, map[string]any{
+			"remaining": remaining,
+		})
+	}
+
+	secret, err := s.mfaSecret(mfa)
+	if err != nil {
+		return false, err
+	}
+
+	step, ok := validateTOTP(secret, code, mfa.LastUsedStep, time.Now())
+	if !ok {
+		return false, nil
+	}
+
+	return true, querier.UpdateUserMfaLastUsedStep(ctx, sqlc.UpdateUserMfaLastUsedStepParams{
+		UserID:       mfa.UserID,
+		LastUsedStep: step,
+	})
+}
+
+func (s *Service) mfaSecret(mfa sqlc.UserMfa) (string, error) {
+	secret, err := s.mfaBox.Open(mfa.EncryptedSecret, mfaAdditionalData(mfa.UserID))
+	if err != nil {
+		return "", err
+	}
+
+	return string(secret), nil
+}
+
+func mfaAdditionalData(userID pgtype.UUID) []byte {
+	return []byte(utils.PgUUIDToString(userID))
+}
+
+// validateTOTP accepts the code for the current time step or one step either
+// side, and returns the matched step. Steps at or before lastStep have
+// already been used and are rejected.
+func validateTOTP(secret, code string, lastStep int64, now time.Time) (int64, bool) {
+	current := now.Unix() / totpPeriod
+	for _, step := range []int64{current, current - 1, current + 1} {
+		if step <= lastStep {
+			continue
+		}
+
+		expected, err := totp.GenerateCodeCustom(secret, time.Unix(step*totpPeriod, 0), totp.ValidateOpts{
+			Period:    totpPeriod,
+			Digits:    otp.DigitsSix,
+			Algorithm: otp.AlgorithmSHA1,
+		})
+		if err != nil {
+			return 0, false
+		}
+
+		if subtle.ConstantTimeCompare([]byte(expected), []byte(code)) == 1 {
+			return step, true
+		}
+	}
+
+	return 0, false
+}
+
+func replaceRecoveryCodes(ctx context.Context, querier sqlc.Querier, userID pgtype.UUID) ([]string, error) {
+	if err := querier.DeleteRecoveryCodes(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	recoveryCodes := make([]string, 0, recoveryCodeCount)
+	for range recoveryCodeCount {
+		code, err := generateRecoveryCode()
+		if err != nil {
+			return nil, err
+		}
+
+		if err := querier.CreateRecoveryCode(ctx, sqlc.CreateRecoveryCodeParams{
+			UserID:   userID,
+			CodeHash: HashKey(normalizeRecoveryCode(code)),
+		}); err != nil {
+			return nil, err
+		}
+		recoveryCodes = append(recoveryCodes, code)
+	}
+
+	return recoveryCodes, nil
+}
+
+func generateRecoveryCode() (string, error) {
+	bytes := make([]byte, 10)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+
+	encoded := strings.ToLower(base32.StdEncoding.EncodeToString(bytes))
+	return encoded[:8] + "-" + encoded[8:], nil
+}
+
+func normalizeRecoveryCode(code string) string {
+	return strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+}
@@ -0,0 +1,114 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net"
+	"sync"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	"golang.org/x/oauth2"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// OidcClient talks to the OpenID Connect providers configured by tenants.
+// Discovery documents are cached per issuer.
+type OidcClient struct {
+	box         *SecretBox
+	redirectURL string
+	lookupTXT   func(ctx context.Context, name string) ([]string, error)
+
+	mu        sync.Mutex
+	providers map[string]*oidc.Provider
+}
+
+func NewOidcClient(encryptionKey, redirectURL string) (*OidcClient, error) {
+	box, err := NewSecretBox(encryptionKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return &OidcClient{
+		box:         box,
+		redirectURL: redirectURL,
+		lookupTXT:   net.DefaultResolver.LookupTXT,
+		providers:   make(map[string]*oidc.Provider),
+	}, nil
+}
+
+func (c *OidcClient) provider(ctx context.Context, issuer string) (*oidc.Provider, error) {
+	c.mu.Lock()
+	provider, ok := c.providers[issuer]
+	c.mu.Unlock()
+	if ok {
+		return provider, nil
+	}
+
+	// The provider keeps the context for fetching signing keys later on, so
+	// it must outlive the request that triggered discovery.
+	provider, err := oidc.NewProvider(context.WithoutCancel(ctx), issuer)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	c.providers[issuer] = provider
+	c.mu.Unlock()
+
+	return provider, nil
+}
+
+func (c *OidcClient) oauth2Config(provider *oidc.Provider, clientID, clientSecret string) *oauth2.Config {
+	return &oauth2.Config{
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		Endpoint:     provider.Endpoint(),
+		RedirectURL:  c.redirectURL,
+		Scopes:       []string{oidc.ScopeOpenID, "email", "profile"},
+	}
+}
+
+// exchange redeems an authorization code with its PKCE verifier and returns
+// the verified id token, which must carry the nonce of the login and a
+// verified email.
+func (c *OidcClient) exchange(ctx context.Context, provider *oidc.Provider, clientID, clientSecret, code, verifier, nonce string) (*oidc.IDToken, oidcClaims, error) {
+	oauthToken, err := c.oauth2Config(provider, clientID, clientSecret).
+		Exchange(ctx, code, oauth2.VerifierOption(verifier))
+	if err != nil {
+		return nil, oidcClaims{}, status.Errorf(codes.Unauthenticated, "error exchanging authorization code: %v", err)
+	}
+
+	rawIDToken, ok := oauthToken.Extra("id_token").(string)
+	if !ok {
+		return nil, oidcClaims{}, status.Error(codes.Unauthenticated, "identity provider did not return an id token")
+	}
+
+	idToken, err := provider.Verifier(&oidc.Config{ClientID: clientID}).Verify(ctx, rawIDToken)
+	if err != nil {
+		return nil, oidcClaims{}, status.Errorf(codes.Unauthenticated, "invalid id token: %v", err)
+	}
+	if idToken.Nonce != nonce {
+		return nil, oidcClaims{}, status.Error(codes.Unauthenticated, "invalid id token nonce")
+	}
+
+	var claims oidcClaims
+	if err := idToken.Claims(&claims); err != nil {
+		return nil, oidcClaims{}, status.Errorf(codes.Unauthenticated, "invalid id token claims: %v", err)
+	}
+	if claims.Email == "" || !claims.EmailVerified {
+		return nil, oidcClaims{}, status.Error(codes.PermissionDenied, "identity provider did not return a verified email")
+	}
+
+	return idToken, claims, nil
+}
+
+func randomString() (string, error) {
+	bytes := make([]byte, 32)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(bytes), nil
+}
@@ -0,0 +1,176 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	"github.com/vantutran2k1/rwe/internal/auth/oidctest"
+	"golang.org/x/oauth2"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	testClientID     = "rwe"
+	testClientSecret = "secret"
+	testRedirectURL  = "http://rwe.test/v1/auth/oidc/callback"
+)
+
+// authorize starts a code flow at the provider and returns the code it
+// redirects back with.
+func authorize(t *testing.T, client *OidcClient, provider *oidc.Provider, state, nonce, verifier string) string {
+	t.Helper()
+
+	authURL := client.oauth2Config(provider, testClientID, "").
+		AuthCodeURL(state, oidc.Nonce(nonce), oauth2.S256ChallengeOption(verifier))
+
+	httpClient := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}}
+	resp, err := httpClient.Get(authURL)
+	if err != nil {
+		t.Fatalf("authorize: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusFound {
+		t.Fatalf("authorize: got status %d, want %d", resp.StatusCode, http.StatusFound)
+	}
+
+	location, err := url.Parse(resp.Header.Get("Location"))
+	if err != nil {
+		t.Fatalf("authorize: invalid redirect: %v", err)
+	}
+	if got := location.Scheme + "://" + location.Host + location.Path; got != testRedirectURL {
+		t.Fatalf("authorize: redirected to %s, want %s", got, testRedirectURL)
+	}
+	if got := location.Query().Get("state"); got != state {
+		t.Fatalf("authorize: got state %q, want %q", got, state)
+	}
+
+	return location.Query().Get("code")
+}
+
+func newTestOidc(t *testing.T, user oidctest.User) (*OidcClient, *oidc.Provider) {
+	t.Helper()
+
+	server, _, err := oidctest.NewServer(testClientID, testClientSecret, user)
+	if err != nil {
+		t.Fatalf("starting provider: %v", err)
+	}
+	t.Cleanup(server.Close)
+
+	client, err := NewOidcClient("abcdefghijklmnopqrstuvwxyz012345", testRedirectURL)
+	if err != nil {
+		t.Fatalf("creating client: %v", err)
+	}
+
+	provider, err := client.provider(context.Background(), server.URL)
+	if err != nil {
+		t.Fatalf("discovering provider: %v", err)
+	}
+
+	return client, provider
+}
+
+func TestOidcCodeFlow(t *testing.T) {
+	user := oidctest.User{
+		Subject:       "user-1",
+		Email:         "Dev@Example.com",
+		EmailVerified: true,
+		Name:          "Dev User",
+	}
+
+	tests := []struct {
+		name         string
+		clientSecret string
+		verifier     func(string) string
+		nonce        func(string) string
+		wantCode     codes.Code
+	}{
+		{
+			name:     "valid",
+			wantCode: codes.OK,
+		},
+		{
+			name:     "wrong code verifier",
+			verifier: func(string) string { return oauth2.GenerateVerifier() },
+			wantCode: codes.Unauthenticated,
+		},
+		{
+			name:     "wrong nonce",
+			nonce:    func(string) string { return "other-nonce" },
+			wantCode: codes.Unauthenticated,
+		},
+		{
+			name:         "wrong client secret",
+			clientSecret: "wrong",
+			wantCode:     codes.Unauthenticated,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, provider := newTestOidc(t, user)
+
+			verifier := oauth2.GenerateVerifier()
+			code := authorize(t, client, provider, "state-1", "nonce-1", verifier)
+
+			clientSecret := testClientSecret
+			if tt.clientSecret != "" {
+				clientSecret = tt.clientSecret
+			}
+			if tt.verifier != nil {
+				verifier = tt.verifier(verifier)
+			}
+			nonce := "nonce-1"
+			if tt.nonce != nil {
+				nonce = tt.nonce(nonce)
+			}
+
+			idToken, claims, err := client.exchange(context.Background(), provider, testClientID, clientSecret, code, verifier, nonce)
+			if got := status.Code(err); got != tt.wantCode {
+				t.Fatalf("exchange: got code %s, want %s (err: %v)", got, tt.wantCode, err)
+			}
+			if tt.wantCode != codes.OK {
+				return
+			}
+
+			if idToken.Subject != user.Subject {
+				t.Errorf("got subject %q, want %q", idToken.Subject, user.Subject)
+			}
+			if claims.Email != user.Email || claims.Name != user.Name || !claims.EmailVerified {
+				t.Errorf("got claims %+v, want email %q and name %q", claims, user.Email, user.Name)
+			}
+		})
+	}
+}
+
+func TestOidcCodeFlowRejectsReusedCode(t *testing.T) {
+	client, provider := newTestOidc(t, oidctest.User{Subject: "user-1", Email: "dev@example.com", EmailVerified: true})
+
+	verifier := oauth2.GenerateVerifier()
+	code := authorize(t, client, provider, "state-1", "nonce-1", verifier)
+
+	if _, _, err := client.exchange(context.Background(), provider, testClientID, testClientSecret, code, verifier, "nonce-1"); err != nil {
+		t.Fatalf("first exchange: %v", err)
+	}
+	if _, _, err := client.exchange(context.Background(), provider, testClientID, testClientSecret, code, verifier, "nonce-1"); status.Code(err) != codes.Unauthenticated {
+		t.Fatalf("second exchange: got %v, want Unauthenticated", err)
+	}
+}
+
+func TestOidcCodeFlowRequiresVerifiedEmail(t *testing.T) {
+	client, provider := newTestOidc(t, oidctest.User{Subject: "user-1", Email: "dev@example.com", EmailVerified: false})
+
+	verifier := oauth2.GenerateVerifier()
+	code := authorize(t, client, provider, "state-1", "nonce-1", verifier)
+
+	_, _, err := client.exchange(context.Background(), provider, testClientID, testClientSecret, code, verifier, "nonce-1")
+	if status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("got %v, want PermissionDenied", err)
+	}
+}
@@ -30,8 +30,8 @@ func NewPasetoMaker(symmetricKey string, duration time.Duration) (TokenMaker, er
 	return maker, nil
 }
 
-func (p *PasetoMaker) CreateToken(email string, userID uuid.UUID) (string, *token.Payload, error) {
-	payload, err := token.NewPayload(email, userID, p.duration)
+func (p *PasetoMaker) CreateToken(email string, userID, sessionID uuid.UUID) (string, *token.Payload, error) {
+	payload, err := token.NewPayload(email, userID, sessionID, p.duration)
 	if err != nil {
 		return "", payload, err
 	}
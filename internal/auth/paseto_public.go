@@ -0,0 +1,93 @@
+package auth
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	paseto "aidanwoods.dev/go-paseto"
+	"github.com/google/uuid"
+	"github.com/vantutran2k1/rwe/internal/common/token"
+)
+
+type keyFooter struct {
+	Kid string `json:"kid"`
+}
+
+// PasetoPublicMaker issues v4.public tokens signed by the active key of a
+// KeyRing. The signing key id travels in the footer so that verifiers can
+// select the matching public key.
+type PasetoPublicMaker struct {
+	keyRing  *KeyRing
+	duration time.Duration
+}
+
+func NewPasetoPublicMaker(keyRing *KeyRing, duration time.Duration) TokenMaker {
+	return &PasetoPublicMaker{
+		keyRing:  keyRing,
+		duration: duration,
+	}
+}
+
+func (p *PasetoPublicMaker) CreateToken(email string, userID, sessionID uuid.UUID) (string, *token.Payload, error) {
+	payload, err := token.NewPayload(email, userID, sessionID, p.duration)
+	if err != nil {
+		return "", payload, err
+	}
+
+	key, err := p.keyRing.signingKey()
+	if err != nil {
+		return "", payload, err
+	}
+
+	claims, err := json.Marshal(payload)
+	if err != nil {
+		return "", payload, err
+	}
+	footer, err := json.Marshal(keyFooter{Kid: key.kid})
+	if err != nil {
+		return "", payload, err
+	}
+
+	t, err := paseto.NewTokenFromClaimsJSON(claims, footer)
+	if err != nil {
+		return "", payload, err
+	}
+	return t.V4Sign(key.secret, nil), payload, nil
+}
+
+func (p *PasetoPublicMaker) VerifyToken(t string) (*token.Payload, error) {
+	// Expiry is checked by Payload.Validate, so the library's registered
+	// claim rules are not used.
+	parser := paseto.NewParserWithoutExpiryCheck()
+
+	rawFooter, err := parser.UnsafeParseFooter(paseto.V4Public, t)
+	if err != nil {
+		return nil, errors.New("token is invalid")
+	}
+	var footer keyFooter
+	if err := json.Unmarshal(rawFooter, &footer); err != nil || footer.Kid == "" {
+		return nil, errors.New("token is invalid")
+	}
+
+	key, err := p.keyRing.verificationKey(footer.Kid)
+	if err != nil {
+		return nil, errors.New("token is invalid")
+	}
+
+	parsed, err := parser.ParseV4Public(key.public, t, nil)
+	if err != nil {
+		return nil, errors.New("token is invalid")
+	}
+
+	payload := &token.Payload{}
+	if err := json.Unmarshal(parsed.ClaimsJSON(), payload); err != nil {
+		return nil, errors.New("token is invalid")
+	}
+
+	if err := payload.Validate(); err != nil {
+		return nil, err
+	}
+
+	return payload, nil
+}
@@ -0,0 +1,242 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	authv1 "github.com/vantutran2k1/rwe/gen/go/auth/v1"
+	sqlc "github.com/vantutran2k1/rwe/internal/auth/db"
+	"github.com/vantutran2k1/rwe/internal/common/events"
+	"github.com/vantutran2k1/rwe/internal/common/token"
+	"github.com/vantutran2k1/rwe/internal/common/utils"
+	"github.com/vantutran2k1/rwe/internal/mailer"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	tokenPurposePasswordReset     = "password_reset"
+	tokenPurposeEmailVerification = "email_verification"
+
+	passwordResetTokenDuration     = 30 * time.Minute
+	emailVerificationTokenDuration = 24 * time.Hour
+)
+
+var errInvalidUserToken = errors.New("invalid or expired token")
+
+func (s *Service) RequestPasswordReset(ctx context.Context, req *authv1.RequestPasswordResetRequest) (*authv1.RequestPasswordResetResponse, error) {
+	// The response is the same whether or not the email is registered so that
+	// the endpoint cannot be used to enumerate accounts.
+	resp := &authv1.RequestPasswordResetResponse{Message: "if the email is registered, a reset link has been sent"}
+
+	user, err := s.querier.GetUserByEmail(ctx, normalizeEmail(req.Email))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return resp, nil
+		}
+
+		return nil, status.Errorf(codes.Internal, "error getting user: %v", err)
+	}
+
+	var rawToken string
+	if err := s.execTx(ctx, func(querier sqlc.Querier) error {
+		rawToken, err = createUserToken(ctx, querier, user.ID, tokenPurposePasswordReset, passwordResetTokenDuration)
+		if err != nil {
+			return err
+		}
+
+		return appendUserEvent(ctx, querier, user.ID, user.ID, events.PasswordResetRequested, nil)
+	}); err != nil {
+		return nil, status.Errorf(codes.Internal, "error creating reset token: %v", err)
+	}
+
+	if err := s.mailer.Send(ctx, mailer.Message{
+		To:      user.Email,
+		Subject: "Reset your password",
+		Body: fmt.Sprintf(
+			"A password reset was requested for your account.\n\nUse the link below within %s to choose a new password:\n\n%s\n\nIf you did not request this, you can ignore this email.\n",
+			passwordResetTokenDuration, s.link("/reset-password", rawToken),
+		),
+	}); err != nil {
+		slog.Error("failed to send password reset email", "user_id", utils.PgUUIDToString(user.ID), "error", err)
+	}
+
+	return resp, nil
+}
+
+func (s *Service) ResetPassword(ctx context.Context, req *authv1.ResetPasswordRequest) (*authv1.ResetPasswordResponse, error) {
+	if err := ValidatePassword(req.NewPassword); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	passwordHash, err := HashPassword(req.NewPassword)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "error hashing password: %v", err)
+	}
+
+	if err := s.execTx(ctx, func(querier sqlc.Querier) error {
+		userToken, err := consumeUserToken(ctx, querier, req.Token, tokenPurposePasswordReset)
+		if err != nil {
+			return err
+		}
+
+		if err := querier.UpdateUserPassword(ctx, sqlc.UpdateUserPasswordParams{
+			ID:           userToken.UserID,
+			PasswordHash: passwordHash,
+		}); err != nil {
+			return err
+		}
+
+		// Any other outstanding reset links are no longer needed.
+		if err := querier.InvalidateUserTokens(ctx, sqlc.InvalidateUserTokensParams{
+			UserID:  userToken.UserID,
+			Purpose: tokenPurposePasswordReset,
+		}); err != nil {
+			return err
+		}
+
+		// Sign out everywhere: whoever knew the old password may hold a session.
+		sessions, err := querier.RevokeUserSessions(ctx, userToken.UserID)
+		if err != nil {
+			return err
+		}
+		for _, session := range sessions {
+			if err := s.blocklistAccessToken(ctx, session.AccessTokenID, session.AccessTokenExpiresAt); err != nil {
+				return err
+			}
+		}
+
+		return appendUserEvent(ctx, querier, userToken.UserID, userToken.UserID, events.PasswordReset, map[string]any{
+			"revoked_sessions": len(sessions),
+		})
+	}); err != nil {
+		if errors.Is(err, errInvalidUserToken) {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+
+		return nil, status.Errorf(codes.Internal, "error resetting password: %v", err)
+	}
+
+	return &authv1.ResetPasswordResponse{Success: true}, nil
+}
+
+func (s *Service) SendVerificationEmail(ctx context.Context, req *authv1.SendVerificationEmailRequest) (*authv1.SendVerificationEmailResponse, error) {
+	tokenPayload := token.GetTokenPayload(ctx)
+	if tokenPayload == nil {
+		return nil, status.Error(codes.Unauthenticated, "missing user authentication")
+	}
+
+	user, err := s.querier.GetUserByID(ctx, utils.UUIDToPgUUID(tokenPayload.UserID))
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "error getting user: %v", err)
+	}
+	if user.EmailVerifiedAt.Valid {
+		return nil, status.Error(codes.FailedPrecondition, "email is already verified")
+	}
+
+	if err := s.sendVerificationEmail(ctx, user.ID, user.Email); err != nil {
+		return nil, status.Errorf(codes.Unavailable, "error sending verification email: %v", err)
+	}
+
+	return &authv1.SendVerificationEmailResponse{Message: "verification email sent"}, nil
+}
+
+func (s *Service) VerifyEmail(ctx context.Context, req *authv1.VerifyEmailRequest) (*authv1.VerifyEmailResponse, error) {
+	if err := s.execTx(ctx, func(querier sqlc.Querier) error {
+		userToken, err := consumeUserToken(ctx, querier, req.Token, tokenPurposeEmailVerification)
+		if err != nil {
+			return err
+		}
+
+		if err := querier.MarkUserEmailVerified(ctx, userToken.UserID); err != nil {
+			return err
+		}
+
+		if err := querier.InvalidateUserTokens(ctx, sqlc.InvalidateUserTokensParams{
+			UserID:  userToken.UserID,
+			Purpose: tokenPurposeEmailVerification,
+		}); err != nil {
+			return err
+		}
+
+		return appendUserEvent(ctx, querier, userToken.UserID, userToken.UserID, events.EmailVerified, map[string]any{
+			"email": userToken.Email,
+		})
+	}); err != nil {
+		if errors.Is(err, errInvalidUserToken) {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+
+		return nil, status.Errorf(codes.Internal, "error verifying email: %v", err)
+	}
+
+	return &authv1.VerifyEmailResponse{Success: true}, nil
+}
+
+func (s *Service) sendVerificationEmail(ctx context.Context, userID pgtype.UUID, email string) error {
+	var rawToken string
+	if err := s.execTx(ctx, func(querier sqlc.Querier) error {
+		var err error
+		rawToken, err = createUserToken(ctx, querier, userID, tokenPurposeEmailVerification, emailVerificationTokenDuration)
+		return err
+	}); err != nil {
+		return err
+	}
+
+	return s.mailer.Send(ctx, mailer.Message{
+		To:      email,
+		Subject: "Verify your email address",
+		Body: fmt.Sprintf(
+			"Confirm your email address by opening the link below within %s:\n\n%s\n",
+			emailVerificationTokenDuration, s.link("/verify-email", rawToken),
+		),
+	})
+}
+
+func (s *Service) link(path, rawToken string) string {
+	return s.linkBaseURL + path + "?token=" + url.QueryEscape(rawToken)
+}
+
+func createUserToken(ctx context.Context, querier sqlc.Querier, userID pgtype.UUID, purpose string, duration time.Duration) (string, error) {
+	rawToken, hashedToken, err := GenerateUserToken()
+	if err != nil {
+		return "", err
+	}
+
+	if err := querier.CreateUserToken(ctx, sqlc.CreateUserTokenParams{
+		UserID:    userID,
+		Purpose:   purpose,
+		TokenHash: hashedToken,
+		ExpiresAt: utils.TimeToPgTimestamptz(time.Now().Add(duration)),
+	}); err != nil {
+		return "", err
+	}
+
+	return rawToken, nil
+}
+
+func consumeUserToken(ctx context.Context, querier sqlc.Querier, rawToken, purpose string) (sqlc.GetUserTokenForUpdateRow, error) {
+	userToken, err := querier.GetUserTokenForUpdate(ctx, sqlc.GetUserTokenForUpdateParams{
+		TokenHash: HashKey(rawToken),
+		Purpose:   purpose,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return userToken, errInvalidUserToken
+		}
+
+		return userToken, err
+	}
+
+	if userToken.UsedAt.Valid || time.Now().After(userToken.ExpiresAt.Time) {
+		return userToken, errInvalidUserToken
+	}
+
+	return userToken, querier.MarkUserTokenUsed(ctx, userToken.ID)
+}
@@ -0,0 +1,49 @@
+package auth
+
+import (
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+	"fmt"
+
+	"golang.org/x/crypto/chacha20poly1305"
+)
+
+// SecretBox encrypts secrets that are stored at rest, such as signing keys
+// and TOTP seeds, with XChaCha20-Poly1305.
+type SecretBox struct {
+	aead cipher.AEAD
+}
+
+func NewSecretBox(key string) (*SecretBox, error) {
+	if len(key) != chacha20poly1305.KeySize {
+		return nil, fmt.Errorf("invalid key size: must be exactly %d characters", chacha20poly1305.KeySize)
+	}
+
+	aead, err := chacha20poly1305.NewX([]byte(key))
+	if err != nil {
+		return nil, err
+	}
+
+	return &SecretBox{aead: aead}, nil
+}
+
+// Seal encrypts plaintext bound to additionalData, which must be presented
+// again to Open.
+func (b *SecretBox) Seal(plaintext, additionalData []byte) ([]byte, error) {
+	nonce := make([]byte, b.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	return b.aead.Seal(nonce, nonce, plaintext, additionalData), nil
+}
+
+func (b *SecretBox) Open(ciphertext, additionalData []byte) ([]byte, error) {
+	if len(ciphertext) < b.aead.NonceSize() {
+		return nil, errors.New("ciphertext too short")
+	}
+
+	nonce, sealed := ciphertext[:b.aead.NonceSize()], ciphertext[b.aead.NonceSize():]
+	return b.aead.Open(nil, nonce, sealed, additionalData)
+}
@@ -3,6 +3,9 @@ package auth
 import (
 	"context"
 	"errors"
+	"log/slog"
+	"net/netip"
+	"slices"
 	"strings"
 	"time"
 
@@ -14,52 +17,64 @@ import (
 	authv1 "github.com/vantutran2k1/rwe/gen/go/auth/v1"
 	"github.com/vantutran2k1/rwe/internal/auth/cache"
 	sqlc "github.com/vantutran2k1/rwe/internal/auth/db"
+	"github.com/vantutran2k1/rwe/internal/common/events"
 	"github.com/vantutran2k1/rwe/internal/common/token"
 	"github.com/vantutran2k1/rwe/internal/common/utils"
+	"github.com/vantutran2k1/rwe/internal/mailer"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 type Service struct {
-	pool       *pgxpool.Pool
-	querier    sqlc.Querier
-	blocklist  cache.Blocklist
-	tokenMaker TokenMaker
+	pool                 *pgxpool.Pool
+	querier              sqlc.Querier
+	blocklist            cache.Blocklist
+	tokenMaker           TokenMaker
+	keyRing              *KeyRing
+	mailer               mailer.Mailer
+	mfaBox               *SecretBox
+	mfaIssuer            string
+	oidc                 *OidcClient
+	loginGuard           *LoginGuard
+	trustedProxies       []netip.Prefix
+	refreshTokenDuration time.Duration
+	linkBaseURL          string
 	authv1.UnimplementedAuthServiceServer
 }
 
-func NewService(pool *pgxpool.Pool, tokenMaker TokenMaker, blocklist cache.Blocklist) *Service {
+func NewService(pool *pgxpool.Pool, tokenMaker TokenMaker, keyRing *KeyRing, blocklist cache.Blocklist, mailer mailer.Mailer, mfaBox *SecretBox, mfaIssuer string, oidc *OidcClient, loginGuard *LoginGuard, trustedProxies []netip.Prefix, refreshTokenDuration time.Duration, linkBaseURL string) *Service {
 	return &Service{
-		pool:       pool,
-		querier:    sqlc.New(pool),
-		blocklist:  blocklist,
-		tokenMaker: tokenMaker,
+		pool:                 pool,
+		querier:              sqlc.New(pool),
+		blocklist:            blocklist,
+		tokenMaker:           tokenMaker,
+		keyRing:              keyRing,
+		mailer:               mailer,
+		mfaBox:               mfaBox,
+		mfaIssuer:            mfaIssuer,
+		oidc:                 oidc,
+		loginGuard:           loginGuard,
+		trustedProxies:       trustedProxies,
+		linkBaseURL:          strings.TrimSuffix(linkBaseURL, "/"),
+		refreshTokenDuration: refreshTokenDuration,
 	}
 }
 
 func (s *Service) ValidateApiKey(ctx context.Context, req *authv1.ValidateApiKeyRequest) (*authv1.ValidateApiKeyResponse, error) {
-	if req.ApiKey == "" || !strings.HasPrefix(req.ApiKey, keyPrefix) {
-		return &authv1.ValidateApiKeyResponse{Valid: false}, nil
-	}
-
-	inputHash := HashKey(req.ApiKey)
-
-	key, err := s.querier.GetApiKeyByHash(ctx, inputHash)
+	key, err := LookupApiKey(ctx, s.querier, req.ApiKey)
 	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
+		if errors.Is(err, ErrInvalidApiKey) || errors.Is(err, ErrTenantInactive) {
 			return &authv1.ValidateApiKeyResponse{Valid: false}, nil
 		}
 		return nil, status.Errorf(codes.Internal, "validation error: %v", err)
 	}
 
-	if key.Revoked.Bool {
-		return &authv1.ValidateApiKeyResponse{Valid: false}, nil
-	}
-
 	return &authv1.ValidateApiKeyResponse{
-		Valid:    true,
-		TenantId: utils.PgUUIDToString(key.TenantID),
+		Valid:      true,
+		TenantId:   utils.PgUUIDToString(key.TenantID),
+		TenantRole: key.Role.String,
+		Scopes:     key.Scopes,
 	}, nil
 }
 
@@ -69,21 +84,70 @@ func (s *Service) IssueApiKey(ctx context.Context, req *authv1.IssueApiKeyReques
 		return nil, status.Errorf(codes.Internal, "failed to generate key: %v", err)
 	}
 
-	tenantId, err := uuid.Parse(req.TenantId)
+	scope := token.GetTenantScope(ctx)
+	if scope == nil {
+		return nil, status.Error(codes.PermissionDenied, "missing tenant scope")
+	}
+	tenantID := utils.UUIDToPgUUID(scope.TenantID)
+
+	role := req.Role
+	if role == "" {
+		role = defaultApiKeyRole
+	}
+
+	if ParseRole(role) == authv1.Role_ROLE_UNSPECIFIED {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid role: %s", role)
+	}
+
+	scopes, err := NormalizeScopes(req.Scopes)
 	if err != nil {
-		return nil, status.Errorf(codes.InvalidArgument, "invalid tenant id: %v", err)
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	prefixes, err := ParseCIDRs(req.AllowedCidrs)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	allowedCidrs := make([]string, 0, len(prefixes))
+	for _, prefix := range prefixes {
+		allowedCidrs = append(allowedCidrs, prefix.String())
+	}
+
+	var expiresAt pgtype.Timestamptz
+	if req.ExpiresAt != nil {
+		if !req.ExpiresAt.AsTime().After(time.Now()) {
+			return nil, status.Error(codes.InvalidArgument, "expires_at must be in the future")
+		}
+		expiresAt = utils.TimeToPgTimestamptz(req.ExpiresAt.AsTime())
+	}
+
+	if err := checkApiKeyGrant(ctx, scope, role, scopes); err != nil {
+		return nil, err
 	}
 
 	var row sqlc.CreateApiKeyRow
 	if err := s.execTx(ctx, func(querier sqlc.Querier) error {
-		row, err = s.querier.CreateApiKey(ctx, sqlc.CreateApiKeyParams{
-			TenantID:  utils.UUIDToPgUUID(tenantId),
-			KeyHash:   hashedKey,
-			KeyPrefix: prefix,
-			Name:      utils.StringToPgText(req.Name),
+		row, err = querier.CreateApiKey(ctx, sqlc.CreateApiKeyParams{
+			TenantID:     tenantID,
+			KeyHash:      hashedKey,
+			KeyPrefix:    prefix,
+			Name:         utils.StringToPgText(req.Name),
+			Role:         utils.StringToPgText(role),
+			ExpiresAt:    expiresAt,
+			Scopes:       scopes,
+			AllowedCidrs: allowedCidrs,
 		})
+		if err != nil {
+			return err
+		}
 
-		return err
+		return appendEvent(ctx, querier, tenantID, row.ID, events.ApiKeyIssued, map[string]any{
+			"name":          req.Name,
+			"prefix":        prefix,
+			"role":          role,
+			"scopes":        scopes,
+			"allowed_cidrs": allowedCidrs,
+		})
 	}); err != nil {
 		return nil, status.Errorf(codes.Internal, "error issuing api key: %v", err)
 	}
@@ -94,13 +158,139 @@ func (s *Service) IssueApiKey(ctx context.Context, req *authv1.IssueApiKeyReques
 	}, nil
 }
 
+func (s *Service) RotateApiKey(ctx context.Context, req *authv1.RotateApiKeyRequest) (*authv1.RotateApiKeyResponse, error) {
+	scope := token.GetTenantScope(ctx)
+	if scope == nil {
+		return nil, status.Error(codes.PermissionDenied, "missing tenant scope")
+	}
+	tenantID := utils.UUIDToPgUUID(scope.TenantID)
+
+	keyID, err := uuid.Parse(req.Id)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid api key id: %v", err)
+	}
+
+	overlap := defaultApiKeyRotationOverlap
+	if req.OverlapSeconds < 0 || time.Duration(req.OverlapSeconds)*time.Second > maxApiKeyRotationOverlap {
+		return nil, status.Errorf(codes.InvalidArgument, "overlap_seconds must be between 0 and %d", int(maxApiKeyRotationOverlap.Seconds()))
+	}
+	if req.OverlapSeconds > 0 {
+		overlap = time.Duration(req.OverlapSeconds) * time.Second
+	}
+
+	if req.ExpiresAt != nil && !req.ExpiresAt.AsTime().After(time.Now()) {
+		return nil, status.Error(codes.InvalidArgument, "expires_at must be in the future")
+	}
+
+	rawKey, hashedKey, prefix, err := GenerateAPIKey()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to generate key: %v", err)
+	}
+
+	var reqErr error
+	var row sqlc.CreateApiKeyRow
+	var oldExpiresAt time.Time
+	if err := s.execTx(ctx, func(querier sqlc.Querier) error {
+		old, err := querier.GetApiKeyForUpdate(ctx, sqlc.GetApiKeyForUpdateParams{
+			ID:       utils.UUIDToPgUUID(keyID),
+			TenantID: tenantID,
+		})
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				reqErr = status.Errorf(codes.NotFound, "api key with id %s not found", keyID)
+			}
+			return err
+		}
+
+		if old.Revoked.Bool || old.RotatedTo.Valid || (old.ExpiresAt.Valid && !old.ExpiresAt.Time.After(time.Now())) {
+			reqErr = status.Errorf(codes.FailedPrecondition, "api key %s is revoked, expired or already rotated", keyID)
+			return reqErr
+		}
+
+		if reqErr = checkApiKeyGrant(ctx, scope, old.Role.String, old.Scopes); reqErr != nil {
+			return reqErr
+		}
+
+		expiresAt := old.ExpiresAt
+		if req.ExpiresAt != nil {
+			expiresAt = utils.TimeToPgTimestamptz(req.ExpiresAt.AsTime())
+		}
+
+		row, err = querier.CreateApiKey(ctx, sqlc.CreateApiKeyParams{
+			TenantID:     tenantID,
+			KeyHash:      hashedKey,
+			KeyPrefix:    prefix,
+			Name:         old.Name,
+			Role:         old.Role,
+			ExpiresAt:    expiresAt,
+			Scopes:       old.Scopes,
+			AllowedCidrs: old.AllowedCidrs,
+		})
+		if err != nil {
+			return err
+		}
+
+		// The old key keeps working for the overlap window, but never longer
+		// than it would have without the rotation.
+		oldExpiresAt = time.Now().Add(overlap)
+		if old.ExpiresAt.Valid && old.ExpiresAt.Time.Before(oldExpiresAt) {
+			oldExpiresAt = old.ExpiresAt.Time
+		}
+
+		if err := querier.MarkApiKeyRotated(ctx, sqlc.MarkApiKeyRotatedParams{
+			ID:        old.ID,
+			RotatedTo: row.ID,
+			ExpiresAt: utils.TimeToPgTimestamptz(oldExpiresAt),
+		}); err != nil {
+			return err
+		}
+
+		return appendEvent(ctx, querier, tenantID, old.ID, events.ApiKeyRotated, map[string]any{
+			"rotated_to":         utils.PgUUIDToString(row.ID),
+			"prefix":             prefix,
+			"old_key_expires_at": oldExpiresAt,
+		})
+	}); err != nil {
+		if reqErr != nil {
+			return nil, reqErr
+		}
+
+		return nil, status.Errorf(codes.Internal, "error rotating api key: %v", err)
+	}
+
+	return &authv1.RotateApiKeyResponse{
+		RawApiKey:       rawKey,
+		Id:              utils.PgUUIDToString(row.ID),
+		OldKeyExpiresAt: timestamppb.New(oldExpiresAt),
+	}, nil
+}
+
 func (s *Service) RevokeApiKey(ctx context.Context, req *authv1.RevokeApiKeyRequest) (*authv1.RevokeApiKeyResponse, error) {
+	tenantID, err := tenantFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	keyId, err := uuid.Parse(req.Id)
 	if err != nil {
 		return &authv1.RevokeApiKeyResponse{Success: false}, status.Errorf(codes.InvalidArgument, "invalid key id: %v", err)
 	}
 
-	if err := s.querier.RevokeApiKey(ctx, utils.UUIDToPgUUID(keyId)); err != nil {
+	if err := s.execTx(ctx, func(querier sqlc.Querier) error {
+		key, err := querier.RevokeApiKey(ctx, sqlc.RevokeApiKeyParams{
+			ID:       utils.UUIDToPgUUID(keyId),
+			TenantID: tenantID,
+		})
+		if err != nil {
+			return err
+		}
+
+		return appendEvent(ctx, querier, key.TenantID, key.ID, events.ApiKeyRevoked, nil)
+	}); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, status.Errorf(codes.NotFound, "api key with id %s not found", keyId)
+		}
+
 		return nil, status.Errorf(codes.Internal, "failed to revoke key: %v", err)
 	}
 
@@ -108,20 +298,21 @@ func (s *Service) RevokeApiKey(ctx context.Context, req *authv1.RevokeApiKeyRequ
 }
 
 func (s *Service) ListApiKeys(ctx context.Context, req *authv1.ListApiKeysRequest) (*authv1.ListApiKeysResponse, error) {
-	tenantId, err := uuid.Parse(req.TenantId)
+	tenantID, err := tenantFromContext(ctx)
 	if err != nil {
-		return nil, status.Errorf(codes.InvalidArgument, "invalid tenant id: %v", err)
+		return nil, err
 	}
 
-	rows, err := s.querier.ListApiKeys(ctx, utils.UUIDToPgUUID(tenantId))
+	rows, err := s.querier.ListApiKeys(ctx, tenantID)
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "error listing api keys: %v", err)
 	}
 
 	results := make([]*authv1.ApiKeyMetadata, 0, len(rows))
-	var lastUsed *timestamppb.Timestamp
-	var expires *timestamppb.Timestamp
 	for _, r := range rows {
+		var lastUsed *timestamppb.Timestamp
+		var expires *timestamppb.Timestamp
+		var rotatedTo string
 		if r.LastUsedAt.Valid {
 			lastUsed = timestamppb.New(r.LastUsedAt.Time)
 		}
@@ -130,14 +321,22 @@ func (s *Service) ListApiKeys(ctx context.Context, req *authv1.ListApiKeysReques
 			expires = timestamppb.New(r.ExpiresAt.Time)
 		}
 
+		if r.RotatedTo.Valid {
+			rotatedTo = utils.PgUUIDToString(r.RotatedTo)
+		}
+
 		meta := &authv1.ApiKeyMetadata{
-			Id:         utils.PgUUIDToString(r.ID),
-			Name:       r.Name.String,
-			Prefix:     r.KeyPrefix,
-			Revoked:    r.Revoked.Bool,
-			CreatedAt:  timestamppb.New(r.CreatedAt.Time),
-			LastUsedAt: lastUsed,
-			ExpiresAt:  expires,
+			Id:           utils.PgUUIDToString(r.ID),
+			Name:         r.Name.String,
+			Prefix:       r.KeyPrefix,
+			Role:         r.Role.String,
+			Revoked:      r.Revoked.Bool,
+			CreatedAt:    timestamppb.New(r.CreatedAt.Time),
+			LastUsedAt:   lastUsed,
+			ExpiresAt:    expires,
+			Scopes:       r.Scopes,
+			AllowedCidrs: r.AllowedCidrs,
+			RotatedTo:    rotatedTo,
 		}
 
 		results = append(results, meta)
@@ -147,7 +346,12 @@ func (s *Service) ListApiKeys(ctx context.Context, req *authv1.ListApiKeysReques
 }
 
 func (s *Service) Register(ctx context.Context, req *authv1.RegisterRequest) (*authv1.RegisterResponse, error) {
-	if err := ValidateEmail(req.Email); err != nil {
+	email := normalizeEmail(req.Email)
+	if err := ValidateEmail(email); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	if err := ValidatePassword(req.Password); err != nil {
 		return nil, status.Error(codes.InvalidArgument, err.Error())
 	}
 
@@ -160,7 +364,7 @@ func (s *Service) Register(ctx context.Context, req *authv1.RegisterRequest) (*a
 	var userId pgtype.UUID
 	if err := s.execTx(ctx, func(querier sqlc.Querier) error {
 		userId, err = querier.CreateUser(ctx, sqlc.CreateUserParams{
-			Email:        req.Email,
+			Email:        email,
 			PasswordHash: passwordHash,
 			FullName:     utils.StringToPgText(req.FullName),
 		})
@@ -170,24 +374,39 @@ func (s *Service) Register(ctx context.Context, req *authv1.RegisterRequest) (*a
 				reqErr = errors.New("duplicate email")
 				return reqErr
 			}
+
+			return err
 		}
 
-		return err
+		return appendUserEvent(ctx, querier, userId, userId, events.UserRegistered, map[string]any{
+			"email": email,
+		})
 	}); err != nil {
 		if reqErr != nil {
-			return nil, status.Errorf(codes.AlreadyExists, "duplicate email: %s", req.Email)
+			return nil, status.Errorf(codes.AlreadyExists, "duplicate email: %s", email)
 		}
 
 		return nil, status.Errorf(codes.Internal, "error registering user: %v", err)
 	}
 
+	if err := s.sendVerificationEmail(ctx, userId, email); err != nil {
+		slog.Error("failed to send verification email", "user_id", utils.PgUUIDToString(userId), "error", err)
+	}
+
 	return &authv1.RegisterResponse{UserId: utils.PgUUIDToString(userId)}, nil
 }
 
 func (s *Service) Login(ctx context.Context, req *authv1.LoginRequest) (*authv1.LoginResponse, error) {
-	row, err := s.querier.GetUserByEmailWithPassword(ctx, req.Email)
+	email := normalizeEmail(req.Email)
+	ip := s.loginGuard.clientIP(ctx)
+	if err := s.loginGuard.check(ctx, email, ip); err != nil {
+		return nil, err
+	}
+
+	row, err := s.querier.GetUserByEmailWithPassword(ctx, email)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
+			s.recordLoginFailure(ctx, email, ip, pgtype.UUID{})
 			return nil, status.Error(codes.Unauthenticated, "invalid email or password")
 		}
 
@@ -195,22 +414,40 @@ func (s *Service) Login(ctx context.Context, req *authv1.LoginRequest) (*authv1.
 	}
 
 	if err := CheckPassword(req.Password, row.PasswordHash); err != nil {
+		s.recordLoginFailure(ctx, email, ip, row.ID)
 		return nil, status.Error(codes.Unauthenticated, "invalid email or password")
 	}
 
+	// With MFA enabled the failures are only reset once VerifyMfa succeeds,
+	// so that knowing the password does not give unlimited code guesses.
+	challenge, err := s.mfaChallenge(ctx, row.ID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "error creating mfa challenge: %v", err)
+	}
+	if challenge != nil {
+		return challenge, nil
+	}
+
+	if err := s.loginGuard.reset(ctx, email); err != nil {
+		slog.Warn("failed to reset login failures", "error", err)
+	}
+
 	userID, err := uuid.Parse(utils.PgUUIDToString(row.ID))
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "error parsing user id: %v", err)
 	}
 
-	t, payload, err := s.tokenMaker.CreateToken(req.Email, userID)
+	tokens, err := s.createSession(ctx, userID, email)
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "error generating token: %v", err)
+		return nil, status.Errorf(codes.Internal, "error creating session: %v", err)
 	}
 
 	return &authv1.LoginResponse{
-		AccessToken: t,
-		ExpiresAt:   timestamppb.New(payload.ExpiredAt),
+		AccessToken:           tokens.accessToken,
+		ExpiresAt:             timestamppb.New(tokens.accessPayload.ExpiredAt),
+		RefreshToken:          tokens.refreshToken,
+		RefreshTokenExpiresAt: timestamppb.New(tokens.refreshExpiresAt),
+		SessionId:             tokens.accessPayload.SessionID.String(),
 	}, nil
 }
 
@@ -220,17 +457,95 @@ func (s *Service) Logout(ctx context.Context, req *authv1.LogoutRequest) (*authv
 		return nil, status.Error(codes.Unauthenticated, "missing user authentication")
 	}
 
-	timeLeft := time.Until(tokenPayload.ExpiredAt)
-	if timeLeft > 0 {
-		err := s.blocklist.AddToBlocklist(ctx, tokenPayload.ID.String(), timeLeft)
-		if err != nil {
-			return nil, status.Errorf(codes.Internal, "failed to process logout")
+	if err := s.execTx(ctx, func(querier sqlc.Querier) error {
+		session, err := querier.RevokeSession(ctx, sqlc.RevokeSessionParams{
+			ID:     utils.UUIDToPgUUID(tokenPayload.SessionID),
+			UserID: utils.UUIDToPgUUID(tokenPayload.UserID),
+		})
+		if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			return err
 		}
+
+		if err == nil {
+			if err := appendUserEvent(ctx, querier, utils.UUIDToPgUUID(tokenPayload.UserID), session.ID, events.SessionRevoked, map[string]any{
+				"user_id": tokenPayload.UserID.String(),
+				"reason":  "logout",
+			}); err != nil {
+				return err
+			}
+		}
+
+		timeLeft := time.Until(tokenPayload.ExpiredAt)
+		if timeLeft > 0 {
+			return s.blocklist.AddToBlocklist(ctx, tokenPayload.ID.String(), timeLeft)
+		}
+
+		return nil
+	}); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to process logout")
 	}
 
 	return &authv1.LogoutResponse{Message: "log out successfully"}, nil
 }
 
+func (s *Service) ListPublicKeys(ctx context.Context, req *authv1.ListPublicKeysRequest) (*authv1.ListPublicKeysResponse, error) {
+	// Symmetric tokens have no public verification keys.
+	if s.keyRing == nil {
+		return &authv1.ListPublicKeysResponse{}, nil
+	}
+
+	signingKeys := s.keyRing.publicKeys()
+	keys := make([]*authv1.PublicKey, 0, len(signingKeys))
+	for _, key := range signingKeys {
+		publicKey := &authv1.PublicKey{
+			Kid:       key.kid,
+			Algorithm: signingKeyAlgorithm,
+			Key:       paserk(key.public),
+			CreatedAt: timestamppb.New(key.createdAt),
+		}
+		if key.expiresAt != nil {
+			publicKey.ExpiresAt = timestamppb.New(*key.expiresAt)
+		}
+		keys = append(keys, publicKey)
+	}
+
+	return &authv1.ListPublicKeysResponse{Keys: keys}, nil
+}
+
+// checkApiKeyGrant stops callers from minting keys more powerful than their
+// own credentials: the key role may not exceed the caller's role, and a caller
+// authenticated by a scoped key may only grant scopes it holds.
+func checkApiKeyGrant(ctx context.Context, scope *token.TenantScope, role string, scopes []string) error {
+	if ParseRole(role) > ParseRole(scope.Role) {
+		return status.Errorf(codes.PermissionDenied, "cannot issue an api key with role %s above your own role %s", role, scope.Role)
+	}
+
+	callerKey := token.GetApiKey(ctx)
+	if callerKey == nil || len(callerKey.Scopes) == 0 {
+		return nil
+	}
+
+	if len(scopes) == 0 {
+		return status.Error(codes.PermissionDenied, "a scoped api key cannot issue an unscoped api key")
+	}
+	for _, granted := range scopes {
+		if !slices.ContainsFunc(callerKey.Scopes, func(held string) bool { return scopeCovers(held, granted) }) {
+			return status.Errorf(codes.PermissionDenied, "cannot grant scope %s", granted)
+		}
+	}
+
+	return nil
+}
+
+func tenantFromContext(ctx context.Context) (pgtype.UUID, error) {
+	scope := token.GetTenantScope(ctx)
+	if scope == nil {
+		return pgtype.UUID{}, status.Error(codes.PermissionDenied, "missing tenant scope")
+	}
+
+	return utils.UUIDToPgUUID(scope.TenantID), nil
+}
+
 func (s *Service) execTx(ctx context.Context, fn func(sqlc.Querier) error) error {
 	tx, err := s.pool.Begin(ctx)
 	if err != nil {
@@ -246,3 +561,37 @@ func (s *Service) execTx(ctx context.Context, fn func(sqlc.Querier) error) error
 
 	return tx.Commit(ctx)
 }
+
+func appendEvent(ctx context.Context, querier sqlc.Querier, tenantID, aggregateID pgtype.UUID, eventType string, payload any) error {
+	data, err := events.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	_, err = querier.AppendEvent(ctx, sqlc.AppendEventParams{
+		TenantID:    tenantID,
+		EventType:   eventType,
+		AggregateID: aggregateID,
+		Payload:     data,
+	})
+
+	return err
+}
+
+// appendUserEvent appends an account event, which belongs to no tenant and
+// is streamed to its user instead.
+func appendUserEvent(ctx context.Context, querier sqlc.Querier, userID, aggregateID pgtype.UUID, eventType string, payload any) error {
+	data, err := events.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	_, err = querier.AppendEvent(ctx, sqlc.AppendEventParams{
+		EventType:   eventType,
+		AggregateID: aggregateID,
+		Payload:     data,
+		UserID:      userID,
+	})
+
+	return err
+}
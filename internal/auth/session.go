@@ -0,0 +1,288 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	authv1 "github.com/vantutran2k1/rwe/gen/go/auth/v1"
+	sqlc "github.com/vantutran2k1/rwe/internal/auth/db"
+	"github.com/vantutran2k1/rwe/internal/common/events"
+	"github.com/vantutran2k1/rwe/internal/common/token"
+	"github.com/vantutran2k1/rwe/internal/common/utils"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type sessionTokens struct {
+	accessToken      string
+	accessPayload    *token.Payload
+	refreshToken     string
+	refreshExpiresAt time.Time
+}
+
+func (s *Service) RefreshToken(ctx context.Context, req *authv1.RefreshTokenRequest) (*authv1.RefreshTokenResponse, error) {
+	if req.RefreshToken == "" || !strings.HasPrefix(req.RefreshToken, refreshTokenPrefix) {
+		return nil, status.Error(codes.Unauthenticated, "invalid refresh token")
+	}
+
+	userAgent, ipAddress := s.clientInfo(ctx)
+
+	var reqErr error
+	var tokens sessionTokens
+	if err := s.execTx(ctx, func(querier sqlc.Querier) error {
+		row, err := querier.GetRefreshTokenForUpdate(ctx, HashKey(req.RefreshToken))
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				reqErr = status.Error(codes.Unauthenticated, "invalid refresh token")
+			}
+			return err
+		}
+
+		if row.RevokedAt.Valid || !row.ExpiresAt.Time.After(time.Now()) {
+			reqErr = status.Error(codes.Unauthenticated, "session has expired or been revoked")
+			return reqErr
+		}
+
+		if row.UsedAt.Valid {
+			// A rotated token was presented again, so it may have been stolen:
+			// revoke the whole session but commit so the revocation sticks.
+			reqErr = status.Error(codes.Unauthenticated, "refresh token reuse detected, session revoked")
+			return s.revokeReusedSession(ctx, querier, row)
+		}
+
+		if err := querier.MarkRefreshTokenUsed(ctx, row.ID); err != nil {
+			return err
+		}
+
+		tokens, err = s.issueTokens(ctx, querier, uuid.UUID(row.UserID.Bytes), row.Email, uuid.UUID(row.SessionID.Bytes), row.ExpiresAt.Time)
+		if err != nil {
+			return err
+		}
+
+		return querier.RotateSessionAccessToken(ctx, sqlc.RotateSessionAccessTokenParams{
+			ID:                   row.SessionID,
+			AccessTokenID:        utils.UUIDToPgUUID(tokens.accessPayload.ID),
+			AccessTokenExpiresAt: utils.TimeToPgTimestamptz(tokens.accessPayload.ExpiredAt),
+			UserAgent:            pgtype.Text{String: userAgent, Valid: userAgent != ""},
+			IpAddress:            pgtype.Text{String: ipAddress, Valid: ipAddress != ""},
+		})
+	}); err != nil {
+		if reqErr != nil {
+			return nil, reqErr
+		}
+
+		return nil, status.Errorf(codes.Internal, "error refreshing token: %v", err)
+	}
+
+	if reqErr != nil {
+		return nil, reqErr
+	}
+
+	return &authv1.RefreshTokenResponse{
+		AccessToken:           tokens.accessToken,
+		ExpiresAt:             timestamppb.New(tokens.accessPayload.ExpiredAt),
+		RefreshToken:          tokens.refreshToken,
+		RefreshTokenExpiresAt: timestamppb.New(tokens.refreshExpiresAt),
+		SessionId:             tokens.accessPayload.SessionID.String(),
+	}, nil
+}
+
+func (s *Service) ListSessions(ctx context.Context, req *authv1.ListSessionsRequest) (*authv1.ListSessionsResponse, error) {
+	tokenPayload := token.GetTokenPayload(ctx)
+	if tokenPayload == nil {
+		return nil, status.Error(codes.Unauthenticated, "missing user authentication")
+	}
+
+	rows, err := s.querier.ListActiveSessions(ctx, utils.UUIDToPgUUID(tokenPayload.UserID))
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "error listing sessions: %v", err)
+	}
+
+	sessions := make([]*authv1.Session, 0, len(rows))
+	for _, row := range rows {
+		sessions = append(sessions, &authv1.Session{
+			Id:         utils.PgUUIDToString(row.ID),
+			UserAgent:  row.UserAgent.String,
+			IpAddress:  row.IpAddress.String,
+			CreatedAt:  timestamppb.New(row.CreatedAt.Time),
+			LastUsedAt: timestamppb.New(row.LastUsedAt.Time),
+			ExpiresAt:  timestamppb.New(row.ExpiresAt.Time),
+			Current:    uuid.UUID(row.ID.Bytes) == tokenPayload.SessionID,
+		})
+	}
+
+	return &authv1.ListSessionsResponse{Sessions: sessions}, nil
+}
+
+func (s *Service) RevokeSession(ctx context.Context, req *authv1.RevokeSessionRequest) (*authv1.RevokeSessionResponse, error) {
+	tokenPayload := token.GetTokenPayload(ctx)
+	if tokenPayload == nil {
+		return nil, status.Error(codes.Unauthenticated, "missing user authentication")
+	}
+
+	sessionID, err := uuid.Parse(req.Id)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid session id: %v", err)
+	}
+
+	if err := s.execTx(ctx, func(querier sqlc.Querier) error {
+		session, err := querier.RevokeSession(ctx, sqlc.RevokeSessionParams{
+			ID:     utils.UUIDToPgUUID(sessionID),
+			UserID: utils.UUIDToPgUUID(tokenPayload.UserID),
+		})
+		if err != nil {
+			return err
+		}
+
+		if err := appendUserEvent(ctx, querier, utils.UUIDToPgUUID(tokenPayload.UserID), session.ID, events.SessionRevoked, map[string]any{
+			"user_id": tokenPayload.UserID.String(),
+			"reason":  "revoked",
+		}); err != nil {
+			return err
+		}
+
+		return s.blocklistAccessToken(ctx, session.AccessTokenID, session.AccessTokenExpiresAt)
+	}); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, status.Errorf(codes.NotFound, "active session with id %s not found", sessionID)
+		}
+
+		return nil, status.Errorf(codes.Internal, "error revoking session: %v", err)
+	}
+
+	return &authv1.RevokeSessionResponse{Success: true}, nil
+}
+
+func (s *Service) createSession(ctx context.Context, userID uuid.UUID, email string) (sessionTokens, error) {
+	sessionID, err := uuid.NewRandom()
+	if err != nil {
+		return sessionTokens{}, err
+	}
+
+	userAgent, ipAddress := s.clientInfo(ctx)
+	expiresAt := time.Now().Add(s.refreshTokenDuration)
+
+	var tokens sessionTokens
+	err = s.execTx(ctx, func(querier sqlc.Querier) error {
+		session, err := querier.CreateSession(ctx, sqlc.CreateSessionParams{
+			ID:        utils.UUIDToPgUUID(sessionID),
+			UserID:    utils.UUIDToPgUUID(userID),
+			UserAgent: pgtype.Text{String: userAgent, Valid: userAgent != ""},
+			IpAddress: pgtype.Text{String: ipAddress, Valid: ipAddress != ""},
+			ExpiresAt: utils.TimeToPgTimestamptz(expiresAt),
+		})
+		if err != nil {
+			return err
+		}
+
+		tokens, err = s.issueTokens(ctx, querier, userID, email, sessionID, expiresAt)
+		if err != nil {
+			return err
+		}
+
+		if err := querier.RotateSessionAccessToken(ctx, sqlc.RotateSessionAccessTokenParams{
+			ID:                   session.ID,
+			AccessTokenID:        utils.UUIDToPgUUID(tokens.accessPayload.ID),
+			AccessTokenExpiresAt: utils.TimeToPgTimestamptz(tokens.accessPayload.ExpiredAt),
+		}); err != nil {
+			return err
+		}
+
+		return appendUserEvent(ctx, querier, utils.UUIDToPgUUID(userID), session.ID, events.SessionCreated, map[string]any{
+			"user_id":    userID.String(),
+			"user_agent": userAgent,
+			"ip_address": ipAddress,
+		})
+	})
+
+	return tokens, err
+}
+
+func (s *Service) issueTokens(ctx context.Context, querier sqlc.Querier, userID uuid.UUID, email string, sessionID uuid.UUID, expiresAt time.Time) (sessionTokens, error) {
+	accessToken, payload, err := s.tokenMaker.CreateToken(email, userID, sessionID)
+	if err != nil {
+		return sessionTokens{}, err
+	}
+
+	refreshToken, err := createRefreshToken(ctx, querier, utils.UUIDToPgUUID(sessionID))
+	if err != nil {
+		return sessionTokens{}, err
+	}
+
+	return sessionTokens{
+		accessToken:      accessToken,
+		accessPayload:    payload,
+		refreshToken:     refreshToken,
+		refreshExpiresAt: expiresAt,
+	}, nil
+}
+
+func (s *Service) revokeReusedSession(ctx context.Context, querier sqlc.Querier, row sqlc.GetRefreshTokenForUpdateRow) error {
+	if _, err := querier.RevokeSession(ctx, sqlc.RevokeSessionParams{
+		ID:     row.SessionID,
+		UserID: row.UserID,
+	}); err != nil {
+		return err
+	}
+
+	if err := appendUserEvent(ctx, querier, row.UserID, row.SessionID, events.RefreshTokenReused, map[string]any{
+		"user_id": utils.PgUUIDToString(row.UserID),
+	}); err != nil {
+		return err
+	}
+
+	return s.blocklistAccessToken(ctx, row.AccessTokenID, row.AccessTokenExpiresAt)
+}
+
+func (s *Service) blocklistAccessToken(ctx context.Context, tokenID pgtype.UUID, expiresAt pgtype.Timestamptz) error {
+	if !tokenID.Valid || !expiresAt.Valid {
+		return nil
+	}
+
+	timeLeft := time.Until(expiresAt.Time)
+	if timeLeft <= 0 {
+		return nil
+	}
+
+	return s.blocklist.AddToBlocklist(ctx, utils.PgUUIDToString(tokenID), timeLeft)
+}
+
+func createRefreshToken(ctx context.Context, querier sqlc.Querier, sessionID pgtype.UUID) (string, error) {
+	rawToken, hashedToken, err := GenerateRefreshToken()
+	if err != nil {
+		return "", err
+	}
+
+	if err := querier.CreateRefreshToken(ctx, sqlc.CreateRefreshTokenParams{
+		SessionID: sessionID,
+		TokenHash: hashedToken,
+	}); err != nil {
+		return "", err
+	}
+
+	return rawToken, nil
+}
+
+func (s *Service) clientInfo(ctx context.Context) (string, string) {
+	var userAgent, ipAddress string
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	if values := md.Get("grpcgateway-user-agent"); len(values) > 0 {
+		userAgent = values[0]
+	} else if values := md.Get("user-agent"); len(values) > 0 {
+		userAgent = values[0]
+	}
+
+	if ip, ok := ClientIP(ctx, s.trustedProxies); ok {
+		ipAddress = ip.String()
+	}
+
+	return userAgent, ipAddress
+}
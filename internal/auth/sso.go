@@ -0,0 +1,485 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	authv1 "github.com/vantutran2k1/rwe/gen/go/auth/v1"
+	sqlc "github.com/vantutran2k1/rwe/internal/auth/db"
+	"github.com/vantutran2k1/rwe/internal/common/events"
+	"github.com/vantutran2k1/rwe/internal/common/token"
+	"github.com/vantutran2k1/rwe/internal/common/utils"
+	"golang.org/x/oauth2"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	oidcLoginStateDuration = 10 * time.Minute
+	defaultSsoRole         = "member"
+)
+
+type oidcClaims struct {
+	Email         string `json:"email"`
+	EmailVerified bool   `json:"email_verified"`
+	Name          string `json:"name"`
+}
+
+func (s *Service) SetOidcProvider(ctx context.Context, req *authv1.SetOidcProviderRequest) (*authv1.SetOidcProviderResponse, error) {
+	tenantID, err := tenantFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Issuer == "" || req.ClientId == "" {
+		return nil, status.Error(codes.InvalidArgument, "issuer and client_id are required")
+	}
+
+	defaultRole := req.DefaultRole
+	if defaultRole == "" {
+		defaultRole = defaultSsoRole
+	}
+	if role := ParseRole(defaultRole); role == authv1.Role_ROLE_UNSPECIFIED || role >= authv1.Role_ROLE_OWNER {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid default role: %s", req.DefaultRole)
+	}
+
+	verifiedDomains, err := s.querier.ListVerifiedTenantDomains(ctx, tenantID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "error listing domains: %v", err)
+	}
+
+	// Only domains the tenant proved to own may be restricted to, so a
+	// provider cannot claim the addresses of other organisations.
+	allowedDomains := make([]string, 0, len(req.AllowedDomains))
+	for _, allowed := range req.AllowedDomains {
+		domain, ok := normalizeDomain(allowed)
+		if !ok {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid allowed domain: %s", allowed)
+		}
+		if !slices.Contains(verifiedDomains, domain) {
+			return nil, status.Errorf(codes.FailedPrecondition, "domain %s is not verified", domain)
+		}
+		allowedDomains = append(allowedDomains, domain)
+	}
+
+	var encryptedSecret []byte
+	if req.ClientSecret != "" {
+		encryptedSecret, err = s.oidc.box.Seal([]byte(req.ClientSecret), oidcAdditionalData(tenantID))
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "error encrypting client secret: %v", err)
+		}
+	} else {
+		existing, err := s.querier.GetOidcProvider(ctx, tenantID)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return nil, status.Error(codes.InvalidArgument, "client_secret is required")
+			}
+			return nil, status.Errorf(codes.Internal, "error getting oidc provider: %v", err)
+		}
+		encryptedSecret = existing.EncryptedClientSecret
+	}
+
+	// Fail early on an issuer whose discovery document cannot be loaded.
+	if _, err := s.oidc.provider(ctx, req.Issuer); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "error discovering issuer %s: %v", req.Issuer, err)
+	}
+
+	var provider sqlc.OidcProvider
+	if err := s.execTx(ctx, func(querier sqlc.Querier) error {
+		provider, err = querier.UpsertOidcProvider(ctx, sqlc.UpsertOidcProviderParams{
+			TenantID:              tenantID,
+			Issuer:                req.Issuer,
+			ClientID:              req.ClientId,
+			EncryptedClientSecret: encryptedSecret,
+			AllowedDomains:        allowedDomains,
+			DefaultRole:           defaultRole,
+			Enabled:               req.Enabled,
+		})
+		if err != nil {
+			return err
+		}
+
+		return appendEvent(ctx, querier, tenantID, tenantID, events.OidcProviderUpdated, map[string]any{
+			"issuer":    req.Issuer,
+			"client_id": req.ClientId,
+			"enabled":   req.Enabled,
+		})
+	}); err != nil {
+		return nil, status.Errorf(codes.Internal, "error saving oidc provider: %v", err)
+	}
+
+	return &authv1.SetOidcProviderResponse{Provider: &authv1.OidcProvider{
+		Issuer:         provider.Issuer,
+		ClientId:       provider.ClientID,
+		AllowedDomains: provider.AllowedDomains,
+		DefaultRole:    provider.DefaultRole,
+		Enabled:        provider.Enabled,
+		CreatedAt:      timestamppb.New(provider.CreatedAt.Time),
+		UpdatedAt:      timestamppb.New(provider.UpdatedAt.Time),
+	}}, nil
+}
+
+func (s *Service) GetOidcProvider(ctx context.Context, req *authv1.GetOidcProviderRequest) (*authv1.GetOidcProviderResponse, error) {
+	tenantID, err := tenantFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	provider, err := s.querier.GetOidcProvider(ctx, tenantID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, status.Error(codes.NotFound, "oidc provider is not configured")
+		}
+		return nil, status.Errorf(codes.Internal, "error getting oidc provider: %v", err)
+	}
+
+	return &authv1.GetOidcProviderResponse{Provider: &authv1.OidcProvider{
+		Issuer:         provider.Issuer,
+		ClientId:       provider.ClientID,
+		AllowedDomains: provider.AllowedDomains,
+		DefaultRole:    provider.DefaultRole,
+		Enabled:        provider.Enabled,
+		CreatedAt:      timestamppb.New(provider.CreatedAt.Time),
+		UpdatedAt:      timestamppb.New(provider.UpdatedAt.Time),
+	}}, nil
+}
+
+func (s *Service) DeleteOidcProvider(ctx context.Context, req *authv1.DeleteOidcProviderRequest) (*authv1.DeleteOidcProviderResponse, error) {
+	tenantID, err := tenantFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var reqErr error
+	if err := s.execTx(ctx, func(querier sqlc.Querier) error {
+		rows, err := querier.DeleteOidcProvider(ctx, tenantID)
+		if err != nil {
+			return err
+		}
+		if rows == 0 {
+			reqErr = status.Error(codes.NotFound, "oidc provider is not configured")
+			return reqErr
+		}
+
+		return appendEvent(ctx, querier, tenantID, tenantID, events.OidcProviderDeleted, nil)
+	}); err != nil {
+		if reqErr != nil {
+			return nil, reqErr
+		}
+
+		return nil, status.Errorf(codes.Internal, "error deleting oidc provider: %v", err)
+	}
+
+	return &authv1.DeleteOidcProviderResponse{Success: true}, nil
+}
+
+func (s *Service) StartOidcLogin(ctx context.Context, req *authv1.StartOidcLoginRequest) (*authv1.StartOidcLoginResponse, error) {
+	authURL, expiresAt, err := s.startOidcFlow(ctx, req.TenantSlug, req.LoginHint, pgtype.UUID{})
+	if err != nil {
+		return nil, err
+	}
+
+	return &authv1.StartOidcLoginResponse{
+		AuthorizationUrl: authURL,
+		ExpiresAt:        timestamppb.New(expiresAt),
+	}, nil
+}
+
+// StartOidcLink starts a login whose identity is linked to the calling
+// account, which is the only way to attach SSO to an existing account.
+func (s *Service) StartOidcLink(ctx context.Context, req *authv1.StartOidcLinkRequest) (*authv1.StartOidcLinkResponse, error) {
+	tokenPayload := token.GetTokenPayload(ctx)
+	if tokenPayload == nil {
+		return nil, status.Error(codes.Unauthenticated, "missing user authentication")
+	}
+
+	authURL, expiresAt, err := s.startOidcFlow(ctx, req.TenantSlug, tokenPayload.Email, utils.UUIDToPgUUID(tokenPayload.UserID))
+	if err != nil {
+		return nil, err
+	}
+
+	return &authv1.StartOidcLinkResponse{
+		AuthorizationUrl: authURL,
+		ExpiresAt:        timestamppb.New(expiresAt),
+	}, nil
+}
+
+func (s *Service) startOidcFlow(ctx context.Context, tenantSlug, loginHint string, linkUserID pgtype.UUID) (string, time.Time, error) {
+	row, err := s.querier.GetOidcProviderBySlug(ctx, tenantSlug)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return "", time.Time{}, status.Errorf(codes.Internal, "error getting oidc provider: %v", err)
+	}
+	if err != nil || !row.Enabled {
+		return "", time.Time{}, status.Errorf(codes.NotFound, "sso is not enabled for tenant %s", tenantSlug)
+	}
+
+	provider, err := s.oidc.provider(ctx, row.Issuer)
+	if err != nil {
+		return "", time.Time{}, status.Errorf(codes.Unavailable, "error discovering issuer: %v", err)
+	}
+
+	state, err := randomString()
+	if err != nil {
+		return "", time.Time{}, status.Errorf(codes.Internal, "error generating state: %v", err)
+	}
+	nonce, err := randomString()
+	if err != nil {
+		return "", time.Time{}, status.Errorf(codes.Internal, "error generating nonce: %v", err)
+	}
+	verifier := oauth2.GenerateVerifier()
+	expiresAt := time.Now().Add(oidcLoginStateDuration)
+
+	if err := s.querier.CreateOidcLoginState(ctx, sqlc.CreateOidcLoginStateParams{
+		StateHash:    HashKey(state),
+		TenantID:     row.TenantID,
+		CodeVerifier: verifier,
+		Nonce:        nonce,
+		ExpiresAt:    utils.TimeToPgTimestamptz(expiresAt),
+		LinkUserID:   linkUserID,
+	}); err != nil {
+		return "", time.Time{}, status.Errorf(codes.Internal, "error saving login state: %v", err)
+	}
+
+	opts := []oauth2.AuthCodeOption{oidc.Nonce(nonce), oauth2.S256ChallengeOption(verifier)}
+	if loginHint != "" {
+		opts = append(opts, oauth2.SetAuthURLParam("login_hint", loginHint))
+	}
+
+	// The client secret is not needed to build the authorization URL.
+	authURL := s.oidc.oauth2Config(provider, row.ClientID, "").AuthCodeURL(state, opts...)
+
+	return authURL, expiresAt, nil
+}
+
+func (s *Service) OidcCallback(ctx context.Context, req *authv1.OidcCallbackRequest) (*authv1.OidcCallbackResponse, error) {
+	if req.Error != "" {
+		return nil, status.Errorf(codes.Unauthenticated, "identity provider returned %s: %s", req.Error, req.ErrorDescription)
+	}
+
+	loginState, err := s.querier.ConsumeOidcLoginState(ctx, HashKey(req.State))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, status.Error(codes.Unauthenticated, "invalid or expired login state")
+		}
+		return nil, status.Errorf(codes.Internal, "error getting login state: %v", err)
+	}
+
+	row, err := s.querier.GetOidcProvider(ctx, loginState.TenantID)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return nil, status.Errorf(codes.Internal, "error getting oidc provider: %v", err)
+	}
+	if err != nil || !row.Enabled {
+		return nil, status.Error(codes.FailedPrecondition, "sso is not enabled for tenant")
+	}
+
+	clientSecret, err := s.oidc.box.Open(row.EncryptedClientSecret, oidcAdditionalData(row.TenantID))
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "error decrypting client secret: %v", err)
+	}
+
+	provider, err := s.oidc.provider(ctx, row.Issuer)
+	if err != nil {
+		return nil, status.Errorf(codes.Unavailable, "error discovering issuer: %v", err)
+	}
+
+	idToken, claims, err := s.oidc.exchange(ctx, provider, row.ClientID, string(clientSecret), req.Code, loginState.CodeVerifier, loginState.Nonce)
+	if err != nil {
+		return nil, err
+	}
+
+	verifiedDomains, err := s.querier.ListVerifiedTenantDomains(ctx, row.TenantID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "error listing domains: %v", err)
+	}
+
+	email := normalizeEmail(claims.Email)
+	if !emailDomainAllowed(email, row.AllowedDomains, verifiedDomains) {
+		return nil, status.Error(codes.PermissionDenied, "email domain is not allowed for this tenant")
+	}
+
+	var reqErr error
+	var userID pgtype.UUID
+	var created bool
+	if err := s.execTx(ctx, func(querier sqlc.Querier) error {
+		userID, email, created, err = s.provisionSsoUser(ctx, querier, row.TenantID, loginState.LinkUserID, row.Issuer, idToken.Subject, email, claims.Name)
+		if err != nil {
+			if _, ok := status.FromError(err); ok {
+				reqErr = err
+			}
+			return err
+		}
+
+		if err := querier.AddTenantMemberIfMissing(ctx, sqlc.AddTenantMemberIfMissingParams{
+			TenantID: row.TenantID,
+			UserID:   userID,
+			Role:     utils.StringToPgText(row.DefaultRole),
+		}); err != nil {
+			return err
+		}
+
+		return appendEvent(ctx, querier, row.TenantID, userID, events.SsoLogin, map[string]any{
+			"issuer":      row.Issuer,
+			"subject":     idToken.Subject,
+			"provisioned": created,
+		})
+	}); err != nil {
+		if reqErr != nil {
+			return nil, reqErr
+		}
+
+		return nil, status.Errorf(codes.Internal, "error provisioning user: %v", err)
+	}
+
+	uid, err := uuid.Parse(utils.PgUUIDToString(userID))
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "error parsing user id: %v", err)
+	}
+
+	tokens, err := s.createSession(ctx, uid, email)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "error creating session: %v", err)
+	}
+
+	return &authv1.OidcCallbackResponse{
+		AccessToken:           tokens.accessToken,
+		ExpiresAt:             timestamppb.New(tokens.accessPayload.ExpiredAt),
+		RefreshToken:          tokens.refreshToken,
+		RefreshTokenExpiresAt: timestamppb.New(tokens.refreshExpiresAt),
+		SessionId:             tokens.accessPayload.SessionID.String(),
+		TenantId:              utils.PgUUIDToString(row.TenantID),
+		UserId:                utils.PgUUIDToString(userID),
+		UserCreated:           created,
+	}, nil
+}
+
+// provisionSsoUser resolves the local user for an external identity and
+// returns its id and email. Known identities map directly. A new identity is
+// linked to the account that started a link, or to a verified member of the
+// tenant with the same email; it never takes over any other account with
+// that email. Otherwise a new passwordless account is created.
+func (s *Service) provisionSsoUser(ctx context.Context, querier sqlc.Querier, tenantID, linkUserID pgtype.UUID, issuer, subject, email, fullName string) (pgtype.UUID, string, bool, error) {
+	identity, err := querier.GetUserIdentity(ctx, sqlc.GetUserIdentityParams{
+		Issuer:  issuer,
+		Subject: subject,
+	})
+	if err == nil {
+		if linkUserID.Valid && identity.UserID != linkUserID {
+			return pgtype.UUID{}, "", false, status.Error(codes.AlreadyExists, "identity is already linked to another account")
+		}
+		return identity.UserID, identity.Email, false, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return pgtype.UUID{}, "", false, err
+	}
+
+	if linkUserID.Valid {
+		user, err := querier.GetUserByID(ctx, linkUserID)
+		if err != nil {
+			return pgtype.UUID{}, "", false, err
+		}
+
+		return user.ID, user.Email, false, linkSsoIdentity(ctx, querier, tenantID, user.ID, issuer, subject)
+	}
+
+	memberID, err := querier.GetVerifiedTenantMemberByEmail(ctx, sqlc.GetVerifiedTenantMemberByEmailParams{
+		Email:    email,
+		TenantID: tenantID,
+	})
+	if err == nil {
+		return memberID, email, false, linkSsoIdentity(ctx, querier, tenantID, memberID, issuer, subject)
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return pgtype.UUID{}, "", false, err
+	}
+
+	if _, err := querier.GetUserByEmail(ctx, email); err == nil {
+		return pgtype.UUID{}, "", false, status.Error(codes.FailedPrecondition, "an account with this email already exists; sign in and link the identity")
+	} else if !errors.Is(err, pgx.ErrNoRows) {
+		return pgtype.UUID{}, "", false, err
+	}
+
+	// SSO users sign in through their provider; the random password only
+	// fills the column and is never handed out.
+	password, err := randomString()
+	if err != nil {
+		return pgtype.UUID{}, "", false, err
+	}
+	passwordHash, err := HashPassword(password)
+	if err != nil {
+		return pgtype.UUID{}, "", false, err
+	}
+
+	userID, err := querier.CreateUser(ctx, sqlc.CreateUserParams{
+		Email:        email,
+		PasswordHash: passwordHash,
+		FullName:     utils.StringToPgText(fullName),
+	})
+	if err != nil {
+		return pgtype.UUID{}, "", false, err
+	}
+
+	if err := appendUserEvent(ctx, querier, userID, userID, events.UserRegistered, map[string]any{
+		"email":  email,
+		"issuer": issuer,
+	}); err != nil {
+		return pgtype.UUID{}, "", false, err
+	}
+
+	// The provider verified the email of the account it just created.
+	if err := querier.MarkUserEmailVerified(ctx, userID); err != nil {
+		return pgtype.UUID{}, "", false, err
+	}
+
+	if err := querier.CreateUserIdentity(ctx, sqlc.CreateUserIdentityParams{
+		Issuer:  issuer,
+		Subject: subject,
+		UserID:  userID,
+	}); err != nil {
+		return pgtype.UUID{}, "", false, err
+	}
+
+	return userID, email, true, nil
+}
+
+func linkSsoIdentity(ctx context.Context, querier sqlc.Querier, tenantID, userID pgtype.UUID, issuer, subject string) error {
+	if err := querier.CreateUserIdentity(ctx, sqlc.CreateUserIdentityParams{
+		Issuer:  issuer,
+		Subject: subject,
+		UserID:  userID,
+	}); err != nil {
+		return err
+	}
+
+	return appendEvent(ctx, querier, tenantID, userID, events.SsoIdentityLinked, map[string]any{
+		"issuer":  issuer,
+		"subject": subject,
+	})
+}
+
+// emailDomainAllowed reports whether the domain of an email is verified for
+// the tenant and, when the provider restricts domains, one of those.
+func emailDomainAllowed(email string, allowedDomains, verifiedDomains []string) bool {
+	at := strings.LastIndex(email, "@")
+	if at < 0 {
+		return false
+	}
+	domain := email[at+1:]
+
+	if !slices.Contains(verifiedDomains, domain) {
+		return false
+	}
+
+	return len(allowedDomains) == 0 || slices.Contains(allowedDomains, domain)
+}
+
+func oidcAdditionalData(tenantID pgtype.UUID) []byte {
+	return []byte(utils.PgUUIDToString(tenantID))
+}
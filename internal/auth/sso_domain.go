@@ -0,0 +1,235 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	authv1 "github.com/vantutran2k1/rwe/gen/go/auth/v1"
+	sqlc "github.com/vantutran2k1/rwe/internal/auth/db"
+	"github.com/vantutran2k1/rwe/internal/common/events"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	domainChallengePrefix = "_rwe-challenge."
+	domainChallengeValue  = "rwe-domain-verification="
+)
+
+// AddSsoDomain registers a domain for the tenant. It stays unverified, and
+// unusable for SSO, until the tenant publishes the returned TXT record.
+func (s *Service) AddSsoDomain(ctx context.Context, req *authv1.AddSsoDomainRequest) (*authv1.AddSsoDomainResponse, error) {
+	tenantID, err := tenantFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	domain, ok := normalizeDomain(req.Domain)
+	if !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid domain: %s", req.Domain)
+	}
+
+	verificationToken, err := randomString()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "error generating verification token: %v", err)
+	}
+
+	var reqErr error
+	var row sqlc.TenantDomain
+	if err := s.execTx(ctx, func(querier sqlc.Querier) error {
+		row, err = querier.CreateTenantDomain(ctx, sqlc.CreateTenantDomainParams{
+			TenantID:          tenantID,
+			Domain:            domain,
+			VerificationToken: verificationToken,
+		})
+		if err != nil {
+			var pgErr *pgconn.PgError
+			if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+				reqErr = status.Errorf(codes.AlreadyExists, "domain %s is already added", domain)
+			}
+			return err
+		}
+
+		return appendEvent(ctx, querier, tenantID, tenantID, events.SsoDomainAdded, map[string]any{
+			"domain": domain,
+		})
+	}); err != nil {
+		if reqErr != nil {
+			return nil, reqErr
+		}
+
+		return nil, status.Errorf(codes.Internal, "error adding domain: %v", err)
+	}
+
+	return &authv1.AddSsoDomainResponse{Domain: toSsoDomain(row)}, nil
+}
+
+// VerifySsoDomain looks up the TXT record of a domain and marks the domain
+// verified when it carries the expected token.
+func (s *Service) VerifySsoDomain(ctx context.Context, req *authv1.VerifySsoDomainRequest) (*authv1.VerifySsoDomainResponse, error) {
+	tenantID, err := tenantFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	domain, ok := normalizeDomain(req.Domain)
+	if !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid domain: %s", req.Domain)
+	}
+
+	row, err := s.querier.GetTenantDomain(ctx, sqlc.GetTenantDomainParams{
+		TenantID: tenantID,
+		Domain:   domain,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, status.Errorf(codes.NotFound, "domain %s not found", domain)
+		}
+		return nil, status.Errorf(codes.Internal, "error getting domain: %v", err)
+	}
+	if row.VerifiedAt.Valid {
+		return &authv1.VerifySsoDomainResponse{Domain: toSsoDomain(row)}, nil
+	}
+
+	records, err := s.oidc.lookupTXT(ctx, domainChallengePrefix+domain)
+	if err != nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "error looking up txt record of %s: %v", domain, err)
+	}
+	if !slices.Contains(records, domainChallengeValue+row.VerificationToken) {
+		return nil, status.Errorf(codes.FailedPrecondition, "txt record %s does not contain the verification token", domainChallengePrefix+domain)
+	}
+
+	var reqErr error
+	if err := s.execTx(ctx, func(querier sqlc.Querier) error {
+		row, err = querier.MarkTenantDomainVerified(ctx, sqlc.MarkTenantDomainVerifiedParams{
+			TenantID: tenantID,
+			Domain:   domain,
+		})
+		if err != nil {
+			var pgErr *pgconn.PgError
+			if errors.Is(err, pgx.ErrNoRows) {
+				reqErr = status.Errorf(codes.NotFound, "domain %s not found", domain)
+			} else if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+				reqErr = status.Errorf(codes.AlreadyExists, "domain %s is verified by another tenant", domain)
+			}
+			return err
+		}
+
+		return appendEvent(ctx, querier, tenantID, tenantID, events.SsoDomainVerified, map[string]any{
+			"domain": domain,
+		})
+	}); err != nil {
+		if reqErr != nil {
+			return nil, reqErr
+		}
+
+		return nil, status.Errorf(codes.Internal, "error verifying domain: %v", err)
+	}
+
+	return &authv1.VerifySsoDomainResponse{Domain: toSsoDomain(row)}, nil
+}
+
+func (s *Service) ListSsoDomains(ctx context.Context, req *authv1.ListSsoDomainsRequest) (*authv1.ListSsoDomainsResponse, error) {
+	tenantID, err := tenantFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.querier.ListTenantDomains(ctx, tenantID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "error listing domains: %v", err)
+	}
+
+	domains := make([]*authv1.SsoDomain, 0, len(rows))
+	for _, row := range rows {
+		domains = append(domains, toSsoDomain(row))
+	}
+
+	return &authv1.ListSsoDomainsResponse{Domains: domains}, nil
+}
+
+func (s *Service) DeleteSsoDomain(ctx context.Context, req *authv1.DeleteSsoDomainRequest) (*authv1.DeleteSsoDomainResponse, error) {
+	tenantID, err := tenantFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	domain, ok := normalizeDomain(req.Domain)
+	if !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid domain: %s", req.Domain)
+	}
+
+	var reqErr error
+	if err := s.execTx(ctx, func(querier sqlc.Querier) error {
+		rows, err := querier.DeleteTenantDomain(ctx, sqlc.DeleteTenantDomainParams{
+			TenantID: tenantID,
+			Domain:   domain,
+		})
+		if err != nil {
+			return err
+		}
+		if rows == 0 {
+			reqErr = status.Errorf(codes.NotFound, "domain %s not found", domain)
+			return reqErr
+		}
+
+		return appendEvent(ctx, querier, tenantID, tenantID, events.SsoDomainDeleted, map[string]any{
+			"domain": domain,
+		})
+	}); err != nil {
+		if reqErr != nil {
+			return nil, reqErr
+		}
+
+		return nil, status.Errorf(codes.Internal, "error deleting domain: %v", err)
+	}
+
+	return &authv1.DeleteSsoDomainResponse{Success: true}, nil
+}
+
+// normalizeDomain lowercases a domain name and checks that it is a valid
+// name with at least two labels.
+func normalizeDomain(domain string) (string, bool) {
+	domain = strings.TrimSuffix(strings.ToLower(strings.TrimSpace(domain)), ".")
+	if len(domain) > 253 {
+		return "", false
+	}
+
+	labels := strings.Split(domain, ".")
+	if len(labels) < 2 {
+		return "", false
+	}
+
+	for _, label := range labels {
+		if label == "" || len(label) > 63 || label[0] == '-' || label[len(label)-1] == '-' {
+			return "", false
+		}
+		for _, c := range label {
+			if (c < 'a' || c > 'z') && (c < '0' || c > '9') && c != '-' {
+				return "", false
+			}
+		}
+	}
+
+	return domain, true
+}
+
+func toSsoDomain(row sqlc.TenantDomain) *authv1.SsoDomain {
+	domain := &authv1.SsoDomain{
+		Domain:         row.Domain,
+		TxtRecordName:  domainChallengePrefix + row.Domain,
+		TxtRecordValue: domainChallengeValue + row.VerificationToken,
+		Verified:       row.VerifiedAt.Valid,
+		CreatedAt:      timestamppb.New(row.CreatedAt.Time),
+	}
+	if row.VerifiedAt.Valid {
+		domain.VerifiedAt = timestamppb.New(row.VerifiedAt.Time)
+	}
+
+	return domain
+}
@@ -0,0 +1,106 @@
+package auth
+
+import "testing"
+
+func TestEmailDomainAllowed(t *testing.T) {
+	tests := []struct {
+		name     string
+		email    string
+		allowed  []string
+		verified []string
+		want     bool
+	}{
+		{
+			name:     "verified domain without restriction",
+			email:    "dev@example.com",
+			verified: []string{"example.com"},
+			want:     true,
+		},
+		{
+			name:     "unverified domain without restriction",
+			email:    "dev@other.com",
+			verified: []string{"example.com"},
+			want:     false,
+		},
+		{
+			name:  "no verified domains",
+			email: "dev@example.com",
+			want:  false,
+		},
+		{
+			name:     "allowed and verified",
+			email:    "dev@example.com",
+			allowed:  []string{"example.com"},
+			verified: []string{"example.com", "example.org"},
+			want:     true,
+		},
+		{
+			name:     "verified but not allowed",
+			email:    "dev@example.org",
+			allowed:  []string{"example.com"},
+			verified: []string{"example.com", "example.org"},
+			want:     false,
+		},
+		{
+			name:    "allowed but no longer verified",
+			email:   "dev@example.com",
+			allowed: []string{"example.com"},
+			want:    false,
+		},
+		{
+			name:     "subdomain is not the domain",
+			email:    "dev@mail.example.com",
+			verified: []string{"example.com"},
+			want:     false,
+		},
+		{
+			name:     "last at sign splits the domain",
+			email:    "dev@example.com@evil.com",
+			verified: []string{"example.com"},
+			want:     false,
+		},
+		{
+			name:     "missing at sign",
+			email:    "example.com",
+			verified: []string{"example.com"},
+			want:     false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := emailDomainAllowed(tt.email, tt.allowed, tt.verified); got != tt.want {
+				t.Errorf("emailDomainAllowed(%q, %v, %v) = %v, want %v", tt.email, tt.allowed, tt.verified, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeDomain(t *testing.T) {
+	tests := []struct {
+		domain string
+		want   string
+		ok     bool
+	}{
+		{domain: "example.com", want: "example.com", ok: true},
+		{domain: " Example.COM. ", want: "example.com", ok: true},
+		{domain: "sub-1.example.co.uk", want: "sub-1.example.co.uk", ok: true},
+		{domain: "localhost", ok: false},
+		{domain: "", ok: false},
+		{domain: "example..com", ok: false},
+		{domain: "-example.com", ok: false},
+		{domain: "example-.com", ok: false},
+		{domain: "dev@example.com", ok: false},
+		{domain: "exa mple.com", ok: false},
+		{domain: "example.com/path", ok: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.domain, func(t *testing.T) {
+			got, ok := normalizeDomain(tt.domain)
+			if ok != tt.ok || got != tt.want {
+				t.Errorf("normalizeDomain(%q) = %q, %v, want %q, %v", tt.domain, got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
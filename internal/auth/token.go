@@ -6,6 +6,6 @@ import (
 )
 
 type TokenMaker interface {
-	CreateToken(email string, userID uuid.UUID) (string, *token.Payload, error)
+	CreateToken(email string, userID, sessionID uuid.UUID) (string, *token.Payload, error)
 	VerifyToken(token string) (*token.Payload, error)
 }
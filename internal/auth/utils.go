@@ -6,11 +6,24 @@ import (
 	"encoding/hex"
 	"fmt"
 	"regexp"
+	"strings"
+	"time"
 
+	authv1 "github.com/vantutran2k1/rwe/gen/go/auth/v1"
 	"golang.org/x/crypto/bcrypt"
 )
 
-const keyPrefix = "rwe_sk_"
+const (
+	keyPrefix          = "rwe_sk_"
+	refreshTokenPrefix = "rwe_rt_"
+	userTokenPrefix    = "rwe_ut_"
+	invitationPrefix   = "rwe_inv_"
+	webhookTokenPrefix = "rwe_wh_"
+	defaultApiKeyRole  = "member"
+
+	defaultApiKeyRotationOverlap = 24 * time.Hour
+	maxApiKeyRotationOverlap     = 30 * 24 * time.Hour
+)
 
 var emailRegex = regexp.MustCompile(`^[a-zA-Z0-9._%+\-]+@[a-zA-Z0-9.\-]+\.[a-zA-Z]{2,}$`)
 
@@ -27,6 +40,33 @@ func GenerateAPIKey() (string, string, string, error) {
 	return rawKey, hashedKey, keyPrefix, nil
 }
 
+func GenerateRefreshToken() (string, string, error) {
+	return generateToken(refreshTokenPrefix)
+}
+
+func GenerateUserToken() (string, string, error) {
+	return generateToken(userTokenPrefix)
+}
+
+func GenerateInvitationToken() (string, string, error) {
+	return generateToken(invitationPrefix)
+}
+
+func GenerateWebhookToken() (string, string, error) {
+	return generateToken(webhookTokenPrefix)
+}
+
+func generateToken(prefix string) (string, string, error) {
+	bytes := make([]byte, 32)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", "", err
+	}
+
+	rawToken := prefix + hex.EncodeToString(bytes)
+
+	return rawToken, HashKey(rawToken), nil
+}
+
 func HashKey(rawKey string) string {
 	h := sha256.New()
 	h.Write([]byte(rawKey))
@@ -47,6 +87,11 @@ func HashPassword(password string) (string, error) {
 	return string(bytes), nil
 }
 
+// normalizeEmail returns the form emails are stored and looked up in.
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
 func ValidateEmail(email string) error {
 	if len(email) < 3 || len(email) > 254 {
 		return fmt.Errorf("email length must be between 3 and 254 characters")
@@ -58,3 +103,16 @@ func ValidateEmail(email string) error {
 
 	return nil
 }
+
+func ValidatePassword(password string) error {
+	// bcrypt ignores everything past 72 bytes.
+	if len(password) < 8 || len(password) > 72 {
+		return fmt.Errorf("password length must be between 8 and 72 characters")
+	}
+
+	return nil
+}
+
+func ParseRole(role string) authv1.Role {
+	return authv1.Role(authv1.Role_value["ROLE_"+strings.ToUpper(role)])
+}
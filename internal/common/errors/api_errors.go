@@ -1 +1,71 @@
 package errors
+
+import (
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+type FieldViolations []*errdetails.BadRequest_FieldViolation
+
+func (v *FieldViolations) Add(field, description string) {
+	*v = append(*v, &errdetails.BadRequest_FieldViolation{
+		Field:       field,
+		Description: description,
+	})
+}
+
+func BadRequest(msg string, violations FieldViolations) error {
+	st := status.New(codes.InvalidArgument, msg)
+
+	detailed, err := st.WithDetails(&errdetails.BadRequest{FieldViolations: violations})
+	if err != nil {
+		return st.Err()
+	}
+
+	return detailed.Err()
+}
+
+// RetryLater builds a ResourceExhausted error telling the client how long to
+// wait before trying again.
+func RetryLater(msg string, retryAfter time.Duration) error {
+	st := status.New(codes.ResourceExhausted, msg)
+
+	detailed, err := st.WithDetails(&errdetails.RetryInfo{RetryDelay: durationpb.New(retryAfter)})
+	if err != nil {
+		return st.Err()
+	}
+
+	return detailed.Err()
+}
+
+type QuotaViolations []*errdetails.QuotaFailure_Violation
+
+func (v *QuotaViolations) Add(subject, description string) {
+	*v = append(*v, &errdetails.QuotaFailure_Violation{
+		Subject:     subject,
+		Description: description,
+	})
+}
+
+// QuotaExceeded builds a ResourceExhausted error describing the exceeded
+// quotas. A positive retryAfter also tells the client when the quota resets.
+func QuotaExceeded(msg string, violations QuotaViolations, retryAfter time.Duration) error {
+	st := status.New(codes.ResourceExhausted, msg)
+
+	details := []protoadapt.MessageV1{&errdetails.QuotaFailure{Violations: violations}}
+	if retryAfter > 0 {
+		details = append(details, &errdetails.RetryInfo{RetryDelay: durationpb.New(retryAfter)})
+	}
+
+	detailed, err := st.WithDetails(details...)
+	if err != nil {
+		return st.Err()
+	}
+
+	return detailed.Err()
+}
@@ -0,0 +1,73 @@
+package events
+
+import (
+	"encoding/json"
+)
+
+const (
+	WorkflowCreated = "workflow.created"
+	WorkflowUpdated = "workflow.updated"
+
+	RunCreated   = "workflow_run.created"
+	RunStarted   = "workflow_run.started"
+	RunSucceeded = "workflow_run.succeeded"
+	RunFailed    = "workflow_run.failed"
+	RunCancelled = "workflow_run.cancelled"
+
+	ScheduleCreated = "workflow_schedule.created"
+	SchedulePaused  = "workflow_schedule.paused"
+	ScheduleResumed = "workflow_schedule.resumed"
+	ScheduleDeleted = "workflow_schedule.deleted"
+
+	WebhookCreated = "workflow_webhook.created"
+	WebhookDeleted = "workflow_webhook.deleted"
+
+	TaskDeadLettered = "task.dead_lettered"
+	TaskRedriven     = "task.redriven"
+
+	TenantCreated           = "tenant.created"
+	TenantUpdated           = "tenant.updated"
+	TenantSuspended         = "tenant.suspended"
+	TenantReactivated       = "tenant.reactivated"
+	TenantArchived          = "tenant.archived"
+	TenantDeletionScheduled = "tenant.deletion_scheduled"
+	TenantDeleted           = "tenant.deleted"
+
+	MemberInvited     = "tenant_member.invited"
+	MemberJoined      = "tenant_member.joined"
+	MemberRoleUpdated = "tenant_member.role_updated"
+	MemberRemoved     = "tenant_member.removed"
+	MemberLeft        = "tenant_member.left"
+
+	UserRegistered         = "user.registered"
+	PasswordResetRequested = "user.password_reset_requested"
+	PasswordReset          = "user.password_reset"
+	EmailVerified          = "user.email_verified"
+	MfaEnabled             = "user.mfa_enabled"
+	MfaDisabled            = "user.mfa_disabled"
+	RecoveryCodeUsed       = "user.mfa_recovery_code_used"
+	SsoLogin               = "user.sso_login"
+	SsoIdentityLinked      = "user.sso_identity_linked"
+	LoginLockedOut         = "user.login_locked_out"
+	UserUnlocked           = "user.unlocked"
+	OidcProviderUpdated    = "tenant.oidc_provider_updated"
+	OidcProviderDeleted    = "tenant.oidc_provider_deleted"
+	SsoDomainAdded         = "tenant.sso_domain_added"
+	SsoDomainVerified      = "tenant.sso_domain_verified"
+	SsoDomainDeleted       = "tenant.sso_domain_deleted"
+	ApiKeyIssued           = "api_key.issued"
+	ApiKeyRotated          = "api_key.rotated"
+	ApiKeyRevoked          = "api_key.revoked"
+
+	SessionCreated     = "session.created"
+	SessionRevoked     = "session.revoked"
+	RefreshTokenReused = "session.refresh_token_reused"
+)
+
+func Marshal(payload any) ([]byte, error) {
+	if payload == nil {
+		return []byte("{}"), nil
+	}
+
+	return json.Marshal(payload)
+}
@@ -2,6 +2,8 @@ package token
 
 import (
 	"context"
+
+	"github.com/google/uuid"
 )
 
 type contextKey string
@@ -9,9 +11,25 @@ type contextKey string
 const (
 	AuthorizationHeader = "authorization"
 	AuthorizationBearer = "bearer"
+	AuthorizationApiKey = "apikey"
+	ApiKeyHeader        = "x-api-key"
+	TenantHeader        = "x-tenant-id"
 	PayloadContextKey   = contextKey("authorization_payload")
+	TenantContextKey    = contextKey("tenant_scope")
+	ApiKeyContextKey    = contextKey("api_key")
 )
 
+type TenantScope struct {
+	TenantID uuid.UUID
+	Role     string
+}
+
+type ApiKey struct {
+	ID       uuid.UUID
+	TenantID uuid.UUID
+	Scopes   []string
+}
+
 func GetTokenPayload(ctx context.Context) *Payload {
 	payload, ok := ctx.Value(PayloadContextKey).(*Payload)
 	if !ok {
@@ -20,3 +38,21 @@ func GetTokenPayload(ctx context.Context) *Payload {
 
 	return payload
 }
+
+func GetTenantScope(ctx context.Context) *TenantScope {
+	scope, ok := ctx.Value(TenantContextKey).(*TenantScope)
+	if !ok {
+		return nil
+	}
+
+	return scope
+}
+
+func GetApiKey(ctx context.Context) *ApiKey {
+	key, ok := ctx.Value(ApiKeyContextKey).(*ApiKey)
+	if !ok {
+		return nil
+	}
+
+	return key
+}
@@ -10,12 +10,13 @@ import (
 type Payload struct {
 	ID        uuid.UUID `json:"id"`
 	UserID    uuid.UUID `json:"user_id"`
+	SessionID uuid.UUID `json:"session_id"`
 	Email     string    `json:"email"`
 	IssuedAt  time.Time `json:"issued_at"`
 	ExpiredAt time.Time `json:"expired_at"`
 }
 
-func NewPayload(email string, userID uuid.UUID, duration time.Duration) (*Payload, error) {
+func NewPayload(email string, userID, sessionID uuid.UUID, duration time.Duration) (*Payload, error) {
 	tokenID, err := uuid.NewRandom()
 	if err != nil {
 		return nil, err
@@ -24,6 +25,7 @@ func NewPayload(email string, userID uuid.UUID, duration time.Duration) (*Payloa
 	payload := &Payload{
 		ID:        tokenID,
 		UserID:    userID,
+		SessionID: sessionID,
 		Email:     email,
 		IssuedAt:  time.Now(),
 		ExpiredAt: time.Now().Add(duration),
@@ -0,0 +1,16 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+
+package sqlc
+
+import (
+	"context"
+)
+
+type Querier interface {
+	ListEventsAfter(ctx context.Context, arg ListEventsAfterParams) ([]Event, error)
+	ListUserEventsAfter(ctx context.Context, arg ListUserEventsAfterParams) ([]Event, error)
+}
+
+var _ Querier = (*Queries)(nil)
@@ -0,0 +1,17 @@
+-- name: ListEventsAfter :many
+SELECT *
+FROM events
+WHERE tenant_id = @tenant_id
+  AND id > @after_id
+  AND (cardinality(@event_types::text[]) = 0 OR event_type = ANY (@event_types::text[]))
+ORDER BY id
+LIMIT @batch_size;
+
+-- name: ListUserEventsAfter :many
+SELECT *
+FROM events
+WHERE user_id = @user_id
+  AND id > @after_id
+  AND (cardinality(@event_types::text[]) = 0 OR event_type = ANY (@event_types::text[]))
+ORDER BY id
+LIMIT @batch_size;
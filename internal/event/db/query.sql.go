@@ -0,0 +1,112 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: query.sql
+
+package sqlc
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const listEventsAfter = `-- name: ListEventsAfter :many
+SELECT id, tenant_id, event_type, aggregate_id, payload, created_at, user_id
+FROM events
+WHERE tenant_id = $1
+  AND id > $2
+  AND (cardinality($3::text[]) = 0 OR event_type = ANY ($3::text[]))
+ORDER BY id
+LIMIT $4
+`
+
+type ListEventsAfterParams struct {
+	TenantID   pgtype.UUID `db:"tenant_id" json:"tenant_id"`
+	AfterID    int64       `db:"after_id" json:"after_id"`
+	EventTypes []string    `db:"event_types" json:"event_types"`
+	BatchSize  int32       `db:"batch_size" json:"batch_size"`
+}
+
+func (q *Queries) ListEventsAfter(ctx context.Context, arg ListEventsAfterParams) ([]Event, error) {
+	rows, err := q.db.Query(ctx, listEventsAfter,
+		arg.TenantID,
+		arg.AfterID,
+		arg.EventTypes,
+		arg.BatchSize,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Event
+	for rows.Next() {
+		var i Event
+		if err := rows.Scan(
+			&i.ID,
+			&i.TenantID,
+			&i.EventType,
+			&i.AggregateID,
+			&i.Payload,
+			&i.CreatedAt,
+			&i.UserID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUserEventsAfter = `-- name: ListUserEventsAfter :many
+SELECT id, tenant_id, event_type, aggregate_id, payload, created_at, user_id
+FROM events
+WHERE user_id = $1
+  AND id > $2
+  AND (cardinality($3::text[]) = 0 OR event_type = ANY ($3::text[]))
+ORDER BY id
+LIMIT $4
+`
+
+type ListUserEventsAfterParams struct {
+	UserID     pgtype.UUID `db:"user_id" json:"user_id"`
+	AfterID    int64       `db:"after_id" json:"after_id"`
+	EventTypes []string    `db:"event_types" json:"event_types"`
+	BatchSize  int32       `db:"batch_size" json:"batch_size"`
+}
+
+func (q *Queries) ListUserEventsAfter(ctx context.Context, arg ListUserEventsAfterParams) ([]Event, error) {
+	rows, err := q.db.Query(ctx, listUserEventsAfter,
+		arg.UserID,
+		arg.AfterID,
+		arg.EventTypes,
+		arg.BatchSize,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Event
+	for rows.Next() {
+		var i Event
+		if err := rows.Scan(
+			&i.ID,
+			&i.TenantID,
+			&i.EventType,
+			&i.AggregateID,
+			&i.Payload,
+			&i.CreatedAt,
+			&i.UserID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -0,0 +1,159 @@
+package event
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	eventv1 "github.com/vantutran2k1/rwe/gen/go/event/v1"
+	"github.com/vantutran2k1/rwe/internal/common/token"
+	"github.com/vantutran2k1/rwe/internal/common/utils"
+	sqlc "github.com/vantutran2k1/rwe/internal/event/db"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const streamBatchSize = int32(100)
+
+type Service struct {
+	querier      sqlc.Querier
+	pollInterval time.Duration
+	eventv1.UnimplementedEventServiceServer
+}
+
+func NewService(pool *pgxpool.Pool, pollInterval time.Duration) *Service {
+	return &Service{
+		querier:      sqlc.New(pool),
+		pollInterval: pollInterval,
+	}
+}
+
+func (s *Service) StreamEvents(req *eventv1.StreamEventsRequest, stream grpc.ServerStreamingServer[eventv1.Event]) error {
+	ctx := stream.Context()
+
+	tenantID, err := tenantFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	return s.stream(stream, req.AfterId, req.EventTypes, func(afterID int64, eventTypes []string) ([]sqlc.Event, error) {
+		return s.querier.ListEventsAfter(ctx, sqlc.ListEventsAfterParams{
+			TenantID:   tenantID,
+			AfterID:    afterID,
+			EventTypes: eventTypes,
+			BatchSize:  streamBatchSize,
+		})
+	})
+}
+
+func (s *Service) StreamUserEvents(req *eventv1.StreamUserEventsRequest, stream grpc.ServerStreamingServer[eventv1.Event]) error {
+	ctx := stream.Context()
+
+	tokenPayload := token.GetTokenPayload(ctx)
+	if tokenPayload == nil {
+		return status.Error(codes.Unauthenticated, "missing user authentication")
+	}
+	userID := utils.UUIDToPgUUID(tokenPayload.UserID)
+
+	return s.stream(stream, req.AfterId, req.EventTypes, func(afterID int64, eventTypes []string) ([]sqlc.Event, error) {
+		return s.querier.ListUserEventsAfter(ctx, sqlc.ListUserEventsAfterParams{
+			UserID:     userID,
+			AfterID:    afterID,
+			EventTypes: eventTypes,
+			BatchSize:  streamBatchSize,
+		})
+	})
+}
+
+// stream sends the events returned by list after afterID, then polls for new
+// ones until the client goes away.
+func (s *Service) stream(stream grpc.ServerStreamingServer[eventv1.Event], afterID int64, eventTypes []string, list func(int64, []string) ([]sqlc.Event, error)) error {
+	ctx := stream.Context()
+
+	if afterID < 0 {
+		return status.Error(codes.InvalidArgument, "after id must not be negative")
+	}
+
+	if eventTypes == nil {
+		eventTypes = []string{}
+	}
+
+	ticker := time.NewTicker(s.pollInterval)
+	defer ticker.Stop()
+
+	lastID := afterID
+	for {
+		rows, err := list(lastID, eventTypes)
+		if err != nil {
+			if ctx.Err() != nil {
+				return status.FromContextError(ctx.Err()).Err()
+			}
+
+			return status.Errorf(codes.Internal, "error listing events: %v", err)
+		}
+
+		for _, row := range rows {
+			e, err := toEvent(row)
+			if err != nil {
+				return status.Errorf(codes.Internal, "error parsing event %d: %v", row.ID, err)
+			}
+
+			if err := stream.Send(e); err != nil {
+				return err
+			}
+			lastID = row.ID
+		}
+
+		if int32(len(rows)) == streamBatchSize {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return status.FromContextError(ctx.Err()).Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func tenantFromContext(ctx context.Context) (pgtype.UUID, error) {
+	scope := token.GetTenantScope(ctx)
+	if scope == nil {
+		return pgtype.UUID{}, status.Error(codes.PermissionDenied, "missing tenant scope")
+	}
+
+	return utils.UUIDToPgUUID(scope.TenantID), nil
+}
+
+func toEvent(row sqlc.Event) (*eventv1.Event, error) {
+	var payload *structpb.Struct
+	if len(row.Payload) > 0 {
+		payload = &structpb.Struct{}
+		if err := protojson.Unmarshal(row.Payload, payload); err != nil {
+			return nil, err
+		}
+	}
+
+	e := &eventv1.Event{
+		Id:          row.ID,
+		TenantId:    utils.PgUUIDToString(row.TenantID),
+		EventType:   row.EventType.String,
+		AggregateId: utils.PgUUIDToString(row.AggregateID),
+		Payload:     payload,
+	}
+
+	if row.UserID.Valid {
+		e.UserId = utils.PgUUIDToString(row.UserID)
+	}
+
+	if row.CreatedAt.Valid {
+		e.CreatedAt = timestamppb.New(row.CreatedAt.Time)
+	}
+
+	return e, nil
+}
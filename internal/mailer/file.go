@@ -0,0 +1,59 @@
+package mailer
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// FileMailer appends every message as a JSON line to a file instead of
+// delivering it, which is useful for local development and tests.
+type FileMailer struct {
+	mu   sync.Mutex
+	path string
+}
+
+func NewFileMailer(path string) Mailer {
+	return &FileMailer{path: path}
+}
+
+func (m *FileMailer) Send(ctx context.Context, msg Message) error {
+	line, err := json.Marshal(struct {
+		Message
+		SentAt time.Time
+	}{msg, time.Now()})
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(m.path), 0o755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(m.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = f.Write(append(line, '\n'))
+	return err
+}
+
+// LogMailer writes every message to the structured log.
+type LogMailer struct{}
+
+func NewLogMailer() Mailer {
+	return LogMailer{}
+}
+
+func (LogMailer) Send(ctx context.Context, msg Message) error {
+	slog.InfoContext(ctx, "email", "to", msg.To, "subject", msg.Subject, "body", msg.Body)
+	return nil
+}
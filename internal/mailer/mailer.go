@@ -0,0 +1,17 @@
+package mailer
+
+import (
+	"context"
+)
+
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer delivers transactional email such as password reset and
+// verification links.
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+}
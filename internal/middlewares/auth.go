@@ -2,10 +2,19 @@ package middlewares
 
 import (
 	"context"
+	"errors"
+	"log/slog"
+	"net/netip"
 	"strings"
 
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/vantutran2k1/rwe/internal/auth"
+	"github.com/vantutran2k1/rwe/internal/auth/cache"
+	sqlc "github.com/vantutran2k1/rwe/internal/auth/db"
 	"github.com/vantutran2k1/rwe/internal/common/token"
+	"github.com/vantutran2k1/rwe/internal/common/utils"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
@@ -13,16 +22,34 @@ import (
 )
 
 type AuthInterceptor struct {
-	tokenMaker auth.TokenMaker
-	// TODO: add RBAC
+	tokenMaker        auth.TokenMaker
+	querier           sqlc.Querier
+	blocklist         cache.Blocklist
+	blocklistFailOpen bool
+	trustedProxies    []netip.Prefix
+	policies          Policies
 }
 
-func NewAuthInterceptor(tokenMaker auth.TokenMaker) *AuthInterceptor {
+func NewAuthInterceptor(tokenMaker auth.TokenMaker, pool *pgxpool.Pool, blocklist cache.Blocklist, blocklistFailOpen bool, trustedProxies []netip.Prefix) *AuthInterceptor {
 	return &AuthInterceptor{
-		tokenMaker: tokenMaker,
+		tokenMaker:        tokenMaker,
+		querier:           sqlc.New(pool),
+		blocklist:         blocklist,
+		blocklistFailOpen: blocklistFailOpen,
+		trustedProxies:    trustedProxies,
 	}
 }
 
+func (i *AuthInterceptor) LoadPolicies(server *grpc.Server) error {
+	policies, err := loadPolicies(server)
+	if err != nil {
+		return err
+	}
+
+	i.policies = policies
+	return nil
+}
+
 func (i *AuthInterceptor) Unary() grpc.UnaryServerInterceptor {
 	return func(
 		ctx context.Context,
@@ -30,19 +57,77 @@ func (i *AuthInterceptor) Unary() grpc.UnaryServerInterceptor {
 		info *grpc.UnaryServerInfo,
 		handler grpc.UnaryHandler,
 	) (any, error) {
-		if isPublicEndpoint(info.FullMethod) {
-			return handler(ctx, req)
+		newCtx, err := i.authenticate(ctx, info.FullMethod)
+		if err != nil {
+			return nil, err
 		}
 
+		return handler(newCtx, req)
+	}
+}
+
+func (i *AuthInterceptor) Stream() grpc.StreamServerInterceptor {
+	return func(
+		srv any,
+		ss grpc.ServerStream,
+		info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler,
+	) error {
+		newCtx, err := i.authenticate(ss.Context(), info.FullMethod)
+		if err != nil {
+			return err
+		}
+
+		return handler(srv, &wrappedStream{ServerStream: ss, ctx: newCtx})
+	}
+}
+
+func (i *AuthInterceptor) authenticate(ctx context.Context, method string) (context.Context, error) {
+	policy, ok := i.policies[method]
+	if !ok {
+		return nil, status.Errorf(codes.PermissionDenied, "no access policy defined for %s", method)
+	}
+
+	if policy.Public {
+		return ctx, nil
+	}
+
+	var scope *token.TenantScope
+	if rawKey := apiKeyFromMetadata(ctx); rawKey != "" {
+		key, err := i.authorizeApiKey(ctx, rawKey, method, policy)
+		if err != nil {
+			return nil, err
+		}
+
+		ctx = context.WithValue(ctx, token.ApiKeyContextKey, &token.ApiKey{
+			ID:       uuid.UUID(key.ID.Bytes),
+			TenantID: uuid.UUID(key.TenantID.Bytes),
+			Scopes:   key.Scopes,
+		})
+		scope = &token.TenantScope{TenantID: uuid.UUID(key.TenantID.Bytes), Role: key.Role.String}
+	} else {
 		payload, err := i.authorize(ctx)
 		if err != nil {
 			return nil, err
 		}
 
-		newCtx := context.WithValue(ctx, token.PayloadContextKey, payload)
+		ctx = context.WithValue(ctx, token.PayloadContextKey, payload)
 
-		return handler(newCtx, req)
+		if policy.Tenantless {
+			return ctx, nil
+		}
+
+		scope, err = i.resolveTenant(ctx, payload, policy.AllowInactiveTenant)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if auth.ParseRole(scope.Role) < policy.Role {
+		return nil, status.Errorf(codes.PermissionDenied, "role %q is not allowed to call %s", scope.Role, method)
 	}
+
+	return context.WithValue(ctx, token.TenantContextKey, scope), nil
 }
 
 func (i *AuthInterceptor) authorize(ctx context.Context) (*token.Payload, error) {
@@ -73,15 +158,145 @@ func (i *AuthInterceptor) authorize(ctx context.Context) (*token.Payload, error)
 		return nil, status.Errorf(codes.Unauthenticated, "invalid access token: %v", err)
 	}
 
+	blocklisted, err := i.blocklist.IsBlocklisted(ctx, payload.ID.String())
+	if err != nil {
+		if !i.blocklistFailOpen {
+			return nil, status.Error(codes.Unavailable, "unable to verify access token status")
+		}
+
+		slog.Warn("blocklist lookup failed, allowing token", "token_id", payload.ID, "error", err)
+	}
+
+	if blocklisted {
+		return nil, status.Error(codes.Unauthenticated, "access token has been revoked")
+	}
+
 	return payload, nil
 }
 
-func isPublicEndpoint(method string) bool {
-	publicPaths := map[string]bool{
-		"/auth.v1.AuthService/Login":    true,
-		"/auth.v1.AuthService/Register": true,
-		"/grpc.health.v1.Health/Check":  true,
+func (i *AuthInterceptor) authorizeApiKey(ctx context.Context, rawKey, method string, policy Policy) (sqlc.GetApiKeyByHashRow, error) {
+	key, err := auth.LookupApiKey(ctx, i.querier, rawKey)
+	if err != nil {
+		if errors.Is(err, auth.ErrInvalidApiKey) {
+			return sqlc.GetApiKeyByHashRow{}, status.Error(codes.Unauthenticated, err.Error())
+		}
+		if errors.Is(err, auth.ErrTenantInactive) {
+			return sqlc.GetApiKeyByHashRow{}, status.Error(codes.PermissionDenied, err.Error())
+		}
+
+		return sqlc.GetApiKeyByHashRow{}, status.Errorf(codes.Internal, "error validating api key: %v", err)
 	}
 
-	return publicPaths[method]
+	md, _ := metadata.FromIncomingContext(ctx)
+	if values := md.Get(token.TenantHeader); len(values) > 0 {
+		tenantID, err := uuid.Parse(values[0])
+		if err != nil {
+			return sqlc.GetApiKeyByHashRow{}, status.Errorf(codes.InvalidArgument, "invalid %s header: %v", token.TenantHeader, err)
+		}
+
+		if tenantID != uuid.UUID(key.TenantID.Bytes) {
+			return sqlc.GetApiKeyByHashRow{}, status.Errorf(codes.PermissionDenied, "api key does not belong to tenant %s", tenantID)
+		}
+	}
+
+	if len(key.AllowedCidrs) > 0 {
+		ip, ok := auth.ClientIP(ctx, i.trustedProxies)
+		if !ok || !auth.IPAllowed(key.AllowedCidrs, ip) {
+			return sqlc.GetApiKeyByHashRow{}, status.Error(codes.PermissionDenied, "api key is not allowed from this address")
+		}
+	}
+
+	if len(key.Scopes) > 0 && !auth.ScopeAllows(key.Scopes, policy.Scope) {
+		if policy.Scope == "" {
+			return sqlc.GetApiKeyByHashRow{}, status.Errorf(codes.PermissionDenied, "scoped api keys cannot call %s", method)
+		}
+
+		return sqlc.GetApiKeyByHashRow{}, status.Errorf(codes.PermissionDenied, "api key lacks scope %s required by %s", policy.Scope, method)
+	}
+
+	if err := i.querier.TouchApiKey(ctx, key.ID); err != nil {
+		return sqlc.GetApiKeyByHashRow{}, status.Errorf(codes.Internal, "error updating api key: %v", err)
+	}
+
+	return key, nil
+}
+
+func (i *AuthInterceptor) resolveTenant(ctx context.Context, payload *token.Payload, allowInactive bool) (*token.TenantScope, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	if values := md.Get(token.TenantHeader); len(values) > 0 {
+		tenantID, err := uuid.Parse(values[0])
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid %s header: %v", token.TenantHeader, err)
+		}
+
+		member, err := i.querier.GetTenantMembership(ctx, sqlc.GetTenantMembershipParams{
+			TenantID: utils.UUIDToPgUUID(tenantID),
+			UserID:   utils.UUIDToPgUUID(payload.UserID),
+		})
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return nil, status.Errorf(codes.PermissionDenied, "caller is not a member of tenant %s", tenantID)
+			}
+
+			return nil, status.Errorf(codes.Internal, "error getting tenant membership: %v", err)
+		}
+
+		if !allowInactive && !auth.TenantActive(member.TenantStatus) {
+			return nil, status.Errorf(codes.PermissionDenied, "tenant %s is %s", tenantID, member.TenantStatus.TenantStatus)
+		}
+
+		return &token.TenantScope{TenantID: tenantID, Role: member.Role.String}, nil
+	}
+
+	members, err := i.querier.ListTenantMembershipsByUserID(ctx, sqlc.ListTenantMembershipsByUserIDParams{
+		UserID: utils.UUIDToPgUUID(payload.UserID),
+		Limit:  2,
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "error listing tenant memberships: %v", err)
+	}
+
+	switch len(members) {
+	case 0:
+		return nil, status.Error(codes.PermissionDenied, "caller does not belong to any tenant")
+	case 1:
+		tenantID := uuid.UUID(members[0].TenantID.Bytes)
+		if !allowInactive && !auth.TenantActive(members[0].TenantStatus) {
+			return nil, status.Errorf(codes.PermissionDenied, "tenant %s is %s", tenantID, members[0].TenantStatus.TenantStatus)
+		}
+
+		return &token.TenantScope{TenantID: tenantID, Role: members[0].Role.String}, nil
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "%s header is required for callers belonging to multiple tenants", token.TenantHeader)
+	}
+}
+
+func apiKeyFromMetadata(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+
+	if values := md.Get(token.AuthorizationHeader); len(values) > 0 {
+		fields := strings.Fields(values[0])
+		if len(fields) == 2 && strings.ToLower(fields[0]) == token.AuthorizationApiKey {
+			return fields[1]
+		}
+	}
+
+	if values := md.Get(token.ApiKeyHeader); len(values) > 0 {
+		return values[0]
+	}
+
+	return ""
+}
+
+type wrappedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (w *wrappedStream) Context() context.Context {
+	return w.ctx
 }
@@ -0,0 +1,97 @@
+package middlewares
+
+import (
+	"fmt"
+
+	authv1 "github.com/vantutran2k1/rwe/gen/go/auth/v1"
+	"github.com/vantutran2k1/rwe/internal/auth"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+type Policy struct {
+	Public              bool
+	Tenantless          bool
+	Role                authv1.Role
+	Scope               string
+	AllowInactiveTenant bool
+}
+
+type Policies map[string]Policy
+
+// builtinPolicies covers services registered from third-party packages whose
+// protos cannot carry the auth.v1.policy option.
+var builtinPolicies = Policies{
+	"/grpc.health.v1.Health/Check":                                   {Public: true},
+	"/grpc.reflection.v1.ServerReflection/ServerReflectionInfo":      {Tenantless: true},
+	"/grpc.reflection.v1alpha.ServerReflection/ServerReflectionInfo": {Tenantless: true},
+}
+
+func loadPolicies(server *grpc.Server) (Policies, error) {
+	policies := make(Policies, len(builtinPolicies))
+	for method, policy := range builtinPolicies {
+		policies[method] = policy
+	}
+
+	for name, info := range server.GetServiceInfo() {
+		for _, m := range info.Methods {
+			method := fmt.Sprintf("/%s/%s", name, m.Name)
+			if _, ok := policies[method]; ok {
+				continue
+			}
+
+			policy, err := methodPolicy(name, m.Name)
+			if err != nil {
+				return nil, fmt.Errorf("method %s: %w", method, err)
+			}
+			policies[method] = policy
+		}
+	}
+
+	return policies, nil
+}
+
+func methodPolicy(service, method string) (Policy, error) {
+	d, err := protoregistry.GlobalFiles.FindDescriptorByName(protoreflect.FullName(service))
+	if err != nil {
+		return Policy{}, fmt.Errorf("service descriptor not found: %w", err)
+	}
+
+	sd, ok := d.(protoreflect.ServiceDescriptor)
+	if !ok {
+		return Policy{}, fmt.Errorf("%s is not a service", service)
+	}
+
+	md := sd.Methods().ByName(protoreflect.Name(method))
+	if md == nil {
+		return Policy{}, fmt.Errorf("method descriptor not found")
+	}
+
+	if !proto.HasExtension(md.Options(), authv1.E_Policy) {
+		return Policy{}, fmt.Errorf("no access policy defined")
+	}
+
+	opt := proto.GetExtension(md.Options(), authv1.E_Policy).(*authv1.Policy)
+
+	policy := Policy{
+		Public:              opt.Public,
+		Tenantless:          opt.Tenantless,
+		Role:                opt.Role,
+		Scope:               opt.Scope,
+		AllowInactiveTenant: opt.AllowInactiveTenant,
+	}
+
+	if policy.Scope != "" {
+		if err := auth.ValidateScope(policy.Scope); err != nil {
+			return Policy{}, err
+		}
+	}
+
+	if !policy.Public && !policy.Tenantless && policy.Role == authv1.Role_ROLE_UNSPECIFIED {
+		return Policy{}, fmt.Errorf("access policy must be public, tenantless or require a role")
+	}
+
+	return policy, nil
+}
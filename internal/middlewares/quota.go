@@ -0,0 +1,88 @@
+package middlewares
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	apierrors "github.com/vantutran2k1/rwe/internal/common/errors"
+	"github.com/vantutran2k1/rwe/internal/common/token"
+	"github.com/vantutran2k1/rwe/internal/quota"
+	"google.golang.org/grpc"
+)
+
+// QuotaInterceptor enforces the per-minute API request quota of the tenant.
+// It must run after the AuthInterceptor. Failures to look up the quota or
+// count the request let the call through.
+type QuotaInterceptor struct {
+	store   *quota.Store
+	counter quota.RequestCounter
+}
+
+func NewQuotaInterceptor(store *quota.Store, counter quota.RequestCounter) *QuotaInterceptor {
+	return &QuotaInterceptor{
+		store:   store,
+		counter: counter,
+	}
+}
+
+func (i *QuotaInterceptor) Unary() grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (any, error) {
+		if err := i.check(ctx); err != nil {
+			return nil, err
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+func (i *QuotaInterceptor) Stream() grpc.StreamServerInterceptor {
+	return func(
+		srv any,
+		ss grpc.ServerStream,
+		info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler,
+	) error {
+		if err := i.check(ss.Context()); err != nil {
+			return err
+		}
+
+		return handler(srv, ss)
+	}
+}
+
+func (i *QuotaInterceptor) check(ctx context.Context) error {
+	scope := token.GetTenantScope(ctx)
+	if scope == nil {
+		return nil
+	}
+
+	limits, err := i.store.Limits(ctx, scope.TenantID)
+	if err != nil {
+		slog.Warn("quota lookup failed, allowing request", "tenant_id", scope.TenantID, "error", err)
+		return nil
+	}
+	if limits.ApiRequestsPerMinute < 0 {
+		return nil
+	}
+
+	count, resetIn, err := i.counter.Incr(ctx, "api:"+scope.TenantID.String(), time.Minute)
+	if err != nil {
+		slog.Warn("request counting failed, allowing request", "tenant_id", scope.TenantID, "error", err)
+		return nil
+	}
+
+	if !quota.Allows(limits.ApiRequestsPerMinute, count-1, 1) {
+		var violations apierrors.QuotaViolations
+		violations.Add("tenant:"+scope.TenantID.String(), fmt.Sprintf("api_requests_per_minute limit of %d exceeded", limits.ApiRequestsPerMinute))
+		return apierrors.QuotaExceeded("api request quota exceeded", violations, resetIn)
+	}
+
+	return nil
+}
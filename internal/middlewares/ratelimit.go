@@ -0,0 +1,143 @@
+package middlewares
+
+import (
+	"context"
+	"log/slog"
+	"math"
+	"net/netip"
+	"strconv"
+	"time"
+
+	"github.com/vantutran2k1/rwe/internal/auth"
+	apierrors "github.com/vantutran2k1/rwe/internal/common/errors"
+	"github.com/vantutran2k1/rwe/internal/common/token"
+	"github.com/vantutran2k1/rwe/internal/ratelimit"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+type RateLimitRules struct {
+	ApiKey    ratelimit.Limit
+	User      ratelimit.Limit
+	Tenant    ratelimit.Limit
+	Anonymous ratelimit.Limit
+	Methods   map[string]ratelimit.Limit
+}
+
+// RateLimitInterceptor applies token bucket limits per API key, user, tenant
+// and method. It must run after the AuthInterceptor so the caller is known;
+// unauthenticated calls are limited per client address instead.
+type RateLimitInterceptor struct {
+	limiter        ratelimit.Limiter
+	rules          RateLimitRules
+	trustedProxies []netip.Prefix
+	failOpen       bool
+}
+
+func NewRateLimitInterceptor(limiter ratelimit.Limiter, rules RateLimitRules, trustedProxies []netip.Prefix, failOpen bool) *RateLimitInterceptor {
+	return &RateLimitInterceptor{
+		limiter:        limiter,
+		rules:          rules,
+		trustedProxies: trustedProxies,
+		failOpen:       failOpen,
+	}
+}
+
+func (i *RateLimitInterceptor) Unary() grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (any, error) {
+		retryAfter, err := i.check(ctx, info.FullMethod)
+		if err != nil {
+			if retryAfter > 0 {
+				_ = grpc.SetHeader(ctx, retryAfterHeader(retryAfter))
+			}
+			return nil, err
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+func (i *RateLimitInterceptor) Stream() grpc.StreamServerInterceptor {
+	return func(
+		srv any,
+		ss grpc.ServerStream,
+		info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler,
+	) error {
+		retryAfter, err := i.check(ss.Context(), info.FullMethod)
+		if err != nil {
+			if retryAfter > 0 {
+				_ = ss.SetHeader(retryAfterHeader(retryAfter))
+			}
+			return err
+		}
+
+		return handler(srv, ss)
+	}
+}
+
+func (i *RateLimitInterceptor) check(ctx context.Context, method string) (time.Duration, error) {
+	buckets := i.buckets(ctx, method)
+	if len(buckets) == 0 {
+		return 0, nil
+	}
+
+	allowed, retryAfter, err := i.limiter.Allow(ctx, buckets)
+	if err != nil {
+		if !i.failOpen {
+			return 0, status.Error(codes.Unavailable, "unable to check rate limit")
+		}
+
+		slog.Warn("rate limit check failed, allowing request", "method", method, "error", err)
+		return 0, nil
+	}
+
+	if !allowed {
+		return retryAfter, apierrors.RetryLater("rate limit exceeded", retryAfter)
+	}
+
+	return 0, nil
+}
+
+func (i *RateLimitInterceptor) buckets(ctx context.Context, method string) []ratelimit.Bucket {
+	var buckets []ratelimit.Bucket
+	add := func(key string, limit ratelimit.Limit) {
+		if limit.Enabled() {
+			buckets = append(buckets, ratelimit.Bucket{Key: key, Limit: limit})
+		}
+	}
+
+	var caller string
+	if key := token.GetApiKey(ctx); key != nil {
+		caller = "key:" + key.ID.String()
+		add(caller, i.rules.ApiKey)
+	} else if payload := token.GetTokenPayload(ctx); payload != nil {
+		caller = "user:" + payload.UserID.String()
+		add(caller, i.rules.User)
+	} else if ip, ok := auth.ClientIP(ctx, i.trustedProxies); ok {
+		caller = "ip:" + ip.String()
+		add(caller, i.rules.Anonymous)
+	}
+
+	if scope := token.GetTenantScope(ctx); scope != nil {
+		add("tenant:"+scope.TenantID.String(), i.rules.Tenant)
+	}
+
+	if limit, ok := i.rules.Methods[method]; ok && caller != "" {
+		add("method:"+method+":"+caller, limit)
+	}
+
+	return buckets
+}
+
+func retryAfterHeader(retryAfter time.Duration) metadata.MD {
+	seconds := int64(math.Ceil(retryAfter.Seconds()))
+	return metadata.Pairs(ratelimit.RetryAfterHeader, strconv.FormatInt(seconds, 10))
+}
@@ -0,0 +1,50 @@
+package middlewares
+
+import (
+	"context"
+
+	"github.com/vantutran2k1/rwe/internal/common/token"
+	"github.com/vantutran2k1/rwe/internal/usage"
+	"google.golang.org/grpc"
+)
+
+// UsageInterceptor meters API calls per tenant. It must run after the
+// AuthInterceptor so that the tenant scope is known; calls without a tenant
+// are not metered.
+type UsageInterceptor struct {
+	meter *usage.Meter
+}
+
+func NewUsageInterceptor(meter *usage.Meter) *UsageInterceptor {
+	return &UsageInterceptor{meter: meter}
+}
+
+func (i *UsageInterceptor) Unary() grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (any, error) {
+		i.record(ctx)
+		return handler(ctx, req)
+	}
+}
+
+func (i *UsageInterceptor) Stream() grpc.StreamServerInterceptor {
+	return func(
+		srv any,
+		ss grpc.ServerStream,
+		info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler,
+	) error {
+		i.record(ss.Context())
+		return handler(srv, ss)
+	}
+}
+
+func (i *UsageInterceptor) record(ctx context.Context) {
+	if scope := token.GetTenantScope(ctx); scope != nil {
+		i.meter.Add(scope.TenantID, usage.MetricApiCalls, 1)
+	}
+}
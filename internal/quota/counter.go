@@ -0,0 +1,41 @@
+package quota
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RequestCounter counts requests per key in fixed windows.
+type RequestCounter interface {
+	// Incr counts a request in the current window and returns the number of
+	// requests in it together with the time left until the window ends.
+	Incr(ctx context.Context, key string, window time.Duration) (int64, time.Duration, error)
+}
+
+type RedisRequestCounter struct {
+	client *redis.Client
+}
+
+func NewRedisRequestCounter(client *redis.Client) *RedisRequestCounter {
+	return &RedisRequestCounter{client: client}
+}
+
+func (r *RedisRequestCounter) Incr(ctx context.Context, key string, window time.Duration) (int64, time.Duration, error) {
+	now := time.Now()
+	start := now.Truncate(window)
+	redisKey := fmt.Sprintf("quota:%s:%d", key, start.Unix())
+
+	var count *redis.IntCmd
+	if _, err := r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		count = pipe.Incr(ctx, redisKey)
+		pipe.ExpireNX(ctx, redisKey, 2*window)
+		return nil
+	}); err != nil {
+		return 0, 0, err
+	}
+
+	return count.Val(), start.Add(window).Sub(now), nil
+}
@@ -0,0 +1,17 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+
+package sqlc
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type Querier interface {
+	GetTenantPlan(ctx context.Context, id pgtype.UUID) (GetTenantPlanRow, error)
+}
+
+var _ Querier = (*Queries)(nil)
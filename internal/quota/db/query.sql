@@ -0,0 +1,4 @@
+-- name: GetTenantPlan :one
+SELECT tier, settings
+FROM tenants
+WHERE id = $1;
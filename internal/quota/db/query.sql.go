@@ -0,0 +1,30 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: query.sql
+
+package sqlc
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const getTenantPlan = `-- name: GetTenantPlan :one
+SELECT tier, settings
+FROM tenants
+WHERE id = $1
+`
+
+type GetTenantPlanRow struct {
+	Tier     pgtype.Text `db:"tier" json:"tier"`
+	Settings []byte      `db:"settings" json:"settings"`
+}
+
+func (q *Queries) GetTenantPlan(ctx context.Context, id pgtype.UUID) (GetTenantPlanRow, error) {
+	row := q.db.QueryRow(ctx, getTenantPlan, id)
+	var i GetTenantPlanRow
+	err := row.Scan(&i.Tier, &i.Settings)
+	return i, err
+}
@@ -0,0 +1,79 @@
+package quota
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Unlimited disables a limit.
+const Unlimited = -1
+
+// Limits are the quotas of a tenant. Each tier has defaults which can be
+// overridden per tenant under the "quotas" key of tenants.settings, e.g.
+// {"quotas": {"max_workflows": 500}}.
+type Limits struct {
+	MaxWorkflows         int64 `json:"max_workflows"`
+	MaxConcurrentRuns    int64 `json:"max_concurrent_runs"`
+	RunsPerMonth         int64 `json:"runs_per_month"`
+	ApiRequestsPerMinute int64 `json:"api_requests_per_minute"`
+	MaxDefinitionBytes   int64 `json:"max_definition_bytes"`
+}
+
+var tierLimits = map[string]Limits{
+	"free": {
+		MaxWorkflows:         10,
+		MaxConcurrentRuns:    5,
+		RunsPerMonth:         1_000,
+		ApiRequestsPerMinute: 60,
+		MaxDefinitionBytes:   64 << 10,
+	},
+	"basic": {
+		MaxWorkflows:         100,
+		MaxConcurrentRuns:    25,
+		RunsPerMonth:         25_000,
+		ApiRequestsPerMinute: 600,
+		MaxDefinitionBytes:   256 << 10,
+	},
+	"pro": {
+		MaxWorkflows:         1_000,
+		MaxConcurrentRuns:    200,
+		RunsPerMonth:         500_000,
+		ApiRequestsPerMinute: 6_000,
+		MaxDefinitionBytes:   1 << 20,
+	},
+}
+
+// Resolve returns the limits of a tenant on the given tier with the overrides
+// from its settings applied. Unknown tiers get the free limits.
+func Resolve(tier string, settings []byte) (Limits, error) {
+	limits, ok := tierLimits[tier]
+	if !ok {
+		limits = tierLimits["free"]
+	}
+
+	if len(settings) == 0 {
+		return limits, nil
+	}
+
+	var s struct {
+		Quotas json.RawMessage `json:"quotas"`
+	}
+	if err := json.Unmarshal(settings, &s); err != nil {
+		return limits, fmt.Errorf("invalid tenant settings: %w", err)
+	}
+
+	if len(s.Quotas) > 0 && string(s.Quotas) != "null" {
+		// Fields missing from the overrides keep their tier defaults.
+		if err := json.Unmarshal(s.Quotas, &limits); err != nil {
+			return limits, fmt.Errorf("invalid quota overrides: %w", err)
+		}
+	}
+
+	return limits, nil
+}
+
+// Allows reports whether using n more of a resource, with used already in
+// use, stays within limit.
+func Allows(limit, used, n int64) bool {
+	return limit < 0 || used+n <= limit
+}
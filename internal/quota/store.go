@@ -0,0 +1,62 @@
+package quota
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/vantutran2k1/rwe/internal/common/utils"
+	sqlc "github.com/vantutran2k1/rwe/internal/quota/db"
+)
+
+type cachedLimits struct {
+	limits    Limits
+	expiresAt time.Time
+}
+
+// Store resolves tenant limits and caches them briefly, since they are
+// needed on every API call but change rarely.
+type Store struct {
+	querier sqlc.Querier
+	ttl     time.Duration
+
+	mu      sync.Mutex
+	entries map[uuid.UUID]cachedLimits
+}
+
+func NewStore(pool *pgxpool.Pool, ttl time.Duration) *Store {
+	return &Store{
+		querier: sqlc.New(pool),
+		ttl:     ttl,
+		entries: make(map[uuid.UUID]cachedLimits),
+	}
+}
+
+func (s *Store) Limits(ctx context.Context, tenantID uuid.UUID) (Limits, error) {
+	now := time.Now()
+
+	s.mu.Lock()
+	entry, ok := s.entries[tenantID]
+	s.mu.Unlock()
+	if ok && now.Before(entry.expiresAt) {
+		return entry.limits, nil
+	}
+
+	plan, err := s.querier.GetTenantPlan(ctx, utils.UUIDToPgUUID(tenantID))
+	if err != nil {
+		return Limits{}, err
+	}
+
+	limits, err := Resolve(plan.Tier.String, plan.Settings)
+	if err != nil {
+		return Limits{}, err
+	}
+
+	s.mu.Lock()
+	s.entries[tenantID] = cachedLimits{limits: limits, expiresAt: now.Add(s.ttl)}
+	s.mu.Unlock()
+
+	return limits, nil
+}
@@ -0,0 +1,106 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RetryAfterHeader is the response metadata key carrying the number of
+// seconds a rate limited client should wait.
+const RetryAfterHeader = "retry-after"
+
+// Limit is a token bucket refilled at Rate tokens per second holding at most
+// Burst tokens.
+type Limit struct {
+	Rate  float64
+	Burst int
+}
+
+func (l Limit) Enabled() bool {
+	return l.Rate > 0 && l.Burst > 0
+}
+
+type Bucket struct {
+	Key   string
+	Limit Limit
+}
+
+// Limiter takes one token from every bucket, or from none of them when any
+// bucket is empty, in which case it reports how long to wait.
+type Limiter interface {
+	Allow(ctx context.Context, buckets []Bucket) (bool, time.Duration, error)
+}
+
+// tokenBucketScript refills and checks all buckets against the Redis clock,
+// then consumes a token from each only if all of them have one. It returns
+// {allowed, retry_after_ms}.
+var tokenBucketScript = redis.NewScript(`
+local t = redis.call('TIME')
+local now = tonumber(t[1]) * 1000 + math.floor(tonumber(t[2]) / 1000)
+local tokens = {}
+local retry = 0
+
+for i, key in ipairs(KEYS) do
+  local rate = tonumber(ARGV[2 * i - 1]) / 1000
+  local burst = tonumber(ARGV[2 * i])
+  local state = redis.call('HMGET', key, 'tokens', 'ts')
+  local available = tonumber(state[1]) or burst
+  local ts = tonumber(state[2]) or now
+  available = math.min(burst, available + math.max(0, now - ts) * rate)
+  tokens[i] = available
+  if available < 1 then
+    retry = math.max(retry, math.ceil((1 - available) / rate))
+  end
+end
+
+local allowed = retry == 0
+for i, key in ipairs(KEYS) do
+  local rate = tonumber(ARGV[2 * i - 1]) / 1000
+  local burst = tonumber(ARGV[2 * i])
+  local available = tokens[i]
+  if allowed then
+    available = available - 1
+  end
+  redis.call('HSET', key, 'tokens', available, 'ts', now)
+  redis.call('PEXPIRE', key, math.ceil(burst / rate) + 1000)
+end
+
+if allowed then
+  return {1, 0}
+end
+return {0, retry}
+`)
+
+type RedisLimiter struct {
+	client *redis.Client
+}
+
+func NewRedisLimiter(client *redis.Client) *RedisLimiter {
+	return &RedisLimiter{client: client}
+}
+
+func (r *RedisLimiter) Allow(ctx context.Context, buckets []Bucket) (bool, time.Duration, error) {
+	if len(buckets) == 0 {
+		return true, 0, nil
+	}
+
+	keys := make([]string, 0, len(buckets))
+	args := make([]any, 0, 2*len(buckets))
+	for _, b := range buckets {
+		keys = append(keys, "ratelimit:"+b.Key)
+		args = append(args, b.Limit.Rate, b.Limit.Burst)
+	}
+
+	res, err := tokenBucketScript.Run(ctx, r.client, keys, args...).Int64Slice()
+	if err != nil {
+		return false, 0, err
+	}
+	if len(res) != 2 {
+		return false, 0, fmt.Errorf("unexpected rate limit result %v", res)
+	}
+
+	return res[0] == 1, time.Duration(res[1]) * time.Millisecond, nil
+}
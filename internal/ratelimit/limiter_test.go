@@ -0,0 +1,131 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+type step struct {
+	// advance moves the Redis clock forward before the request.
+	advance   time.Duration
+	buckets   []Bucket
+	wantAllow bool
+	wantRetry time.Duration
+}
+
+func TestRedisLimiter(t *testing.T) {
+	perSecond := Bucket{Key: "a", Limit: Limit{Rate: 1, Burst: 2}}
+	slow := Bucket{Key: "b", Limit: Limit{Rate: 0.5, Burst: 1}}
+	fast := Bucket{Key: "c", Limit: Limit{Rate: 10, Burst: 1}}
+
+	tests := []struct {
+		name  string
+		steps []step
+	}{
+		{
+			name: "burst then empty",
+			steps: []step{
+				{buckets: []Bucket{perSecond}, wantAllow: true},
+				{buckets: []Bucket{perSecond}, wantAllow: true},
+				{buckets: []Bucket{perSecond}, wantRetry: time.Second},
+			},
+		},
+		{
+			name: "refills over time",
+			steps: []step{
+				{buckets: []Bucket{perSecond}, wantAllow: true},
+				{buckets: []Bucket{perSecond}, wantAllow: true},
+				{advance: 400 * time.Millisecond, buckets: []Bucket{perSecond}, wantRetry: 600 * time.Millisecond},
+				{advance: 600 * time.Millisecond, buckets: []Bucket{perSecond}, wantAllow: true},
+				{buckets: []Bucket{perSecond}, wantRetry: time.Second},
+			},
+		},
+		{
+			name: "refill is capped at burst",
+			steps: []step{
+				{buckets: []Bucket{perSecond}, wantAllow: true},
+				{advance: time.Hour, buckets: []Bucket{perSecond}, wantAllow: true},
+				{buckets: []Bucket{perSecond}, wantAllow: true},
+				{buckets: []Bucket{perSecond}, wantRetry: time.Second},
+			},
+		},
+		{
+			name: "denied requests consume nothing",
+			steps: []step{
+				{buckets: []Bucket{perSecond}, wantAllow: true},
+				{buckets: []Bucket{perSecond}, wantAllow: true},
+				{buckets: []Bucket{perSecond}, wantRetry: time.Second},
+				{buckets: []Bucket{perSecond}, wantRetry: time.Second},
+				{advance: time.Second, buckets: []Bucket{perSecond}, wantAllow: true},
+			},
+		},
+		{
+			name: "all buckets or none",
+			steps: []step{
+				{buckets: []Bucket{fast, slow}, wantAllow: true},
+				{advance: 100 * time.Millisecond, buckets: []Bucket{fast, slow}, wantRetry: 1900 * time.Millisecond},
+				// The fast bucket was not charged for the denied request.
+				{buckets: []Bucket{fast}, wantAllow: true},
+			},
+		},
+		{
+			name: "retry after is the longest wait",
+			steps: []step{
+				{buckets: []Bucket{fast, slow}, wantAllow: true},
+				{buckets: []Bucket{fast, slow}, wantRetry: 2 * time.Second},
+			},
+		},
+		{
+			name: "no buckets",
+			steps: []step{
+				{wantAllow: true},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := miniredis.RunT(t)
+			now := time.Unix(1_700_000_000, 0)
+			server.SetTime(now)
+
+			client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+			t.Cleanup(func() { client.Close() })
+			limiter := NewRedisLimiter(client)
+
+			for i, s := range tt.steps {
+				now = now.Add(s.advance)
+				server.SetTime(now)
+
+				allowed, retry, err := limiter.Allow(context.Background(), s.buckets)
+				if err != nil {
+					t.Fatalf("step %d: %v", i, err)
+				}
+				if allowed != s.wantAllow || retry != s.wantRetry {
+					t.Fatalf("step %d: got %v, %v, want %v, %v", i, allowed, retry, s.wantAllow, s.wantRetry)
+				}
+			}
+		})
+	}
+}
+
+func TestRedisLimiterExpiresIdleBuckets(t *testing.T) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { client.Close() })
+
+	bucket := Bucket{Key: "a", Limit: Limit{Rate: 1, Burst: 5}}
+	if _, _, err := NewRedisLimiter(client).Allow(context.Background(), []Bucket{bucket}); err != nil {
+		t.Fatal(err)
+	}
+
+	// A bucket refills completely after burst / rate, so it only has to be
+	// kept that long.
+	if ttl := server.TTL("ratelimit:a"); ttl <= 5*time.Second || ttl > 7*time.Second {
+		t.Errorf("got ttl %v, want just over 5s", ttl)
+	}
+}
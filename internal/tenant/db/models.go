@@ -56,15 +56,19 @@ func (ns NullTenantStatus) Value() (driver.Value, error) {
 }
 
 type ApiKey struct {
-	ID         pgtype.UUID        `db:"id" json:"id"`
-	TenantID   pgtype.UUID        `db:"tenant_id" json:"tenant_id"`
-	KeyHash    string             `db:"key_hash" json:"key_hash"`
-	KeyPrefix  string             `db:"key_prefix" json:"key_prefix"`
-	Name       pgtype.Text        `db:"name" json:"name"`
-	CreatedAt  pgtype.Timestamptz `db:"created_at" json:"created_at"`
-	ExpiresAt  pgtype.Timestamptz `db:"expires_at" json:"expires_at"`
-	Revoked    pgtype.Bool        `db:"revoked" json:"revoked"`
-	LastUsedAt pgtype.Timestamptz `db:"last_used_at" json:"last_used_at"`
+	ID           pgtype.UUID        `db:"id" json:"id"`
+	TenantID     pgtype.UUID        `db:"tenant_id" json:"tenant_id"`
+	KeyHash      string             `db:"key_hash" json:"key_hash"`
+	KeyPrefix    string             `db:"key_prefix" json:"key_prefix"`
+	Name         pgtype.Text        `db:"name" json:"name"`
+	CreatedAt    pgtype.Timestamptz `db:"created_at" json:"created_at"`
+	ExpiresAt    pgtype.Timestamptz `db:"expires_at" json:"expires_at"`
+	Revoked      pgtype.Bool        `db:"revoked" json:"revoked"`
+	LastUsedAt   pgtype.Timestamptz `db:"last_used_at" json:"last_used_at"`
+	Role         pgtype.Text        `db:"role" json:"role"`
+	Scopes       []string           `db:"scopes" json:"scopes"`
+	AllowedCidrs []string           `db:"allowed_cidrs" json:"allowed_cidrs"`
+	RotatedTo    pgtype.UUID        `db:"rotated_to" json:"rotated_to"`
 }
 
 type Event struct {
@@ -74,34 +78,136 @@ type Event struct {
 	AggregateID pgtype.UUID        `db:"aggregate_id" json:"aggregate_id"`
 	Payload     []byte             `db:"payload" json:"payload"`
 	CreatedAt   pgtype.Timestamptz `db:"created_at" json:"created_at"`
+	UserID      pgtype.UUID        `db:"user_id" json:"user_id"`
+}
+
+type MfaRecoveryCode struct {
+	ID        pgtype.UUID        `db:"id" json:"id"`
+	UserID    pgtype.UUID        `db:"user_id" json:"user_id"`
+	CodeHash  string             `db:"code_hash" json:"code_hash"`
+	CreatedAt pgtype.Timestamptz `db:"created_at" json:"created_at"`
+	UsedAt    pgtype.Timestamptz `db:"used_at" json:"used_at"`
+}
+
+type OidcLoginState struct {
+	StateHash    string             `db:"state_hash" json:"state_hash"`
+	TenantID     pgtype.UUID        `db:"tenant_id" json:"tenant_id"`
+	CodeVerifier string             `db:"code_verifier" json:"code_verifier"`
+	Nonce        string             `db:"nonce" json:"nonce"`
+	LinkUserID   pgtype.UUID        `db:"link_user_id" json:"link_user_id"`
+	CreatedAt    pgtype.Timestamptz `db:"created_at" json:"created_at"`
+	ExpiresAt    pgtype.Timestamptz `db:"expires_at" json:"expires_at"`
+	UsedAt       pgtype.Timestamptz `db:"used_at" json:"used_at"`
+}
+
+type OidcProvider struct {
+	TenantID              pgtype.UUID        `db:"tenant_id" json:"tenant_id"`
+	Issuer                string             `db:"issuer" json:"issuer"`
+	ClientID              string             `db:"client_id" json:"client_id"`
+	EncryptedClientSecret []byte             `db:"encrypted_client_secret" json:"encrypted_client_secret"`
+	AllowedDomains        []string           `db:"allowed_domains" json:"allowed_domains"`
+	DefaultRole           string             `db:"default_role" json:"default_role"`
+	Enabled               bool               `db:"enabled" json:"enabled"`
+	CreatedAt             pgtype.Timestamptz `db:"created_at" json:"created_at"`
+	UpdatedAt             pgtype.Timestamptz `db:"updated_at" json:"updated_at"`
+}
+
+type RefreshToken struct {
+	ID        pgtype.UUID        `db:"id" json:"id"`
+	SessionID pgtype.UUID        `db:"session_id" json:"session_id"`
+	TokenHash string             `db:"token_hash" json:"token_hash"`
+	CreatedAt pgtype.Timestamptz `db:"created_at" json:"created_at"`
+	UsedAt    pgtype.Timestamptz `db:"used_at" json:"used_at"`
+}
+
+type Session struct {
+	ID                   pgtype.UUID        `db:"id" json:"id"`
+	UserID               pgtype.UUID        `db:"user_id" json:"user_id"`
+	UserAgent            pgtype.Text        `db:"user_agent" json:"user_agent"`
+	IpAddress            pgtype.Text        `db:"ip_address" json:"ip_address"`
+	AccessTokenID        pgtype.UUID        `db:"access_token_id" json:"access_token_id"`
+	AccessTokenExpiresAt pgtype.Timestamptz `db:"access_token_expires_at" json:"access_token_expires_at"`
+	CreatedAt            pgtype.Timestamptz `db:"created_at" json:"created_at"`
+	LastUsedAt           pgtype.Timestamptz `db:"last_used_at" json:"last_used_at"`
+	ExpiresAt            pgtype.Timestamptz `db:"expires_at" json:"expires_at"`
+	RevokedAt            pgtype.Timestamptz `db:"revoked_at" json:"revoked_at"`
+}
+
+type SigningKey struct {
+	Kid                 string             `db:"kid" json:"kid"`
+	Algorithm           string             `db:"algorithm" json:"algorithm"`
+	PublicKey           []byte             `db:"public_key" json:"public_key"`
+	EncryptedPrivateKey []byte             `db:"encrypted_private_key" json:"encrypted_private_key"`
+	CreatedAt           pgtype.Timestamptz `db:"created_at" json:"created_at"`
+	RetiredAt           pgtype.Timestamptz `db:"retired_at" json:"retired_at"`
+	ExpiresAt           pgtype.Timestamptz `db:"expires_at" json:"expires_at"`
 }
 
 type Task struct {
-	ID         pgtype.UUID        `db:"id" json:"id"`
-	RunID      pgtype.UUID        `db:"run_id" json:"run_id"`
-	StepID     string             `db:"step_id" json:"step_id"`
-	Status     string             `db:"status" json:"status"`
-	WorkerID   pgtype.UUID        `db:"worker_id" json:"worker_id"`
-	Attempts   pgtype.Int4        `db:"attempts" json:"attempts"`
-	LastError  pgtype.Text        `db:"last_error" json:"last_error"`
-	StartedAt  pgtype.Timestamptz `db:"started_at" json:"started_at"`
-	FinishedAt pgtype.Timestamptz `db:"finished_at" json:"finished_at"`
-	Result     []byte             `db:"result" json:"result"`
+	ID             pgtype.UUID        `db:"id" json:"id"`
+	RunID          pgtype.UUID        `db:"run_id" json:"run_id"`
+	StepID         string             `db:"step_id" json:"step_id"`
+	Status         string             `db:"status" json:"status"`
+	WorkerID       pgtype.UUID        `db:"worker_id" json:"worker_id"`
+	Attempts       pgtype.Int4        `db:"attempts" json:"attempts"`
+	LastError      pgtype.Text        `db:"last_error" json:"last_error"`
+	StartedAt      pgtype.Timestamptz `db:"started_at" json:"started_at"`
+	FinishedAt     pgtype.Timestamptz `db:"finished_at" json:"finished_at"`
+	Result         []byte             `db:"result" json:"result"`
+	StepType       string             `db:"step_type" json:"step_type"`
+	Input          []byte             `db:"input" json:"input"`
+	DependsOn      []string           `db:"depends_on" json:"depends_on"`
+	CreatedAt      pgtype.Timestamptz `db:"created_at" json:"created_at"`
+	UpdatedAt      pgtype.Timestamptz `db:"updated_at" json:"updated_at"`
+	Queue          pgtype.Text        `db:"queue" json:"queue"`
+	LeaseExpiresAt pgtype.Timestamptz `db:"lease_expires_at" json:"lease_expires_at"`
+	TimeoutSeconds pgtype.Int4        `db:"timeout_seconds" json:"timeout_seconds"`
+	DeadlineAt     pgtype.Timestamptz `db:"deadline_at" json:"deadline_at"`
+	RetryPolicy    []byte             `db:"retry_policy" json:"retry_policy"`
+	ErrorCode      pgtype.Text        `db:"error_code" json:"error_code"`
+	AvailableAt    pgtype.Timestamptz `db:"available_at" json:"available_at"`
+	DeadLetteredAt pgtype.Timestamptz `db:"dead_lettered_at" json:"dead_lettered_at"`
 }
 
 type Tenant struct {
-	ID           pgtype.UUID        `db:"id" json:"id"`
-	Name         string             `db:"name" json:"name"`
-	CreatedAt    pgtype.Timestamptz `db:"created_at" json:"created_at"`
-	ParentID     pgtype.UUID        `db:"parent_id" json:"parent_id"`
-	Slug         string             `db:"slug" json:"slug"`
-	Domain       pgtype.Text        `db:"domain" json:"domain"`
-	Status       NullTenantStatus   `db:"status" json:"status"`
-	Region       pgtype.Text        `db:"region" json:"region"`
-	Tier         pgtype.Text        `db:"tier" json:"tier"`
-	Settings     []byte             `db:"settings" json:"settings"`
-	ContactEmail pgtype.Text        `db:"contact_email" json:"contact_email"`
-	UpdatedAt    pgtype.Timestamptz `db:"updated_at" json:"updated_at"`
+	ID              pgtype.UUID        `db:"id" json:"id"`
+	Name            string             `db:"name" json:"name"`
+	CreatedAt       pgtype.Timestamptz `db:"created_at" json:"created_at"`
+	ParentID        pgtype.UUID        `db:"parent_id" json:"parent_id"`
+	Slug            string             `db:"slug" json:"slug"`
+	Domain          pgtype.Text        `db:"domain" json:"domain"`
+	Status          NullTenantStatus   `db:"status" json:"status"`
+	Region          pgtype.Text        `db:"region" json:"region"`
+	Tier            pgtype.Text        `db:"tier" json:"tier"`
+	Settings        []byte             `db:"settings" json:"settings"`
+	ContactEmail    pgtype.Text        `db:"contact_email" json:"contact_email"`
+	UpdatedAt       pgtype.Timestamptz `db:"updated_at" json:"updated_at"`
+	StatusReason    pgtype.Text        `db:"status_reason" json:"status_reason"`
+	StatusChangedAt pgtype.Timestamptz `db:"status_changed_at" json:"status_changed_at"`
+	DeleteAfter     pgtype.Timestamptz `db:"delete_after" json:"delete_after"`
+	SuspendedBy     pgtype.Text        `db:"suspended_by" json:"suspended_by"`
+}
+
+type TenantDomain struct {
+	TenantID          pgtype.UUID        `db:"tenant_id" json:"tenant_id"`
+	Domain            string             `db:"domain" json:"domain"`
+	VerificationToken string             `db:"verification_token" json:"verification_token"`
+	CreatedAt         pgtype.Timestamptz `db:"created_at" json:"created_at"`
+	VerifiedAt        pgtype.Timestamptz `db:"verified_at" json:"verified_at"`
+}
+
+type TenantInvitation struct {
+	ID         pgtype.UUID        `db:"id" json:"id"`
+	TenantID   pgtype.UUID        `db:"tenant_id" json:"tenant_id"`
+	Email      string             `db:"email" json:"email"`
+	Role       string             `db:"role" json:"role"`
+	TokenHash  string             `db:"token_hash" json:"token_hash"`
+	InvitedBy  pgtype.UUID        `db:"invited_by" json:"invited_by"`
+	CreatedAt  pgtype.Timestamptz `db:"created_at" json:"created_at"`
+	ExpiresAt  pgtype.Timestamptz `db:"expires_at" json:"expires_at"`
+	AcceptedAt pgtype.Timestamptz `db:"accepted_at" json:"accepted_at"`
+	AcceptedBy pgtype.UUID        `db:"accepted_by" json:"accepted_by"`
+	RevokedAt  pgtype.Timestamptz `db:"revoked_at" json:"revoked_at"`
 }
 
 type TenantMember struct {
@@ -119,12 +225,60 @@ type UsageRecord struct {
 	SampleAt pgtype.Timestamptz `db:"sample_at" json:"sample_at"`
 }
 
+type UsageRollup struct {
+	TenantID    pgtype.UUID        `db:"tenant_id" json:"tenant_id"`
+	Metric      string             `db:"metric" json:"metric"`
+	Granularity string             `db:"granularity" json:"granularity"`
+	BucketStart pgtype.Timestamptz `db:"bucket_start" json:"bucket_start"`
+	Value       pgtype.Numeric     `db:"value" json:"value"`
+	UpdatedAt   pgtype.Timestamptz `db:"updated_at" json:"updated_at"`
+}
+
+type UsageWatermark struct {
+	Name      string             `db:"name" json:"name"`
+	Watermark pgtype.Timestamptz `db:"watermark" json:"watermark"`
+}
+
 type User struct {
-	ID           pgtype.UUID        `db:"id" json:"id"`
-	Email        string             `db:"email" json:"email"`
-	PasswordHash string             `db:"password_hash" json:"password_hash"`
-	FullName     pgtype.Text        `db:"full_name" json:"full_name"`
-	CreatedAt    pgtype.Timestamptz `db:"created_at" json:"created_at"`
+	ID              pgtype.UUID        `db:"id" json:"id"`
+	Email           string             `db:"email" json:"email"`
+	PasswordHash    string             `db:"password_hash" json:"password_hash"`
+	FullName        pgtype.Text        `db:"full_name" json:"full_name"`
+	CreatedAt       pgtype.Timestamptz `db:"created_at" json:"created_at"`
+	EmailVerifiedAt pgtype.Timestamptz `db:"email_verified_at" json:"email_verified_at"`
+}
+
+type UserIdentity struct {
+	Issuer    string             `db:"issuer" json:"issuer"`
+	Subject   string             `db:"subject" json:"subject"`
+	UserID    pgtype.UUID        `db:"user_id" json:"user_id"`
+	CreatedAt pgtype.Timestamptz `db:"created_at" json:"created_at"`
+}
+
+type UserMfa struct {
+	UserID          pgtype.UUID        `db:"user_id" json:"user_id"`
+	EncryptedSecret []byte             `db:"encrypted_secret" json:"encrypted_secret"`
+	ConfirmedAt     pgtype.Timestamptz `db:"confirmed_at" json:"confirmed_at"`
+	LastUsedStep    int64              `db:"last_used_step" json:"last_used_step"`
+	CreatedAt       pgtype.Timestamptz `db:"created_at" json:"created_at"`
+}
+
+type UserToken struct {
+	ID        pgtype.UUID        `db:"id" json:"id"`
+	UserID    pgtype.UUID        `db:"user_id" json:"user_id"`
+	Purpose   string             `db:"purpose" json:"purpose"`
+	TokenHash string             `db:"token_hash" json:"token_hash"`
+	CreatedAt pgtype.Timestamptz `db:"created_at" json:"created_at"`
+	ExpiresAt pgtype.Timestamptz `db:"expires_at" json:"expires_at"`
+	UsedAt    pgtype.Timestamptz `db:"used_at" json:"used_at"`
+	Attempts  int32              `db:"attempts" json:"attempts"`
+}
+
+type WebhookDelivery struct {
+	WebhookID  pgtype.UUID        `db:"webhook_id" json:"webhook_id"`
+	DeliveryID string             `db:"delivery_id" json:"delivery_id"`
+	RunID      pgtype.UUID        `db:"run_id" json:"run_id"`
+	ReceivedAt pgtype.Timestamptz `db:"received_at" json:"received_at"`
 }
 
 type Worker struct {
@@ -134,6 +288,8 @@ type Worker struct {
 	LastHeartbeat pgtype.Timestamptz `db:"last_heartbeat" json:"last_heartbeat"`
 	Capacity      []byte             `db:"capacity" json:"capacity"`
 	Metadata      []byte             `db:"metadata" json:"metadata"`
+	TenantID      pgtype.UUID        `db:"tenant_id" json:"tenant_id"`
+	CreatedAt     pgtype.Timestamptz `db:"created_at" json:"created_at"`
 }
 
 type Workflow struct {
@@ -148,12 +304,63 @@ type Workflow struct {
 }
 
 type WorkflowRun struct {
-	ID         pgtype.UUID        `db:"id" json:"id"`
-	TenantID   pgtype.UUID        `db:"tenant_id" json:"tenant_id"`
+	ID              pgtype.UUID        `db:"id" json:"id"`
+	TenantID        pgtype.UUID        `db:"tenant_id" json:"tenant_id"`
+	WorkflowID      pgtype.UUID        `db:"workflow_id" json:"workflow_id"`
+	Status          string             `db:"status" json:"status"`
+	StartedAt       pgtype.Timestamptz `db:"started_at" json:"started_at"`
+	FinishedAt      pgtype.Timestamptz `db:"finished_at" json:"finished_at"`
+	Payload         []byte             `db:"payload" json:"payload"`
+	Metadata        []byte             `db:"metadata" json:"metadata"`
+	Result          []byte             `db:"result" json:"result"`
+	Error           pgtype.Text        `db:"error" json:"error"`
+	UpdatedAt       pgtype.Timestamptz `db:"updated_at" json:"updated_at"`
+	WorkflowVersion pgtype.Int4        `db:"workflow_version" json:"workflow_version"`
+	ScheduleID      pgtype.UUID        `db:"schedule_id" json:"schedule_id"`
+	ScheduledAt     pgtype.Timestamptz `db:"scheduled_at" json:"scheduled_at"`
+}
+
+type WorkflowSchedule struct {
+	ID                   pgtype.UUID        `db:"id" json:"id"`
+	TenantID             pgtype.UUID        `db:"tenant_id" json:"tenant_id"`
+	WorkflowID           pgtype.UUID        `db:"workflow_id" json:"workflow_id"`
+	Name                 string             `db:"name" json:"name"`
+	CronExpression       pgtype.Text        `db:"cron_expression" json:"cron_expression"`
+	Timezone             string             `db:"timezone" json:"timezone"`
+	IntervalSeconds      pgtype.Int4        `db:"interval_seconds" json:"interval_seconds"`
+	WorkflowVersion      pgtype.Int4        `db:"workflow_version" json:"workflow_version"`
+	Payload              []byte             `db:"payload" json:"payload"`
+	OverlapPolicy        string             `db:"overlap_policy" json:"overlap_policy"`
+	CatchupWindowSeconds int32              `db:"catchup_window_seconds" json:"catchup_window_seconds"`
+	Paused               bool               `db:"paused" json:"paused"`
+	PauseReason          pgtype.Text        `db:"pause_reason" json:"pause_reason"`
+	NextRunAt            pgtype.Timestamptz `db:"next_run_at" json:"next_run_at"`
+	LastRunAt            pgtype.Timestamptz `db:"last_run_at" json:"last_run_at"`
+	LastRunID            pgtype.UUID        `db:"last_run_id" json:"last_run_id"`
+	CreatedAt            pgtype.Timestamptz `db:"created_at" json:"created_at"`
+	UpdatedAt            pgtype.Timestamptz `db:"updated_at" json:"updated_at"`
+}
+
+type WorkflowVersion struct {
 	WorkflowID pgtype.UUID        `db:"workflow_id" json:"workflow_id"`
-	Status     string             `db:"status" json:"status"`
-	StartedAt  pgtype.Timestamptz `db:"started_at" json:"started_at"`
-	FinishedAt pgtype.Timestamptz `db:"finished_at" json:"finished_at"`
-	Payload    []byte             `db:"payload" json:"payload"`
-	Metadata   []byte             `db:"metadata" json:"metadata"`
+	Version    int32              `db:"version" json:"version"`
+	Definition []byte             `db:"definition" json:"definition"`
+	CreatedAt  pgtype.Timestamptz `db:"created_at" json:"created_at"`
+}
+
+type WorkflowWebhook struct {
+	ID               pgtype.UUID        `db:"id" json:"id"`
+	TenantID         pgtype.UUID        `db:"tenant_id" json:"tenant_id"`
+	WorkflowID       pgtype.UUID        `db:"workflow_id" json:"workflow_id"`
+	Name             string             `db:"name" json:"name"`
+	TokenHash        string             `db:"token_hash" json:"token_hash"`
+	Secret           []byte             `db:"secret" json:"secret"`
+	SignatureHeader  string             `db:"signature_header" json:"signature_header"`
+	SignatureScheme  string             `db:"signature_scheme" json:"signature_scheme"`
+	DeliveryIDSource pgtype.Text        `db:"delivery_id_source" json:"delivery_id_source"`
+	PayloadMapping   []byte             `db:"payload_mapping" json:"payload_mapping"`
+	WorkflowVersion  pgtype.Int4        `db:"workflow_version" json:"workflow_version"`
+	CreatedAt        pgtype.Timestamptz `db:"created_at" json:"created_at"`
+	UpdatedAt        pgtype.Timestamptz `db:"updated_at" json:"updated_at"`
+	LastDeliveryAt   pgtype.Timestamptz `db:"last_delivery_at" json:"last_delivery_at"`
 }
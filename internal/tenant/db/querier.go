@@ -11,9 +11,44 @@ import (
 )
 
 type Querier interface {
+	AcceptInvitation(ctx context.Context, arg AcceptInvitationParams) error
 	AddTenantMember(ctx context.Context, arg AddTenantMemberParams) (TenantMember, error)
+	// Appends to one stream at a time, so that ids grow in commit order within
+	// the tenant or user stream a subscriber follows.
+	AppendEvent(ctx context.Context, arg AppendEventParams) (int64, error)
+	CountChildTenants(ctx context.Context, parentID pgtype.UUID) (int64, error)
+	CreateInvitation(ctx context.Context, arg CreateInvitationParams) (TenantInvitation, error)
 	CreateTenant(ctx context.Context, arg CreateTenantParams) (Tenant, error)
+	DeleteTenant(ctx context.Context, id pgtype.UUID) error
+	DeleteTenantEvents(ctx context.Context, tenantID pgtype.UUID) error
+	DeleteTenantRuns(ctx context.Context, tenantID pgtype.UUID) error
+	DeleteTenantTasks(ctx context.Context, tenantID pgtype.UUID) error
+	DeleteTenantUsageRecords(ctx context.Context, tenantID pgtype.UUID) error
+	DeleteTenantUsageRollups(ctx context.Context, tenantID pgtype.UUID) error
+	DeleteTenantWorkflows(ctx context.Context, tenantID pgtype.UUID) error
+	DetachChildTenants(ctx context.Context, parentID pgtype.UUID) error
+	// Returns the highest role the user holds in the tenant or any of its
+	// ancestors.
+	GetEffectiveTenantRole(ctx context.Context, arg GetEffectiveTenantRoleParams) (pgtype.Text, error)
+	GetInvitationForUpdate(ctx context.Context, tokenHash string) (GetInvitationForUpdateRow, error)
+	GetTenant(ctx context.Context, id pgtype.UUID) (Tenant, error)
 	GetTenantBySlug(ctx context.Context, slug string) (pgtype.UUID, error)
+	GetTenantDepth(ctx context.Context, id pgtype.UUID) (int32, error)
+	GetTenantForUpdate(ctx context.Context, id pgtype.UUID) (Tenant, error)
+	GetTenantMember(ctx context.Context, arg GetTenantMemberParams) (GetTenantMemberRow, error)
+	GetTenantMemberForUpdate(ctx context.Context, arg GetTenantMemberForUpdateParams) (TenantMember, error)
+	GetTenantName(ctx context.Context, id pgtype.UUID) (string, error)
+	IsTenantMemberByEmail(ctx context.Context, arg IsTenantMemberByEmailParams) (bool, error)
+	ListTenantMembers(ctx context.Context, tenantID pgtype.UUID) ([]ListTenantMembersRow, error)
+	ListTenantOwnersForUpdate(ctx context.Context, tenantID pgtype.UUID) ([]pgtype.UUID, error)
+	ListTenantSubtree(ctx context.Context, id pgtype.UUID) ([]ListTenantSubtreeRow, error)
+	ListTenantsByUserID(ctx context.Context, userID pgtype.UUID) ([]ListTenantsByUserIDRow, error)
+	ListTenantsDueForDeletion(ctx context.Context, limit int32) ([]pgtype.UUID, error)
+	RemoveTenantMember(ctx context.Context, arg RemoveTenantMemberParams) error
+	RevokePendingInvitations(ctx context.Context, arg RevokePendingInvitationsParams) error
+	SetTenantStatus(ctx context.Context, arg SetTenantStatusParams) (Tenant, error)
+	UpdateTenant(ctx context.Context, arg UpdateTenantParams) (Tenant, error)
+	UpdateTenantMemberRole(ctx context.Context, arg UpdateTenantMemberRoleParams) error
 }
 
 var _ Querier = (*Queries)(nil)
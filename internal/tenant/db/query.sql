@@ -1,6 +1,6 @@
 -- name: CreateTenant :one
-INSERT INTO tenants (name, slug, contact_email, tier, region, status)
-VALUES ($1, $2, $3, $4, $5, 'active')
+INSERT INTO tenants (name, slug, contact_email, tier, region, parent_id, status)
+VALUES ($1, $2, $3, $4, $5, $6, 'active')
 RETURNING *;
 
 -- name: GetTenantBySlug :one
@@ -11,4 +11,232 @@ WHERE slug = $1;
 -- name: AddTenantMember :one
 INSERT INTO tenant_members (tenant_id, user_id, role)
 VALUES ($1, $2, $3)
-RETURNING *;
\ No newline at end of file
+RETURNING *;
+
+-- name: GetTenantMember :one
+SELECT tm.user_id, tm.role, tm.joined_at, u.email, u.full_name
+FROM tenant_members tm
+         JOIN users u ON u.id = tm.user_id
+WHERE tm.tenant_id = $1
+  AND tm.user_id = $2;
+
+-- name: GetTenantMemberForUpdate :one
+SELECT *
+FROM tenant_members
+WHERE tenant_id = $1
+  AND user_id = $2
+    FOR UPDATE;
+
+-- name: IsTenantMemberByEmail :one
+SELECT EXISTS (SELECT 1
+               FROM tenant_members tm
+                        JOIN users u ON u.id = tm.user_id
+               WHERE tm.tenant_id = $1
+                 AND lower(u.email) = lower(@email::text));
+
+-- name: ListTenantMembers :many
+SELECT tm.user_id, tm.role, tm.joined_at, u.email, u.full_name
+FROM tenant_members tm
+         JOIN users u ON u.id = tm.user_id
+WHERE tm.tenant_id = $1
+ORDER BY tm.joined_at, tm.user_id;
+
+-- name: ListTenantOwnersForUpdate :many
+SELECT user_id
+FROM tenant_members
+WHERE tenant_id = $1
+  AND role = 'owner'
+    FOR UPDATE;
+
+-- name: UpdateTenantMemberRole :exec
+UPDATE tenant_members
+SET role = $3
+WHERE tenant_id = $1
+  AND user_id = $2;
+
+-- name: RemoveTenantMember :exec
+DELETE
+FROM tenant_members
+WHERE tenant_id = $1
+  AND user_id = $2;
+
+-- name: RevokePendingInvitations :exec
+UPDATE tenant_invitations
+SET revoked_at = now()
+WHERE tenant_id = $1
+  AND lower(email) = lower(@email::text)
+  AND accepted_at IS NULL
+  AND revoked_at IS NULL;
+
+-- name: CreateInvitation :one
+INSERT INTO tenant_invitations (tenant_id, email, role, token_hash, invited_by, expires_at)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING *;
+
+-- name: GetInvitationForUpdate :one
+SELECT i.*, t.name AS tenant_name
+FROM tenant_invitations i
+         JOIN tenants t ON t.id = i.tenant_id
+WHERE i.token_hash = $1
+    FOR UPDATE OF i;
+
+-- name: AcceptInvitation :exec
+UPDATE tenant_invitations
+SET accepted_at = now(),
+    accepted_by = $2
+WHERE id = $1;
+
+-- name: GetTenantName :one
+SELECT name
+FROM tenants
+WHERE id = $1;
+
+-- name: GetTenant :one
+SELECT *
+FROM tenants
+WHERE id = $1;
+
+-- name: GetTenantForUpdate :one
+SELECT *
+FROM tenants
+WHERE id = $1
+    FOR UPDATE;
+
+-- name: ListTenantsByUserID :many
+SELECT t.*, tm.role AS member_role
+FROM tenant_members tm
+         JOIN tenants t ON t.id = tm.tenant_id
+WHERE tm.user_id = $1
+ORDER BY tm.joined_at, t.id;
+
+-- name: UpdateTenant :one
+UPDATE tenants
+SET name          = coalesce(sqlc.narg(name), name),
+    -- quota overrides are managed by the platform and survive replacing the settings
+    settings      = CASE
+                        WHEN sqlc.narg(settings)::jsonb IS NULL THEN settings
+                        ELSE sqlc.narg(settings)::jsonb ||
+                             jsonb_strip_nulls(jsonb_build_object('quotas', settings -> 'quotas')) END,
+    contact_email = coalesce(sqlc.narg(contact_email), contact_email),
+    domain        = coalesce(sqlc.narg(domain), domain),
+    updated_at    = now()
+WHERE id = @id
+RETURNING *;
+
+-- name: SetTenantStatus :one
+UPDATE tenants
+SET status            = $2,
+    status_reason     = $3,
+    delete_after      = $4,
+    suspended_by      = $5,
+    status_changed_at = now(),
+    updated_at        = now()
+WHERE id = $1
+RETURNING *;
+
+-- name: ListTenantsDueForDeletion :many
+SELECT id
+FROM tenants
+WHERE delete_after <= now()
+ORDER BY delete_after
+LIMIT $1 FOR UPDATE SKIP LOCKED;
+
+-- name: DeleteTenantTasks :exec
+DELETE
+FROM tasks
+WHERE run_id IN (SELECT id FROM workflow_runs WHERE tenant_id = $1);
+
+-- name: DeleteTenantRuns :exec
+DELETE
+FROM workflow_runs
+WHERE tenant_id = $1;
+
+-- name: DeleteTenantWorkflows :exec
+DELETE
+FROM workflows
+WHERE tenant_id = $1;
+
+-- name: DeleteTenantUsageRecords :exec
+DELETE
+FROM usage_records
+WHERE tenant_id = $1;
+
+-- name: DeleteTenantUsageRollups :exec
+DELETE
+FROM usage_rollups
+WHERE tenant_id = $1;
+
+-- name: DeleteTenantEvents :exec
+DELETE
+FROM events
+WHERE tenant_id = $1;
+
+-- name: DetachChildTenants :exec
+UPDATE tenants
+SET parent_id = NULL
+WHERE parent_id = $1;
+
+-- name: DeleteTenant :exec
+DELETE
+FROM tenants
+WHERE id = $1;
+
+-- name: GetEffectiveTenantRole :one
+-- Returns the highest role the user holds in the tenant or any of its
+-- ancestors.
+WITH RECURSIVE ancestors AS (SELECT tenants.id, tenants.parent_id
+                             FROM tenants
+                             WHERE tenants.id = @tenant_id
+                             UNION ALL
+                             SELECT t.id, t.parent_id
+                             FROM tenants t
+                                      JOIN ancestors a ON t.id = a.parent_id)
+SELECT tm.role
+FROM ancestors a
+         JOIN tenant_members tm ON tm.tenant_id = a.id
+WHERE tm.user_id = @user_id
+ORDER BY CASE tm.role
+             WHEN 'owner' THEN 4
+             WHEN 'admin' THEN 3
+             WHEN 'member' THEN 2
+             WHEN 'viewer' THEN 1
+             ELSE 0 END DESC
+LIMIT 1;
+
+-- name: GetTenantDepth :one
+WITH RECURSIVE ancestors AS (SELECT tenants.id, tenants.parent_id, 1 AS depth
+                             FROM tenants
+                             WHERE tenants.id = $1
+                             UNION ALL
+                             SELECT t.id, t.parent_id, a.depth + 1
+                             FROM tenants t
+                                      JOIN ancestors a ON t.id = a.parent_id)
+SELECT max(depth)::int
+FROM ancestors;
+
+-- name: ListTenantSubtree :many
+WITH RECURSIVE subtree AS (SELECT t.*, 0 AS depth
+                           FROM tenants t
+                           WHERE t.id = $1
+                           UNION ALL
+                           SELECT c.*, s.depth + 1
+                           FROM tenants c
+                                    JOIN subtree s ON c.parent_id = s.id)
+SELECT *
+FROM subtree
+ORDER BY depth, name, id;
+
+-- name: CountChildTenants :one
+SELECT count(*)
+FROM tenants
+WHERE parent_id = $1;
+
+-- name: AppendEvent :one
+-- Appends to one stream at a time, so that ids grow in commit order within
+-- the tenant or user stream a subscriber follows.
+WITH ordering_lock AS (SELECT pg_advisory_xact_lock(hashtext('events:' || sqlc.narg(tenant_id)::uuid::text)))
+INSERT
+INTO events (tenant_id, event_type, aggregate_id, payload)
+SELECT sqlc.narg(tenant_id)::uuid, @event_type::text, @aggregate_id::uuid, @payload::jsonb
+FROM ordering_lock
+RETURNING id;
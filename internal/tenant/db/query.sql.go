@@ -11,6 +11,23 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const acceptInvitation = `-- name: AcceptInvitation :exec
+UPDATE tenant_invitations
+SET accepted_at = now(),
+    accepted_by = $2
+WHERE id = $1
+`
+
+type AcceptInvitationParams struct {
+	ID         pgtype.UUID `db:"id" json:"id"`
+	AcceptedBy pgtype.UUID `db:"accepted_by" json:"accepted_by"`
+}
+
+func (q *Queries) AcceptInvitation(ctx context.Context, arg AcceptInvitationParams) error {
+	_, err := q.db.Exec(ctx, acceptInvitation, arg.ID, arg.AcceptedBy)
+	return err
+}
+
 const addTenantMember = `-- name: AddTenantMember :one
 INSERT INTO tenant_members (tenant_id, user_id, role)
 VALUES ($1, $2, $3)
@@ -35,10 +52,94 @@ func (q *Queries) AddTenantMember(ctx context.Context, arg AddTenantMemberParams
 	return i, err
 }
 
+const appendEvent = `-- name: AppendEvent :one
+WITH ordering_lock AS (SELECT pg_advisory_xact_lock(hashtext('events:' || $1::uuid::text)))
+INSERT
+INTO events (tenant_id, event_type, aggregate_id, payload)
+SELECT $1::uuid, $2::text, $3::uuid, $4::jsonb
+FROM ordering_lock
+RETURNING id
+`
+
+type AppendEventParams struct {
+	TenantID    pgtype.UUID `db:"tenant_id" json:"tenant_id"`
+	EventType   string      `db:"event_type" json:"event_type"`
+	AggregateID pgtype.UUID `db:"aggregate_id" json:"aggregate_id"`
+	Payload     []byte      `db:"payload" json:"payload"`
+}
+
+// Appends to one stream at a time, so that ids grow in commit order within
+// the tenant or user stream a subscriber follows.
+func (q *Queries) AppendEvent(ctx context.Context, arg AppendEventParams) (int64, error) {
+	row := q.db.QueryRow(ctx, appendEvent,
+		arg.TenantID,
+		arg.EventType,
+		arg.AggregateID,
+		arg.Payload,
+	)
+	var id int64
+	err := row.Scan(&id)
+	return id, err
+}
+
+const countChildTenants = `-- name: CountChildTenants :one
+SELECT count(*)
+FROM tenants
+WHERE parent_id = $1
+`
+
+func (q *Queries) CountChildTenants(ctx context.Context, parentID pgtype.UUID) (int64, error) {
+	row := q.db.QueryRow(ctx, countChildTenants, parentID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const createInvitation = `-- name: CreateInvitation :one
+INSERT INTO tenant_invitations (tenant_id, email, role, token_hash, invited_by, expires_at)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING id, tenant_id, email, role, token_hash, invited_by, created_at, expires_at, accepted_at, accepted_by, revoked_at
+`
+
+type CreateInvitationParams struct {
+	TenantID  pgtype.UUID        `db:"tenant_id" json:"tenant_id"`
+	Email     string             `db:"email" json:"email"`
+	Role      string             `db:"role" json:"role"`
+	TokenHash string             `db:"token_hash" json:"token_hash"`
+	InvitedBy pgtype.UUID        `db:"invited_by" json:"invited_by"`
+	ExpiresAt pgtype.Timestamptz `db:"expires_at" json:"expires_at"`
+}
+
+func (q *Queries) CreateInvitation(ctx context.Context, arg CreateInvitationParams) (TenantInvitation, error) {
+	row := q.db.QueryRow(ctx, createInvitation,
+		arg.TenantID,
+		arg.Email,
+		arg.Role,
+		arg.TokenHash,
+		arg.InvitedBy,
+		arg.ExpiresAt,
+	)
+	var i TenantInvitation
+	err := row.Scan(
+		&i.ID,
+		&i.TenantID,
+		&i.Email,
+		&i.Role,
+		&i.TokenHash,
+		&i.InvitedBy,
+		&i.CreatedAt,
+		&i.ExpiresAt,
+		&i.AcceptedAt,
+		&i.AcceptedBy,
+		&i.RevokedAt,
+	)
+	return i, err
+}
+
 const createTenant = `-- name: CreateTenant :one
-INSERT INTO tenants (name, slug, contact_email, tier, region, status)
-VALUES ($1, $2, $3, $4, $5, 'active')
-RETURNING id, name, created_at, parent_id, slug, domain, status, region, tier, settings, contact_email, updated_at
+INSERT INTO tenants (name, slug, contact_email, tier, region, parent_id, status)
+VALUES ($1, $2, $3, $4, $5, $6, 'active')
+RETURNING id, name, created_at, parent_id, slug, domain, status, region, tier, settings, contact_email, updated_at, status_reason, status_changed_at, delete_after, suspended_by
 `
 
 type CreateTenantParams struct {
@@ -47,6 +148,7 @@ type CreateTenantParams struct {
 	ContactEmail pgtype.Text `db:"contact_email" json:"contact_email"`
 	Tier         pgtype.Text `db:"tier" json:"tier"`
 	Region       pgtype.Text `db:"region" json:"region"`
+	ParentID     pgtype.UUID `db:"parent_id" json:"parent_id"`
 }
 
 func (q *Queries) CreateTenant(ctx context.Context, arg CreateTenantParams) (Tenant, error) {
@@ -56,7 +158,204 @@ func (q *Queries) CreateTenant(ctx context.Context, arg CreateTenantParams) (Ten
 		arg.ContactEmail,
 		arg.Tier,
 		arg.Region,
+		arg.ParentID,
+	)
+	var i Tenant
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.CreatedAt,
+		&i.ParentID,
+		&i.Slug,
+		&i.Domain,
+		&i.Status,
+		&i.Region,
+		&i.Tier,
+		&i.Settings,
+		&i.ContactEmail,
+		&i.UpdatedAt,
+		&i.StatusReason,
+		&i.StatusChangedAt,
+		&i.DeleteAfter,
+		&i.SuspendedBy,
+	)
+	return i, err
+}
+
+const deleteTenant = `-- name: DeleteTenant :exec
+DELETE
+FROM tenants
+WHERE id = $1
+`
+
+func (q *Queries) DeleteTenant(ctx context.Context, id pgtype.UUID) error {
+	_, err := q.db.Exec(ctx, deleteTenant, id)
+	return err
+}
+
+const deleteTenantEvents = `-- name: DeleteTenantEvents :exec
+DELETE
+FROM events
+WHERE tenant_id = $1
+`
+
+func (q *Queries) DeleteTenantEvents(ctx context.Context, tenantID pgtype.UUID) error {
+	_, err := q.db.Exec(ctx, deleteTenantEvents, tenantID)
+	return err
+}
+
+const deleteTenantRuns = `-- name: DeleteTenantRuns :exec
+DELETE
+FROM workflow_runs
+WHERE tenant_id = $1
+`
+
+func (q *Queries) DeleteTenantRuns(ctx context.Context, tenantID pgtype.UUID) error {
+	_, err := q.db.Exec(ctx, deleteTenantRuns, tenantID)
+	return err
+}
+
+const deleteTenantTasks = `-- name: DeleteTenantTasks :exec
+DELETE
+FROM tasks
+WHERE run_id IN (SELECT id FROM workflow_runs WHERE tenant_id = $1)
+`
+
+func (q *Queries) DeleteTenantTasks(ctx context.Context, tenantID pgtype.UUID) error {
+	_, err := q.db.Exec(ctx, deleteTenantTasks, tenantID)
+	return err
+}
+
+const deleteTenantUsageRecords = `-- name: DeleteTenantUsageRecords :exec
+DELETE
+FROM usage_records
+WHERE tenant_id = $1
+`
+
+func (q *Queries) DeleteTenantUsageRecords(ctx context.Context, tenantID pgtype.UUID) error {
+	_, err := q.db.Exec(ctx, deleteTenantUsageRecords, tenantID)
+	return err
+}
+
+const deleteTenantUsageRollups = `-- name: DeleteTenantUsageRollups :exec
+DELETE
+FROM usage_rollups
+WHERE tenant_id = $1
+`
+
+func (q *Queries) DeleteTenantUsageRollups(ctx context.Context, tenantID pgtype.UUID) error {
+	_, err := q.db.Exec(ctx, deleteTenantUsageRollups, tenantID)
+	return err
+}
+
+const deleteTenantWorkflows = `-- name: DeleteTenantWorkflows :exec
+DELETE
+FROM workflows
+WHERE tenant_id = $1
+`
+
+func (q *Queries) DeleteTenantWorkflows(ctx context.Context, tenantID pgtype.UUID) error {
+	_, err := q.db.Exec(ctx, deleteTenantWorkflows, tenantID)
+	return err
+}
+
+const detachChildTenants = `-- name: DetachChildTenants :exec
+UPDATE tenants
+SET parent_id = NULL
+WHERE parent_id = $1
+`
+
+func (q *Queries) DetachChildTenants(ctx context.Context, parentID pgtype.UUID) error {
+	_, err := q.db.Exec(ctx, detachChildTenants, parentID)
+	return err
+}
+
+const getEffectiveTenantRole = `-- name: GetEffectiveTenantRole :one
+WITH RECURSIVE ancestors AS (SELECT tenants.id, tenants.parent_id
+                             FROM tenants
+                             WHERE tenants.id = $2
+                             UNION ALL
+                             SELECT t.id, t.parent_id
+                             FROM tenants t
+                                      JOIN ancestors a ON t.id = a.parent_id)
+SELECT tm.role
+FROM ancestors a
+         JOIN tenant_members tm ON tm.tenant_id = a.id
+WHERE tm.user_id = $1
+ORDER BY CASE tm.role
+             WHEN 'owner' THEN 4
+             WHEN 'admin' THEN 3
+             WHEN 'member' THEN 2
+             WHEN 'viewer' THEN 1
+             ELSE 0 END DESC
+LIMIT 1
+`
+
+type GetEffectiveTenantRoleParams struct {
+	UserID   pgtype.UUID `db:"user_id" json:"user_id"`
+	TenantID pgtype.UUID `db:"tenant_id" json:"tenant_id"`
+}
+
+// Returns the highest role the user holds in the tenant or any of its
+// ancestors.
+func (q *Queries) GetEffectiveTenantRole(ctx context.Context, arg GetEffectiveTenantRoleParams) (pgtype.Text, error) {
+	row := q.db.QueryRow(ctx, getEffectiveTenantRole, arg.UserID, arg.TenantID)
+	var role pgtype.Text
+	err := row.Scan(&role)
+	return role, err
+}
+
+const getInvitationForUpdate = `-- name: GetInvitationForUpdate :one
+SELECT i.id, i.tenant_id, i.email, i.role, i.token_hash, i.invited_by, i.created_at, i.expires_at, i.accepted_at, i.accepted_by, i.revoked_at, t.name AS tenant_name
+FROM tenant_invitations i
+         JOIN tenants t ON t.id = i.tenant_id
+WHERE i.token_hash = $1
+    FOR UPDATE OF i
+`
+
+type GetInvitationForUpdateRow struct {
+	ID         pgtype.UUID        `db:"id" json:"id"`
+	TenantID   pgtype.UUID        `db:"tenant_id" json:"tenant_id"`
+	Email      string             `db:"email" json:"email"`
+	Role       string             `db:"role" json:"role"`
+	TokenHash  string             `db:"token_hash" json:"token_hash"`
+	InvitedBy  pgtype.UUID        `db:"invited_by" json:"invited_by"`
+	CreatedAt  pgtype.Timestamptz `db:"created_at" json:"created_at"`
+	ExpiresAt  pgtype.Timestamptz `db:"expires_at" json:"expires_at"`
+	AcceptedAt pgtype.Timestamptz `db:"accepted_at" json:"accepted_at"`
+	AcceptedBy pgtype.UUID        `db:"accepted_by" json:"accepted_by"`
+	RevokedAt  pgtype.Timestamptz `db:"revoked_at" json:"revoked_at"`
+	TenantName string             `db:"tenant_name" json:"tenant_name"`
+}
+
+func (q *Queries) GetInvitationForUpdate(ctx context.Context, tokenHash string) (GetInvitationForUpdateRow, error) {
+	row := q.db.QueryRow(ctx, getInvitationForUpdate, tokenHash)
+	var i GetInvitationForUpdateRow
+	err := row.Scan(
+		&i.ID,
+		&i.TenantID,
+		&i.Email,
+		&i.Role,
+		&i.TokenHash,
+		&i.InvitedBy,
+		&i.CreatedAt,
+		&i.ExpiresAt,
+		&i.AcceptedAt,
+		&i.AcceptedBy,
+		&i.RevokedAt,
+		&i.TenantName,
 	)
+	return i, err
+}
+
+const getTenant = `-- name: GetTenant :one
+SELECT id, name, created_at, parent_id, slug, domain, status, region, tier, settings, contact_email, updated_at, status_reason, status_changed_at, delete_after, suspended_by
+FROM tenants
+WHERE id = $1
+`
+
+func (q *Queries) GetTenant(ctx context.Context, id pgtype.UUID) (Tenant, error) {
+	row := q.db.QueryRow(ctx, getTenant, id)
 	var i Tenant
 	err := row.Scan(
 		&i.ID,
@@ -71,6 +370,10 @@ func (q *Queries) CreateTenant(ctx context.Context, arg CreateTenantParams) (Ten
 		&i.Settings,
 		&i.ContactEmail,
 		&i.UpdatedAt,
+		&i.StatusReason,
+		&i.StatusChangedAt,
+		&i.DeleteAfter,
+		&i.SuspendedBy,
 	)
 	return i, err
 }
@@ -87,3 +390,537 @@ func (q *Queries) GetTenantBySlug(ctx context.Context, slug string) (pgtype.UUID
 	err := row.Scan(&id)
 	return id, err
 }
+
+const getTenantDepth = `-- name: GetTenantDepth :one
+WITH RECURSIVE ancestors AS (SELECT tenants.id, tenants.parent_id, 1 AS depth
+                             FROM tenants
+                             WHERE tenants.id = $1
+                             UNION ALL
+                             SELECT t.id, t.parent_id, a.depth + 1
+                             FROM tenants t
+                                      JOIN ancestors a ON t.id = a.parent_id)
+SELECT max(depth)::int
+FROM ancestors
+`
+
+func (q *Queries) GetTenantDepth(ctx context.Context, id pgtype.UUID) (int32, error) {
+	row := q.db.QueryRow(ctx, getTenantDepth, id)
+	var column_1 int32
+	err := row.Scan(&column_1)
+	return column_1, err
+}
+
+const getTenantForUpdate = `-- name: GetTenantForUpdate :one
+SELECT id, name, created_at, parent_id, slug, domain, status, region, tier, settings, contact_email, updated_at, status_reason, status_changed_at, delete_after, suspended_by
+FROM tenants
+WHERE id = $1
+    FOR UPDATE
+`
+
+func (q *Queries) GetTenantForUpdate(ctx context.Context, id pgtype.UUID) (Tenant, error) {
+	row := q.db.QueryRow(ctx, getTenantForUpdate, id)
+	var i Tenant
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.CreatedAt,
+		&i.ParentID,
+		&i.Slug,
+		&i.Domain,
+		&i.Status,
+		&i.Region,
+		&i.Tier,
+		&i.Settings,
+		&i.ContactEmail,
+		&i.UpdatedAt,
+		&i.StatusReason,
+		&i.StatusChangedAt,
+		&i.DeleteAfter,
+		&i.SuspendedBy,
+	)
+	return i, err
+}
+
+const getTenantMember = `-- name: GetTenantMember :one
+SELECT tm.user_id, tm.role, tm.joined_at, u.email, u.full_name
+FROM tenant_members tm
+         JOIN users u ON u.id = tm.user_id
+WHERE tm.tenant_id = $1
+  AND tm.user_id = $2
+`
+
+type GetTenantMemberParams struct {
+	TenantID pgtype.UUID `db:"tenant_id" json:"tenant_id"`
+	UserID   pgtype.UUID `db:"user_id" json:"user_id"`
+}
+
+type GetTenantMemberRow struct {
+	UserID   pgtype.UUID        `db:"user_id" json:"user_id"`
+	Role     pgtype.Text        `db:"role" json:"role"`
+	JoinedAt pgtype.Timestamptz `db:"joined_at" json:"joined_at"`
+	Email    string             `db:"email" json:"email"`
+	FullName pgtype.Text        `db:"full_name" json:"full_name"`
+}
+
+func (q *Queries) GetTenantMember(ctx context.Context, arg GetTenantMemberParams) (GetTenantMemberRow, error) {
+	row := q.db.QueryRow(ctx, getTenantMember, arg.TenantID, arg.UserID)
+	var i GetTenantMemberRow
+	err := row.Scan(
+		&i.UserID,
+		&i.Role,
+		&i.JoinedAt,
+		&i.Email,
+		&i.FullName,
+	)
+	return i, err
+}
+
+const getTenantMemberForUpdate = `-- name: GetTenantMemberForUpdate :one
+SELECT tenant_id, user_id, role, joined_at
+FROM tenant_members
+WHERE tenant_id = $1
+  AND user_id = $2
+    FOR UPDATE
+`
+
+type GetTenantMemberForUpdateParams struct {
+	TenantID pgtype.UUID `db:"tenant_id" json:"tenant_id"`
+	UserID   pgtype.UUID `db:"user_id" json:"user_id"`
+}
+
+func (q *Queries) GetTenantMemberForUpdate(ctx context.Context, arg GetTenantMemberForUpdateParams) (TenantMember, error) {
+	row := q.db.QueryRow(ctx, getTenantMemberForUpdate, arg.TenantID, arg.UserID)
+	var i TenantMember
+	err := row.Scan(
+		&i.TenantID,
+		&i.UserID,
+		&i.Role,
+		&i.JoinedAt,
+	)
+	return i, err
+}
+
+const getTenantName = `-- name: GetTenantName :one
+SELECT name
+FROM tenants
+WHERE id = $1
+`
+
+func (q *Queries) GetTenantName(ctx context.Context, id pgtype.UUID) (string, error) {
+	row := q.db.QueryRow(ctx, getTenantName, id)
+	var name string
+	err := row.Scan(&name)
+	return name, err
+}
+
+const isTenantMemberByEmail = `-- name: IsTenantMemberByEmail :one
+SELECT EXISTS (SELECT 1
+               FROM tenant_members tm
+                        JOIN users u ON u.id = tm.user_id
+               WHERE tm.tenant_id = $1
+                 AND lower(u.email) = lower($2::text))
+`
+
+type IsTenantMemberByEmailParams struct {
+	TenantID pgtype.UUID `db:"tenant_id" json:"tenant_id"`
+	Email    string      `db:"email" json:"email"`
+}
+
+func (q *Queries) IsTenantMemberByEmail(ctx context.Context, arg IsTenantMemberByEmailParams) (bool, error) {
+	row := q.db.QueryRow(ctx, isTenantMemberByEmail, arg.TenantID, arg.Email)
+	var exists bool
+	err := row.Scan(&exists)
+	return exists, err
+}
+
+const listTenantMembers = `-- name: ListTenantMembers :many
+SELECT tm.user_id, tm.role, tm.joined_at, u.email, u.full_name
+FROM tenant_members tm
+         JOIN users u ON u.id = tm.user_id
+WHERE tm.tenant_id = $1
+ORDER BY tm.joined_at, tm.user_id
+`
+
+type ListTenantMembersRow struct {
+	UserID   pgtype.UUID        `db:"user_id" json:"user_id"`
+	Role     pgtype.Text        `db:"role" json:"role"`
+	JoinedAt pgtype.Timestamptz `db:"joined_at" json:"joined_at"`
+	Email    string             `db:"email" json:"email"`
+	FullName pgtype.Text        `db:"full_name" json:"full_name"`
+}
+
+func (q *Queries) ListTenantMembers(ctx context.Context, tenantID pgtype.UUID) ([]ListTenantMembersRow, error) {
+	rows, err := q.db.Query(ctx, listTenantMembers, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListTenantMembersRow
+	for rows.Next() {
+		var i ListTenantMembersRow
+		if err := rows.Scan(
+			&i.UserID,
+			&i.Role,
+			&i.JoinedAt,
+			&i.Email,
+			&i.FullName,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTenantOwnersForUpdate = `-- name: ListTenantOwnersForUpdate :many
+SELECT user_id
+FROM tenant_members
+WHERE tenant_id = $1
+  AND role = 'owner'
+    FOR UPDATE
+`
+
+func (q *Queries) ListTenantOwnersForUpdate(ctx context.Context, tenantID pgtype.UUID) ([]pgtype.UUID, error) {
+	rows, err := q.db.Query(ctx, listTenantOwnersForUpdate, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []pgtype.UUID
+	for rows.Next() {
+		var user_id pgtype.UUID
+		if err := rows.Scan(&user_id); err != nil {
+			return nil, err
+		}
+		items = append(items, user_id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTenantSubtree = `-- name: ListTenantSubtree :many
+WITH RECURSIVE subtree AS (SELECT t.id, t.name, t.created_at, t.parent_id, t.slug, t.domain, t.status, t.region, t.tier, t.settings, t.contact_email, t.updated_at, t.status_reason, t.status_changed_at, t.delete_after, t.suspended_by, 0 AS depth
+                           FROM tenants t
+                           WHERE t.id = $1
+                           UNION ALL
+                           SELECT c.id, c.name, c.created_at, c.parent_id, c.slug, c.domain, c.status, c.region, c.tier, c.settings, c.contact_email, c.updated_at, c.status_reason, c.status_changed_at, c.delete_after, c.suspended_by, s.depth + 1
+                           FROM tenants c
+                                    JOIN subtree s ON c.parent_id = s.id)
+SELECT id, name, created_at, parent_id, slug, domain, status, region, tier, settings, contact_email, updated_at, status_reason, status_changed_at, delete_after, suspended_by, depth
+FROM subtree
+ORDER BY depth, name, id
+`
+
+type ListTenantSubtreeRow struct {
+	ID              pgtype.UUID        `db:"id" json:"id"`
+	Name            string             `db:"name" json:"name"`
+	CreatedAt       pgtype.Timestamptz `db:"created_at" json:"created_at"`
+	ParentID        pgtype.UUID        `db:"parent_id" json:"parent_id"`
+	Slug            string             `db:"slug" json:"slug"`
+	Domain          pgtype.Text        `db:"domain" json:"domain"`
+	Status          NullTenantStatus   `db:"status" json:"status"`
+	Region          pgtype.Text        `db:"region" json:"region"`
+	Tier            pgtype.Text        `db:"tier" json:"tier"`
+	Settings        []byte             `db:"settings" json:"settings"`
+	ContactEmail    pgtype.Text        `db:"contact_email" json:"contact_email"`
+	UpdatedAt       pgtype.Timestamptz `db:"updated_at" json:"updated_at"`
+	StatusReason    pgtype.Text        `db:"status_reason" json:"status_reason"`
+	StatusChangedAt pgtype.Timestamptz `db:"status_changed_at" json:"status_changed_at"`
+	DeleteAfter     pgtype.Timestamptz `db:"delete_after" json:"delete_after"`
+	SuspendedBy     pgtype.Text        `db:"suspended_by" json:"suspended_by"`
+	Depth           int32              `db:"depth" json:"depth"`
+}
+
+func (q *Queries) ListTenantSubtree(ctx context.Context, id pgtype.UUID) ([]ListTenantSubtreeRow, error) {
+	rows, err := q.db.Query(ctx, listTenantSubtree, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListTenantSubtreeRow
+	for rows.Next() {
+		var i ListTenantSubtreeRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.CreatedAt,
+			&i.ParentID,
+			&i.Slug,
+			&i.Domain,
+			&i.Status,
+			&i.Region,
+			&i.Tier,
+			&i.Settings,
+			&i.ContactEmail,
+			&i.UpdatedAt,
+			&i.StatusReason,
+			&i.StatusChangedAt,
+			&i.DeleteAfter,
+			&i.SuspendedBy,
+			&i.Depth,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTenantsByUserID = `-- name: ListTenantsByUserID :many
+SELECT t.id, t.name, t.created_at, t.parent_id, t.slug, t.domain, t.status, t.region, t.tier, t.settings, t.contact_email, t.updated_at, t.status_reason, t.status_changed_at, t.delete_after, t.suspended_by, tm.role AS member_role
+FROM tenant_members tm
+         JOIN tenants t ON t.id = tm.tenant_id
+WHERE tm.user_id = $1
+ORDER BY tm.joined_at, t.id
+`
+
+type ListTenantsByUserIDRow struct {
+	ID              pgtype.UUID        `db:"id" json:"id"`
+	Name            string             `db:"name" json:"name"`
+	CreatedAt       pgtype.Timestamptz `db:"created_at" json:"created_at"`
+	ParentID        pgtype.UUID        `db:"parent_id" json:"parent_id"`
+	Slug            string             `db:"slug" json:"slug"`
+	Domain          pgtype.Text        `db:"domain" json:"domain"`
+	Status          NullTenantStatus   `db:"status" json:"status"`
+	Region          pgtype.Text        `db:"region" json:"region"`
+	Tier            pgtype.Text        `db:"tier" json:"tier"`
+	Settings        []byte             `db:"settings" json:"settings"`
+	ContactEmail    pgtype.Text        `db:"contact_email" json:"contact_email"`
+	UpdatedAt       pgtype.Timestamptz `db:"updated_at" json:"updated_at"`
+	StatusReason    pgtype.Text        `db:"status_reason" json:"status_reason"`
+	StatusChangedAt pgtype.Timestamptz `db:"status_changed_at" json:"status_changed_at"`
+	DeleteAfter     pgtype.Timestamptz `db:"delete_after" json:"delete_after"`
+	SuspendedBy     pgtype.Text        `db:"suspended_by" json:"suspended_by"`
+	MemberRole      pgtype.Text        `db:"member_role" json:"member_role"`
+}
+
+func (q *Queries) ListTenantsByUserID(ctx context.Context, userID pgtype.UUID) ([]ListTenantsByUserIDRow, error) {
+	rows, err := q.db.Query(ctx, listTenantsByUserID, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListTenantsByUserIDRow
+	for rows.Next() {
+		var i ListTenantsByUserIDRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.CreatedAt,
+			&i.ParentID,
+			&i.Slug,
+			&i.Domain,
+			&i.Status,
+			&i.Region,
+			&i.Tier,
+			&i.Settings,
+			&i.ContactEmail,
+			&i.UpdatedAt,
+			&i.StatusReason,
+			&i.StatusChangedAt,
+			&i.DeleteAfter,
+			&i.SuspendedBy,
+			&i.MemberRole,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTenantsDueForDeletion = `-- name: ListTenantsDueForDeletion :many
+SELECT id
+FROM tenants
+WHERE delete_after <= now()
+ORDER BY delete_after
+LIMIT $1 FOR UPDATE SKIP LOCKED
+`
+
+func (q *Queries) ListTenantsDueForDeletion(ctx context.Context, limit int32) ([]pgtype.UUID, error) {
+	rows, err := q.db.Query(ctx, listTenantsDueForDeletion, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []pgtype.UUID
+	for rows.Next() {
+		var id pgtype.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const removeTenantMember = `-- name: RemoveTenantMember :exec
+DELETE
+FROM tenant_members
+WHERE tenant_id = $1
+  AND user_id = $2
+`
+
+type RemoveTenantMemberParams struct {
+	TenantID pgtype.UUID `db:"tenant_id" json:"tenant_id"`
+	UserID   pgtype.UUID `db:"user_id" json:"user_id"`
+}
+
+func (q *Queries) RemoveTenantMember(ctx context.Context, arg RemoveTenantMemberParams) error {
+	_, err := q.db.Exec(ctx, removeTenantMember, arg.TenantID, arg.UserID)
+	return err
+}
+
+const revokePendingInvitations = `-- name: RevokePendingInvitations :exec
+UPDATE tenant_invitations
+SET revoked_at = now()
+WHERE tenant_id = $1
+  AND lower(email) = lower($2::text)
+  AND accepted_at IS NULL
+  AND revoked_at IS NULL
+`
+
+type RevokePendingInvitationsParams struct {
+	TenantID pgtype.UUID `db:"tenant_id" json:"tenant_id"`
+	Email    string      `db:"email" json:"email"`
+}
+
+func (q *Queries) RevokePendingInvitations(ctx context.Context, arg RevokePendingInvitationsParams) error {
+	_, err := q.db.Exec(ctx, revokePendingInvitations, arg.TenantID, arg.Email)
+	return err
+}
+
+const setTenantStatus = `-- name: SetTenantStatus :one
+UPDATE tenants
+SET status            = $2,
+    status_reason     = $3,
+    delete_after      = $4,
+    suspended_by      = $5,
+    status_changed_at = now(),
+    updated_at        = now()
+WHERE id = $1
+RETURNING id, name, created_at, parent_id, slug, domain, status, region, tier, settings, contact_email, updated_at, status_reason, status_changed_at, delete_after, suspended_by
+`
+
+type SetTenantStatusParams struct {
+	ID           pgtype.UUID        `db:"id" json:"id"`
+	Status       NullTenantStatus   `db:"status" json:"status"`
+	StatusReason pgtype.Text        `db:"status_reason" json:"status_reason"`
+	DeleteAfter  pgtype.Timestamptz `db:"delete_after" json:"delete_after"`
+	SuspendedBy  pgtype.Text        `db:"suspended_by" json:"suspended_by"`
+}
+
+func (q *Queries) SetTenantStatus(ctx context.Context, arg SetTenantStatusParams) (Tenant, error) {
+	row := q.db.QueryRow(ctx, setTenantStatus,
+		arg.ID,
+		arg.Status,
+		arg.StatusReason,
+		arg.DeleteAfter,
+		arg.SuspendedBy,
+	)
+	var i Tenant
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.CreatedAt,
+		&i.ParentID,
+		&i.Slug,
+		&i.Domain,
+		&i.Status,
+		&i.Region,
+		&i.Tier,
+		&i.Settings,
+		&i.ContactEmail,
+		&i.UpdatedAt,
+		&i.StatusReason,
+		&i.StatusChangedAt,
+		&i.DeleteAfter,
+		&i.SuspendedBy,
+	)
+	return i, err
+}
+
+const updateTenant = `-- name: UpdateTenant :one
+UPDATE tenants
+SET name          = coalesce($1, name),
+    -- quota overrides are managed by the platform and survive replacing the settings
+    settings      = CASE
+                        WHEN $2::jsonb IS NULL THEN settings
+                        ELSE $2::jsonb ||
+                             jsonb_strip_nulls(jsonb_build_object('quotas', settings -> 'quotas')) END,
+    contact_email = coalesce($3, contact_email),
+    domain        = coalesce($4, domain),
+    updated_at    = now()
+WHERE id = $5
+RETURNING id, name, created_at, parent_id, slug, domain, status, region, tier, settings, contact_email, updated_at, status_reason, status_changed_at, delete_after, suspended_by
+`
+
+type UpdateTenantParams struct {
+	Name         pgtype.Text `db:"name" json:"name"`
+	Settings     []byte      `db:"settings" json:"settings"`
+	ContactEmail pgtype.Text `db:"contact_email" json:"contact_email"`
+	Domain       pgtype.Text `db:"domain" json:"domain"`
+	ID           pgtype.UUID `db:"id" json:"id"`
+}
+
+func (q *Queries) UpdateTenant(ctx context.Context, arg UpdateTenantParams) (Tenant, error) {
+	row := q.db.QueryRow(ctx, updateTenant,
+		arg.Name,
+		arg.Settings,
+		arg.ContactEmail,
+		arg.Domain,
+		arg.ID,
+	)
+	var i Tenant
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.CreatedAt,
+		&i.ParentID,
+		&i.Slug,
+		&i.Domain,
+		&i.Status,
+		&i.Region,
+		&i.Tier,
+		&i.Settings,
+		&i.ContactEmail,
+		&i.UpdatedAt,
+		&i.StatusReason,
+		&i.StatusChangedAt,
+		&i.DeleteAfter,
+		&i.SuspendedBy,
+	)
+	return i, err
+}
+
+const updateTenantMemberRole = `-- name: UpdateTenantMemberRole :exec
+UPDATE tenant_members
+SET role = $3
+WHERE tenant_id = $1
+  AND user_id = $2
+`
+
+type UpdateTenantMemberRoleParams struct {
+	TenantID pgtype.UUID `db:"tenant_id" json:"tenant_id"`
+	UserID   pgtype.UUID `db:"user_id" json:"user_id"`
+	Role     pgtype.Text `db:"role" json:"role"`
+}
+
+func (q *Queries) UpdateTenantMemberRole(ctx context.Context, arg UpdateTenantMemberRoleParams) error {
+	_, err := q.db.Exec(ctx, updateTenantMemberRole, arg.TenantID, arg.UserID, arg.Role)
+	return err
+}
@@ -0,0 +1,424 @@
+package tenant
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	tenantv1 "github.com/vantutran2k1/rwe/gen/go/tenant/v1"
+	"github.com/vantutran2k1/rwe/internal/auth"
+	"github.com/vantutran2k1/rwe/internal/common/events"
+	"github.com/vantutran2k1/rwe/internal/common/token"
+	"github.com/vantutran2k1/rwe/internal/common/utils"
+	sqlc "github.com/vantutran2k1/rwe/internal/tenant/db"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func (s *Service) GetTenant(ctx context.Context, req *tenantv1.GetTenantRequest) (*tenantv1.GetTenantResponse, error) {
+	tenantID, err := tenantFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tenant, err := s.querier.GetTenant(ctx, tenantID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, status.Error(codes.NotFound, "tenant not found")
+		}
+
+		return nil, status.Errorf(codes.Internal, "error getting tenant: %v", err)
+	}
+
+	t, err := toTenant(tenant)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "error converting tenant: %v", err)
+	}
+
+	return &tenantv1.GetTenantResponse{Tenant: t}, nil
+}
+
+func (s *Service) ListMyTenants(ctx context.Context, req *tenantv1.ListMyTenantsRequest) (*tenantv1.ListMyTenantsResponse, error) {
+	tokenPayload := token.GetTokenPayload(ctx)
+	if tokenPayload == nil {
+		return nil, status.Error(codes.Unauthenticated, "missing user authentication")
+	}
+
+	rows, err := s.querier.ListTenantsByUserID(ctx, utils.UUIDToPgUUID(tokenPayload.UserID))
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "error listing tenants: %v", err)
+	}
+
+	memberships := make([]*tenantv1.ListMyTenantsResponse_Membership, 0, len(rows))
+	for _, r := range rows {
+		t, err := toTenant(sqlc.Tenant{
+			ID:              r.ID,
+			Name:            r.Name,
+			CreatedAt:       r.CreatedAt,
+			ParentID:        r.ParentID,
+			Slug:            r.Slug,
+			Domain:          r.Domain,
+			Status:          r.Status,
+			Region:          r.Region,
+			Tier:            r.Tier,
+			Settings:        r.Settings,
+			ContactEmail:    r.ContactEmail,
+			UpdatedAt:       r.UpdatedAt,
+			StatusReason:    r.StatusReason,
+			StatusChangedAt: r.StatusChangedAt,
+			DeleteAfter:     r.DeleteAfter,
+			SuspendedBy:     r.SuspendedBy,
+		})
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "error converting tenant: %v", err)
+		}
+
+		memberships = append(memberships, &tenantv1.ListMyTenantsResponse_Membership{
+			Tenant: t,
+			Role:   r.MemberRole.String,
+		})
+	}
+
+	return &tenantv1.ListMyTenantsResponse{Tenants: memberships}, nil
+}
+
+func (s *Service) ListTenantTree(ctx context.Context, req *tenantv1.ListTenantTreeRequest) (*tenantv1.ListTenantTreeResponse, error) {
+	tenantID, err := tenantFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.querier.ListTenantSubtree(ctx, tenantID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "error listing tenant tree: %v", err)
+	}
+	if len(rows) == 0 {
+		return nil, status.Error(codes.NotFound, "tenant not found")
+	}
+
+	// Rows are ordered by depth, so every parent has a node before its
+	// children are visited.
+	nodes := make(map[pgtype.UUID]*tenantv1.TenantNode, len(rows))
+	var root *tenantv1.TenantNode
+	for _, r := range rows {
+		t, err := toTenant(sqlc.Tenant{
+			ID:              r.ID,
+			Name:            r.Name,
+			CreatedAt:       r.CreatedAt,
+			ParentID:        r.ParentID,
+			Slug:            r.Slug,
+			Domain:          r.Domain,
+			Status:          r.Status,
+			Region:          r.Region,
+			Tier:            r.Tier,
+			Settings:        r.Settings,
+			ContactEmail:    r.ContactEmail,
+			UpdatedAt:       r.UpdatedAt,
+			StatusReason:    r.StatusReason,
+			StatusChangedAt: r.StatusChangedAt,
+			DeleteAfter:     r.DeleteAfter,
+			SuspendedBy:     r.SuspendedBy,
+		})
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "error converting tenant: %v", err)
+		}
+
+		node := &tenantv1.TenantNode{Tenant: t}
+		nodes[r.ID] = node
+		if r.Depth == 0 {
+			root = node
+			continue
+		}
+		if parent, ok := nodes[r.ParentID]; ok {
+			parent.Children = append(parent.Children, node)
+		}
+	}
+
+	return &tenantv1.ListTenantTreeResponse{Root: root}, nil
+}
+
+func (s *Service) UpdateTenant(ctx context.Context, req *tenantv1.UpdateTenantRequest) (*tenantv1.UpdateTenantResponse, error) {
+	tenantID, err := tenantFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	params := sqlc.UpdateTenantParams{ID: tenantID}
+	changes := map[string]any{}
+
+	if name := strings.TrimSpace(req.Name); name != "" {
+		params.Name = utils.StringToPgText(name)
+		changes["name"] = name
+	}
+
+	if req.Settings != nil {
+		if _, ok := req.Settings.Fields[quotasSettingsKey]; ok {
+			return nil, status.Error(codes.InvalidArgument, "quotas cannot be changed through tenant settings")
+		}
+
+		params.Settings, err = protojson.Marshal(req.Settings)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid settings: %v", err)
+		}
+		changes["settings"] = req.Settings.AsMap()
+	}
+
+	if email := strings.ToLower(strings.TrimSpace(req.ContactEmail)); email != "" {
+		if err := auth.ValidateEmail(email); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid contact email: %v", err)
+		}
+		params.ContactEmail = utils.StringToPgText(email)
+		changes["contact_email"] = email
+	}
+
+	if domain := strings.ToLower(strings.TrimSpace(req.Domain)); domain != "" {
+		if strings.ContainsAny(domain, " /@") || !strings.Contains(domain, ".") {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid domain: %s", req.Domain)
+		}
+		params.Domain = utils.StringToPgText(domain)
+		changes["domain"] = domain
+	}
+
+	if len(changes) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "no fields to update")
+	}
+
+	var reqErr error
+	var tenant sqlc.Tenant
+	if err := s.execTx(ctx, func(querier sqlc.Querier) error {
+		tenant, err = querier.UpdateTenant(ctx, params)
+		if err != nil {
+			var pgErr *pgconn.PgError
+			if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+				reqErr = status.Errorf(codes.AlreadyExists, "domain %s is already in use", params.Domain.String)
+			}
+			return err
+		}
+
+		return appendEvent(ctx, querier, tenantID, tenantID, events.TenantUpdated, changes)
+	}); err != nil {
+		if reqErr != nil {
+			return nil, reqErr
+		}
+
+		return nil, status.Errorf(codes.Internal, "error updating tenant: %v", err)
+	}
+
+	t, err := toTenant(tenant)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "error converting tenant: %v", err)
+	}
+
+	return &tenantv1.UpdateTenantResponse{Tenant: t}, nil
+}
+
+func (s *Service) SuspendTenant(ctx context.Context, req *tenantv1.SuspendTenantRequest) (*tenantv1.SuspendTenantResponse, error) {
+	tenant, err := s.transitionTenant(ctx, sqlc.TenantStatusSuspended, req.Reason, pgtype.Timestamptz{}, events.TenantSuspended,
+		sqlc.TenantStatusActive)
+	if err != nil {
+		return nil, err
+	}
+
+	return &tenantv1.SuspendTenantResponse{Tenant: tenant}, nil
+}
+
+func (s *Service) ReactivateTenant(ctx context.Context, req *tenantv1.ReactivateTenantRequest) (*tenantv1.ReactivateTenantResponse, error) {
+	tenant, err := s.transitionTenant(ctx, sqlc.TenantStatusActive, "", pgtype.Timestamptz{}, events.TenantReactivated,
+		sqlc.TenantStatusSuspended, sqlc.TenantStatusArchived)
+	if err != nil {
+		return nil, err
+	}
+
+	return &tenantv1.ReactivateTenantResponse{Tenant: tenant}, nil
+}
+
+func (s *Service) ArchiveTenant(ctx context.Context, req *tenantv1.ArchiveTenantRequest) (*tenantv1.ArchiveTenantResponse, error) {
+	tenant, err := s.transitionTenant(ctx, sqlc.TenantStatusArchived, req.Reason, pgtype.Timestamptz{}, events.TenantArchived,
+		sqlc.TenantStatusActive, sqlc.TenantStatusSuspended)
+	if err != nil {
+		return nil, err
+	}
+
+	return &tenantv1.ArchiveTenantResponse{Tenant: tenant}, nil
+}
+
+// DeleteTenant archives the tenant and schedules it for permanent deletion
+// once the grace period has passed. Reactivating the tenant before then
+// cancels the deletion.
+func (s *Service) DeleteTenant(ctx context.Context, req *tenantv1.DeleteTenantRequest) (*tenantv1.DeleteTenantResponse, error) {
+	tenantID, err := tenantFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	current, err := s.querier.GetTenant(ctx, tenantID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "error getting tenant: %v", err)
+	}
+	if req.ConfirmSlug != current.Slug {
+		return nil, status.Error(codes.InvalidArgument, "confirm_slug must match the tenant slug")
+	}
+	if current.DeleteAfter.Valid {
+		return &tenantv1.DeleteTenantResponse{DeleteAfter: timestamppb.New(current.DeleteAfter.Time)}, nil
+	}
+
+	children, err := s.querier.CountChildTenants(ctx, tenantID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "error counting child tenants: %v", err)
+	}
+	if children > 0 {
+		return nil, status.Errorf(codes.FailedPrecondition, "tenant has %d child tenants that must be deleted first", children)
+	}
+
+	deleteAfter := utils.TimeToPgTimestamptz(time.Now().Add(s.deletionGrace))
+	tenant, err := s.transitionTenant(ctx, sqlc.TenantStatusArchived, "deletion requested", deleteAfter, events.TenantDeletionScheduled,
+		sqlc.TenantStatusActive, sqlc.TenantStatusSuspended, sqlc.TenantStatusArchived, sqlc.TenantStatusPending)
+	if err != nil {
+		return nil, err
+	}
+
+	return &tenantv1.DeleteTenantResponse{DeleteAfter: tenant.DeleteAfter}, nil
+}
+
+func (s *Service) transitionTenant(ctx context.Context, to sqlc.TenantStatus, reason string, deleteAfter pgtype.Timestamptz, eventType string, from ...sqlc.TenantStatus) (*tenantv1.Tenant, error) {
+	tenantID, err := tenantFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var reqErr error
+	var tenant sqlc.Tenant
+	if err := s.execTx(ctx, func(querier sqlc.Querier) error {
+		current, err := querier.GetTenantForUpdate(ctx, tenantID)
+		if err != nil {
+			return err
+		}
+
+		currentStatus := tenantStatus(current.Status)
+		allowed := false
+		for _, st := range from {
+			if currentStatus == st {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			reqErr = status.Errorf(codes.FailedPrecondition, "tenant is %s and cannot become %s", currentStatus, to)
+			return reqErr
+		}
+
+		// A platform suspension outlives archiving and deletion requests,
+		// so that neither becomes a way around it.
+		suspendedBy := current.SuspendedBy
+		switch to {
+		case sqlc.TenantStatusSuspended:
+			suspendedBy = utils.StringToPgText(suspendedByTenant)
+		case sqlc.TenantStatusActive:
+			if suspendedBy.String == suspendedByPlatform {
+				reqErr = status.Error(codes.PermissionDenied, "tenant was suspended by the platform and can only be reactivated by it")
+				return reqErr
+			}
+			suspendedBy = pgtype.Text{}
+		}
+
+		var statusReason pgtype.Text
+		if reason != "" {
+			statusReason = utils.StringToPgText(reason)
+		}
+
+		tenant, err = querier.SetTenantStatus(ctx, sqlc.SetTenantStatusParams{
+			ID:           tenantID,
+			Status:       sqlc.NullTenantStatus{TenantStatus: to, Valid: true},
+			StatusReason: statusReason,
+			DeleteAfter:  deleteAfter,
+			SuspendedBy:  suspendedBy,
+		})
+		if err != nil {
+			return err
+		}
+
+		payload := map[string]any{
+			"previous_status": string(currentStatus),
+			"status":          string(to),
+			"reason":          reason,
+		}
+		if tokenPayload := token.GetTokenPayload(ctx); tokenPayload != nil {
+			payload["changed_by"] = tokenPayload.UserID.String()
+		}
+		if deleteAfter.Valid {
+			payload["delete_after"] = deleteAfter.Time
+		}
+
+		return appendEvent(ctx, querier, tenantID, tenantID, eventType, payload)
+	}); err != nil {
+		if reqErr != nil {
+			return nil, reqErr
+		}
+
+		return nil, status.Errorf(codes.Internal, "error updating tenant status: %v", err)
+	}
+
+	t, err := toTenant(tenant)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "error converting tenant: %v", err)
+	}
+
+	return t, nil
+}
+
+func tenantStatus(status sqlc.NullTenantStatus) sqlc.TenantStatus {
+	if !status.Valid {
+		return sqlc.TenantStatusActive
+	}
+
+	return status.TenantStatus
+}
+
+func toTenant(row sqlc.Tenant) (*tenantv1.Tenant, error) {
+	var settings *structpb.Struct
+	if len(row.Settings) > 0 {
+		settings = &structpb.Struct{}
+		if err := protojson.Unmarshal(row.Settings, settings); err != nil {
+			return nil, err
+		}
+	}
+
+	var parentID string
+	if row.ParentID.Valid {
+		parentID = utils.PgUUIDToString(row.ParentID)
+	}
+
+	return &tenantv1.Tenant{
+		Id:              utils.PgUUIDToString(row.ID),
+		ParentId:        parentID,
+		Name:            row.Name,
+		Slug:            row.Slug,
+		Domain:          row.Domain.String,
+		Status:          string(tenantStatus(row.Status)),
+		Region:          row.Region.String,
+		Tier:            row.Tier.String,
+		Settings:        settings,
+		ContactEmail:    row.ContactEmail.String,
+		CreatedAt:       toTimestamp(row.CreatedAt),
+		UpdatedAt:       toTimestamp(row.UpdatedAt),
+		StatusReason:    row.StatusReason.String,
+		StatusChangedAt: toTimestamp(row.StatusChangedAt),
+		DeleteAfter:     toTimestamp(row.DeleteAfter),
+		SuspendedBy:     row.SuspendedBy.String,
+	}, nil
+}
+
+func toTimestamp(t pgtype.Timestamptz) *timestamppb.Timestamp {
+	if !t.Valid {
+		return nil
+	}
+
+	return timestamppb.New(t.Time)
+}
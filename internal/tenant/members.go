@@ -0,0 +1,430 @@
+package tenant
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	tenantv1 "github.com/vantutran2k1/rwe/gen/go/tenant/v1"
+	"github.com/vantutran2k1/rwe/internal/auth"
+	"github.com/vantutran2k1/rwe/internal/common/events"
+	"github.com/vantutran2k1/rwe/internal/common/token"
+	"github.com/vantutran2k1/rwe/internal/common/utils"
+	"github.com/vantutran2k1/rwe/internal/mailer"
+	sqlc "github.com/vantutran2k1/rwe/internal/tenant/db"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const invitationDuration = 7 * 24 * time.Hour
+
+var errLastOwner = status.Error(codes.FailedPrecondition, "tenant must keep at least one owner")
+
+func (s *Service) InviteMember(ctx context.Context, req *tenantv1.InviteMemberRequest) (*tenantv1.InviteMemberResponse, error) {
+	scope := token.GetTenantScope(ctx)
+	if scope == nil {
+		return nil, status.Error(codes.PermissionDenied, "missing tenant scope")
+	}
+	tenantID := utils.UUIDToPgUUID(scope.TenantID)
+
+	email := strings.ToLower(strings.TrimSpace(req.Email))
+	if err := auth.ValidateEmail(email); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	role := req.Role
+	if role == "" {
+		role = string(memberRoleMember)
+	}
+	if !isValidMemberRole(role) {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid role: %s", role)
+	}
+	if auth.ParseRole(role) > auth.ParseRole(scope.Role) {
+		return nil, status.Errorf(codes.PermissionDenied, "cannot invite a member with role %s above your own role %s", role, scope.Role)
+	}
+
+	rawToken, hashedToken, err := auth.GenerateInvitationToken()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "error generating invitation token: %v", err)
+	}
+
+	var invitedBy pgtype.UUID
+	if tokenPayload := token.GetTokenPayload(ctx); tokenPayload != nil {
+		invitedBy = utils.UUIDToPgUUID(tokenPayload.UserID)
+	}
+
+	var reqErr error
+	var invitation sqlc.TenantInvitation
+	var tenantName string
+	if err := s.execTx(ctx, func(querier sqlc.Querier) error {
+		isMember, err := querier.IsTenantMemberByEmail(ctx, sqlc.IsTenantMemberByEmailParams{
+			TenantID: tenantID,
+			Email:    email,
+		})
+		if err != nil {
+			return err
+		}
+		if isMember {
+			reqErr = status.Errorf(codes.AlreadyExists, "%s is already a member", email)
+			return reqErr
+		}
+
+		tenantName, err = querier.GetTenantName(ctx, tenantID)
+		if err != nil {
+			return err
+		}
+
+		// A new invitation replaces any that are still pending for the email.
+		if err := querier.RevokePendingInvitations(ctx, sqlc.RevokePendingInvitationsParams{
+			TenantID: tenantID,
+			Email:    email,
+		}); err != nil {
+			return err
+		}
+
+		invitation, err = querier.CreateInvitation(ctx, sqlc.CreateInvitationParams{
+			TenantID:  tenantID,
+			Email:     email,
+			Role:      role,
+			TokenHash: hashedToken,
+			InvitedBy: invitedBy,
+			ExpiresAt: utils.TimeToPgTimestamptz(time.Now().Add(invitationDuration)),
+		})
+		if err != nil {
+			return err
+		}
+
+		return appendEvent(ctx, querier, tenantID, invitation.ID, events.MemberInvited, map[string]any{
+			"email":      email,
+			"role":       role,
+			"invited_by": utils.PgUUIDToString(invitedBy),
+		})
+	}); err != nil {
+		if reqErr != nil {
+			return nil, reqErr
+		}
+
+		return nil, status.Errorf(codes.Internal, "error inviting member: %v", err)
+	}
+
+	if err := s.mailer.Send(ctx, mailer.Message{
+		To:      email,
+		Subject: fmt.Sprintf("You have been invited to %s", tenantName),
+		Body: fmt.Sprintf(
+			"You have been invited to join %s as %s.\n\nAccept the invitation within %s by opening:\n\n%s\n",
+			tenantName, role, invitationDuration, s.linkBaseURL+"/accept-invitation?token="+url.QueryEscape(rawToken),
+		),
+	}); err != nil {
+		slog.Error("failed to send invitation email", "invitation_id", utils.PgUUIDToString(invitation.ID), "error", err)
+	}
+
+	return &tenantv1.InviteMemberResponse{
+		InvitationId: utils.PgUUIDToString(invitation.ID),
+		ExpiresAt:    timestamppb.New(invitation.ExpiresAt.Time),
+	}, nil
+}
+
+func (s *Service) AcceptInvitation(ctx context.Context, req *tenantv1.AcceptInvitationRequest) (*tenantv1.AcceptInvitationResponse, error) {
+	tokenPayload := token.GetTokenPayload(ctx)
+	if tokenPayload == nil {
+		return nil, status.Error(codes.Unauthenticated, "missing user authentication")
+	}
+	userID := utils.UUIDToPgUUID(tokenPayload.UserID)
+
+	var reqErr error
+	var invitation sqlc.GetInvitationForUpdateRow
+	if err := s.execTx(ctx, func(querier sqlc.Querier) error {
+		var err error
+		invitation, err = querier.GetInvitationForUpdate(ctx, auth.HashKey(req.Token))
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				reqErr = status.Error(codes.NotFound, "invitation not found")
+			}
+			return err
+		}
+
+		if invitation.AcceptedAt.Valid || invitation.RevokedAt.Valid || time.Now().After(invitation.ExpiresAt.Time) {
+			reqErr = status.Error(codes.FailedPrecondition, "invitation is no longer valid")
+			return reqErr
+		}
+
+		if !strings.EqualFold(invitation.Email, tokenPayload.Email) {
+			reqErr = status.Error(codes.PermissionDenied, "invitation was sent to a different email")
+			return reqErr
+		}
+
+		if _, err := querier.AddTenantMember(ctx, sqlc.AddTenantMemberParams{
+			TenantID: invitation.TenantID,
+			UserID:   userID,
+			Role:     utils.StringToPgText(invitation.Role),
+		}); err != nil {
+			var pgErr *pgconn.PgError
+			if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+				reqErr = status.Error(codes.AlreadyExists, "already a member of the tenant")
+			}
+			return err
+		}
+
+		if err := querier.AcceptInvitation(ctx, sqlc.AcceptInvitationParams{
+			ID:         invitation.ID,
+			AcceptedBy: userID,
+		}); err != nil {
+			return err
+		}
+
+		return appendEvent(ctx, querier, invitation.TenantID, userID, events.MemberJoined, map[string]any{
+			"invitation_id": utils.PgUUIDToString(invitation.ID),
+			"role":          invitation.Role,
+		})
+	}); err != nil {
+		if reqErr != nil {
+			return nil, reqErr
+		}
+
+		return nil, status.Errorf(codes.Internal, "error accepting invitation: %v", err)
+	}
+
+	return &tenantv1.AcceptInvitationResponse{
+		TenantId: utils.PgUUIDToString(invitation.TenantID),
+		Role:     invitation.Role,
+	}, nil
+}
+
+func (s *Service) ListMembers(ctx context.Context, req *tenantv1.ListMembersRequest) (*tenantv1.ListMembersResponse, error) {
+	tenantID, err := tenantFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.querier.ListTenantMembers(ctx, tenantID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "error listing members: %v", err)
+	}
+
+	members := make([]*tenantv1.Member, 0, len(rows))
+	for _, r := range rows {
+		members = append(members, &tenantv1.Member{
+			UserId:   utils.PgUUIDToString(r.UserID),
+			Email:    r.Email,
+			FullName: r.FullName.String,
+			Role:     r.Role.String,
+			JoinedAt: timestamppb.New(r.JoinedAt.Time),
+		})
+	}
+
+	return &tenantv1.ListMembersResponse{Members: members}, nil
+}
+
+func (s *Service) UpdateMemberRole(ctx context.Context, req *tenantv1.UpdateMemberRoleRequest) (*tenantv1.UpdateMemberRoleResponse, error) {
+	scope := token.GetTenantScope(ctx)
+	if scope == nil {
+		return nil, status.Error(codes.PermissionDenied, "missing tenant scope")
+	}
+	tenantID := utils.UUIDToPgUUID(scope.TenantID)
+
+	userID, err := uuid.Parse(req.UserId)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid user id: %v", err)
+	}
+
+	if !isValidMemberRole(req.Role) {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid role: %s", req.Role)
+	}
+	if auth.ParseRole(req.Role) > auth.ParseRole(scope.Role) {
+		return nil, status.Errorf(codes.PermissionDenied, "cannot grant role %s above your own role %s", req.Role, scope.Role)
+	}
+
+	var reqErr error
+	var previousRole string
+	if err := s.execTx(ctx, func(querier sqlc.Querier) error {
+		member, err := lockMember(ctx, querier, tenantID, utils.UUIDToPgUUID(userID), scope.Role)
+		if err != nil {
+			reqErr = requestError(err)
+			return err
+		}
+		previousRole = member.Role.String
+
+		if previousRole == string(memberRoleOwner) && req.Role != string(memberRoleOwner) {
+			if reqErr = ensureAnotherOwner(ctx, querier, tenantID); reqErr != nil {
+				return reqErr
+			}
+		}
+
+		if err := querier.UpdateTenantMemberRole(ctx, sqlc.UpdateTenantMemberRoleParams{
+			TenantID: tenantID,
+			UserID:   member.UserID,
+			Role:     utils.StringToPgText(req.Role),
+		}); err != nil {
+			return err
+		}
+
+		return appendEvent(ctx, querier, tenantID, member.UserID, events.MemberRoleUpdated, map[string]any{
+			"previous_role": previousRole,
+			"role":          req.Role,
+		})
+	}); err != nil {
+		if reqErr != nil {
+			return nil, reqErr
+		}
+
+		return nil, status.Errorf(codes.Internal, "error updating member role: %v", err)
+	}
+
+	member, err := s.querier.GetTenantMember(ctx, sqlc.GetTenantMemberParams{
+		TenantID: tenantID,
+		UserID:   utils.UUIDToPgUUID(userID),
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "error getting member: %v", err)
+	}
+
+	return &tenantv1.UpdateMemberRoleResponse{Member: &tenantv1.Member{
+		UserId:   utils.PgUUIDToString(member.UserID),
+		Email:    member.Email,
+		FullName: member.FullName.String,
+		Role:     member.Role.String,
+		JoinedAt: timestamppb.New(member.JoinedAt.Time),
+	}}, nil
+}
+
+func (s *Service) RemoveMember(ctx context.Context, req *tenantv1.RemoveMemberRequest) (*tenantv1.RemoveMemberResponse, error) {
+	scope := token.GetTenantScope(ctx)
+	if scope == nil {
+		return nil, status.Error(codes.PermissionDenied, "missing tenant scope")
+	}
+	tenantID := utils.UUIDToPgUUID(scope.TenantID)
+
+	userID, err := uuid.Parse(req.UserId)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid user id: %v", err)
+	}
+
+	var removedBy string
+	if tokenPayload := token.GetTokenPayload(ctx); tokenPayload != nil {
+		removedBy = tokenPayload.UserID.String()
+	}
+
+	if err := s.removeMember(ctx, tenantID, utils.UUIDToPgUUID(userID), scope.Role, events.MemberRemoved, map[string]any{
+		"removed_by": removedBy,
+	}); err != nil {
+		return nil, err
+	}
+
+	return &tenantv1.RemoveMemberResponse{Success: true}, nil
+}
+
+func (s *Service) LeaveTenant(ctx context.Context, req *tenantv1.LeaveTenantRequest) (*tenantv1.LeaveTenantResponse, error) {
+	scope := token.GetTenantScope(ctx)
+	if scope == nil {
+		return nil, status.Error(codes.PermissionDenied, "missing tenant scope")
+	}
+
+	tokenPayload := token.GetTokenPayload(ctx)
+	if tokenPayload == nil {
+		return nil, status.Error(codes.PermissionDenied, "only users can leave a tenant")
+	}
+
+	if err := s.removeMember(ctx, utils.UUIDToPgUUID(scope.TenantID), utils.UUIDToPgUUID(tokenPayload.UserID), scope.Role, events.MemberLeft, nil); err != nil {
+		return nil, err
+	}
+
+	return &tenantv1.LeaveTenantResponse{Success: true}, nil
+}
+
+func (s *Service) removeMember(ctx context.Context, tenantID, userID pgtype.UUID, callerRole string, eventType string, payload map[string]any) error {
+	var reqErr error
+	if err := s.execTx(ctx, func(querier sqlc.Querier) error {
+		member, err := lockMember(ctx, querier, tenantID, userID, callerRole)
+		if err != nil {
+			reqErr = requestError(err)
+			return err
+		}
+
+		if member.Role.String == string(memberRoleOwner) {
+			if reqErr = ensureAnotherOwner(ctx, querier, tenantID); reqErr != nil {
+				return reqErr
+			}
+		}
+
+		if err := querier.RemoveTenantMember(ctx, sqlc.RemoveTenantMemberParams{
+			TenantID: tenantID,
+			UserID:   userID,
+		}); err != nil {
+			return err
+		}
+
+		if payload == nil {
+			payload = map[string]any{}
+		}
+		payload["role"] = member.Role.String
+
+		return appendEvent(ctx, querier, tenantID, userID, eventType, payload)
+	}); err != nil {
+		if reqErr != nil {
+			return reqErr
+		}
+
+		return status.Errorf(codes.Internal, "error removing member: %v", err)
+	}
+
+	return nil
+}
+
+// lockMember locks the tenant owners and then the target membership, always
+// in that order so that concurrent role changes cannot deadlock. Callers may
+// not manage members whose role is above their own.
+func lockMember(ctx context.Context, querier sqlc.Querier, tenantID, userID pgtype.UUID, callerRole string) (sqlc.TenantMember, error) {
+	if _, err := querier.ListTenantOwnersForUpdate(ctx, tenantID); err != nil {
+		return sqlc.TenantMember{}, err
+	}
+
+	member, err := querier.GetTenantMemberForUpdate(ctx, sqlc.GetTenantMemberForUpdateParams{
+		TenantID: tenantID,
+		UserID:   userID,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return member, status.Errorf(codes.NotFound, "member with id %s not found", utils.PgUUIDToString(userID))
+		}
+		return member, err
+	}
+
+	if auth.ParseRole(member.Role.String) > auth.ParseRole(callerRole) {
+		return member, status.Errorf(codes.PermissionDenied, "cannot manage a member with role %s above your own role %s", member.Role.String, callerRole)
+	}
+
+	return member, nil
+}
+
+// ensureAnotherOwner returns errLastOwner unless the tenant has an owner
+// besides the one being demoted or removed. The owners are already locked by
+// lockMember.
+func ensureAnotherOwner(ctx context.Context, querier sqlc.Querier, tenantID pgtype.UUID) error {
+	owners, err := querier.ListTenantOwnersForUpdate(ctx, tenantID)
+	if err != nil {
+		return err
+	}
+	if len(owners) <= 1 {
+		return errLastOwner
+	}
+
+	return nil
+}
+
+// requestError returns err if it is a gRPC status meant for the caller.
+func requestError(err error) error {
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+
+	return nil
+}
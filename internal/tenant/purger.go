@@ -0,0 +1,116 @@
+package tenant
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/vantutran2k1/rwe/internal/common/events"
+	"github.com/vantutran2k1/rwe/internal/common/utils"
+	sqlc "github.com/vantutran2k1/rwe/internal/tenant/db"
+)
+
+// Purger permanently deletes tenants whose deletion grace period has passed,
+// together with their workflows, runs, tasks, events and usage.
+type Purger struct {
+	pool      *pgxpool.Pool
+	interval  time.Duration
+	batchSize int
+}
+
+func NewPurger(pool *pgxpool.Pool, interval time.Duration, batchSize int) *Purger {
+	return &Purger{
+		pool:      pool,
+		interval:  interval,
+		batchSize: batchSize,
+	}
+}
+
+func (p *Purger) Run(ctx context.Context) {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		for range p.batchSize {
+			purged, err := p.purgeNext(ctx)
+			if err != nil {
+				if ctx.Err() == nil {
+					slog.Error("tenant purge failed", "error", err)
+				}
+				break
+			}
+			if !purged {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+var errNothingToPurge = errors.New("no tenant due for deletion")
+
+// purgeNext deletes a single due tenant in its own transaction so that a
+// large tenant does not hold locks for the rest of the batch.
+func (p *Purger) purgeNext(ctx context.Context) (bool, error) {
+	var tenantID pgtype.UUID
+	err := p.execTx(ctx, func(querier sqlc.Querier) error {
+		ids, err := querier.ListTenantsDueForDeletion(ctx, 1)
+		if err != nil {
+			return err
+		}
+		if len(ids) == 0 {
+			return errNothingToPurge
+		}
+		tenantID = ids[0]
+
+		for _, purge := range []func(context.Context, pgtype.UUID) error{
+			querier.DeleteTenantTasks,
+			querier.DeleteTenantRuns,
+			querier.DeleteTenantWorkflows,
+			querier.DeleteTenantUsageRecords,
+			querier.DeleteTenantUsageRollups,
+			querier.DeleteTenantEvents,
+			querier.DetachChildTenants,
+			querier.DeleteTenant,
+		} {
+			if err := purge(ctx, tenantID); err != nil {
+				return err
+			}
+		}
+
+		// The tenant's own events are gone, so the deletion is recorded
+		// without a tenant to keep an audit trail.
+		return appendEvent(ctx, querier, pgtype.UUID{}, tenantID, events.TenantDeleted, nil)
+	})
+	if errors.Is(err, errNothingToPurge) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	slog.Info("tenant purged", "tenant_id", utils.PgUUIDToString(tenantID))
+	return true, nil
+}
+
+func (p *Purger) execTx(ctx context.Context, fn func(sqlc.Querier) error) error {
+	tx, err := p.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := fn(sqlc.New(tx)); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
@@ -3,29 +3,43 @@ package tenant
 import (
 	"context"
 	"errors"
+	"strings"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/vantutran2k1/rwe/internal/common/events"
 	"github.com/vantutran2k1/rwe/internal/common/token"
 	"github.com/vantutran2k1/rwe/internal/common/utils"
+	"github.com/vantutran2k1/rwe/internal/mailer"
 
 	"github.com/gosimple/slug"
 	"github.com/jackc/pgx/v5/pgxpool"
+	authv1 "github.com/vantutran2k1/rwe/gen/go/auth/v1"
 	tenantv1 "github.com/vantutran2k1/rwe/gen/go/tenant/v1"
+	"github.com/vantutran2k1/rwe/internal/auth"
 	sqlc "github.com/vantutran2k1/rwe/internal/tenant/db"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
 
 type Service struct {
-	pool    *pgxpool.Pool
-	querier sqlc.Querier
+	pool          *pgxpool.Pool
+	querier       sqlc.Querier
+	mailer        mailer.Mailer
+	linkBaseURL   string
+	deletionGrace time.Duration
 	tenantv1.UnimplementedTenantServiceServer
 }
 
-func NewService(pool *pgxpool.Pool) *Service {
+func NewService(pool *pgxpool.Pool, mailer mailer.Mailer, linkBaseURL string, deletionGrace time.Duration) *Service {
 	return &Service{
-		pool:    pool,
-		querier: sqlc.New(pool),
+		pool:          pool,
+		querier:       sqlc.New(pool),
+		mailer:        mailer,
+		linkBaseURL:   strings.TrimSuffix(linkBaseURL, "/"),
+		deletionGrace: deletionGrace,
 	}
 }
 
@@ -47,6 +61,28 @@ func (s *Service) CreateTenant(ctx context.Context, req *tenantv1.CreateTenantRe
 		return nil, status.Errorf(codes.Internal, "error getting tenant: %v", err)
 	}
 
+	var parentID pgtype.UUID
+	tier := string(tenantTierFree)
+	if req.ParentId != "" {
+		id, err := uuid.Parse(req.ParentId)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid parent id: %v", err)
+		}
+		parentID = utils.UUIDToPgUUID(id)
+
+		parent, err := s.checkParent(ctx, parentID, utils.UUIDToPgUUID(tokenPayload.UserID))
+		if err != nil {
+			return nil, err
+		}
+
+		// Child tenants belong to the same organization and inherit its
+		// plan and, unless given, its region.
+		tier = parent.Tier.String
+		if req.Region == "" {
+			req.Region = parent.Region.String
+		}
+	}
+
 	if !isValidRegion(req.Region) {
 		return nil, status.Errorf(codes.InvalidArgument, "invalid region for tenant")
 	}
@@ -57,24 +93,97 @@ func (s *Service) CreateTenant(ctx context.Context, req *tenantv1.CreateTenantRe
 			Name:         req.Name,
 			Slug:         sl,
 			ContactEmail: utils.StringToPgText(tokenPayload.Email),
-			Tier:         utils.StringToPgText(string(tenantTierFree)),
+			Tier:         utils.StringToPgText(tier),
 			Region:       utils.StringToPgText(req.Region),
+			ParentID:     parentID,
+		})
+		if err != nil {
+			return err
+		}
+
+		if _, err := querier.AddTenantMember(ctx, sqlc.AddTenantMemberParams{
+			TenantID: tenant.ID,
+			UserID:   utils.UUIDToPgUUID(tokenPayload.UserID),
+			Role:     utils.StringToPgText(string(memberRoleOwner)),
+		}); err != nil {
+			return err
+		}
+
+		return appendEvent(ctx, querier, tenant.ID, tenant.ID, events.TenantCreated, map[string]any{
+			"name":      tenant.Name,
+			"slug":      tenant.Slug,
+			"tier":      tenant.Tier.String,
+			"region":    tenant.Region.String,
+			"parent_id": req.ParentId,
 		})
-
-		return err
 	}); err != nil {
 		return nil, status.Errorf(codes.Internal, "error creating tenant: %v", err)
 	}
 
+	var parent string
+	if tenant.ParentID.Valid {
+		parent = utils.PgUUIDToString(tenant.ParentID)
+	}
+
 	return &tenantv1.CreateTenantResponse{
-		Name:   tenant.Name,
-		Slug:   tenant.Slug,
-		Tier:   tenant.Tier.String,
-		Region: tenant.Region.String,
-		Status: string(tenant.Status.TenantStatus),
+		Id:       utils.PgUUIDToString(tenant.ID),
+		Name:     tenant.Name,
+		Slug:     tenant.Slug,
+		Tier:     tenant.Tier.String,
+		Region:   tenant.Region.String,
+		Status:   string(tenant.Status.TenantStatus),
+		ParentId: parent,
 	}, nil
 }
 
+// checkParent verifies that a child tenant may be created under the parent:
+// the caller must be an admin of it, directly or through an ancestor, the
+// parent must be active and the hierarchy must not grow too deep.
+func (s *Service) checkParent(ctx context.Context, parentID, userID pgtype.UUID) (sqlc.Tenant, error) {
+	parent, err := s.querier.GetTenant(ctx, parentID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return parent, status.Errorf(codes.NotFound, "parent tenant with id %s not found", utils.PgUUIDToString(parentID))
+		}
+
+		return parent, status.Errorf(codes.Internal, "error getting parent tenant: %v", err)
+	}
+
+	role, err := s.querier.GetEffectiveTenantRole(ctx, sqlc.GetEffectiveTenantRoleParams{
+		TenantID: parentID,
+		UserID:   userID,
+	})
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return parent, status.Errorf(codes.Internal, "error getting tenant membership: %v", err)
+	}
+	if auth.ParseRole(role.String) < authv1.Role_ROLE_ADMIN {
+		return parent, status.Error(codes.PermissionDenied, "creating a child tenant requires the admin role in the parent")
+	}
+
+	if tenantStatus(parent.Status) != sqlc.TenantStatusActive {
+		return parent, status.Errorf(codes.FailedPrecondition, "parent tenant is %s", tenantStatus(parent.Status))
+	}
+
+	depth, err := s.querier.GetTenantDepth(ctx, parentID)
+	if err != nil {
+		return parent, status.Errorf(codes.Internal, "error getting tenant depth: %v", err)
+	}
+	if depth >= maxTenantDepth {
+		return parent, status.Errorf(codes.FailedPrecondition, "tenant hierarchies are limited to %d levels", maxTenantDepth)
+	}
+
+	return parent, nil
+}
+
+func tenantFromContext(ctx context.Context) (pgtype.UUID, error) {
+	scope := token.GetTenantScope(ctx)
+	if scope == nil {
+		return pgtype.UUID{}, status.Error(codes.PermissionDenied, "missing tenant scope")
+	}
+
+	return utils.UUIDToPgUUID(scope.TenantID), nil
+}
+
 func (s *Service) execTx(ctx context.Context, fn func(sqlc.Querier) error) error {
 	tx, err := s.pool.Begin(ctx)
 	if err != nil {
@@ -90,3 +199,19 @@ func (s *Service) execTx(ctx context.Context, fn func(sqlc.Querier) error) error
 
 	return tx.Commit(ctx)
 }
+
+func appendEvent(ctx context.Context, querier sqlc.Querier, tenantID, aggregateID pgtype.UUID, eventType string, payload any) error {
+	data, err := events.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	_, err = querier.AppendEvent(ctx, sqlc.AppendEventParams{
+		TenantID:    tenantID,
+		EventType:   eventType,
+		AggregateID: aggregateID,
+		Payload:     data,
+	})
+
+	return err
+}
@@ -8,6 +8,37 @@ const (
 	tenantTierPro              = "pro"
 )
 
+// maxTenantDepth limits how many levels an organization's tenant hierarchy
+// may have, counting the root.
+const maxTenantDepth = 5
+
+// quotasSettingsKey holds the per-tenant quota overrides in tenants.settings.
+const quotasSettingsKey = "quotas"
+
+// Values of tenants.suspended_by. Only the platform lifts its suspensions.
+const (
+	suspendedByTenant   = "tenant"
+	suspendedByPlatform = "platform"
+)
+
+type memberRole string
+
+const (
+	memberRoleOwner  memberRole = "owner"
+	memberRoleAdmin  memberRole = "admin"
+	memberRoleMember memberRole = "member"
+	memberRoleViewer memberRole = "viewer"
+)
+
+func isValidMemberRole(role string) bool {
+	switch memberRole(role) {
+	case memberRoleOwner, memberRoleAdmin, memberRoleMember, memberRoleViewer:
+		return true
+	default:
+		return false
+	}
+}
+
 type tenantRegion string
 
 const (
@@ -0,0 +1,116 @@
+package usage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/vantutran2k1/rwe/internal/common/utils"
+	sqlc "github.com/vantutran2k1/rwe/internal/usage/db"
+)
+
+const (
+	rollupWatermark = "rollups"
+
+	// rollupLookback is how far back buckets are recomputed on every pass so
+	// that late records are still included.
+	rollupLookback = 2 * time.Hour
+)
+
+// Aggregator maintains the hourly and daily usage rollups. Only one instance
+// works at a time.
+type Aggregator struct {
+	pool     *pgxpool.Pool
+	interval time.Duration
+}
+
+func NewAggregator(pool *pgxpool.Pool, interval time.Duration) *Aggregator {
+	return &Aggregator{
+		pool:     pool,
+		interval: interval,
+	}
+}
+
+func (a *Aggregator) Run(ctx context.Context) {
+	ticker := time.NewTicker(a.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := a.aggregate(ctx); err != nil && ctx.Err() == nil {
+			slog.Error("usage aggregation failed", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (a *Aggregator) aggregate(ctx context.Context) error {
+	tx, err := a.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	querier := sqlc.New(tx)
+
+	locked, err := querier.TryLockUsageAggregation(ctx)
+	if err != nil {
+		return fmt.Errorf("error locking usage aggregation: %w", err)
+	}
+	if !locked {
+		return nil
+	}
+
+	now := time.Now()
+
+	// Recompute from the last pass, or further back if it was recent, so a
+	// stopped aggregator catches up on every bucket it missed.
+	rollupSince, err := watermark(ctx, querier, rollupWatermark, now)
+	if err != nil {
+		return err
+	}
+	if lookback := now.Add(-rollupLookback); lookback.Before(rollupSince) {
+		rollupSince = lookback
+	}
+	rollupSince = rollupSince.UTC().Truncate(time.Hour)
+
+	if _, err := querier.RollupHourlyUsage(ctx, utils.TimeToPgTimestamptz(rollupSince)); err != nil {
+		return fmt.Errorf("error rolling up hourly usage: %w", err)
+	}
+
+	if _, err := querier.RollupDailyUsage(ctx, utils.TimeToPgTimestamptz(rollupSince)); err != nil {
+		return fmt.Errorf("error rolling up daily usage: %w", err)
+	}
+
+	if err := querier.SetUsageWatermark(ctx, sqlc.SetUsageWatermarkParams{
+		Name:      rollupWatermark,
+		Watermark: utils.TimeToPgTimestamptz(now),
+	}); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// watermark returns the stored watermark, or def when none has been
+// recorded yet.
+func watermark(ctx context.Context, querier sqlc.Querier, name string, def time.Time) (time.Time, error) {
+	w, err := querier.GetUsageWatermark(ctx, name)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return def, nil
+		}
+
+		return time.Time{}, fmt.Errorf("error getting %s watermark: %w", name, err)
+	}
+
+	return w.Time, nil
+}
@@ -0,0 +1,24 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+
+package sqlc
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type Querier interface {
+	GetUsageWatermark(ctx context.Context, name string) (pgtype.Timestamptz, error)
+	ListSubtreeTenantIDs(ctx context.Context, id pgtype.UUID) ([]pgtype.UUID, error)
+	ListUsageRollups(ctx context.Context, arg ListUsageRollupsParams) ([]ListUsageRollupsRow, error)
+	RecordUsageBatch(ctx context.Context, arg RecordUsageBatchParams) error
+	RollupDailyUsage(ctx context.Context, since pgtype.Timestamptz) (int64, error)
+	RollupHourlyUsage(ctx context.Context, since pgtype.Timestamptz) (int64, error)
+	SetUsageWatermark(ctx context.Context, arg SetUsageWatermarkParams) error
+	TryLockUsageAggregation(ctx context.Context) (bool, error)
+}
+
+var _ Querier = (*Queries)(nil)
@@ -0,0 +1,61 @@
+-- name: RecordUsageBatch :exec
+INSERT INTO usage_records (tenant_id, metric, value, sample_at)
+SELECT unnest(@tenant_ids::uuid[]), unnest(@metrics::text[]), unnest(@values::float8[]), @sample_at::timestamptz;
+
+-- name: TryLockUsageAggregation :one
+SELECT pg_try_advisory_xact_lock(hashtext('usage_aggregation'));
+
+-- name: GetUsageWatermark :one
+SELECT watermark
+FROM usage_watermarks
+WHERE name = $1;
+
+-- name: SetUsageWatermark :exec
+INSERT INTO usage_watermarks (name, watermark)
+VALUES ($1, $2)
+ON CONFLICT (name) DO UPDATE
+    SET watermark = excluded.watermark;
+
+-- name: RollupHourlyUsage :execrows
+INSERT INTO usage_rollups (tenant_id, metric, granularity, bucket_start, value)
+SELECT tenant_id, metric, 'hour', date_trunc('hour', sample_at, 'UTC'), sum(value)
+FROM usage_records
+WHERE sample_at >= @since::timestamptz
+  AND tenant_id IS NOT NULL
+  AND metric IS NOT NULL
+GROUP BY tenant_id, metric, date_trunc('hour', sample_at, 'UTC')
+ON CONFLICT (tenant_id, metric, granularity, bucket_start) DO UPDATE
+    SET value      = excluded.value,
+        updated_at = now();
+
+-- name: RollupDailyUsage :execrows
+INSERT INTO usage_rollups (tenant_id, metric, granularity, bucket_start, value)
+SELECT tenant_id, metric, 'day', date_trunc('day', bucket_start, 'UTC'), sum(value)
+FROM usage_rollups
+WHERE granularity = 'hour'
+  AND bucket_start >= date_trunc('day', @since::timestamptz, 'UTC')
+GROUP BY tenant_id, metric, date_trunc('day', bucket_start, 'UTC')
+ON CONFLICT (tenant_id, metric, granularity, bucket_start) DO UPDATE
+    SET value      = excluded.value,
+        updated_at = now();
+
+-- name: ListUsageRollups :many
+SELECT tenant_id, metric, bucket_start, value::float8 AS value
+FROM usage_rollups
+WHERE tenant_id = ANY (@tenant_ids::uuid[])
+  AND granularity = @granularity
+  AND (cardinality(@metrics::text[]) = 0 OR metric = ANY (@metrics::text[]))
+  AND bucket_start >= @start_time
+  AND bucket_start < @end_time
+ORDER BY tenant_id, metric, bucket_start;
+
+-- name: ListSubtreeTenantIDs :many
+WITH RECURSIVE subtree AS (SELECT tenants.id
+                           FROM tenants
+                           WHERE tenants.id = $1
+                           UNION ALL
+                           SELECT c.id
+                           FROM tenants c
+                                    JOIN subtree s ON c.parent_id = s.id)
+SELECT id
+FROM subtree;
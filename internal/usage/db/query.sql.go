@@ -0,0 +1,205 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: query.sql
+
+package sqlc
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const getUsageWatermark = `-- name: GetUsageWatermark :one
+SELECT watermark
+FROM usage_watermarks
+WHERE name = $1
+`
+
+func (q *Queries) GetUsageWatermark(ctx context.Context, name string) (pgtype.Timestamptz, error) {
+	row := q.db.QueryRow(ctx, getUsageWatermark, name)
+	var watermark pgtype.Timestamptz
+	err := row.Scan(&watermark)
+	return watermark, err
+}
+
+const listSubtreeTenantIDs = `-- name: ListSubtreeTenantIDs :many
+WITH RECURSIVE subtree AS (SELECT tenants.id
+                           FROM tenants
+                           WHERE tenants.id = $1
+                           UNION ALL
+                           SELECT c.id
+                           FROM tenants c
+                                    JOIN subtree s ON c.parent_id = s.id)
+SELECT id
+FROM subtree
+`
+
+func (q *Queries) ListSubtreeTenantIDs(ctx context.Context, id pgtype.UUID) ([]pgtype.UUID, error) {
+	rows, err := q.db.Query(ctx, listSubtreeTenantIDs, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []pgtype.UUID
+	for rows.Next() {
+		var id pgtype.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUsageRollups = `-- name: ListUsageRollups :many
+SELECT tenant_id, metric, bucket_start, value::float8 AS value
+FROM usage_rollups
+WHERE tenant_id = ANY ($1::uuid[])
+  AND granularity = $2
+  AND (cardinality($3::text[]) = 0 OR metric = ANY ($3::text[]))
+  AND bucket_start >= $4
+  AND bucket_start < $5
+ORDER BY tenant_id, metric, bucket_start
+`
+
+type ListUsageRollupsParams struct {
+	TenantIds   []pgtype.UUID      `db:"tenant_ids" json:"tenant_ids"`
+	Granularity string             `db:"granularity" json:"granularity"`
+	Metrics     []string           `db:"metrics" json:"metrics"`
+	StartTime   pgtype.Timestamptz `db:"start_time" json:"start_time"`
+	EndTime     pgtype.Timestamptz `db:"end_time" json:"end_time"`
+}
+
+type ListUsageRollupsRow struct {
+	TenantID    pgtype.UUID        `db:"tenant_id" json:"tenant_id"`
+	Metric      string             `db:"metric" json:"metric"`
+	BucketStart pgtype.Timestamptz `db:"bucket_start" json:"bucket_start"`
+	Value       float64            `db:"value" json:"value"`
+}
+
+func (q *Queries) ListUsageRollups(ctx context.Context, arg ListUsageRollupsParams) ([]ListUsageRollupsRow, error) {
+	rows, err := q.db.Query(ctx, listUsageRollups,
+		arg.TenantIds,
+		arg.Granularity,
+		arg.Metrics,
+		arg.StartTime,
+		arg.EndTime,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListUsageRollupsRow
+	for rows.Next() {
+		var i ListUsageRollupsRow
+		if err := rows.Scan(
+			&i.TenantID,
+			&i.Metric,
+			&i.BucketStart,
+			&i.Value,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const recordUsageBatch = `-- name: RecordUsageBatch :exec
+INSERT INTO usage_records (tenant_id, metric, value, sample_at)
+SELECT unnest($1::uuid[]), unnest($2::text[]), unnest($3::float8[]), $4::timestamptz
+`
+
+type RecordUsageBatchParams struct {
+	TenantIds []pgtype.UUID      `db:"tenant_ids" json:"tenant_ids"`
+	Metrics   []string           `db:"metrics" json:"metrics"`
+	Values    []float64          `db:"values" json:"values"`
+	SampleAt  pgtype.Timestamptz `db:"sample_at" json:"sample_at"`
+}
+
+func (q *Queries) RecordUsageBatch(ctx context.Context, arg RecordUsageBatchParams) error {
+	_, err := q.db.Exec(ctx, recordUsageBatch,
+		arg.TenantIds,
+		arg.Metrics,
+		arg.Values,
+		arg.SampleAt,
+	)
+	return err
+}
+
+const rollupDailyUsage = `-- name: RollupDailyUsage :execrows
+INSERT INTO usage_rollups (tenant_id, metric, granularity, bucket_start, value)
+SELECT tenant_id, metric, 'day', date_trunc('day', bucket_start, 'UTC'), sum(value)
+FROM usage_rollups
+WHERE granularity = 'hour'
+  AND bucket_start >= date_trunc('day', $1::timestamptz, 'UTC')
+GROUP BY tenant_id, metric, date_trunc('day', bucket_start, 'UTC')
+ON CONFLICT (tenant_id, metric, granularity, bucket_start) DO UPDATE
+    SET value      = excluded.value,
+        updated_at = now()
+`
+
+func (q *Queries) RollupDailyUsage(ctx context.Context, since pgtype.Timestamptz) (int64, error) {
+	result, err := q.db.Exec(ctx, rollupDailyUsage, since)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const rollupHourlyUsage = `-- name: RollupHourlyUsage :execrows
+INSERT INTO usage_rollups (tenant_id, metric, granularity, bucket_start, value)
+SELECT tenant_id, metric, 'hour', date_trunc('hour', sample_at, 'UTC'), sum(value)
+FROM usage_records
+WHERE sample_at >= $1::timestamptz
+  AND tenant_id IS NOT NULL
+  AND metric IS NOT NULL
+GROUP BY tenant_id, metric, date_trunc('hour', sample_at, 'UTC')
+ON CONFLICT (tenant_id, metric, granularity, bucket_start) DO UPDATE
+    SET value      = excluded.value,
+        updated_at = now()
+`
+
+func (q *Queries) RollupHourlyUsage(ctx context.Context, since pgtype.Timestamptz) (int64, error) {
+	result, err := q.db.Exec(ctx, rollupHourlyUsage, since)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const setUsageWatermark = `-- name: SetUsageWatermark :exec
+INSERT INTO usage_watermarks (name, watermark)
+VALUES ($1, $2)
+ON CONFLICT (name) DO UPDATE
+    SET watermark = excluded.watermark
+`
+
+type SetUsageWatermarkParams struct {
+	Name      string             `db:"name" json:"name"`
+	Watermark pgtype.Timestamptz `db:"watermark" json:"watermark"`
+}
+
+func (q *Queries) SetUsageWatermark(ctx context.Context, arg SetUsageWatermarkParams) error {
+	_, err := q.db.Exec(ctx, setUsageWatermark, arg.Name, arg.Watermark)
+	return err
+}
+
+const tryLockUsageAggregation = `-- name: TryLockUsageAggregation :one
+SELECT pg_try_advisory_xact_lock(hashtext('usage_aggregation'))
+`
+
+func (q *Queries) TryLockUsageAggregation(ctx context.Context) (bool, error) {
+	row := q.db.QueryRow(ctx, tryLockUsageAggregation)
+	var pg_try_advisory_xact_lock bool
+	err := row.Scan(&pg_try_advisory_xact_lock)
+	return pg_try_advisory_xact_lock, err
+}
@@ -0,0 +1,124 @@
+package usage
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/vantutran2k1/rwe/internal/common/utils"
+	sqlc "github.com/vantutran2k1/rwe/internal/usage/db"
+)
+
+const (
+	MetricRunsStarted = "runs_started"
+	MetricTaskSeconds = "task_seconds"
+	MetricApiCalls    = "api_calls"
+	// MetricDefinitionBytes records changes in the stored definition size,
+	// so that its sum since the first workflow is the current size.
+	MetricDefinitionBytes = "definition_bytes"
+)
+
+var metrics = []string{MetricRunsStarted, MetricTaskSeconds, MetricApiCalls, MetricDefinitionBytes}
+
+func isValidMetric(metric string) bool {
+	for _, m := range metrics {
+		if m == metric {
+			return true
+		}
+	}
+
+	return false
+}
+
+type meterKey struct {
+	tenantID uuid.UUID
+	metric   string
+}
+
+// Meter accumulates high-volume usage such as API calls in memory and writes
+// it to usage_records in batches.
+type Meter struct {
+	querier       sqlc.Querier
+	flushInterval time.Duration
+
+	mu     sync.Mutex
+	counts map[meterKey]float64
+}
+
+func NewMeter(pool *pgxpool.Pool, flushInterval time.Duration) *Meter {
+	return &Meter{
+		querier:       sqlc.New(pool),
+		flushInterval: flushInterval,
+		counts:        make(map[meterKey]float64),
+	}
+}
+
+func (m *Meter) Add(tenantID uuid.UUID, metric string, value float64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.counts[meterKey{tenantID: tenantID, metric: metric}] += value
+}
+
+// Run flushes the meter periodically until ctx is done, then flushes once
+// more so that usage recorded during shutdown is not lost.
+func (m *Meter) Run(ctx context.Context) {
+	ticker := time.NewTicker(m.flushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			flushCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			if err := m.Flush(flushCtx); err != nil {
+				slog.Error("final usage flush failed", "error", err)
+			}
+			return
+		case <-ticker.C:
+			if err := m.Flush(ctx); err != nil && ctx.Err() == nil {
+				slog.Error("usage flush failed", "error", err)
+			}
+		}
+	}
+}
+
+func (m *Meter) Flush(ctx context.Context) error {
+	m.mu.Lock()
+	counts := m.counts
+	m.counts = make(map[meterKey]float64)
+	m.mu.Unlock()
+
+	if len(counts) == 0 {
+		return nil
+	}
+
+	params := sqlc.RecordUsageBatchParams{
+		TenantIds: make([]pgtype.UUID, 0, len(counts)),
+		Metrics:   make([]string, 0, len(counts)),
+		Values:    make([]float64, 0, len(counts)),
+		SampleAt:  utils.TimeToPgTimestamptz(time.Now()),
+	}
+	for k, v := range counts {
+		params.TenantIds = append(params.TenantIds, utils.UUIDToPgUUID(k.tenantID))
+		params.Metrics = append(params.Metrics, k.metric)
+		params.Values = append(params.Values, v)
+	}
+
+	if err := m.querier.RecordUsageBatch(ctx, params); err != nil {
+		// Put the counts back so the next flush retries them.
+		m.mu.Lock()
+		for k, v := range counts {
+			m.counts[k] += v
+		}
+		m.mu.Unlock()
+
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,134 @@
+package usage
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	usagev1 "github.com/vantutran2k1/rwe/gen/go/usage/v1"
+	"github.com/vantutran2k1/rwe/internal/common/token"
+	"github.com/vantutran2k1/rwe/internal/common/utils"
+	sqlc "github.com/vantutran2k1/rwe/internal/usage/db"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	granularityHour = "hour"
+	granularityDay  = "day"
+
+	maxHourlyRange = 31 * 24 * time.Hour
+	maxDailyRange  = 366 * 24 * time.Hour
+)
+
+type Service struct {
+	querier sqlc.Querier
+	usagev1.UnimplementedUsageServiceServer
+}
+
+func NewService(pool *pgxpool.Pool) *Service {
+	return &Service{
+		querier: sqlc.New(pool),
+	}
+}
+
+func (s *Service) GetUsage(ctx context.Context, req *usagev1.GetUsageRequest) (*usagev1.GetUsageResponse, error) {
+	tenantID, err := tenantFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	granularity := req.Granularity
+	if granularity == "" {
+		granularity = granularityDay
+	}
+
+	var bucket, maxRange time.Duration
+	switch granularity {
+	case granularityHour:
+		bucket, maxRange = time.Hour, maxHourlyRange
+	case granularityDay:
+		bucket, maxRange = 24*time.Hour, maxDailyRange
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "invalid granularity: %s", req.Granularity)
+	}
+
+	for _, m := range req.Metrics {
+		if !isValidMetric(m) {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid metric: %s", m)
+		}
+	}
+	metricFilter := req.Metrics
+	if metricFilter == nil {
+		metricFilter = []string{}
+	}
+
+	now := time.Now().UTC()
+	start := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	if req.StartTime != nil {
+		start = req.StartTime.AsTime()
+	}
+	end := now
+	if req.EndTime != nil {
+		end = req.EndTime.AsTime()
+	}
+
+	// Buckets are aligned to UTC; a partial bucket at the start is included
+	// whole.
+	start = start.Truncate(bucket)
+	if !end.After(start) {
+		return nil, status.Error(codes.InvalidArgument, "end time must be after start time")
+	}
+	if end.Sub(start) > maxRange {
+		return nil, status.Errorf(codes.InvalidArgument, "time range must not exceed %s for %s granularity", maxRange, granularity)
+	}
+
+	tenantIDs := []pgtype.UUID{tenantID}
+	if req.IncludeDescendants {
+		tenantIDs, err = s.querier.ListSubtreeTenantIDs(ctx, tenantID)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "error listing descendant tenants: %v", err)
+		}
+	}
+
+	rows, err := s.querier.ListUsageRollups(ctx, sqlc.ListUsageRollupsParams{
+		TenantIds:   tenantIDs,
+		Granularity: granularity,
+		Metrics:     metricFilter,
+		StartTime:   utils.TimeToPgTimestamptz(start),
+		EndTime:     utils.TimeToPgTimestamptz(end),
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "error listing usage: %v", err)
+	}
+
+	// Rows are ordered by tenant and metric, so each series is contiguous.
+	var series []*usagev1.UsageSeries
+	var current *usagev1.UsageSeries
+	for _, r := range rows {
+		id := utils.PgUUIDToString(r.TenantID)
+		if current == nil || current.TenantId != id || current.Metric != r.Metric {
+			current = &usagev1.UsageSeries{TenantId: id, Metric: r.Metric}
+			series = append(series, current)
+		}
+
+		current.Total += r.Value
+		current.Points = append(current.Points, &usagev1.UsagePoint{
+			StartTime: timestamppb.New(r.BucketStart.Time),
+			Value:     r.Value,
+		})
+	}
+
+	return &usagev1.GetUsageResponse{Series: series}, nil
+}
+
+func tenantFromContext(ctx context.Context) (pgtype.UUID, error) {
+	scope := token.GetTenantScope(ctx)
+	if scope == nil {
+		return pgtype.UUID{}, status.Error(codes.PermissionDenied, "missing tenant scope")
+	}
+
+	return utils.UUIDToPgUUID(scope.TenantID), nil
+}
@@ -0,0 +1,24 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+
+package sqlc
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type Querier interface {
+	ClaimTasks(ctx context.Context, arg ClaimTasksParams) ([]ClaimTasksRow, error)
+	// Every attempt is metered as it ends, because a retry clears its timing.
+	CompleteTask(ctx context.Context, arg CompleteTaskParams) (int64, error)
+	CreateWorker(ctx context.Context, arg CreateWorkerParams) (Worker, error)
+	ExtendWorkerLeases(ctx context.Context, arg ExtendWorkerLeasesParams) (int64, error)
+	FailTask(ctx context.Context, arg FailTaskParams) (int64, error)
+	GetWorkerByID(ctx context.Context, arg GetWorkerByIDParams) (Worker, error)
+	TouchWorker(ctx context.Context, id pgtype.UUID) error
+}
+
+var _ Querier = (*Queries)(nil)
@@ -0,0 +1,83 @@
+-- name: CreateWorker :one
+INSERT INTO workers (tenant_id, name, version, capacity, metadata, last_heartbeat)
+VALUES ($1, $2, $3, $4, $5, now())
+RETURNING *;
+
+-- name: GetWorkerByID :one
+SELECT *
+FROM workers
+WHERE id = $1
+  AND tenant_id = $2;
+
+-- name: TouchWorker :exec
+UPDATE workers
+SET last_heartbeat = now()
+WHERE id = $1;
+
+-- name: ExtendWorkerLeases :execrows
+UPDATE tasks
+SET lease_expires_at = now() + make_interval(secs => @lease_seconds::int),
+    updated_at       = now()
+WHERE worker_id = @worker_id
+  AND status = 'running';
+
+-- name: ClaimTasks :many
+UPDATE tasks t
+SET status           = 'running',
+    worker_id        = @worker_id,
+    attempts         = t.attempts + 1,
+    started_at       = now(),
+    lease_expires_at = now() + make_interval(secs => @lease_seconds::int),
+    deadline_at      = now() + make_interval(secs => t.timeout_seconds),
+    updated_at       = now()
+FROM workflow_runs r
+WHERE r.id = t.run_id
+  AND t.id IN (SELECT c.id
+               FROM tasks c
+                        JOIN workflow_runs cr ON cr.id = c.run_id
+               WHERE c.status = 'queued'
+                 AND c.queue = ANY (@queues::text[])
+                 AND (c.available_at IS NULL OR c.available_at <= now())
+                 AND cr.tenant_id = @tenant_id
+                 AND cr.status = 'running'
+               ORDER BY c.created_at
+               LIMIT @max_tasks FOR UPDATE OF c SKIP LOCKED)
+RETURNING t.id, t.run_id, t.step_id, t.queue, t.input, t.attempts, t.lease_expires_at, r.payload;
+
+-- name: CompleteTask :execrows
+-- Every attempt is metered as it ends, because a retry clears its timing.
+WITH finished AS (UPDATE tasks t
+                  SET status           = 'succeeded',
+                      result           = $3,
+                      finished_at      = now(),
+                      lease_expires_at = NULL,
+                      updated_at       = now()
+                  WHERE t.id = $1
+                    AND t.worker_id = $2
+                    AND t.status = 'running'
+                    AND t.lease_expires_at > now()
+                    AND EXISTS (SELECT 1 FROM workflow_runs r WHERE r.id = t.run_id AND r.status = 'running')
+                  RETURNING t.run_id, t.started_at, t.finished_at)
+INSERT INTO usage_records (tenant_id, metric, value, sample_at)
+SELECT r.tenant_id, 'task_seconds', coalesce(extract(EPOCH FROM f.finished_at - f.started_at), 0), f.finished_at
+FROM finished f
+         JOIN workflow_runs r ON r.id = f.run_id;
+
+-- name: FailTask :execrows
+WITH finished AS (UPDATE tasks t
+                  SET status           = 'failed',
+                      last_error       = $3,
+                      error_code       = $4,
+                      finished_at      = now(),
+                      lease_expires_at = NULL,
+                      updated_at       = now()
+                  WHERE t.id = $1
+                    AND t.worker_id = $2
+                    AND t.status = 'running'
+                    AND t.lease_expires_at > now()
+                    AND EXISTS (SELECT 1 FROM workflow_runs r WHERE r.id = t.run_id AND r.status = 'running')
+                  RETURNING t.run_id, t.started_at, t.finished_at)
+INSERT INTO usage_records (tenant_id, metric, value, sample_at)
+SELECT r.tenant_id, 'task_seconds', coalesce(extract(EPOCH FROM f.finished_at - f.started_at), 0), f.finished_at
+FROM finished f
+         JOIN workflow_runs r ON r.id = f.run_id;
@@ -0,0 +1,260 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: query.sql
+
+package sqlc
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const claimTasks = `-- name: ClaimTasks :many
+UPDATE tasks t
+SET status           = 'running',
+    worker_id        = $1,
+    attempts         = t.attempts + 1,
+    started_at       = now(),
+    lease_expires_at = now() + make_interval(secs => $2::int),
+    deadline_at      = now() + make_interval(secs => t.timeout_seconds),
+    updated_at       = now()
+FROM workflow_runs r
+WHERE r.id = t.run_id
+  AND t.id IN (SELECT c.id
+               FROM tasks c
+                        JOIN workflow_runs cr ON cr.id = c.run_id
+               WHERE c.status = 'queued'
+                 AND c.queue = ANY ($3::text[])
+                 AND (c.available_at IS NULL OR c.available_at <= now())
+                 AND cr.tenant_id = $4
+                 AND cr.status = 'running'
+               ORDER BY c.created_at
+               LIMIT $5 FOR UPDATE OF c SKIP LOCKED)
+RETURNING t.id, t.run_id, t.step_id, t.queue, t.input, t.attempts, t.lease_expires_at, r.payload
+`
+
+type ClaimTasksParams struct {
+	WorkerID     pgtype.UUID `db:"worker_id" json:"worker_id"`
+	LeaseSeconds int32       `db:"lease_seconds" json:"lease_seconds"`
+	Queues       []string    `db:"queues" json:"queues"`
+	TenantID     pgtype.UUID `db:"tenant_id" json:"tenant_id"`
+	MaxTasks     int32       `db:"max_tasks" json:"max_tasks"`
+}
+
+type ClaimTasksRow struct {
+	ID             pgtype.UUID        `db:"id" json:"id"`
+	RunID          pgtype.UUID        `db:"run_id" json:"run_id"`
+	StepID         string             `db:"step_id" json:"step_id"`
+	Queue          pgtype.Text        `db:"queue" json:"queue"`
+	Input          []byte             `db:"input" json:"input"`
+	Attempts       pgtype.Int4        `db:"attempts" json:"attempts"`
+	LeaseExpiresAt pgtype.Timestamptz `db:"lease_expires_at" json:"lease_expires_at"`
+	Payload        []byte             `db:"payload" json:"payload"`
+}
+
+func (q *Queries) ClaimTasks(ctx context.Context, arg ClaimTasksParams) ([]ClaimTasksRow, error) {
+	rows, err := q.db.Query(ctx, claimTasks,
+		arg.WorkerID,
+		arg.LeaseSeconds,
+		arg.Queues,
+		arg.TenantID,
+		arg.MaxTasks,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ClaimTasksRow
+	for rows.Next() {
+		var i ClaimTasksRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.RunID,
+			&i.StepID,
+			&i.Queue,
+			&i.Input,
+			&i.Attempts,
+			&i.LeaseExpiresAt,
+			&i.Payload,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const completeTask = `-- name: CompleteTask :execrows
+WITH finished AS (UPDATE tasks t
+                  SET status           = 'succeeded',
+                      result           = $3,
+                      finished_at      = now(),
+                      lease_expires_at = NULL,
+                      updated_at       = now()
+                  WHERE t.id = $1
+                    AND t.worker_id = $2
+                    AND t.status = 'running'
+                    AND t.lease_expires_at > now()
+                    AND EXISTS (SELECT 1 FROM workflow_runs r WHERE r.id = t.run_id AND r.status = 'running')
+                  RETURNING t.run_id, t.started_at, t.finished_at)
+INSERT INTO usage_records (tenant_id, metric, value, sample_at)
+SELECT r.tenant_id, 'task_seconds', coalesce(extract(EPOCH FROM f.finished_at - f.started_at), 0), f.finished_at
+FROM finished f
+         JOIN workflow_runs r ON r.id = f.run_id
+`
+
+type CompleteTaskParams struct {
+	ID       pgtype.UUID `db:"id" json:"id"`
+	WorkerID pgtype.UUID `db:"worker_id" json:"worker_id"`
+	Result   []byte      `db:"result" json:"result"`
+}
+
+// Every attempt is metered as it ends, because a retry clears its timing.
+func (q *Queries) CompleteTask(ctx context.Context, arg CompleteTaskParams) (int64, error) {
+	result, err := q.db.Exec(ctx, completeTask, arg.ID, arg.WorkerID, arg.Result)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const createWorker = `-- name: CreateWorker :one
+INSERT INTO workers (tenant_id, name, version, capacity, metadata, last_heartbeat)
+VALUES ($1, $2, $3, $4, $5, now())
+RETURNING id, name, version, last_heartbeat, capacity, metadata, tenant_id, created_at
+`
+
+type CreateWorkerParams struct {
+	TenantID pgtype.UUID `db:"tenant_id" json:"tenant_id"`
+	Name     pgtype.Text `db:"name" json:"name"`
+	Version  pgtype.Text `db:"version" json:"version"`
+	Capacity []byte      `db:"capacity" json:"capacity"`
+	Metadata []byte      `db:"metadata" json:"metadata"`
+}
+
+func (q *Queries) CreateWorker(ctx context.Context, arg CreateWorkerParams) (Worker, error) {
+	row := q.db.QueryRow(ctx, createWorker,
+		arg.TenantID,
+		arg.Name,
+		arg.Version,
+		arg.Capacity,
+		arg.Metadata,
+	)
+	var i Worker
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Version,
+		&i.LastHeartbeat,
+		&i.Capacity,
+		&i.Metadata,
+		&i.TenantID,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const extendWorkerLeases = `-- name: ExtendWorkerLeases :execrows
+UPDATE tasks
+SET lease_expires_at = now() + make_interval(secs => $1::int),
+    updated_at       = now()
+WHERE worker_id = $2
+  AND status = 'running'
+`
+
+type ExtendWorkerLeasesParams struct {
+	LeaseSeconds int32       `db:"lease_seconds" json:"lease_seconds"`
+	WorkerID     pgtype.UUID `db:"worker_id" json:"worker_id"`
+}
+
+func (q *Queries) ExtendWorkerLeases(ctx context.Context, arg ExtendWorkerLeasesParams) (int64, error) {
+	result, err := q.db.Exec(ctx, extendWorkerLeases, arg.LeaseSeconds, arg.WorkerID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const failTask = `-- name: FailTask :execrows
+WITH finished AS (UPDATE tasks t
+                  SET status           = 'failed',
+                      last_error       = $3,
+                      error_code       = $4,
+                      finished_at      = now(),
+                      lease_expires_at = NULL,
+                      updated_at       = now()
+                  WHERE t.id = $1
+                    AND t.worker_id = $2
+                    AND t.status = 'running'
+                    AND t.lease_expires_at > now()
+                    AND EXISTS (SELECT 1 FROM workflow_runs r WHERE r.id = t.run_id AND r.status = 'running')
+                  RETURNING t.run_id, t.started_at, t.finished_at)
+INSERT INTO usage_records (tenant_id, metric, value, sample_at)
+SELECT r.tenant_id, 'task_seconds', coalesce(extract(EPOCH FROM f.finished_at - f.started_at), 0), f.finished_at
+FROM finished f
+         JOIN workflow_runs r ON r.id = f.run_id
+`
+
+type FailTaskParams struct {
+	ID        pgtype.UUID `db:"id" json:"id"`
+	WorkerID  pgtype.UUID `db:"worker_id" json:"worker_id"`
+	LastError pgtype.Text `db:"last_error" json:"last_error"`
+	ErrorCode pgtype.Text `db:"error_code" json:"error_code"`
+}
+
+func (q *Queries) FailTask(ctx context.Context, arg FailTaskParams) (int64, error) {
+	result, err := q.db.Exec(ctx, failTask,
+		arg.ID,
+		arg.WorkerID,
+		arg.LastError,
+		arg.ErrorCode,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const getWorkerByID = `-- name: GetWorkerByID :one
+SELECT id, name, version, last_heartbeat, capacity, metadata, tenant_id, created_at
+FROM workers
+WHERE id = $1
+  AND tenant_id = $2
+`
+
+type GetWorkerByIDParams struct {
+	ID       pgtype.UUID `db:"id" json:"id"`
+	TenantID pgtype.UUID `db:"tenant_id" json:"tenant_id"`
+}
+
+func (q *Queries) GetWorkerByID(ctx context.Context, arg GetWorkerByIDParams) (Worker, error) {
+	row := q.db.QueryRow(ctx, getWorkerByID, arg.ID, arg.TenantID)
+	var i Worker
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Version,
+		&i.LastHeartbeat,
+		&i.Capacity,
+		&i.Metadata,
+		&i.TenantID,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const touchWorker = `-- name: TouchWorker :exec
+UPDATE workers
+SET last_heartbeat = now()
+WHERE id = $1
+`
+
+func (q *Queries) TouchWorker(ctx context.Context, id pgtype.UUID) error {
+	_, err := q.db.Exec(ctx, touchWorker, id)
+	return err
+}
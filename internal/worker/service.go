@@ -0,0 +1,311 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	workerv1 "github.com/vantutran2k1/rwe/gen/go/worker/v1"
+	"github.com/vantutran2k1/rwe/internal/common/token"
+	"github.com/vantutran2k1/rwe/internal/common/utils"
+	sqlc "github.com/vantutran2k1/rwe/internal/worker/db"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const maxPollTasks = int32(100)
+
+type Service struct {
+	pool         *pgxpool.Pool
+	querier      sqlc.Querier
+	leaseSeconds int32
+	workerv1.UnimplementedWorkerServiceServer
+}
+
+func NewService(pool *pgxpool.Pool, leaseDuration time.Duration) *Service {
+	return &Service{
+		pool:         pool,
+		querier:      sqlc.New(pool),
+		leaseSeconds: int32(leaseDuration.Seconds()),
+	}
+}
+
+func (s *Service) RegisterWorker(ctx context.Context, req *workerv1.RegisterWorkerRequest) (*workerv1.RegisterWorkerResponse, error) {
+	tenantID, err := tenantFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name == "" {
+		return nil, status.Error(codes.InvalidArgument, "worker name must not be empty")
+	}
+
+	capacity, err := marshalStruct(req.Capacity)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid capacity: %v", err)
+	}
+
+	metadata, err := marshalStruct(req.Metadata)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid metadata: %v", err)
+	}
+
+	w, err := s.querier.CreateWorker(ctx, sqlc.CreateWorkerParams{
+		TenantID: tenantID,
+		Name:     utils.StringToPgText(req.Name),
+		Version:  utils.StringToPgText(req.Version),
+		Capacity: capacity,
+		Metadata: metadata,
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "error registering worker: %v", err)
+	}
+
+	return &workerv1.RegisterWorkerResponse{
+		Id:           utils.PgUUIDToString(w.ID),
+		LeaseSeconds: s.leaseSeconds,
+	}, nil
+}
+
+func (s *Service) Heartbeat(ctx context.Context, req *workerv1.HeartbeatRequest) (*workerv1.HeartbeatResponse, error) {
+	w, err := s.getWorker(ctx, req.WorkerId)
+	if err != nil {
+		return nil, err
+	}
+
+	var extended int64
+	if err := s.execTx(ctx, func(querier sqlc.Querier) error {
+		if err := querier.TouchWorker(ctx, w.ID); err != nil {
+			return err
+		}
+
+		extended, err = querier.ExtendWorkerLeases(ctx, sqlc.ExtendWorkerLeasesParams{
+			WorkerID:     w.ID,
+			LeaseSeconds: s.leaseSeconds,
+		})
+
+		return err
+	}); err != nil {
+		return nil, status.Errorf(codes.Internal, "error recording heartbeat: %v", err)
+	}
+
+	return &workerv1.HeartbeatResponse{
+		ExtendedTasks:  extended,
+		LeaseExpiresAt: timestamppb.New(time.Now().Add(time.Duration(s.leaseSeconds) * time.Second)),
+	}, nil
+}
+
+func (s *Service) PollTasks(ctx context.Context, req *workerv1.PollTasksRequest) (*workerv1.PollTasksResponse, error) {
+	w, err := s.getWorker(ctx, req.WorkerId)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(req.Queues) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "at least one queue is required")
+	}
+
+	maxTasks := req.MaxTasks
+	if maxTasks <= 0 {
+		maxTasks = 1
+	}
+	if maxTasks > maxPollTasks {
+		maxTasks = maxPollTasks
+	}
+
+	var rows []sqlc.ClaimTasksRow
+	if err := s.execTx(ctx, func(querier sqlc.Querier) error {
+		if err := querier.TouchWorker(ctx, w.ID); err != nil {
+			return err
+		}
+
+		rows, err = querier.ClaimTasks(ctx, sqlc.ClaimTasksParams{
+			WorkerID:     w.ID,
+			LeaseSeconds: s.leaseSeconds,
+			Queues:       req.Queues,
+			TenantID:     w.TenantID,
+			MaxTasks:     maxTasks,
+		})
+
+		return err
+	}); err != nil {
+		return nil, status.Errorf(codes.Internal, "error claiming tasks: %v", err)
+	}
+
+	tasks := make([]*workerv1.ClaimedTask, 0, len(rows))
+	for _, row := range rows {
+		task, err := toClaimedTask(row)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "error parsing task: %v", err)
+		}
+		tasks = append(tasks, task)
+	}
+
+	return &workerv1.PollTasksResponse{Tasks: tasks}, nil
+}
+
+func (s *Service) CompleteTask(ctx context.Context, req *workerv1.CompleteTaskRequest) (*workerv1.CompleteTaskResponse, error) {
+	w, err := s.getWorker(ctx, req.WorkerId)
+	if err != nil {
+		return nil, err
+	}
+
+	taskID, err := uuid.Parse(req.TaskId)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid task id: %v", err)
+	}
+
+	result := []byte("null")
+	if req.Result != nil {
+		result, err = protojson.Marshal(req.Result)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid result: %v", err)
+		}
+	}
+
+	n, err := s.querier.CompleteTask(ctx, sqlc.CompleteTaskParams{
+		ID:       utils.UUIDToPgUUID(taskID),
+		WorkerID: w.ID,
+		Result:   result,
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "error completing task: %v", err)
+	}
+
+	if n == 0 {
+		return nil, status.Errorf(codes.FailedPrecondition, "task %s is not leased by worker %s", taskID, req.WorkerId)
+	}
+
+	return &workerv1.CompleteTaskResponse{Success: true}, nil
+}
+
+func (s *Service) FailTask(ctx context.Context, req *workerv1.FailTaskRequest) (*workerv1.FailTaskResponse, error) {
+	w, err := s.getWorker(ctx, req.WorkerId)
+	if err != nil {
+		return nil, err
+	}
+
+	taskID, err := uuid.Parse(req.TaskId)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid task id: %v", err)
+	}
+
+	n, err := s.querier.FailTask(ctx, sqlc.FailTaskParams{
+		ID:        utils.UUIDToPgUUID(taskID),
+		WorkerID:  w.ID,
+		LastError: utils.StringToPgText(req.Error),
+		ErrorCode: pgtype.Text{String: req.ErrorCode, Valid: req.ErrorCode != ""},
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "error failing task: %v", err)
+	}
+
+	if n == 0 {
+		return nil, status.Errorf(codes.FailedPrecondition, "task %s is not leased by worker %s", taskID, req.WorkerId)
+	}
+
+	return &workerv1.FailTaskResponse{Success: true}, nil
+}
+
+func (s *Service) getWorker(ctx context.Context, id string) (sqlc.Worker, error) {
+	tenantID, err := tenantFromContext(ctx)
+	if err != nil {
+		return sqlc.Worker{}, err
+	}
+
+	workerID, err := uuid.Parse(id)
+	if err != nil {
+		return sqlc.Worker{}, status.Errorf(codes.InvalidArgument, "invalid worker id: %v", err)
+	}
+
+	w, err := s.querier.GetWorkerByID(ctx, sqlc.GetWorkerByIDParams{
+		ID:       utils.UUIDToPgUUID(workerID),
+		TenantID: tenantID,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return sqlc.Worker{}, status.Errorf(codes.NotFound, "worker with id %s not found", workerID)
+		}
+
+		return sqlc.Worker{}, status.Errorf(codes.Internal, "error getting worker: %v", err)
+	}
+
+	return w, nil
+}
+
+func tenantFromContext(ctx context.Context) (pgtype.UUID, error) {
+	scope := token.GetTenantScope(ctx)
+	if scope == nil {
+		return pgtype.UUID{}, status.Error(codes.PermissionDenied, "missing tenant scope")
+	}
+
+	return utils.UUIDToPgUUID(scope.TenantID), nil
+}
+
+func (s *Service) execTx(ctx context.Context, fn func(sqlc.Querier) error) error {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := sqlc.New(tx)
+
+	if err := fn(qtx); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func toClaimedTask(row sqlc.ClaimTasksRow) (*workerv1.ClaimedTask, error) {
+	var input *structpb.Value
+	if len(row.Input) > 0 {
+		input = &structpb.Value{}
+		if err := protojson.Unmarshal(row.Input, input); err != nil {
+			return nil, err
+		}
+	}
+
+	var payload *structpb.Struct
+	if len(row.Payload) > 0 {
+		payload = &structpb.Struct{}
+		if err := protojson.Unmarshal(row.Payload, payload); err != nil {
+			return nil, err
+		}
+	}
+
+	return &workerv1.ClaimedTask{
+		Id:             utils.PgUUIDToString(row.ID),
+		RunId:          utils.PgUUIDToString(row.RunID),
+		StepId:         row.StepID,
+		Queue:          row.Queue.String,
+		Input:          input,
+		Payload:        payload,
+		Attempt:        row.Attempts.Int32,
+		LeaseExpiresAt: toTimestamp(row.LeaseExpiresAt),
+	}, nil
+}
+
+func marshalStruct(s *structpb.Struct) ([]byte, error) {
+	if s == nil {
+		return nil, nil
+	}
+
+	return protojson.Marshal(s)
+}
+
+func toTimestamp(t pgtype.Timestamptz) *timestamppb.Timestamp {
+	if !t.Valid {
+		return nil
+	}
+
+	return timestamppb.New(t.Time)
+}
@@ -0,0 +1,167 @@
+package workflow
+
+import (
+	"encoding/json"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	workflowv1 "github.com/vantutran2k1/rwe/gen/go/workflow/v1"
+	"github.com/vantutran2k1/rwe/internal/common/utils"
+	sqlc "github.com/vantutran2k1/rwe/internal/workflow/db"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func toWorkflowRun(run sqlc.WorkflowRun, tasks []sqlc.Task) (*workflowv1.WorkflowRun, error) {
+	payload, err := toStruct(run.Payload)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := toStruct(run.Result)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &workflowv1.WorkflowRun{
+		Id:              utils.PgUUIDToString(run.ID),
+		TenantId:        utils.PgUUIDToString(run.TenantID),
+		WorkflowId:      utils.PgUUIDToString(run.WorkflowID),
+		Status:          run.Status,
+		Payload:         payload,
+		Result:          result,
+		Error:           run.Error.String,
+		StartedAt:       toTimestamp(run.StartedAt),
+		FinishedAt:      toTimestamp(run.FinishedAt),
+		Tasks:           make([]*workflowv1.Task, 0, len(tasks)),
+		WorkflowVersion: run.WorkflowVersion.Int32,
+		ScheduledAt:     toTimestamp(run.ScheduledAt),
+	}
+	if run.ScheduleID.Valid {
+		r.ScheduleId = utils.PgUUIDToString(run.ScheduleID)
+	}
+
+	for _, t := range tasks {
+		task, err := toTask(t)
+		if err != nil {
+			return nil, err
+		}
+		r.Tasks = append(r.Tasks, task)
+	}
+
+	return r, nil
+}
+
+func toSchedule(sched sqlc.WorkflowSchedule) (*workflowv1.Schedule, error) {
+	payload, err := toStruct(sched.Payload)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &workflowv1.Schedule{
+		Id:                   utils.PgUUIDToString(sched.ID),
+		TenantId:             utils.PgUUIDToString(sched.TenantID),
+		WorkflowId:           utils.PgUUIDToString(sched.WorkflowID),
+		Name:                 sched.Name,
+		CronExpression:       sched.CronExpression.String,
+		Timezone:             sched.Timezone,
+		IntervalSeconds:      sched.IntervalSeconds.Int32,
+		WorkflowVersion:      sched.WorkflowVersion.Int32,
+		Payload:              payload,
+		OverlapPolicy:        sched.OverlapPolicy,
+		CatchupWindowSeconds: sched.CatchupWindowSeconds,
+		Paused:               sched.Paused,
+		PauseReason:          sched.PauseReason.String,
+		NextRunAt:            toTimestamp(sched.NextRunAt),
+		LastRunAt:            toTimestamp(sched.LastRunAt),
+		CreatedAt:            toTimestamp(sched.CreatedAt),
+		UpdatedAt:            toTimestamp(sched.UpdatedAt),
+	}
+	if sched.LastRunID.Valid {
+		s.LastRunId = utils.PgUUIDToString(sched.LastRunID)
+	}
+
+	return s, nil
+}
+
+func toWebhook(hook sqlc.WorkflowWebhook) (*workflowv1.Webhook, error) {
+	var mapping map[string]string
+	if err := json.Unmarshal(hook.PayloadMapping, &mapping); err != nil {
+		return nil, err
+	}
+
+	return &workflowv1.Webhook{
+		Id:               utils.PgUUIDToString(hook.ID),
+		TenantId:         utils.PgUUIDToString(hook.TenantID),
+		WorkflowId:       utils.PgUUIDToString(hook.WorkflowID),
+		Name:             hook.Name,
+		SignatureHeader:  hook.SignatureHeader,
+		SignatureScheme:  hook.SignatureScheme,
+		DeliveryIdSource: hook.DeliveryIDSource.String,
+		PayloadMapping:   mapping,
+		WorkflowVersion:  hook.WorkflowVersion.Int32,
+		CreatedAt:        toTimestamp(hook.CreatedAt),
+		LastDeliveryAt:   toTimestamp(hook.LastDeliveryAt),
+	}, nil
+}
+
+func toWorkflowVersion(v sqlc.WorkflowVersion) (*workflowv1.WorkflowVersion, error) {
+	definition, err := toStruct(v.Definition)
+	if err != nil {
+		return nil, err
+	}
+
+	return &workflowv1.WorkflowVersion{
+		WorkflowId: utils.PgUUIDToString(v.WorkflowID),
+		Version:    v.Version,
+		Definition: definition,
+		CreatedAt:  toTimestamp(v.CreatedAt),
+	}, nil
+}
+
+func toTask(t sqlc.Task) (*workflowv1.Task, error) {
+	var result *structpb.Value
+	if len(t.Result) > 0 {
+		result = &structpb.Value{}
+		if err := protojson.Unmarshal(t.Result, result); err != nil {
+			return nil, err
+		}
+	}
+
+	return &workflowv1.Task{
+		Id:             utils.PgUUIDToString(t.ID),
+		StepId:         t.StepID,
+		StepType:       t.StepType,
+		Status:         t.Status,
+		Attempts:       t.Attempts.Int32,
+		LastError:      t.LastError.String,
+		Result:         result,
+		StartedAt:      toTimestamp(t.StartedAt),
+		FinishedAt:     toTimestamp(t.FinishedAt),
+		RunId:          utils.PgUUIDToString(t.RunID),
+		ErrorCode:      t.ErrorCode.String,
+		AvailableAt:    toTimestamp(t.AvailableAt),
+		DeadLetteredAt: toTimestamp(t.DeadLetteredAt),
+	}, nil
+}
+
+func toStruct(raw []byte) (*structpb.Struct, error) {
+	if len(raw) == 0 {
+		return nil, nil
+	}
+
+	var s structpb.Struct
+	if err := protojson.Unmarshal(raw, &s); err != nil {
+		return nil, err
+	}
+
+	return &s, nil
+}
+
+func toTimestamp(t pgtype.Timestamptz) *timestamppb.Timestamp {
+	if !t.Valid {
+		return nil
+	}
+
+	return timestamppb.New(t.Time)
+}
@@ -11,9 +11,82 @@ import (
 )
 
 type Querier interface {
+	AdvanceSchedule(ctx context.Context, arg AdvanceScheduleParams) error
+	// Appends to one stream at a time, so that ids grow in commit order within
+	// the tenant or user stream a subscriber follows.
+	AppendEvent(ctx context.Context, arg AppendEventParams) (int64, error)
+	// Running tasks are cancelled as well; their workers can no longer complete
+	// them, and the time they already ran is metered.
+	CancelRunTasks(ctx context.Context, runIds []pgtype.UUID) error
+	CancelRuns(ctx context.Context, arg CancelRunsParams) error
+	ClaimDueSchedules(ctx context.Context, limit int32) ([]WorkflowSchedule, error)
+	ClaimFailedTasks(ctx context.Context, limit int32) ([]ClaimFailedTasksRow, error)
+	ClaimPendingRuns(ctx context.Context, limit int32) ([]WorkflowRun, error)
+	// Builtin steps are leased like worker tasks so that a scheduler which dies
+	// mid-batch does not leave them running forever.
+	ClaimQueuedTasks(ctx context.Context, arg ClaimQueuedTasksParams) ([]Task, error)
+	CompleteTask(ctx context.Context, arg CompleteTaskParams) error
+	CountActiveRuns(ctx context.Context, tenantID pgtype.UUID) (int64, error)
+	CountActiveWorkflows(ctx context.Context, tenantID pgtype.UUID) (int64, error)
+	CountRunsSince(ctx context.Context, arg CountRunsSinceParams) (int64, error)
+	CreateSchedule(ctx context.Context, arg CreateScheduleParams) (WorkflowSchedule, error)
+	CreateScheduledRun(ctx context.Context, arg CreateScheduledRunParams) (WorkflowRun, error)
+	CreateTask(ctx context.Context, arg CreateTaskParams) error
+	CreateTriggeredRun(ctx context.Context, arg CreateTriggeredRunParams) (WorkflowRun, error)
+	CreateWebhook(ctx context.Context, arg CreateWebhookParams) (WorkflowWebhook, error)
+	CreateWebhookDelivery(ctx context.Context, arg CreateWebhookDeliveryParams) (int64, error)
 	CreateWorkflow(ctx context.Context, arg CreateWorkflowParams) (CreateWorkflowRow, error)
-	GetWorkflowByID(ctx context.Context, id pgtype.UUID) (Workflow, error)
+	CreateWorkflowRun(ctx context.Context, arg CreateWorkflowRunParams) (WorkflowRun, error)
+	CreateWorkflowVersion(ctx context.Context, arg CreateWorkflowVersionParams) error
+	DeadLetterTask(ctx context.Context, id pgtype.UUID) error
+	DeleteExpiredWebhookDeliveries(ctx context.Context, arg DeleteExpiredWebhookDeliveriesParams) (int64, error)
+	DeleteSchedule(ctx context.Context, arg DeleteScheduleParams) (int64, error)
+	DeleteWebhook(ctx context.Context, arg DeleteWebhookParams) (int64, error)
+	FailRun(ctx context.Context, arg FailRunParams) error
+	FailTask(ctx context.Context, arg FailTaskParams) error
+	// Every attempt is metered as it ends, because a retry clears its timing.
+	FailTimedOutTasks(ctx context.Context) (int64, error)
+	FinishFailedRuns(ctx context.Context) ([]FinishFailedRunsRow, error)
+	FinishSucceededRuns(ctx context.Context) ([]FinishSucceededRunsRow, error)
+	GetRunDefinition(ctx context.Context, id pgtype.UUID) ([]byte, error)
+	GetSchedule(ctx context.Context, arg GetScheduleParams) (WorkflowSchedule, error)
+	GetScheduleForUpdate(ctx context.Context, arg GetScheduleForUpdateParams) (WorkflowSchedule, error)
+	GetTaskForUpdate(ctx context.Context, arg GetTaskForUpdateParams) (GetTaskForUpdateRow, error)
+	GetWebhookByTokenHash(ctx context.Context, tokenHash string) (GetWebhookByTokenHashRow, error)
+	GetWebhookDelivery(ctx context.Context, arg GetWebhookDeliveryParams) (WebhookDelivery, error)
+	GetWorkflowByID(ctx context.Context, arg GetWorkflowByIDParams) (Workflow, error)
+	GetWorkflowRunByID(ctx context.Context, arg GetWorkflowRunByIDParams) (WorkflowRun, error)
+	GetWorkflowVersion(ctx context.Context, arg GetWorkflowVersionParams) (WorkflowVersion, error)
+	ListActiveScheduleRuns(ctx context.Context, scheduleID pgtype.UUID) ([]pgtype.UUID, error)
+	ListDeadLetterTasks(ctx context.Context, arg ListDeadLetterTasksParams) ([]Task, error)
+	ListSchedules(ctx context.Context, arg ListSchedulesParams) ([]WorkflowSchedule, error)
+	ListSubtreeTenantIDs(ctx context.Context, id pgtype.UUID) ([]pgtype.UUID, error)
+	ListTasksByRunID(ctx context.Context, runID pgtype.UUID) ([]Task, error)
+	ListWebhooks(ctx context.Context, arg ListWebhooksParams) ([]WorkflowWebhook, error)
+	ListWorkflowVersions(ctx context.Context, arg ListWorkflowVersionsParams) ([]WorkflowVersion, error)
 	ListWorkflowsByTenantID(ctx context.Context, arg ListWorkflowsByTenantIDParams) ([]Workflow, error)
+	// Takes the append locks of several tenants in a fixed order, for batches that
+	// append events to more than one of them.
+	LockEventStreams(ctx context.Context, tenantIds []pgtype.UUID) error
+	LockTenantQuota(ctx context.Context, tenantID string) error
+	MarkRunRunning(ctx context.Context, id pgtype.UUID) error
+	PromoteReadyTasks(ctx context.Context) (int64, error)
+	RecordUsage(ctx context.Context, arg RecordUsageParams) error
+	RedriveTask(ctx context.Context, arg RedriveTaskParams) (Task, error)
+	ReopenRun(ctx context.Context, id pgtype.UUID) error
+	// A lost lease means the worker went away, not that the step failed, so the
+	// claimed attempt is handed back instead of counting against the retries.
+	RequeueExpiredTasks(ctx context.Context) (int64, error)
+	ResumeCancelledTasks(ctx context.Context, runID pgtype.UUID) error
+	ScheduleTaskRetry(ctx context.Context, arg ScheduleTaskRetryParams) error
+	SetSchedulePaused(ctx context.Context, arg SetSchedulePausedParams) (WorkflowSchedule, error)
+	SetWebhookDeliveryRun(ctx context.Context, arg SetWebhookDeliveryRunParams) error
+	TouchWebhook(ctx context.Context, id pgtype.UUID) error
+	TryLockScheduler(ctx context.Context) (bool, error)
+	UnlockScheduler(ctx context.Context) error
+	// definition_bytes_delta is the change in stored definition size, which is
+	// what definition usage meters.
+	UpdateWorkflowDefinition(ctx context.Context, arg UpdateWorkflowDefinitionParams) (UpdateWorkflowDefinitionRow, error)
 }
 
 var _ Querier = (*Queries)(nil)
@@ -1,7 +1,7 @@
 -- name: CreateWorkflow :one
 INSERT INTO workflows (id, tenant_id, name, definition)
 VALUES (gen_random_uuid(), $1, $2, $3)
-RETURNING id, tenant_id, name;
+RETURNING id, tenant_id, name, version, octet_length(definition::text)::bigint AS definition_bytes;
 
 -- name: GetWorkflowByID :one
 SELECT id,
@@ -13,7 +13,8 @@ SELECT id,
        updated_at,
        archived
 FROM workflows
-WHERE id = $1;
+WHERE id = $1
+  AND tenant_id = $2;
 
 -- name: ListWorkflowsByTenantID :many
 SELECT id,
@@ -25,8 +26,522 @@ SELECT id,
        updated_at,
        archived
 FROM workflows
+WHERE tenant_id = ANY (@tenant_ids::uuid[])
+  AND ((updated_at < @updated_at)
+    OR (updated_at = @updated_at AND id < @id))
+ORDER BY updated_at DESC, id DESC
+LIMIT @max_results;
+
+-- name: ListSubtreeTenantIDs :many
+WITH RECURSIVE subtree AS (SELECT tenants.id
+                           FROM tenants
+                           WHERE tenants.id = $1
+                           UNION ALL
+                           SELECT c.id
+                           FROM tenants c
+                                    JOIN subtree s ON c.parent_id = s.id)
+SELECT id
+FROM subtree;
+
+-- name: UpdateWorkflowDefinition :one
+-- definition_bytes_delta is the change in stored definition size, which is
+-- what definition usage meters.
+WITH previous AS (SELECT w.id, octet_length(w.definition::text) AS definition_bytes
+                  FROM workflows w
+                  WHERE w.id = @id
+                    AND w.tenant_id = @tenant_id
+                  FOR UPDATE)
+UPDATE workflows
+SET version    = version + 1,
+    name       = coalesce(sqlc.narg(name), name),
+    definition = @definition,
+    updated_at = now()
+FROM previous
+WHERE workflows.id = previous.id
+RETURNING workflows.id,
+          workflows.tenant_id,
+          workflows.name,
+          workflows.version,
+          workflows.archived,
+          (octet_length(workflows.definition::text) - previous.definition_bytes)::bigint AS definition_bytes_delta;
+
+-- name: CreateWorkflowVersion :exec
+INSERT INTO workflow_versions (workflow_id, version, definition)
+VALUES ($1, $2, $3);
+
+-- name: GetWorkflowVersion :one
+SELECT v.*
+FROM workflow_versions v
+         JOIN workflows w ON w.id = v.workflow_id
+WHERE v.workflow_id = $1
+  AND v.version = $2
+  AND w.tenant_id = $3;
+
+-- name: ListWorkflowVersions :many
+SELECT v.*
+FROM workflow_versions v
+         JOIN workflows w ON w.id = v.workflow_id
+WHERE v.workflow_id = $1
+  AND w.tenant_id = $2
+ORDER BY v.version DESC;
+
+-- name: CreateWorkflowRun :one
+INSERT INTO workflow_runs (tenant_id, workflow_id, workflow_version, status, payload)
+VALUES ($1, $2, $3, 'pending', $4)
+RETURNING *;
+
+-- name: GetRunDefinition :one
+SELECT coalesce(v.definition, w.definition)::jsonb AS definition
+FROM workflow_runs r
+         JOIN workflows w ON w.id = r.workflow_id
+         LEFT JOIN workflow_versions v ON v.workflow_id = r.workflow_id AND v.version = r.workflow_version
+WHERE r.id = $1;
+
+-- name: GetWorkflowRunByID :one
+SELECT *
+FROM workflow_runs
+WHERE id = $1
+  AND tenant_id = $2;
+
+-- name: ListTasksByRunID :many
+SELECT *
+FROM tasks
+WHERE run_id = $1
+ORDER BY created_at, step_id;
+
+-- name: ClaimPendingRuns :many
+SELECT *
+FROM workflow_runs
+WHERE status = 'pending'
+ORDER BY started_at
+LIMIT $1 FOR UPDATE SKIP LOCKED;
+
+-- name: MarkRunRunning :exec
+UPDATE workflow_runs
+SET status     = 'running',
+    updated_at = now()
+WHERE id = $1;
+
+-- name: FailRun :exec
+UPDATE workflow_runs
+SET status      = 'failed',
+    error       = $2,
+    finished_at = now(),
+    updated_at  = now()
+WHERE id = $1;
+
+-- name: CreateTask :exec
+INSERT INTO tasks (run_id, step_id, step_type, queue, status, input, depends_on, timeout_seconds, retry_policy)
+VALUES ($1, $2, $3, $4, 'pending', $5, $6, $7, $8);
+
+-- name: PromoteReadyTasks :execrows
+UPDATE tasks t
+SET status     = 'queued',
+    updated_at = now()
+WHERE t.status = 'pending'
+  AND NOT EXISTS (SELECT 1
+                  FROM tasks d
+                  WHERE d.run_id = t.run_id
+                    AND d.step_id = ANY (t.depends_on)
+                    AND d.status <> 'succeeded');
+
+-- name: ClaimQueuedTasks :many
+-- Builtin steps are leased like worker tasks so that a scheduler which dies
+-- mid-batch does not leave them running forever.
+UPDATE tasks
+SET status           = 'running',
+    attempts         = attempts + 1,
+    started_at       = now(),
+    lease_expires_at = now() + make_interval(secs => @lease_seconds::int),
+    deadline_at      = now() + make_interval(secs => timeout_seconds),
+    updated_at       = now()
+WHERE id IN (SELECT c.id
+             FROM tasks c
+                      JOIN workflow_runs cr ON cr.id = c.run_id
+             WHERE c.status = 'queued'
+               AND c.step_type = ANY (@step_types::text[])
+               AND (c.available_at IS NULL OR c.available_at <= now())
+               AND cr.status = 'running'
+             ORDER BY c.created_at
+             LIMIT @batch_size FOR UPDATE OF c SKIP LOCKED)
+RETURNING *;
+
+-- name: RequeueExpiredTasks :execrows
+-- A lost lease means the worker went away, not that the step failed, so the
+-- claimed attempt is handed back instead of counting against the retries.
+UPDATE tasks
+SET status           = 'queued',
+    worker_id        = NULL,
+    attempts         = greatest(attempts - 1, 0),
+    lease_expires_at = NULL,
+    deadline_at      = NULL,
+    updated_at       = now()
+WHERE status = 'running'
+  AND lease_expires_at < now();
+
+-- name: FailTimedOutTasks :execrows
+-- Every attempt is metered as it ends, because a retry clears its timing.
+WITH finished AS (UPDATE tasks t
+                  SET status           = 'failed',
+                      last_error       = 'timed out after ' || t.timeout_seconds || 's',
+                      error_code       = 'timeout',
+                      finished_at      = now(),
+                      lease_expires_at = NULL,
+                      updated_at       = now()
+                  WHERE t.status = 'running'
+                    AND t.deadline_at < now()
+                  RETURNING t.run_id, t.started_at, t.finished_at)
+INSERT INTO usage_records (tenant_id, metric, value, sample_at)
+SELECT r.tenant_id, 'task_seconds', coalesce(extract(EPOCH FROM f.finished_at - f.started_at), 0), f.finished_at
+FROM finished f
+         JOIN workflow_runs r ON r.id = f.run_id;
+
+-- name: CompleteTask :exec
+WITH finished AS (UPDATE tasks t
+                  SET status           = 'succeeded',
+                      result           = $2,
+                      finished_at      = now(),
+                      lease_expires_at = NULL,
+                      updated_at       = now()
+                  WHERE t.id = $1
+                    AND t.status = 'running'
+                    AND EXISTS (SELECT 1 FROM workflow_runs r WHERE r.id = t.run_id AND r.status = 'running')
+                  RETURNING t.run_id, t.started_at, t.finished_at)
+INSERT INTO usage_records (tenant_id, metric, value, sample_at)
+SELECT r.tenant_id, 'task_seconds', coalesce(extract(EPOCH FROM f.finished_at - f.started_at), 0), f.finished_at
+FROM finished f
+         JOIN workflow_runs r ON r.id = f.run_id;
+
+-- name: FailTask :exec
+WITH finished AS (UPDATE tasks t
+                  SET status           = 'failed',
+                      last_error       = $2,
+                      error_code       = $3,
+                      finished_at      = now(),
+                      lease_expires_at = NULL,
+                      updated_at       = now()
+                  WHERE t.id = $1
+                    AND t.status = 'running'
+                    AND EXISTS (SELECT 1 FROM workflow_runs r WHERE r.id = t.run_id AND r.status = 'running')
+                  RETURNING t.run_id, t.started_at, t.finished_at)
+INSERT INTO usage_records (tenant_id, metric, value, sample_at)
+SELECT r.tenant_id, 'task_seconds', coalesce(extract(EPOCH FROM f.finished_at - f.started_at), 0), f.finished_at
+FROM finished f
+         JOIN workflow_runs r ON r.id = f.run_id;
+
+-- name: ClaimFailedTasks :many
+SELECT t.*, r.tenant_id
+FROM tasks t
+         JOIN workflow_runs r ON r.id = t.run_id
+WHERE t.status = 'failed'
+  AND r.status = 'running'
+ORDER BY t.finished_at
+LIMIT $1 FOR UPDATE OF t SKIP LOCKED;
+
+-- name: ScheduleTaskRetry :exec
+UPDATE tasks
+SET status       = 'queued',
+    worker_id    = NULL,
+    started_at   = NULL,
+    finished_at  = NULL,
+    available_at = $2,
+    updated_at   = now()
+WHERE id = $1;
+
+-- name: DeadLetterTask :exec
+UPDATE tasks
+SET status           = 'dead_lettered',
+    dead_lettered_at = now(),
+    updated_at       = now()
+WHERE id = $1;
+
+-- name: ListDeadLetterTasks :many
+SELECT t.*
+FROM tasks t
+         JOIN workflow_runs r ON r.id = t.run_id
+WHERE r.tenant_id = @tenant_id
+  AND t.status = 'dead_lettered'
+  AND (sqlc.narg(workflow_id)::uuid IS NULL OR r.workflow_id = sqlc.narg(workflow_id)::uuid)
+ORDER BY t.dead_lettered_at DESC
+LIMIT @max_results;
+
+-- name: GetTaskForUpdate :one
+SELECT t.*, r.tenant_id, r.status AS run_status
+FROM tasks t
+         JOIN workflow_runs r ON r.id = t.run_id
+WHERE t.id = $1
+  AND r.tenant_id = $2
+    FOR UPDATE OF t, r;
+
+-- name: RedriveTask :one
+UPDATE tasks
+SET status           = 'queued',
+    attempts         = CASE WHEN @reset_attempts::boolean THEN 0 ELSE attempts END,
+    worker_id        = NULL,
+    started_at       = NULL,
+    finished_at      = NULL,
+    available_at     = NULL,
+    dead_lettered_at = NULL,
+    updated_at       = now()
+WHERE id = @id
+RETURNING *;
+
+-- name: ReopenRun :exec
+UPDATE workflow_runs
+SET status      = 'running',
+    error       = NULL,
+    finished_at = NULL,
+    updated_at  = now()
+WHERE id = $1;
+
+-- name: ResumeCancelledTasks :exec
+UPDATE tasks
+SET status     = 'pending',
+    updated_at = now()
+WHERE run_id = $1
+  AND status = 'cancelled';
+
+-- name: FinishFailedRuns :many
+UPDATE workflow_runs r
+SET status      = 'failed',
+    error       = (SELECT 'step ' || t.step_id || ' failed: ' || coalesce(t.last_error, '')
+                   FROM tasks t
+                   WHERE t.run_id = r.id
+                     AND t.status = 'dead_lettered'
+                   ORDER BY t.dead_lettered_at
+                   LIMIT 1),
+    finished_at = now(),
+    updated_at  = now()
+WHERE r.status = 'running'
+  AND EXISTS (SELECT 1 FROM tasks t WHERE t.run_id = r.id AND t.status = 'dead_lettered')
+RETURNING r.id, r.tenant_id, r.workflow_id, r.error;
+
+-- name: CancelRunTasks :exec
+-- Running tasks are cancelled as well; their workers can no longer complete
+-- them, and the time they already ran is metered.
+WITH cancelled AS (UPDATE tasks t
+                   SET status           = 'cancelled',
+                       finished_at      = CASE WHEN t.status = 'running' THEN now() END,
+                       lease_expires_at = NULL,
+                       updated_at       = now()
+                   WHERE t.run_id = ANY (@run_ids::uuid[])
+                     AND t.status IN ('pending', 'queued', 'running')
+                   RETURNING t.run_id, t.started_at, t.finished_at)
+INSERT INTO usage_records (tenant_id, metric, value, sample_at)
+SELECT r.tenant_id, 'task_seconds', coalesce(extract(EPOCH FROM c.finished_at - c.started_at), 0), c.finished_at
+FROM cancelled c
+         JOIN workflow_runs r ON r.id = c.run_id
+WHERE c.finished_at IS NOT NULL;
+
+-- name: FinishSucceededRuns :many
+UPDATE workflow_runs r
+SET status      = 'succeeded',
+    result      = (SELECT coalesce(jsonb_object_agg(t.step_id, coalesce(t.result, 'null'::jsonb)), '{}'::jsonb)
+                   FROM tasks t
+                   WHERE t.run_id = r.id),
+    finished_at = now(),
+    updated_at  = now()
+WHERE r.status = 'running'
+  AND NOT EXISTS (SELECT 1 FROM tasks t WHERE t.run_id = r.id AND t.status <> 'succeeded')
+RETURNING r.id, r.tenant_id, r.workflow_id;
+
+-- name: AppendEvent :one
+-- Appends to one stream at a time, so that ids grow in commit order within
+-- the tenant or user stream a subscriber follows.
+WITH ordering_lock AS (SELECT pg_advisory_xact_lock(hashtext('events:' || sqlc.narg(tenant_id)::uuid::text)))
+INSERT
+INTO events (tenant_id, event_type, aggregate_id, payload)
+SELECT sqlc.narg(tenant_id)::uuid, @event_type::text, @aggregate_id::uuid, @payload::jsonb
+FROM ordering_lock
+RETURNING id;
+
+-- name: LockEventStreams :exec
+-- Takes the append locks of several tenants in a fixed order, for batches that
+-- append events to more than one of them.
+SELECT pg_advisory_xact_lock(k.key)
+FROM (SELECT DISTINCT hashtext('events:' || t.id::text) AS key
+      FROM unnest(@tenant_ids::uuid[]) AS t(id)
+      ORDER BY key) k;
+
+-- name: RecordUsage :exec
+INSERT INTO usage_records (tenant_id, metric, value)
+VALUES (@tenant_id, @metric::text, @value::float8);
+
+-- name: LockTenantQuota :exec
+SELECT pg_advisory_xact_lock(hashtext('quota:' || @tenant_id::text));
+
+-- name: CountActiveWorkflows :one
+SELECT count(*)
+FROM workflows
+WHERE tenant_id = $1
+  AND NOT coalesce(archived, false);
+
+-- name: CountActiveRuns :one
+SELECT count(*)
+FROM workflow_runs
 WHERE tenant_id = $1
-  AND ((updated_at < $2)
-    OR (updated_at = $2 AND id < $3))
-ORDER BY updated_at DESC
-LIMIT $4;
+  AND status IN ('pending', 'running');
+
+-- name: CountRunsSince :one
+SELECT count(*)
+FROM workflow_runs
+WHERE tenant_id = $1
+  AND started_at >= $2;
+
+-- name: CreateSchedule :one
+INSERT INTO workflow_schedules (tenant_id, workflow_id, name, cron_expression, timezone, interval_seconds,
+                                workflow_version, payload, overlap_policy, catchup_window_seconds, paused,
+                                next_run_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+RETURNING *;
+
+-- name: GetSchedule :one
+SELECT *
+FROM workflow_schedules
+WHERE id = $1
+  AND tenant_id = $2;
+
+-- name: GetScheduleForUpdate :one
+SELECT *
+FROM workflow_schedules
+WHERE id = $1
+  AND tenant_id = $2
+    FOR UPDATE;
+
+-- name: ListSchedules :many
+SELECT *
+FROM workflow_schedules
+WHERE tenant_id = @tenant_id
+  AND (sqlc.narg(workflow_id)::uuid IS NULL OR workflow_id = sqlc.narg(workflow_id))
+ORDER BY created_at, id
+LIMIT @max_results;
+
+-- name: SetSchedulePaused :one
+UPDATE workflow_schedules
+SET paused       = $3,
+    pause_reason = $4,
+    next_run_at  = $5,
+    updated_at   = now()
+WHERE id = $1
+  AND tenant_id = $2
+RETURNING *;
+
+-- name: DeleteSchedule :execrows
+DELETE
+FROM workflow_schedules
+WHERE id = $1
+  AND tenant_id = $2;
+
+-- name: TryLockScheduler :one
+SELECT pg_try_advisory_lock(hashtext('workflow_scheduler'));
+
+-- name: UnlockScheduler :exec
+SELECT pg_advisory_unlock(hashtext('workflow_scheduler'));
+
+-- name: ClaimDueSchedules :many
+SELECT s.*
+FROM workflow_schedules s
+         JOIN workflows w ON w.id = s.workflow_id
+         JOIN tenants t ON t.id = s.tenant_id
+WHERE NOT s.paused
+  AND s.next_run_at <= now()
+  AND NOT coalesce(w.archived, false)
+  AND t.status = 'active'
+ORDER BY s.next_run_at
+LIMIT $1 FOR UPDATE OF s SKIP LOCKED;
+
+-- name: AdvanceSchedule :exec
+UPDATE workflow_schedules
+SET next_run_at = @next_run_at,
+    last_run_at = coalesce(sqlc.narg(last_run_at), last_run_at),
+    last_run_id = coalesce(sqlc.narg(last_run_id), last_run_id),
+    updated_at  = now()
+WHERE id = @id;
+
+-- name: ListActiveScheduleRuns :many
+SELECT id
+FROM workflow_runs
+WHERE schedule_id = $1
+  AND status IN ('pending', 'running')
+    FOR UPDATE;
+
+-- name: CreateScheduledRun :one
+INSERT INTO workflow_runs (tenant_id, workflow_id, workflow_version, status, payload, schedule_id, scheduled_at)
+SELECT w.tenant_id, w.id, coalesce(sqlc.narg(workflow_version)::int, w.version), 'pending', @payload, @schedule_id, @scheduled_at
+FROM workflows w
+WHERE w.id = @workflow_id
+ON CONFLICT (schedule_id, scheduled_at) DO NOTHING
+RETURNING *;
+
+-- name: CancelRuns :exec
+UPDATE workflow_runs
+SET status      = 'cancelled',
+    error       = @reason::text,
+    finished_at = now(),
+    updated_at  = now()
+WHERE id = ANY (@run_ids::uuid[])
+  AND status IN ('pending', 'running');
+
+-- name: CreateWebhook :one
+INSERT INTO workflow_webhooks (tenant_id, workflow_id, name, token_hash, secret, signature_header, signature_scheme,
+                               delivery_id_source, payload_mapping, workflow_version)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+RETURNING *;
+
+-- name: ListWebhooks :many
+SELECT *
+FROM workflow_webhooks
+WHERE tenant_id = @tenant_id
+  AND (sqlc.narg(workflow_id)::uuid IS NULL OR workflow_id = sqlc.narg(workflow_id))
+ORDER BY created_at, id
+LIMIT @max_results;
+
+-- name: DeleteWebhook :execrows
+DELETE
+FROM workflow_webhooks
+WHERE id = $1
+  AND tenant_id = $2;
+
+-- name: GetWebhookByTokenHash :one
+SELECT h.*, w.archived AS workflow_archived, t.status AS tenant_status
+FROM workflow_webhooks h
+         JOIN workflows w ON w.id = h.workflow_id
+         JOIN tenants t ON t.id = h.tenant_id
+WHERE h.token_hash = $1;
+
+-- name: CreateWebhookDelivery :execrows
+INSERT INTO webhook_deliveries (webhook_id, delivery_id)
+VALUES ($1, $2)
+ON CONFLICT DO NOTHING;
+
+-- name: GetWebhookDelivery :one
+SELECT *
+FROM webhook_deliveries
+WHERE webhook_id = $1
+  AND delivery_id = $2;
+
+-- name: SetWebhookDeliveryRun :exec
+UPDATE webhook_deliveries
+SET run_id = $3
+WHERE webhook_id = $1
+  AND delivery_id = $2;
+
+-- name: DeleteExpiredWebhookDeliveries :execrows
+DELETE
+FROM webhook_deliveries
+WHERE ctid IN (SELECT d.ctid
+               FROM webhook_deliveries d
+               WHERE d.received_at < @received_before
+               LIMIT @batch_size);
+
+-- name: TouchWebhook :exec
+UPDATE workflow_webhooks
+SET last_delivery_at = now()
+WHERE id = $1;
+
+-- name: CreateTriggeredRun :one
+INSERT INTO workflow_runs (tenant_id, workflow_id, workflow_version, status, payload, metadata)
+SELECT w.tenant_id, w.id, coalesce(sqlc.narg(workflow_version)::int, w.version), 'pending', @payload, @metadata
+FROM workflows w
+WHERE w.id = @workflow_id
+RETURNING *;
@@ -11,58 +11,1655 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const advanceSchedule = `-- name: AdvanceSchedule :exec
+UPDATE workflow_schedules
+SET next_run_at = $1,
+    last_run_at = coalesce($2, last_run_at),
+    last_run_id = coalesce($3, last_run_id),
+    updated_at  = now()
+WHERE id = $4
+`
+
+type AdvanceScheduleParams struct {
+	NextRunAt pgtype.Timestamptz `db:"next_run_at" json:"next_run_at"`
+	LastRunAt pgtype.Timestamptz `db:"last_run_at" json:"last_run_at"`
+	LastRunID pgtype.UUID        `db:"last_run_id" json:"last_run_id"`
+	ID        pgtype.UUID        `db:"id" json:"id"`
+}
+
+func (q *Queries) AdvanceSchedule(ctx context.Context, arg AdvanceScheduleParams) error {
+	_, err := q.db.Exec(ctx, advanceSchedule,
+		arg.NextRunAt,
+		arg.LastRunAt,
+		arg.LastRunID,
+		arg.ID,
+	)
+	return err
+}
+
+const appendEvent = `-- name: AppendEvent :one
+WITH ordering_lock AS (SELECT pg_advisory_xact_lock(hashtext('events:' || $1::uuid::text)))
+INSERT
+INTO events (tenant_id, event_type, aggregate_id, payload)
+SELECT $1::uuid, $2::text, $3::uuid, $4::jsonb
+FROM ordering_lock
+RETURNING id
+`
+
+type AppendEventParams struct {
+	TenantID    pgtype.UUID `db:"tenant_id" json:"tenant_id"`
+	EventType   string      `db:"event_type" json:"event_type"`
+	AggregateID pgtype.UUID `db:"aggregate_id" json:"aggregate_id"`
+	Payload     []byte      `db:"payload" json:"payload"`
+}
+
+// Appends to one stream at a time, so that ids grow in commit order within
+// the tenant or user stream a subscriber follows.
+func (q *Queries) AppendEvent(ctx context.Context, arg AppendEventParams) (int64, error) {
+	row := q.db.QueryRow(ctx, appendEvent,
+		arg.TenantID,
+		arg.EventType,
+		arg.AggregateID,
+		arg.Payload,
+	)
+	var id int64
+	err := row.Scan(&id)
+	return id, err
+}
+
+const cancelRunTasks = `-- name: CancelRunTasks :exec
+WITH cancelled AS (UPDATE tasks t
+                   SET status           = 'cancelled',
+                       finished_at      = CASE WHEN t.status = 'running' THEN now() END,
+                       lease_expires_at = NULL,
+                       updated_at       = now()
+                   WHERE t.run_id = ANY ($1::uuid[])
+                     AND t.status IN ('pending', 'queued', 'running')
+                   RETURNING t.run_id, t.started_at, t.finished_at)
+INSERT INTO usage_records (tenant_id, metric, value, sample_at)
+SELECT r.tenant_id, 'task_seconds', coalesce(extract(EPOCH FROM c.finished_at - c.started_at), 0), c.finished_at
+FROM cancelled c
+         JOIN workflow_runs r ON r.id = c.run_id
+WHERE c.finished_at IS NOT NULL
+`
+
+// Running tasks are cancelled as well; their workers can no longer complete
+// them, and the time they already ran is metered.
+func (q *Queries) CancelRunTasks(ctx context.Context, runIds []pgtype.UUID) error {
+	_, err := q.db.Exec(ctx, cancelRunTasks, runIds)
+	return err
+}
+
+const cancelRuns = `-- name: CancelRuns :exec
+UPDATE workflow_runs
+SET status      = 'cancelled',
+    error       = $1::text,
+    finished_at = now(),
+    updated_at  = now()
+WHERE id = ANY ($2::uuid[])
+  AND status IN ('pending', 'running')
+`
+
+type CancelRunsParams struct {
+	Reason string        `db:"reason" json:"reason"`
+	RunIds []pgtype.UUID `db:"run_ids" json:"run_ids"`
+}
+
+func (q *Queries) CancelRuns(ctx context.Context, arg CancelRunsParams) error {
+	_, err := q.db.Exec(ctx, cancelRuns, arg.Reason, arg.RunIds)
+	return err
+}
+
+const claimDueSchedules = `-- name: ClaimDueSchedules :many
+SELECT s.id, s.tenant_id, s.workflow_id, s.name, s.cron_expression, s.timezone, s.interval_seconds, s.workflow_version, s.payload, s.overlap_policy, s.catchup_window_seconds, s.paused, s.pause_reason, s.next_run_at, s.last_run_at, s.last_run_id, s.created_at, s.updated_at
+FROM workflow_schedules s
+         JOIN workflows w ON w.id = s.workflow_id
+         JOIN tenants t ON t.id = s.tenant_id
+WHERE NOT s.paused
+  AND s.next_run_at <= now()
+  AND NOT coalesce(w.archived, false)
+  AND t.status = 'active'
+ORDER BY s.next_run_at
+LIMIT $1 FOR UPDATE OF s SKIP LOCKED
+`
+
+func (q *Queries) ClaimDueSchedules(ctx context.Context, limit int32) ([]WorkflowSchedule, error) {
+	rows, err := q.db.Query(ctx, claimDueSchedules, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WorkflowSchedule
+	for rows.Next() {
+		var i WorkflowSchedule
+		if err := rows.Scan(
+			&i.ID,
+			&i.TenantID,
+			&i.WorkflowID,
+			&i.Name,
+			&i.CronExpression,
+			&i.Timezone,
+			&i.IntervalSeconds,
+			&i.WorkflowVersion,
+			&i.Payload,
+			&i.OverlapPolicy,
+			&i.CatchupWindowSeconds,
+			&i.Paused,
+			&i.PauseReason,
+			&i.NextRunAt,
+			&i.LastRunAt,
+			&i.LastRunID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const claimFailedTasks = `-- name: ClaimFailedTasks :many
+SELECT t.id, t.run_id, t.step_id, t.status, t.worker_id, t.attempts, t.last_error, t.started_at, t.finished_at, t.result, t.step_type, t.input, t.depends_on, t.created_at, t.updated_at, t.queue, t.lease_expires_at, t.timeout_seconds, t.deadline_at, t.retry_policy, t.error_code, t.available_at, t.dead_lettered_at, r.tenant_id
+FROM tasks t
+         JOIN workflow_runs r ON r.id = t.run_id
+WHERE t.status = 'failed'
+  AND r.status = 'running'
+ORDER BY t.finished_at
+LIMIT $1 FOR UPDATE OF t SKIP LOCKED
+`
+
+type ClaimFailedTasksRow struct {
+	ID             pgtype.UUID        `db:"id" json:"id"`
+	RunID          pgtype.UUID        `db:"run_id" json:"run_id"`
+	StepID         string             `db:"step_id" json:"step_id"`
+	Status         string             `db:"status" json:"status"`
+	WorkerID       pgtype.UUID        `db:"worker_id" json:"worker_id"`
+	Attempts       pgtype.Int4        `db:"attempts" json:"attempts"`
+	LastError      pgtype.Text        `db:"last_error" json:"last_error"`
+	StartedAt      pgtype.Timestamptz `db:"started_at" json:"started_at"`
+	FinishedAt     pgtype.Timestamptz `db:"finished_at" json:"finished_at"`
+	Result         []byte             `db:"result" json:"result"`
+	StepType       string             `db:"step_type" json:"step_type"`
+	Input          []byte             `db:"input" json:"input"`
+	DependsOn      []string           `db:"depends_on" json:"depends_on"`
+	CreatedAt      pgtype.Timestamptz `db:"created_at" json:"created_at"`
+	UpdatedAt      pgtype.Timestamptz `db:"updated_at" json:"updated_at"`
+	Queue          pgtype.Text        `db:"queue" json:"queue"`
+	LeaseExpiresAt pgtype.Timestamptz `db:"lease_expires_at" json:"lease_expires_at"`
+	TimeoutSeconds pgtype.Int4        `db:"timeout_seconds" json:"timeout_seconds"`
+	DeadlineAt     pgtype.Timestamptz `db:"deadline_at" json:"deadline_at"`
+	RetryPolicy    []byte             `db:"retry_policy" json:"retry_policy"`
+	ErrorCode      pgtype.Text        `db:"error_code" json:"error_code"`
+	AvailableAt    pgtype.Timestamptz `db:"available_at" json:"available_at"`
+	DeadLetteredAt pgtype.Timestamptz `db:"dead_lettered_at" json:"dead_lettered_at"`
+	TenantID       pgtype.UUID        `db:"tenant_id" json:"tenant_id"`
+}
+
+func (q *Queries) ClaimFailedTasks(ctx context.Context, limit int32) ([]ClaimFailedTasksRow, error) {
+	rows, err := q.db.Query(ctx, claimFailedTasks, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ClaimFailedTasksRow
+	for rows.Next() {
+		var i ClaimFailedTasksRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.RunID,
+			&i.StepID,
+			&i.Status,
+			&i.WorkerID,
+			&i.Attempts,
+			&i.LastError,
+			&i.StartedAt,
+			&i.FinishedAt,
+			&i.Result,
+			&i.StepType,
+			&i.Input,
+			&i.DependsOn,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Queue,
+			&i.LeaseExpiresAt,
+			&i.TimeoutSeconds,
+			&i.DeadlineAt,
+			&i.RetryPolicy,
+			&i.ErrorCode,
+			&i.AvailableAt,
+			&i.DeadLetteredAt,
+			&i.TenantID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const claimPendingRuns = `-- name: ClaimPendingRuns :many
+SELECT id, tenant_id, workflow_id, status, started_at, finished_at, payload, metadata, result, error, updated_at, workflow_version, schedule_id, scheduled_at
+FROM workflow_runs
+WHERE status = 'pending'
+ORDER BY started_at
+LIMIT $1 FOR UPDATE SKIP LOCKED
+`
+
+func (q *Queries) ClaimPendingRuns(ctx context.Context, limit int32) ([]WorkflowRun, error) {
+	rows, err := q.db.Query(ctx, claimPendingRuns, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WorkflowRun
+	for rows.Next() {
+		var i WorkflowRun
+		if err := rows.Scan(
+			&i.ID,
+			&i.TenantID,
+			&i.WorkflowID,
+			&i.Status,
+			&i.StartedAt,
+			&i.FinishedAt,
+			&i.Payload,
+			&i.Metadata,
+			&i.Result,
+			&i.Error,
+			&i.UpdatedAt,
+			&i.WorkflowVersion,
+			&i.ScheduleID,
+			&i.ScheduledAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const claimQueuedTasks = `-- name: ClaimQueuedTasks :many
+UPDATE tasks
+SET status           = 'running',
+    attempts         = attempts + 1,
+    started_at       = now(),
+    lease_expires_at = now() + make_interval(secs => $1::int),
+    deadline_at      = now() + make_interval(secs => timeout_seconds),
+    updated_at       = now()
+WHERE id IN (SELECT c.id
+             FROM tasks c
+                      JOIN workflow_runs cr ON cr.id = c.run_id
+             WHERE c.status = 'queued'
+               AND c.step_type = ANY ($2::text[])
+               AND (c.available_at IS NULL OR c.available_at <= now())
+               AND cr.status = 'running'
+             ORDER BY c.created_at
+             LIMIT $3 FOR UPDATE OF c SKIP LOCKED)
+RETURNING id, run_id, step_id, status, worker_id, attempts, last_error, started_at, finished_at, result, step_type, input, depends_on, created_at, updated_at, queue, lease_expires_at, timeout_seconds, deadline_at, retry_policy, error_code, available_at, dead_lettered_at
+`
+
+type ClaimQueuedTasksParams struct {
+	LeaseSeconds int32    `db:"lease_seconds" json:"lease_seconds"`
+	StepTypes    []string `db:"step_types" json:"step_types"`
+	BatchSize    int32    `db:"batch_size" json:"batch_size"`
+}
+
+// Builtin steps are leased like worker tasks so that a scheduler which dies
+// mid-batch does not leave them running forever.
+func (q *Queries) ClaimQueuedTasks(ctx context.Context, arg ClaimQueuedTasksParams) ([]Task, error) {
+	rows, err := q.db.Query(ctx, claimQueuedTasks, arg.LeaseSeconds, arg.StepTypes, arg.BatchSize)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Task
+	for rows.Next() {
+		var i Task
+		if err := rows.Scan(
+			&i.ID,
+			&i.RunID,
+			&i.StepID,
+			&i.Status,
+			&i.WorkerID,
+			&i.Attempts,
+			&i.LastError,
+			&i.StartedAt,
+			&i.FinishedAt,
+			&i.Result,
+			&i.StepType,
+			&i.Input,
+			&i.DependsOn,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Queue,
+			&i.LeaseExpiresAt,
+			&i.TimeoutSeconds,
+			&i.DeadlineAt,
+			&i.RetryPolicy,
+			&i.ErrorCode,
+			&i.AvailableAt,
+			&i.DeadLetteredAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const completeTask = `-- name: CompleteTask :exec
+WITH finished AS (UPDATE tasks t
+                  SET status           = 'succeeded',
+                      result           = $2,
+                      finished_at      = now(),
+                      lease_expires_at = NULL,
+                      updated_at       = now()
+                  WHERE t.id = $1
+                    AND t.status = 'running'
+                    AND EXISTS (SELECT 1 FROM workflow_runs r WHERE r.id = t.run_id AND r.status = 'running')
+                  RETURNING t.run_id, t.started_at, t.finished_at)
+INSERT INTO usage_records (tenant_id, metric, value, sample_at)
+SELECT r.tenant_id, 'task_seconds', coalesce(extract(EPOCH FROM f.finished_at - f.started_at), 0), f.finished_at
+FROM finished f
+         JOIN workflow_runs r ON r.id = f.run_id
+`
+
+type CompleteTaskParams struct {
+	ID     pgtype.UUID `db:"id" json:"id"`
+	Result []byte      `db:"result" json:"result"`
+}
+
+func (q *Queries) CompleteTask(ctx context.Context, arg CompleteTaskParams) error {
+	_, err := q.db.Exec(ctx, completeTask, arg.ID, arg.Result)
+	return err
+}
+
+const countActiveRuns = `-- name: CountActiveRuns :one
+SELECT count(*)
+FROM workflow_runs
+WHERE tenant_id = $1
+  AND status IN ('pending', 'running')
+`
+
+func (q *Queries) CountActiveRuns(ctx context.Context, tenantID pgtype.UUID) (int64, error) {
+	row := q.db.QueryRow(ctx, countActiveRuns, tenantID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countActiveWorkflows = `-- name: CountActiveWorkflows :one
+SELECT count(*)
+FROM workflows
+WHERE tenant_id = $1
+  AND NOT coalesce(archived, false)
+`
+
+func (q *Queries) CountActiveWorkflows(ctx context.Context, tenantID pgtype.UUID) (int64, error) {
+	row := q.db.QueryRow(ctx, countActiveWorkflows, tenantID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countRunsSince = `-- name: CountRunsSince :one
+SELECT count(*)
+FROM workflow_runs
+WHERE tenant_id = $1
+  AND started_at >= $2
+`
+
+type CountRunsSinceParams struct {
+	TenantID  pgtype.UUID        `db:"tenant_id" json:"tenant_id"`
+	StartedAt pgtype.Timestamptz `db:"started_at" json:"started_at"`
+}
+
+func (q *Queries) CountRunsSince(ctx context.Context, arg CountRunsSinceParams) (int64, error) {
+	row := q.db.QueryRow(ctx, countRunsSince, arg.TenantID, arg.StartedAt)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const createSchedule = `-- name: CreateSchedule :one
+INSERT INTO workflow_schedules (tenant_id, workflow_id, name, cron_expression, timezone, interval_seconds,
+                                workflow_version, payload, overlap_policy, catchup_window_seconds, paused,
+                                next_run_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+RETURNING id, tenant_id, workflow_id, name, cron_expression, timezone, interval_seconds, workflow_version, payload, overlap_policy, catchup_window_seconds, paused, pause_reason, next_run_at, last_run_at, last_run_id, created_at, updated_at
+`
+
+type CreateScheduleParams struct {
+	TenantID             pgtype.UUID        `db:"tenant_id" json:"tenant_id"`
+	WorkflowID           pgtype.UUID        `db:"workflow_id" json:"workflow_id"`
+	Name                 string             `db:"name" json:"name"`
+	CronExpression       pgtype.Text        `db:"cron_expression" json:"cron_expression"`
+	Timezone             string             `db:"timezone" json:"timezone"`
+	IntervalSeconds      pgtype.Int4        `db:"interval_seconds" json:"interval_seconds"`
+	WorkflowVersion      pgtype.Int4        `db:"workflow_version" json:"workflow_version"`
+	Payload              []byte             `db:"payload" json:"payload"`
+	OverlapPolicy        string             `db:"overlap_policy" json:"overlap_policy"`
+	CatchupWindowSeconds int32              `db:"catchup_window_seconds" json:"catchup_window_seconds"`
+	Paused               bool               `db:"paused" json:"paused"`
+	NextRunAt            pgtype.Timestamptz `db:"next_run_at" json:"next_run_at"`
+}
+
+func (q *Queries) CreateSchedule(ctx context.Context, arg CreateScheduleParams) (WorkflowSchedule, error) {
+	row := q.db.QueryRow(ctx, createSchedule,
+		arg.TenantID,
+		arg.WorkflowID,
+		arg.Name,
+		arg.CronExpression,
+		arg.Timezone,
+		arg.IntervalSeconds,
+		arg.WorkflowVersion,
+		arg.Payload,
+		arg.OverlapPolicy,
+		arg.CatchupWindowSeconds,
+		arg.Paused,
+		arg.NextRunAt,
+	)
+	var i WorkflowSchedule
+	err := row.Scan(
+		&i.ID,
+		&i.TenantID,
+		&i.WorkflowID,
+		&i.Name,
+		&i.CronExpression,
+		&i.Timezone,
+		&i.IntervalSeconds,
+		&i.WorkflowVersion,
+		&i.Payload,
+		&i.OverlapPolicy,
+		&i.CatchupWindowSeconds,
+		&i.Paused,
+		&i.PauseReason,
+		&i.NextRunAt,
+		&i.LastRunAt,
+		&i.LastRunID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const createScheduledRun = `-- name: CreateScheduledRun :one
+INSERT INTO workflow_runs (tenant_id, workflow_id, workflow_version, status, payload, schedule_id, scheduled_at)
+SELECT w.tenant_id, w.id, coalesce($1::int, w.version), 'pending', $2, $3, $4
+FROM workflows w
+WHERE w.id = $5
+ON CONFLICT (schedule_id, scheduled_at) DO NOTHING
+RETURNING id, tenant_id, workflow_id, status, started_at, finished_at, payload, metadata, result, error, updated_at, workflow_version, schedule_id, scheduled_at
+`
+
+type CreateScheduledRunParams struct {
+	WorkflowVersion pgtype.Int4        `db:"workflow_version" json:"workflow_version"`
+	Payload         []byte             `db:"payload" json:"payload"`
+	ScheduleID      pgtype.UUID        `db:"schedule_id" json:"schedule_id"`
+	ScheduledAt     pgtype.Timestamptz `db:"scheduled_at" json:"scheduled_at"`
+	WorkflowID      pgtype.UUID        `db:"workflow_id" json:"workflow_id"`
+}
+
+func (q *Queries) CreateScheduledRun(ctx context.Context, arg CreateScheduledRunParams) (WorkflowRun, error) {
+	row := q.db.QueryRow(ctx, createScheduledRun,
+		arg.WorkflowVersion,
+		arg.Payload,
+		arg.ScheduleID,
+		arg.ScheduledAt,
+		arg.WorkflowID,
+	)
+	var i WorkflowRun
+	err := row.Scan(
+		&i.ID,
+		&i.TenantID,
+		&i.WorkflowID,
+		&i.Status,
+		&i.StartedAt,
+		&i.FinishedAt,
+		&i.Payload,
+		&i.Metadata,
+		&i.Result,
+		&i.Error,
+		&i.UpdatedAt,
+		&i.WorkflowVersion,
+		&i.ScheduleID,
+		&i.ScheduledAt,
+	)
+	return i, err
+}
+
+const createTask = `-- name: CreateTask :exec
+INSERT INTO tasks (run_id, step_id, step_type, queue, status, input, depends_on, timeout_seconds, retry_policy)
+VALUES ($1, $2, $3, $4, 'pending', $5, $6, $7, $8)
+`
+
+type CreateTaskParams struct {
+	RunID          pgtype.UUID `db:"run_id" json:"run_id"`
+	StepID         string      `db:"step_id" json:"step_id"`
+	StepType       string      `db:"step_type" json:"step_type"`
+	Queue          pgtype.Text `db:"queue" json:"queue"`
+	Input          []byte      `db:"input" json:"input"`
+	DependsOn      []string    `db:"depends_on" json:"depends_on"`
+	TimeoutSeconds pgtype.Int4 `db:"timeout_seconds" json:"timeout_seconds"`
+	RetryPolicy    []byte      `db:"retry_policy" json:"retry_policy"`
+}
+
+func (q *Queries) CreateTask(ctx context.Context, arg CreateTaskParams) error {
+	_, err := q.db.Exec(ctx, createTask,
+		arg.RunID,
+		arg.StepID,
+		arg.StepType,
+		arg.Queue,
+		arg.Input,
+		arg.DependsOn,
+		arg.TimeoutSeconds,
+		arg.RetryPolicy,
+	)
+	return err
+}
+
+const createTriggeredRun = `-- name: CreateTriggeredRun :one
+INSERT INTO workflow_runs (tenant_id, workflow_id, workflow_version, status, payload, metadata)
+SELECT w.tenant_id, w.id, coalesce($1::int, w.version), 'pending', $2, $3
+FROM workflows w
+WHERE w.id = $4
+RETURNING id, tenant_id, workflow_id, status, started_at, finished_at, payload, metadata, result, error, updated_at, workflow_version, schedule_id, scheduled_at
+`
+
+type CreateTriggeredRunParams struct {
+	WorkflowVersion pgtype.Int4 `db:"workflow_version" json:"workflow_version"`
+	Payload         []byte      `db:"payload" json:"payload"`
+	Metadata        []byte      `db:"metadata" json:"metadata"`
+	WorkflowID      pgtype.UUID `db:"workflow_id" json:"workflow_id"`
+}
+
+func (q *Queries) CreateTriggeredRun(ctx context.Context, arg CreateTriggeredRunParams) (WorkflowRun, error) {
+	row := q.db.QueryRow(ctx, createTriggeredRun,
+		arg.WorkflowVersion,
+		arg.Payload,
+		arg.Metadata,
+		arg.WorkflowID,
+	)
+	var i WorkflowRun
+	err := row.Scan(
+		&i.ID,
+		&i.TenantID,
+		&i.WorkflowID,
+		&i.Status,
+		&i.StartedAt,
+		&i.FinishedAt,
+		&i.Payload,
+		&i.Metadata,
+		&i.Result,
+		&i.Error,
+		&i.UpdatedAt,
+		&i.WorkflowVersion,
+		&i.ScheduleID,
+		&i.ScheduledAt,
+	)
+	return i, err
+}
+
+const createWebhook = `-- name: CreateWebhook :one
+INSERT INTO workflow_webhooks (tenant_id, workflow_id, name, token_hash, secret, signature_header, signature_scheme,
+                               delivery_id_source, payload_mapping, workflow_version)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+RETURNING id, tenant_id, workflow_id, name, token_hash, secret, signature_header, signature_scheme, delivery_id_source, payload_mapping, workflow_version, created_at, updated_at, last_delivery_at
+`
+
+type CreateWebhookParams struct {
+	TenantID         pgtype.UUID `db:"tenant_id" json:"tenant_id"`
+	WorkflowID       pgtype.UUID `db:"workflow_id" json:"workflow_id"`
+	Name             string      `db:"name" json:"name"`
+	TokenHash        string      `db:"token_hash" json:"token_hash"`
+	Secret           []byte      `db:"secret" json:"secret"`
+	SignatureHeader  string      `db:"signature_header" json:"signature_header"`
+	SignatureScheme  string      `db:"signature_scheme" json:"signature_scheme"`
+	DeliveryIDSource pgtype.Text `db:"delivery_id_source" json:"delivery_id_source"`
+	PayloadMapping   []byte      `db:"payload_mapping" json:"payload_mapping"`
+	WorkflowVersion  pgtype.Int4 `db:"workflow_version" json:"workflow_version"`
+}
+
+func (q *Queries) CreateWebhook(ctx context.Context, arg CreateWebhookParams) (WorkflowWebhook, error) {
+	row := q.db.QueryRow(ctx, createWebhook,
+		arg.TenantID,
+		arg.WorkflowID,
+		arg.Name,
+		arg.TokenHash,
+		arg.Secret,
+		arg.SignatureHeader,
+		arg.SignatureScheme,
+		arg.DeliveryIDSource,
+		arg.PayloadMapping,
+		arg.WorkflowVersion,
+	)
+	var i WorkflowWebhook
+	err := row.Scan(
+		&i.ID,
+		&i.TenantID,
+		&i.WorkflowID,
+		&i.Name,
+		&i.TokenHash,
+		&i.Secret,
+		&i.SignatureHeader,
+		&i.SignatureScheme,
+		&i.DeliveryIDSource,
+		&i.PayloadMapping,
+		&i.WorkflowVersion,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.LastDeliveryAt,
+	)
+	return i, err
+}
+
+const createWebhookDelivery = `-- name: CreateWebhookDelivery :execrows
+INSERT INTO webhook_deliveries (webhook_id, delivery_id)
+VALUES ($1, $2)
+ON CONFLICT DO NOTHING
+`
+
+type CreateWebhookDeliveryParams struct {
+	WebhookID  pgtype.UUID `db:"webhook_id" json:"webhook_id"`
+	DeliveryID string      `db:"delivery_id" json:"delivery_id"`
+}
+
+func (q *Queries) CreateWebhookDelivery(ctx context.Context, arg CreateWebhookDeliveryParams) (int64, error) {
+	result, err := q.db.Exec(ctx, createWebhookDelivery, arg.WebhookID, arg.DeliveryID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
 const createWorkflow = `-- name: CreateWorkflow :one
 INSERT INTO workflows (id, tenant_id, name, definition)
 VALUES (gen_random_uuid(), $1, $2, $3)
-RETURNING id, tenant_id, name
+RETURNING id, tenant_id, name, version, octet_length(definition::text)::bigint AS definition_bytes
+`
+
+type CreateWorkflowParams struct {
+	TenantID   pgtype.UUID `db:"tenant_id" json:"tenant_id"`
+	Name       string      `db:"name" json:"name"`
+	Definition []byte      `db:"definition" json:"definition"`
+}
+
+type CreateWorkflowRow struct {
+	ID              pgtype.UUID `db:"id" json:"id"`
+	TenantID        pgtype.UUID `db:"tenant_id" json:"tenant_id"`
+	Name            string      `db:"name" json:"name"`
+	Version         pgtype.Int4 `db:"version" json:"version"`
+	DefinitionBytes int64       `db:"definition_bytes" json:"definition_bytes"`
+}
+
+func (q *Queries) CreateWorkflow(ctx context.Context, arg CreateWorkflowParams) (CreateWorkflowRow, error) {
+	row := q.db.QueryRow(ctx, createWorkflow, arg.TenantID, arg.Name, arg.Definition)
+	var i CreateWorkflowRow
+	err := row.Scan(
+		&i.ID,
+		&i.TenantID,
+		&i.Name,
+		&i.Version,
+		&i.DefinitionBytes,
+	)
+	return i, err
+}
+
+const createWorkflowRun = `-- name: CreateWorkflowRun :one
+INSERT INTO workflow_runs (tenant_id, workflow_id, workflow_version, status, payload)
+VALUES ($1, $2, $3, 'pending', $4)
+RETURNING id, tenant_id, workflow_id, status, started_at, finished_at, payload, metadata, result, error, updated_at, workflow_version, schedule_id, scheduled_at
+`
+
+type CreateWorkflowRunParams struct {
+	TenantID        pgtype.UUID `db:"tenant_id" json:"tenant_id"`
+	WorkflowID      pgtype.UUID `db:"workflow_id" json:"workflow_id"`
+	WorkflowVersion pgtype.Int4 `db:"workflow_version" json:"workflow_version"`
+	Payload         []byte      `db:"payload" json:"payload"`
+}
+
+func (q *Queries) CreateWorkflowRun(ctx context.Context, arg CreateWorkflowRunParams) (WorkflowRun, error) {
+	row := q.db.QueryRow(ctx, createWorkflowRun,
+		arg.TenantID,
+		arg.WorkflowID,
+		arg.WorkflowVersion,
+		arg.Payload,
+	)
+	var i WorkflowRun
+	err := row.Scan(
+		&i.ID,
+		&i.TenantID,
+		&i.WorkflowID,
+		&i.Status,
+		&i.StartedAt,
+		&i.FinishedAt,
+		&i.Payload,
+		&i.Metadata,
+		&i.Result,
+		&i.Error,
+		&i.UpdatedAt,
+		&i.WorkflowVersion,
+		&i.ScheduleID,
+		&i.ScheduledAt,
+	)
+	return i, err
+}
+
+const createWorkflowVersion = `-- name: CreateWorkflowVersion :exec
+INSERT INTO workflow_versions (workflow_id, version, definition)
+VALUES ($1, $2, $3)
+`
+
+type CreateWorkflowVersionParams struct {
+	WorkflowID pgtype.UUID `db:"workflow_id" json:"workflow_id"`
+	Version    int32       `db:"version" json:"version"`
+	Definition []byte      `db:"definition" json:"definition"`
+}
+
+func (q *Queries) CreateWorkflowVersion(ctx context.Context, arg CreateWorkflowVersionParams) error {
+	_, err := q.db.Exec(ctx, createWorkflowVersion, arg.WorkflowID, arg.Version, arg.Definition)
+	return err
+}
+
+const deadLetterTask = `-- name: DeadLetterTask :exec
+UPDATE tasks
+SET status           = 'dead_lettered',
+    dead_lettered_at = now(),
+    updated_at       = now()
+WHERE id = $1
+`
+
+func (q *Queries) DeadLetterTask(ctx context.Context, id pgtype.UUID) error {
+	_, err := q.db.Exec(ctx, deadLetterTask, id)
+	return err
+}
+
+const deleteExpiredWebhookDeliveries = `-- name: DeleteExpiredWebhookDeliveries :execrows
+DELETE
+FROM webhook_deliveries
+WHERE ctid IN (SELECT d.ctid
+               FROM webhook_deliveries d
+               WHERE d.received_at < $1
+               LIMIT $2)
+`
+
+type DeleteExpiredWebhookDeliveriesParams struct {
+	ReceivedBefore pgtype.Timestamptz `db:"received_before" json:"received_before"`
+	BatchSize      int32              `db:"batch_size" json:"batch_size"`
+}
+
+func (q *Queries) DeleteExpiredWebhookDeliveries(ctx context.Context, arg DeleteExpiredWebhookDeliveriesParams) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteExpiredWebhookDeliveries, arg.ReceivedBefore, arg.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const deleteSchedule = `-- name: DeleteSchedule :execrows
+DELETE
+FROM workflow_schedules
+WHERE id = $1
+  AND tenant_id = $2
+`
+
+type DeleteScheduleParams struct {
+	ID       pgtype.UUID `db:"id" json:"id"`
+	TenantID pgtype.UUID `db:"tenant_id" json:"tenant_id"`
+}
+
+func (q *Queries) DeleteSchedule(ctx context.Context, arg DeleteScheduleParams) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteSchedule, arg.ID, arg.TenantID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const deleteWebhook = `-- name: DeleteWebhook :execrows
+DELETE
+FROM workflow_webhooks
+WHERE id = $1
+  AND tenant_id = $2
+`
+
+type DeleteWebhookParams struct {
+	ID       pgtype.UUID `db:"id" json:"id"`
+	TenantID pgtype.UUID `db:"tenant_id" json:"tenant_id"`
+}
+
+func (q *Queries) DeleteWebhook(ctx context.Context, arg DeleteWebhookParams) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteWebhook, arg.ID, arg.TenantID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const failRun = `-- name: FailRun :exec
+UPDATE workflow_runs
+SET status      = 'failed',
+    error       = $2,
+    finished_at = now(),
+    updated_at  = now()
+WHERE id = $1
+`
+
+type FailRunParams struct {
+	ID    pgtype.UUID `db:"id" json:"id"`
+	Error pgtype.Text `db:"error" json:"error"`
+}
+
+func (q *Queries) FailRun(ctx context.Context, arg FailRunParams) error {
+	_, err := q.db.Exec(ctx, failRun, arg.ID, arg.Error)
+	return err
+}
+
+const failTask = `-- name: FailTask :exec
+WITH finished AS (UPDATE tasks t
+                  SET status           = 'failed',
+                      last_error       = $2,
+                      error_code       = $3,
+                      finished_at      = now(),
+                      lease_expires_at = NULL,
+                      updated_at       = now()
+                  WHERE t.id = $1
+                    AND t.status = 'running'
+                    AND EXISTS (SELECT 1 FROM workflow_runs r WHERE r.id = t.run_id AND r.status = 'running')
+                  RETURNING t.run_id, t.started_at, t.finished_at)
+INSERT INTO usage_records (tenant_id, metric, value, sample_at)
+SELECT r.tenant_id, 'task_seconds', coalesce(extract(EPOCH FROM f.finished_at - f.started_at), 0), f.finished_at
+FROM finished f
+         JOIN workflow_runs r ON r.id = f.run_id
+`
+
+type FailTaskParams struct {
+	ID        pgtype.UUID `db:"id" json:"id"`
+	LastError pgtype.Text `db:"last_error" json:"last_error"`
+	ErrorCode pgtype.Text `db:"error_code" json:"error_code"`
+}
+
+func (q *Queries) FailTask(ctx context.Context, arg FailTaskParams) error {
+	_, err := q.db.Exec(ctx, failTask, arg.ID, arg.LastError, arg.ErrorCode)
+	return err
+}
+
+const failTimedOutTasks = `-- name: FailTimedOutTasks :execrows
+WITH finished AS (UPDATE tasks t
+                  SET status           = 'failed',
+                      last_error       = 'timed out after ' || t.timeout_seconds || 's',
+                      error_code       = 'timeout',
+                      finished_at      = now(),
+                      lease_expires_at = NULL,
+                      updated_at       = now()
+                  WHERE t.status = 'running'
+                    AND t.deadline_at < now()
+                  RETURNING t.run_id, t.started_at, t.finished_at)
+INSERT INTO usage_records (tenant_id, metric, value, sample_at)
+SELECT r.tenant_id, 'task_seconds', coalesce(extract(EPOCH FROM f.finished_at - f.started_at), 0), f.finished_at
+FROM finished f
+         JOIN workflow_runs r ON r.id = f.run_id
+`
+
+// Every attempt is metered as it ends, because a retry clears its timing.
+func (q *Queries) FailTimedOutTasks(ctx context.Context) (int64, error) {
+	result, err := q.db.Exec(ctx, failTimedOutTasks)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const finishFailedRuns = `-- name: FinishFailedRuns :many
+UPDATE workflow_runs r
+SET status      = 'failed',
+    error       = (SELECT 'step ' || t.step_id || ' failed: ' || coalesce(t.last_error, '')
+                   FROM tasks t
+                   WHERE t.run_id = r.id
+                     AND t.status = 'dead_lettered'
+                   ORDER BY t.dead_lettered_at
+                   LIMIT 1),
+    finished_at = now(),
+    updated_at  = now()
+WHERE r.status = 'running'
+  AND EXISTS (SELECT 1 FROM tasks t WHERE t.run_id = r.id AND t.status = 'dead_lettered')
+RETURNING r.id, r.tenant_id, r.workflow_id, r.error
+`
+
+type FinishFailedRunsRow struct {
+	ID         pgtype.UUID `db:"id" json:"id"`
+	TenantID   pgtype.UUID `db:"tenant_id" json:"tenant_id"`
+	WorkflowID pgtype.UUID `db:"workflow_id" json:"workflow_id"`
+	Error      pgtype.Text `db:"error" json:"error"`
+}
+
+func (q *Queries) FinishFailedRuns(ctx context.Context) ([]FinishFailedRunsRow, error) {
+	rows, err := q.db.Query(ctx, finishFailedRuns)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []FinishFailedRunsRow
+	for rows.Next() {
+		var i FinishFailedRunsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.TenantID,
+			&i.WorkflowID,
+			&i.Error,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const finishSucceededRuns = `-- name: FinishSucceededRuns :many
+UPDATE workflow_runs r
+SET status      = 'succeeded',
+    result      = (SELECT coalesce(jsonb_object_agg(t.step_id, coalesce(t.result, 'null'::jsonb)), '{}'::jsonb)
+                   FROM tasks t
+                   WHERE t.run_id = r.id),
+    finished_at = now(),
+    updated_at  = now()
+WHERE r.status = 'running'
+  AND NOT EXISTS (SELECT 1 FROM tasks t WHERE t.run_id = r.id AND t.status <> 'succeeded')
+RETURNING r.id, r.tenant_id, r.workflow_id
+`
+
+type FinishSucceededRunsRow struct {
+	ID         pgtype.UUID `db:"id" json:"id"`
+	TenantID   pgtype.UUID `db:"tenant_id" json:"tenant_id"`
+	WorkflowID pgtype.UUID `db:"workflow_id" json:"workflow_id"`
+}
+
+func (q *Queries) FinishSucceededRuns(ctx context.Context) ([]FinishSucceededRunsRow, error) {
+	rows, err := q.db.Query(ctx, finishSucceededRuns)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []FinishSucceededRunsRow
+	for rows.Next() {
+		var i FinishSucceededRunsRow
+		if err := rows.Scan(&i.ID, &i.TenantID, &i.WorkflowID); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getRunDefinition = `-- name: GetRunDefinition :one
+SELECT coalesce(v.definition, w.definition)::jsonb AS definition
+FROM workflow_runs r
+         JOIN workflows w ON w.id = r.workflow_id
+         LEFT JOIN workflow_versions v ON v.workflow_id = r.workflow_id AND v.version = r.workflow_version
+WHERE r.id = $1
+`
+
+func (q *Queries) GetRunDefinition(ctx context.Context, id pgtype.UUID) ([]byte, error) {
+	row := q.db.QueryRow(ctx, getRunDefinition, id)
+	var definition []byte
+	err := row.Scan(&definition)
+	return definition, err
+}
+
+const getSchedule = `-- name: GetSchedule :one
+SELECT id, tenant_id, workflow_id, name, cron_expression, timezone, interval_seconds, workflow_version, payload, overlap_policy, catchup_window_seconds, paused, pause_reason, next_run_at, last_run_at, last_run_id, created_at, updated_at
+FROM workflow_schedules
+WHERE id = $1
+  AND tenant_id = $2
+`
+
+type GetScheduleParams struct {
+	ID       pgtype.UUID `db:"id" json:"id"`
+	TenantID pgtype.UUID `db:"tenant_id" json:"tenant_id"`
+}
+
+func (q *Queries) GetSchedule(ctx context.Context, arg GetScheduleParams) (WorkflowSchedule, error) {
+	row := q.db.QueryRow(ctx, getSchedule, arg.ID, arg.TenantID)
+	var i WorkflowSchedule
+	err := row.Scan(
+		&i.ID,
+		&i.TenantID,
+		&i.WorkflowID,
+		&i.Name,
+		&i.CronExpression,
+		&i.Timezone,
+		&i.IntervalSeconds,
+		&i.WorkflowVersion,
+		&i.Payload,
+		&i.OverlapPolicy,
+		&i.CatchupWindowSeconds,
+		&i.Paused,
+		&i.PauseReason,
+		&i.NextRunAt,
+		&i.LastRunAt,
+		&i.LastRunID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getScheduleForUpdate = `-- name: GetScheduleForUpdate :one
+SELECT id, tenant_id, workflow_id, name, cron_expression, timezone, interval_seconds, workflow_version, payload, overlap_policy, catchup_window_seconds, paused, pause_reason, next_run_at, last_run_at, last_run_id, created_at, updated_at
+FROM workflow_schedules
+WHERE id = $1
+  AND tenant_id = $2
+    FOR UPDATE
+`
+
+type GetScheduleForUpdateParams struct {
+	ID       pgtype.UUID `db:"id" json:"id"`
+	TenantID pgtype.UUID `db:"tenant_id" json:"tenant_id"`
+}
+
+func (q *Queries) GetScheduleForUpdate(ctx context.Context, arg GetScheduleForUpdateParams) (WorkflowSchedule, error) {
+	row := q.db.QueryRow(ctx, getScheduleForUpdate, arg.ID, arg.TenantID)
+	var i WorkflowSchedule
+	err := row.Scan(
+		&i.ID,
+		&i.TenantID,
+		&i.WorkflowID,
+		&i.Name,
+		&i.CronExpression,
+		&i.Timezone,
+		&i.IntervalSeconds,
+		&i.WorkflowVersion,
+		&i.Payload,
+		&i.OverlapPolicy,
+		&i.CatchupWindowSeconds,
+		&i.Paused,
+		&i.PauseReason,
+		&i.NextRunAt,
+		&i.LastRunAt,
+		&i.LastRunID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getTaskForUpdate = `-- name: GetTaskForUpdate :one
+SELECT t.id, t.run_id, t.step_id, t.status, t.worker_id, t.attempts, t.last_error, t.started_at, t.finished_at, t.result, t.step_type, t.input, t.depends_on, t.created_at, t.updated_at, t.queue, t.lease_expires_at, t.timeout_seconds, t.deadline_at, t.retry_policy, t.error_code, t.available_at, t.dead_lettered_at, r.tenant_id, r.status AS run_status
+FROM tasks t
+         JOIN workflow_runs r ON r.id = t.run_id
+WHERE t.id = $1
+  AND r.tenant_id = $2
+    FOR UPDATE OF t, r
+`
+
+type GetTaskForUpdateParams struct {
+	ID       pgtype.UUID `db:"id" json:"id"`
+	TenantID pgtype.UUID `db:"tenant_id" json:"tenant_id"`
+}
+
+type GetTaskForUpdateRow struct {
+	ID             pgtype.UUID        `db:"id" json:"id"`
+	RunID          pgtype.UUID        `db:"run_id" json:"run_id"`
+	StepID         string             `db:"step_id" json:"step_id"`
+	Status         string             `db:"status" json:"status"`
+	WorkerID       pgtype.UUID        `db:"worker_id" json:"worker_id"`
+	Attempts       pgtype.Int4        `db:"attempts" json:"attempts"`
+	LastError      pgtype.Text        `db:"last_error" json:"last_error"`
+	StartedAt      pgtype.Timestamptz `db:"started_at" json:"started_at"`
+	FinishedAt     pgtype.Timestamptz `db:"finished_at" json:"finished_at"`
+	Result         []byte             `db:"result" json:"result"`
+	StepType       string             `db:"step_type" json:"step_type"`
+	Input          []byte             `db:"input" json:"input"`
+	DependsOn      []string           `db:"depends_on" json:"depends_on"`
+	CreatedAt      pgtype.Timestamptz `db:"created_at" json:"created_at"`
+	UpdatedAt      pgtype.Timestamptz `db:"updated_at" json:"updated_at"`
+	Queue          pgtype.Text        `db:"queue" json:"queue"`
+	LeaseExpiresAt pgtype.Timestamptz `db:"lease_expires_at" json:"lease_expires_at"`
+	TimeoutSeconds pgtype.Int4        `db:"timeout_seconds" json:"timeout_seconds"`
+	DeadlineAt     pgtype.Timestamptz `db:"deadline_at" json:"deadline_at"`
+	RetryPolicy    []byte             `db:"retry_policy" json:"retry_policy"`
+	ErrorCode      pgtype.Text        `db:"error_code" json:"error_code"`
+	AvailableAt    pgtype.Timestamptz `db:"available_at" json:"available_at"`
+	DeadLetteredAt pgtype.Timestamptz `db:"dead_lettered_at" json:"dead_lettered_at"`
+	TenantID       pgtype.UUID        `db:"tenant_id" json:"tenant_id"`
+	RunStatus      string             `db:"run_status" json:"run_status"`
+}
+
+func (q *Queries) GetTaskForUpdate(ctx context.Context, arg GetTaskForUpdateParams) (GetTaskForUpdateRow, error) {
+	row := q.db.QueryRow(ctx, getTaskForUpdate, arg.ID, arg.TenantID)
+	var i GetTaskForUpdateRow
+	err := row.Scan(
+		&i.ID,
+		&i.RunID,
+		&i.StepID,
+		&i.Status,
+		&i.WorkerID,
+		&i.Attempts,
+		&i.LastError,
+		&i.StartedAt,
+		&i.FinishedAt,
+		&i.Result,
+		&i.StepType,
+		&i.Input,
+		&i.DependsOn,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Queue,
+		&i.LeaseExpiresAt,
+		&i.TimeoutSeconds,
+		&i.DeadlineAt,
+		&i.RetryPolicy,
+		&i.ErrorCode,
+		&i.AvailableAt,
+		&i.DeadLetteredAt,
+		&i.TenantID,
+		&i.RunStatus,
+	)
+	return i, err
+}
+
+const getWebhookByTokenHash = `-- name: GetWebhookByTokenHash :one
+SELECT h.id, h.tenant_id, h.workflow_id, h.name, h.token_hash, h.secret, h.signature_header, h.signature_scheme, h.delivery_id_source, h.payload_mapping, h.workflow_version, h.created_at, h.updated_at, h.last_delivery_at, w.archived AS workflow_archived, t.status AS tenant_status
+FROM workflow_webhooks h
+         JOIN workflows w ON w.id = h.workflow_id
+         JOIN tenants t ON t.id = h.tenant_id
+WHERE h.token_hash = $1
+`
+
+type GetWebhookByTokenHashRow struct {
+	ID               pgtype.UUID        `db:"id" json:"id"`
+	TenantID         pgtype.UUID        `db:"tenant_id" json:"tenant_id"`
+	WorkflowID       pgtype.UUID        `db:"workflow_id" json:"workflow_id"`
+	Name             string             `db:"name" json:"name"`
+	TokenHash        string             `db:"token_hash" json:"token_hash"`
+	Secret           []byte             `db:"secret" json:"secret"`
+	SignatureHeader  string             `db:"signature_header" json:"signature_header"`
+	SignatureScheme  string             `db:"signature_scheme" json:"signature_scheme"`
+	DeliveryIDSource pgtype.Text        `db:"delivery_id_source" json:"delivery_id_source"`
+	PayloadMapping   []byte             `db:"payload_mapping" json:"payload_mapping"`
+	WorkflowVersion  pgtype.Int4        `db:"workflow_version" json:"workflow_version"`
+	CreatedAt        pgtype.Timestamptz `db:"created_at" json:"created_at"`
+	UpdatedAt        pgtype.Timestamptz `db:"updated_at" json:"updated_at"`
+	LastDeliveryAt   pgtype.Timestamptz `db:"last_delivery_at" json:"last_delivery_at"`
+	WorkflowArchived pgtype.Bool        `db:"workflow_archived" json:"workflow_archived"`
+	TenantStatus     NullTenantStatus   `db:"tenant_status" json:"tenant_status"`
+}
+
+func (q *Queries) GetWebhookByTokenHash(ctx context.Context, tokenHash string) (GetWebhookByTokenHashRow, error) {
+	row := q.db.QueryRow(ctx, getWebhookByTokenHash, tokenHash)
+	var i GetWebhookByTokenHashRow
+	err := row.Scan(
+		&i.ID,
+		&i.TenantID,
+		&i.WorkflowID,
+		&i.Name,
+		&i.TokenHash,
+		&i.Secret,
+		&i.SignatureHeader,
+		&i.SignatureScheme,
+		&i.DeliveryIDSource,
+		&i.PayloadMapping,
+		&i.WorkflowVersion,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.LastDeliveryAt,
+		&i.WorkflowArchived,
+		&i.TenantStatus,
+	)
+	return i, err
+}
+
+const getWebhookDelivery = `-- name: GetWebhookDelivery :one
+SELECT webhook_id, delivery_id, run_id, received_at
+FROM webhook_deliveries
+WHERE webhook_id = $1
+  AND delivery_id = $2
+`
+
+type GetWebhookDeliveryParams struct {
+	WebhookID  pgtype.UUID `db:"webhook_id" json:"webhook_id"`
+	DeliveryID string      `db:"delivery_id" json:"delivery_id"`
+}
+
+func (q *Queries) GetWebhookDelivery(ctx context.Context, arg GetWebhookDeliveryParams) (WebhookDelivery, error) {
+	row := q.db.QueryRow(ctx, getWebhookDelivery, arg.WebhookID, arg.DeliveryID)
+	var i WebhookDelivery
+	err := row.Scan(
+		&i.WebhookID,
+		&i.DeliveryID,
+		&i.RunID,
+		&i.ReceivedAt,
+	)
+	return i, err
+}
+
+const getWorkflowByID = `-- name: GetWorkflowByID :one
+SELECT id,
+       tenant_id,
+       name,
+       version,
+       definition,
+       created_at,
+       updated_at,
+       archived
+FROM workflows
+WHERE id = $1
+  AND tenant_id = $2
+`
+
+type GetWorkflowByIDParams struct {
+	ID       pgtype.UUID `db:"id" json:"id"`
+	TenantID pgtype.UUID `db:"tenant_id" json:"tenant_id"`
+}
+
+func (q *Queries) GetWorkflowByID(ctx context.Context, arg GetWorkflowByIDParams) (Workflow, error) {
+	row := q.db.QueryRow(ctx, getWorkflowByID, arg.ID, arg.TenantID)
+	var i Workflow
+	err := row.Scan(
+		&i.ID,
+		&i.TenantID,
+		&i.Name,
+		&i.Version,
+		&i.Definition,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Archived,
+	)
+	return i, err
+}
+
+const getWorkflowRunByID = `-- name: GetWorkflowRunByID :one
+SELECT id, tenant_id, workflow_id, status, started_at, finished_at, payload, metadata, result, error, updated_at, workflow_version, schedule_id, scheduled_at
+FROM workflow_runs
+WHERE id = $1
+  AND tenant_id = $2
+`
+
+type GetWorkflowRunByIDParams struct {
+	ID       pgtype.UUID `db:"id" json:"id"`
+	TenantID pgtype.UUID `db:"tenant_id" json:"tenant_id"`
+}
+
+func (q *Queries) GetWorkflowRunByID(ctx context.Context, arg GetWorkflowRunByIDParams) (WorkflowRun, error) {
+	row := q.db.QueryRow(ctx, getWorkflowRunByID, arg.ID, arg.TenantID)
+	var i WorkflowRun
+	err := row.Scan(
+		&i.ID,
+		&i.TenantID,
+		&i.WorkflowID,
+		&i.Status,
+		&i.StartedAt,
+		&i.FinishedAt,
+		&i.Payload,
+		&i.Metadata,
+		&i.Result,
+		&i.Error,
+		&i.UpdatedAt,
+		&i.WorkflowVersion,
+		&i.ScheduleID,
+		&i.ScheduledAt,
+	)
+	return i, err
+}
+
+const getWorkflowVersion = `-- name: GetWorkflowVersion :one
+SELECT v.workflow_id, v.version, v.definition, v.created_at
+FROM workflow_versions v
+         JOIN workflows w ON w.id = v.workflow_id
+WHERE v.workflow_id = $1
+  AND v.version = $2
+  AND w.tenant_id = $3
+`
+
+type GetWorkflowVersionParams struct {
+	WorkflowID pgtype.UUID `db:"workflow_id" json:"workflow_id"`
+	Version    int32       `db:"version" json:"version"`
+	TenantID   pgtype.UUID `db:"tenant_id" json:"tenant_id"`
+}
+
+func (q *Queries) GetWorkflowVersion(ctx context.Context, arg GetWorkflowVersionParams) (WorkflowVersion, error) {
+	row := q.db.QueryRow(ctx, getWorkflowVersion, arg.WorkflowID, arg.Version, arg.TenantID)
+	var i WorkflowVersion
+	err := row.Scan(
+		&i.WorkflowID,
+		&i.Version,
+		&i.Definition,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const listActiveScheduleRuns = `-- name: ListActiveScheduleRuns :many
+SELECT id
+FROM workflow_runs
+WHERE schedule_id = $1
+  AND status IN ('pending', 'running')
+    FOR UPDATE
+`
+
+func (q *Queries) ListActiveScheduleRuns(ctx context.Context, scheduleID pgtype.UUID) ([]pgtype.UUID, error) {
+	rows, err := q.db.Query(ctx, listActiveScheduleRuns, scheduleID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []pgtype.UUID
+	for rows.Next() {
+		var id pgtype.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listDeadLetterTasks = `-- name: ListDeadLetterTasks :many
+SELECT t.id, t.run_id, t.step_id, t.status, t.worker_id, t.attempts, t.last_error, t.started_at, t.finished_at, t.result, t.step_type, t.input, t.depends_on, t.created_at, t.updated_at, t.queue, t.lease_expires_at, t.timeout_seconds, t.deadline_at, t.retry_policy, t.error_code, t.available_at, t.dead_lettered_at
+FROM tasks t
+         JOIN workflow_runs r ON r.id = t.run_id
+WHERE r.tenant_id = $1
+  AND t.status = 'dead_lettered'
+  AND ($2::uuid IS NULL OR r.workflow_id = $2::uuid)
+ORDER BY t.dead_lettered_at DESC
+LIMIT $3
+`
+
+type ListDeadLetterTasksParams struct {
+	TenantID   pgtype.UUID `db:"tenant_id" json:"tenant_id"`
+	WorkflowID pgtype.UUID `db:"workflow_id" json:"workflow_id"`
+	MaxResults int32       `db:"max_results" json:"max_results"`
+}
+
+func (q *Queries) ListDeadLetterTasks(ctx context.Context, arg ListDeadLetterTasksParams) ([]Task, error) {
+	rows, err := q.db.Query(ctx, listDeadLetterTasks, arg.TenantID, arg.WorkflowID, arg.MaxResults)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Task
+	for rows.Next() {
+		var i Task
+		if err := rows.Scan(
+			&i.ID,
+			&i.RunID,
+			&i.StepID,
+			&i.Status,
+			&i.WorkerID,
+			&i.Attempts,
+			&i.LastError,
+			&i.StartedAt,
+			&i.FinishedAt,
+			&i.Result,
+			&i.StepType,
+			&i.Input,
+			&i.DependsOn,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Queue,
+			&i.LeaseExpiresAt,
+			&i.TimeoutSeconds,
+			&i.DeadlineAt,
+			&i.RetryPolicy,
+			&i.ErrorCode,
+			&i.AvailableAt,
+			&i.DeadLetteredAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listSchedules = `-- name: ListSchedules :many
+SELECT id, tenant_id, workflow_id, name, cron_expression, timezone, interval_seconds, workflow_version, payload, overlap_policy, catchup_window_seconds, paused, pause_reason, next_run_at, last_run_at, last_run_id, created_at, updated_at
+FROM workflow_schedules
+WHERE tenant_id = $1
+  AND ($2::uuid IS NULL OR workflow_id = $2)
+ORDER BY created_at, id
+LIMIT $3
+`
+
+type ListSchedulesParams struct {
+	TenantID   pgtype.UUID `db:"tenant_id" json:"tenant_id"`
+	WorkflowID pgtype.UUID `db:"workflow_id" json:"workflow_id"`
+	MaxResults int32       `db:"max_results" json:"max_results"`
+}
+
+func (q *Queries) ListSchedules(ctx context.Context, arg ListSchedulesParams) ([]WorkflowSchedule, error) {
+	rows, err := q.db.Query(ctx, listSchedules, arg.TenantID, arg.WorkflowID, arg.MaxResults)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WorkflowSchedule
+	for rows.Next() {
+		var i WorkflowSchedule
+		if err := rows.Scan(
+			&i.ID,
+			&i.TenantID,
+			&i.WorkflowID,
+			&i.Name,
+			&i.CronExpression,
+			&i.Timezone,
+			&i.IntervalSeconds,
+			&i.WorkflowVersion,
+			&i.Payload,
+			&i.OverlapPolicy,
+			&i.CatchupWindowSeconds,
+			&i.Paused,
+			&i.PauseReason,
+			&i.NextRunAt,
+			&i.LastRunAt,
+			&i.LastRunID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listSubtreeTenantIDs = `-- name: ListSubtreeTenantIDs :many
+WITH RECURSIVE subtree AS (SELECT tenants.id
+                           FROM tenants
+                           WHERE tenants.id = $1
+                           UNION ALL
+                           SELECT c.id
+                           FROM tenants c
+                                    JOIN subtree s ON c.parent_id = s.id)
+SELECT id
+FROM subtree
 `
 
-type CreateWorkflowParams struct {
-	TenantID   pgtype.UUID `db:"tenant_id" json:"tenant_id"`
-	Name       string      `db:"name" json:"name"`
-	Definition []byte      `db:"definition" json:"definition"`
+func (q *Queries) ListSubtreeTenantIDs(ctx context.Context, id pgtype.UUID) ([]pgtype.UUID, error) {
+	rows, err := q.db.Query(ctx, listSubtreeTenantIDs, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []pgtype.UUID
+	for rows.Next() {
+		var id pgtype.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
-type CreateWorkflowRow struct {
-	ID       pgtype.UUID `db:"id" json:"id"`
-	TenantID pgtype.UUID `db:"tenant_id" json:"tenant_id"`
-	Name     string      `db:"name" json:"name"`
+const listTasksByRunID = `-- name: ListTasksByRunID :many
+SELECT id, run_id, step_id, status, worker_id, attempts, last_error, started_at, finished_at, result, step_type, input, depends_on, created_at, updated_at, queue, lease_expires_at, timeout_seconds, deadline_at, retry_policy, error_code, available_at, dead_lettered_at
+FROM tasks
+WHERE run_id = $1
+ORDER BY created_at, step_id
+`
+
+func (q *Queries) ListTasksByRunID(ctx context.Context, runID pgtype.UUID) ([]Task, error) {
+	rows, err := q.db.Query(ctx, listTasksByRunID, runID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Task
+	for rows.Next() {
+		var i Task
+		if err := rows.Scan(
+			&i.ID,
+			&i.RunID,
+			&i.StepID,
+			&i.Status,
+			&i.WorkerID,
+			&i.Attempts,
+			&i.LastError,
+			&i.StartedAt,
+			&i.FinishedAt,
+			&i.Result,
+			&i.StepType,
+			&i.Input,
+			&i.DependsOn,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Queue,
+			&i.LeaseExpiresAt,
+			&i.TimeoutSeconds,
+			&i.DeadlineAt,
+			&i.RetryPolicy,
+			&i.ErrorCode,
+			&i.AvailableAt,
+			&i.DeadLetteredAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
-func (q *Queries) CreateWorkflow(ctx context.Context, arg CreateWorkflowParams) (CreateWorkflowRow, error) {
-	row := q.db.QueryRow(ctx, createWorkflow, arg.TenantID, arg.Name, arg.Definition)
-	var i CreateWorkflowRow
-	err := row.Scan(&i.ID, &i.TenantID, &i.Name)
-	return i, err
+const listWebhooks = `-- name: ListWebhooks :many
+SELECT id, tenant_id, workflow_id, name, token_hash, secret, signature_header, signature_scheme, delivery_id_source, payload_mapping, workflow_version, created_at, updated_at, last_delivery_at
+FROM workflow_webhooks
+WHERE tenant_id = $1
+  AND ($2::uuid IS NULL OR workflow_id = $2)
+ORDER BY created_at, id
+LIMIT $3
+`
+
+type ListWebhooksParams struct {
+	TenantID   pgtype.UUID `db:"tenant_id" json:"tenant_id"`
+	WorkflowID pgtype.UUID `db:"workflow_id" json:"workflow_id"`
+	MaxResults int32       `db:"max_results" json:"max_results"`
 }
 
-const getWorkflowByID = `-- name: GetWorkflowByID :one
-SELECT id,
-       tenant_id,
-       name,
-       version,
-       definition,
-       created_at,
-       updated_at,
-       archived
-FROM workflows
-WHERE id = $1
+func (q *Queries) ListWebhooks(ctx context.Context, arg ListWebhooksParams) ([]WorkflowWebhook, error) {
+	rows, err := q.db.Query(ctx, listWebhooks, arg.TenantID, arg.WorkflowID, arg.MaxResults)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WorkflowWebhook
+	for rows.Next() {
+		var i WorkflowWebhook
+		if err := rows.Scan(
+			&i.ID,
+			&i.TenantID,
+			&i.WorkflowID,
+			&i.Name,
+			&i.TokenHash,
+			&i.Secret,
+			&i.SignatureHeader,
+			&i.SignatureScheme,
+			&i.DeliveryIDSource,
+			&i.PayloadMapping,
+			&i.WorkflowVersion,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.LastDeliveryAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listWorkflowVersions = `-- name: ListWorkflowVersions :many
+SELECT v.workflow_id, v.version, v.definition, v.created_at
+FROM workflow_versions v
+         JOIN workflows w ON w.id = v.workflow_id
+WHERE v.workflow_id = $1
+  AND w.tenant_id = $2
+ORDER BY v.version DESC
 `
 
-func (q *Queries) GetWorkflowByID(ctx context.Context, id pgtype.UUID) (Workflow, error) {
-	row := q.db.QueryRow(ctx, getWorkflowByID, id)
-	var i Workflow
-	err := row.Scan(
-		&i.ID,
-		&i.TenantID,
-		&i.Name,
-		&i.Version,
-		&i.Definition,
-		&i.CreatedAt,
-		&i.UpdatedAt,
-		&i.Archived,
-	)
-	return i, err
+type ListWorkflowVersionsParams struct {
+	WorkflowID pgtype.UUID `db:"workflow_id" json:"workflow_id"`
+	TenantID   pgtype.UUID `db:"tenant_id" json:"tenant_id"`
+}
+
+func (q *Queries) ListWorkflowVersions(ctx context.Context, arg ListWorkflowVersionsParams) ([]WorkflowVersion, error) {
+	rows, err := q.db.Query(ctx, listWorkflowVersions, arg.WorkflowID, arg.TenantID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WorkflowVersion
+	for rows.Next() {
+		var i WorkflowVersion
+		if err := rows.Scan(
+			&i.WorkflowID,
+			&i.Version,
+			&i.Definition,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
 const listWorkflowsByTenantID = `-- name: ListWorkflowsByTenantID :many
@@ -75,26 +1672,26 @@ SELECT id,
        updated_at,
        archived
 FROM workflows
-WHERE tenant_id = $1
+WHERE tenant_id = ANY ($1::uuid[])
   AND ((updated_at < $2)
     OR (updated_at = $2 AND id < $3))
-ORDER BY updated_at DESC
+ORDER BY updated_at DESC, id DESC
 LIMIT $4
 `
 
 type ListWorkflowsByTenantIDParams struct {
-	TenantID  pgtype.UUID        `db:"tenant_id" json:"tenant_id"`
-	UpdatedAt pgtype.Timestamptz `db:"updated_at" json:"updated_at"`
-	ID        pgtype.UUID        `db:"id" json:"id"`
-	Limit     int32              `db:"limit" json:"limit"`
+	TenantIds  []pgtype.UUID      `db:"tenant_ids" json:"tenant_ids"`
+	UpdatedAt  pgtype.Timestamptz `db:"updated_at" json:"updated_at"`
+	ID         pgtype.UUID        `db:"id" json:"id"`
+	MaxResults int32              `db:"max_results" json:"max_results"`
 }
 
 func (q *Queries) ListWorkflowsByTenantID(ctx context.Context, arg ListWorkflowsByTenantIDParams) ([]Workflow, error) {
 	rows, err := q.db.Query(ctx, listWorkflowsByTenantID,
-		arg.TenantID,
+		arg.TenantIds,
 		arg.UpdatedAt,
 		arg.ID,
-		arg.Limit,
+		arg.MaxResults,
 	)
 	if err != nil {
 		return nil, err
@@ -122,3 +1719,352 @@ func (q *Queries) ListWorkflowsByTenantID(ctx context.Context, arg ListWorkflows
 	}
 	return items, nil
 }
+
+const lockEventStreams = `-- name: LockEventStreams :exec
+SELECT pg_advisory_xact_lock(k.key)
+FROM (SELECT DISTINCT hashtext('events:' || t.id::text) AS key
+      FROM unnest($1::uuid[]) AS t(id)
+      ORDER BY key) k
+`
+
+// Takes the append locks of several tenants in a fixed order, for batches that
+// append events to more than one of them.
+func (q *Queries) LockEventStreams(ctx context.Context, tenantIds []pgtype.UUID) error {
+	_, err := q.db.Exec(ctx, lockEventStreams, tenantIds)
+	return err
+}
+
+const lockTenantQuota = `-- name: LockTenantQuota :exec
+SELECT pg_advisory_xact_lock(hashtext('quota:' || $1::text))
+`
+
+func (q *Queries) LockTenantQuota(ctx context.Context, tenantID string) error {
+	_, err := q.db.Exec(ctx, lockTenantQuota, tenantID)
+	return err
+}
+
+const markRunRunning = `-- name: MarkRunRunning :exec
+UPDATE workflow_runs
+SET status     = 'running',
+    updated_at = now()
+WHERE id = $1
+`
+
+func (q *Queries) MarkRunRunning(ctx context.Context, id pgtype.UUID) error {
+	_, err := q.db.Exec(ctx, markRunRunning, id)
+	return err
+}
+
+const promoteReadyTasks = `-- name: PromoteReadyTasks :execrows
+UPDATE tasks t
+SET status     = 'queued',
+    updated_at = now()
+WHERE t.status = 'pending'
+  AND NOT EXISTS (SELECT 1
+                  FROM tasks d
+                  WHERE d.run_id = t.run_id
+                    AND d.step_id = ANY (t.depends_on)
+                    AND d.status <> 'succeeded')
+`
+
+func (q *Queries) PromoteReadyTasks(ctx context.Context) (int64, error) {
+	result, err := q.db.Exec(ctx, promoteReadyTasks)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const recordUsage = `-- name: RecordUsage :exec
+INSERT INTO usage_records (tenant_id, metric, value)
+VALUES ($1, $2::text, $3::float8)
+`
+
+type RecordUsageParams struct {
+	TenantID pgtype.UUID `db:"tenant_id" json:"tenant_id"`
+	Metric   string      `db:"metric" json:"metric"`
+	Value    float64     `db:"value" json:"value"`
+}
+
+func (q *Queries) RecordUsage(ctx context.Context, arg RecordUsageParams) error {
+	_, err := q.db.Exec(ctx, recordUsage, arg.TenantID, arg.Metric, arg.Value)
+	return err
+}
+
+const redriveTask = `-- name: RedriveTask :one
+UPDATE tasks
+SET status           = 'queued',
+    attempts         = CASE WHEN $1::boolean THEN 0 ELSE attempts END,
+    worker_id        = NULL,
+    started_at       = NULL,
+    finished_at      = NULL,
+    available_at     = NULL,
+    dead_lettered_at = NULL,
+    updated_at       = now()
+WHERE id = $2
+RETURNING id, run_id, step_id, status, worker_id, attempts, last_error, started_at, finished_at, result, step_type, input, depends_on, created_at, updated_at, queue, lease_expires_at, timeout_seconds, deadline_at, retry_policy, error_code, available_at, dead_lettered_at
+`
+
+type RedriveTaskParams struct {
+	ResetAttempts bool        `db:"reset_attempts" json:"reset_attempts"`
+	ID            pgtype.UUID `db:"id" json:"id"`
+}
+
+func (q *Queries) RedriveTask(ctx context.Context, arg RedriveTaskParams) (Task, error) {
+	row := q.db.QueryRow(ctx, redriveTask, arg.ResetAttempts, arg.ID)
+	var i Task
+	err := row.Scan(
+		&i.ID,
+		&i.RunID,
+		&i.StepID,
+		&i.Status,
+		&i.WorkerID,
+		&i.Attempts,
+		&i.LastError,
+		&i.StartedAt,
+		&i.FinishedAt,
+		&i.Result,
+		&i.StepType,
+		&i.Input,
+		&i.DependsOn,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Queue,
+		&i.LeaseExpiresAt,
+		&i.TimeoutSeconds,
+		&i.DeadlineAt,
+		&i.RetryPolicy,
+		&i.ErrorCode,
+		&i.AvailableAt,
+		&i.DeadLetteredAt,
+	)
+	return i, err
+}
+
+const reopenRun = `-- name: ReopenRun :exec
+UPDATE workflow_runs
+SET status      = 'running',
+    error       = NULL,
+    finished_at = NULL,
+    updated_at  = now()
+WHERE id = $1
+`
+
+func (q *Queries) ReopenRun(ctx context.Context, id pgtype.UUID) error {
+	_, err := q.db.Exec(ctx, reopenRun, id)
+	return err
+}
+
+const requeueExpiredTasks = `-- name: RequeueExpiredTasks :execrows
+UPDATE tasks
+SET status           = 'queued',
+    worker_id        = NULL,
+    attempts         = greatest(attempts - 1, 0),
+    lease_expires_at = NULL,
+    deadline_at      = NULL,
+    updated_at       = now()
+WHERE status = 'running'
+  AND lease_expires_at < now()
+`
+
+// A lost lease means the worker went away, not that the step failed, so the
+// claimed attempt is handed back instead of counting against the retries.
+func (q *Queries) RequeueExpiredTasks(ctx context.Context) (int64, error) {
+	result, err := q.db.Exec(ctx, requeueExpiredTasks)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const resumeCancelledTasks = `-- name: ResumeCancelledTasks :exec
+UPDATE tasks
+SET status     = 'pending',
+    updated_at = now()
+WHERE run_id = $1
+  AND status = 'cancelled'
+`
+
+func (q *Queries) ResumeCancelledTasks(ctx context.Context, runID pgtype.UUID) error {
+	_, err := q.db.Exec(ctx, resumeCancelledTasks, runID)
+	return err
+}
+
+const scheduleTaskRetry = `-- name: ScheduleTaskRetry :exec
+UPDATE tasks
+SET status       = 'queued',
+    worker_id    = NULL,
+    started_at   = NULL,
+    finished_at  = NULL,
+    available_at = $2,
+    updated_at   = now()
+WHERE id = $1
+`
+
+type ScheduleTaskRetryParams struct {
+	ID          pgtype.UUID        `db:"id" json:"id"`
+	AvailableAt pgtype.Timestamptz `db:"available_at" json:"available_at"`
+}
+
+func (q *Queries) ScheduleTaskRetry(ctx context.Context, arg ScheduleTaskRetryParams) error {
+	_, err := q.db.Exec(ctx, scheduleTaskRetry, arg.ID, arg.AvailableAt)
+	return err
+}
+
+const setSchedulePaused = `-- name: SetSchedulePaused :one
+UPDATE workflow_schedules
+SET paused       = $3,
+    pause_reason = $4,
+    next_run_at  = $5,
+    updated_at   = now()
+WHERE id = $1
+  AND tenant_id = $2
+RETURNING id, tenant_id, workflow_id, name, cron_expression, timezone, interval_seconds, workflow_version, payload, overlap_policy, catchup_window_seconds, paused, pause_reason, next_run_at, last_run_at, last_run_id, created_at, updated_at
+`
+
+type SetSchedulePausedParams struct {
+	ID          pgtype.UUID        `db:"id" json:"id"`
+	TenantID    pgtype.UUID        `db:"tenant_id" json:"tenant_id"`
+	Paused      bool               `db:"paused" json:"paused"`
+	PauseReason pgtype.Text        `db:"pause_reason" json:"pause_reason"`
+	NextRunAt   pgtype.Timestamptz `db:"next_run_at" json:"next_run_at"`
+}
+
+func (q *Queries) SetSchedulePaused(ctx context.Context, arg SetSchedulePausedParams) (WorkflowSchedule, error) {
+	row := q.db.QueryRow(ctx, setSchedulePaused,
+		arg.ID,
+		arg.TenantID,
+		arg.Paused,
+		arg.PauseReason,
+		arg.NextRunAt,
+	)
+	var i WorkflowSchedule
+	err := row.Scan(
+		&i.ID,
+		&i.TenantID,
+		&i.WorkflowID,
+		&i.Name,
+		&i.CronExpression,
+		&i.Timezone,
+		&i.IntervalSeconds,
+		&i.WorkflowVersion,
+		&i.Payload,
+		&i.OverlapPolicy,
+		&i.CatchupWindowSeconds,
+		&i.Paused,
+		&i.PauseReason,
+		&i.NextRunAt,
+		&i.LastRunAt,
+		&i.LastRunID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const setWebhookDeliveryRun = `-- name: SetWebhookDeliveryRun :exec
+UPDATE webhook_deliveries
+SET run_id = $3
+WHERE webhook_id = $1
+  AND delivery_id = $2
+`
+
+type SetWebhookDeliveryRunParams struct {
+	WebhookID  pgtype.UUID `db:"webhook_id" json:"webhook_id"`
+	DeliveryID string      `db:"delivery_id" json:"delivery_id"`
+	RunID      pgtype.UUID `db:"run_id" json:"run_id"`
+}
+
+func (q *Queries) SetWebhookDeliveryRun(ctx context.Context, arg SetWebhookDeliveryRunParams) error {
+	_, err := q.db.Exec(ctx, setWebhookDeliveryRun, arg.WebhookID, arg.DeliveryID, arg.RunID)
+	return err
+}
+
+const touchWebhook = `-- name: TouchWebhook :exec
+UPDATE workflow_webhooks
+SET last_delivery_at = now()
+WHERE id = $1
+`
+
+func (q *Queries) TouchWebhook(ctx context.Context, id pgtype.UUID) error {
+	_, err := q.db.Exec(ctx, touchWebhook, id)
+	return err
+}
+
+const tryLockScheduler = `-- name: TryLockScheduler :one
+SELECT pg_try_advisory_lock(hashtext('workflow_scheduler'))
+`
+
+func (q *Queries) TryLockScheduler(ctx context.Context) (bool, error) {
+	row := q.db.QueryRow(ctx, tryLockScheduler)
+	var pg_try_advisory_lock bool
+	err := row.Scan(&pg_try_advisory_lock)
+	return pg_try_advisory_lock, err
+}
+
+const unlockScheduler = `-- name: UnlockScheduler :exec
+SELECT pg_advisory_unlock(hashtext('workflow_scheduler'))
+`
+
+func (q *Queries) UnlockScheduler(ctx context.Context) error {
+	_, err := q.db.Exec(ctx, unlockScheduler)
+	return err
+}
+
+const updateWorkflowDefinition = `-- name: UpdateWorkflowDefinition :one
+WITH previous AS (SELECT w.id, octet_length(w.definition::text) AS definition_bytes
+                  FROM workflows w
+                  WHERE w.id = $3
+                    AND w.tenant_id = $4
+                  FOR UPDATE)
+UPDATE workflows
+SET version    = version + 1,
+    name       = coalesce($1, name),
+    definition = $2,
+    updated_at = now()
+FROM previous
+WHERE workflows.id = previous.id
+RETURNING workflows.id,
+          workflows.tenant_id,
+          workflows.name,
+          workflows.version,
+          workflows.archived,
+          (octet_length(workflows.definition::text) - previous.definition_bytes)::bigint AS definition_bytes_delta
+`
+
+type UpdateWorkflowDefinitionParams struct {
+	Name       pgtype.Text `db:"name" json:"name"`
+	Definition []byte      `db:"definition" json:"definition"`
+	ID         pgtype.UUID `db:"id" json:"id"`
+	TenantID   pgtype.UUID `db:"tenant_id" json:"tenant_id"`
+}
+
+type UpdateWorkflowDefinitionRow struct {
+	ID                   pgtype.UUID `db:"id" json:"id"`
+	TenantID             pgtype.UUID `db:"tenant_id" json:"tenant_id"`
+	Name                 string      `db:"name" json:"name"`
+	Version              pgtype.Int4 `db:"version" json:"version"`
+	Archived             pgtype.Bool `db:"archived" json:"archived"`
+	DefinitionBytesDelta int64       `db:"definition_bytes_delta" json:"definition_bytes_delta"`
+}
+
+// definition_bytes_delta is the change in stored definition size, which is
+// what definition usage meters.
+func (q *Queries) UpdateWorkflowDefinition(ctx context.Context, arg UpdateWorkflowDefinitionParams) (UpdateWorkflowDefinitionRow, error) {
+	row := q.db.QueryRow(ctx, updateWorkflowDefinition,
+		arg.Name,
+		arg.Definition,
+		arg.ID,
+		arg.TenantID,
+	)
+	var i UpdateWorkflowDefinitionRow
+	err := row.Scan(
+		&i.ID,
+		&i.TenantID,
+		&i.Name,
+		&i.Version,
+		&i.Archived,
+		&i.DefinitionBytesDelta,
+	)
+	return i, err
+}
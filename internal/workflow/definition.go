@@ -0,0 +1,211 @@
+package workflow
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	workflowv1 "github.com/vantutran2k1/rwe/gen/go/workflow/v1"
+	apierrors "github.com/vantutran2k1/rwe/internal/common/errors"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+const stepTypeTask = "task"
+
+var stepIDRegex = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9_-]{0,63}$`)
+
+func parseDefinition(raw []byte, strict bool) (*workflowv1.WorkflowDefinition, error) {
+	var def workflowv1.WorkflowDefinition
+	opts := protojson.UnmarshalOptions{DiscardUnknown: !strict}
+	if err := opts.Unmarshal(raw, &def); err != nil {
+		return nil, fmt.Errorf("malformed definition: %w", err)
+	}
+
+	return &def, nil
+}
+
+func isKnownStepType(stepType string) bool {
+	if stepType == stepTypeTask {
+		return true
+	}
+
+	_, ok := builtinHandlers()[stepType]
+	return ok
+}
+
+func validateDefinition(def *workflowv1.WorkflowDefinition) apierrors.FieldViolations {
+	var violations apierrors.FieldViolations
+
+	if len(def.Steps) == 0 {
+		violations.Add("definition.steps", "at least one step is required")
+		return violations
+	}
+
+	index := make(map[string]int, len(def.Steps))
+	for i, st := range def.Steps {
+		field := fmt.Sprintf("definition.steps[%d]", i)
+
+		switch {
+		case st.Id == "":
+			violations.Add(field+".id", "step id must not be empty")
+		case !stepIDRegex.MatchString(st.Id):
+			violations.Add(field+".id", "step id must start with a letter and contain only letters, digits, '_' or '-'")
+		default:
+			if j, ok := index[st.Id]; ok {
+				violations.Add(field+".id", fmt.Sprintf("duplicate step id %q, already used by definition.steps[%d]", st.Id, j))
+			} else {
+				index[st.Id] = i
+			}
+		}
+
+		if !isKnownStepType(st.Type) {
+			violations.Add(field+".type", fmt.Sprintf("unknown step type %q", st.Type))
+		}
+
+		if st.Type == stepTypeTask && st.Queue == "" {
+			violations.Add(field+".queue", "queue is required for task steps")
+		} else if st.Type != stepTypeTask && st.Queue != "" {
+			violations.Add(field+".queue", "queue is only allowed for task steps")
+		}
+
+		outputs := make(map[string]bool, len(st.Outputs))
+		for j, out := range st.Outputs {
+			outField := fmt.Sprintf("%s.outputs[%d]", field, j)
+			if out == "" {
+				violations.Add(outField, "output name must not be empty")
+			} else if outputs[out] {
+				violations.Add(outField, fmt.Sprintf("duplicate output %q", out))
+			}
+			outputs[out] = true
+		}
+
+		if st.Retry != nil {
+			validateRetryPolicy(st.Retry, field+".retry", &violations)
+		}
+
+		if st.Timeout != nil {
+			if err := st.Timeout.CheckValid(); err != nil {
+				violations.Add(field+".timeout", err.Error())
+			} else if st.Timeout.AsDuration() <= 0 {
+				violations.Add(field+".timeout", "timeout must be positive")
+			}
+		}
+	}
+
+	for i, st := range def.Steps {
+		seen := make(map[string]bool, len(st.DependsOn))
+		for j, dep := range st.DependsOn {
+			field := fmt.Sprintf("definition.steps[%d].depends_on[%d]", i, j)
+			switch {
+			case dep == st.Id:
+				violations.Add(field, "step must not depend on itself")
+			case seen[dep]:
+				violations.Add(field, fmt.Sprintf("duplicate dependency %q", dep))
+			default:
+				if _, ok := index[dep]; !ok {
+					violations.Add(field, fmt.Sprintf("unknown step %q", dep))
+				}
+			}
+			seen[dep] = true
+		}
+	}
+
+	if cycle := findCycle(def, index); cycle != nil {
+		violations.Add(
+			fmt.Sprintf("definition.steps[%d].depends_on", index[cycle[0]]),
+			fmt.Sprintf("dependency cycle detected: %s", strings.Join(cycle, " -> ")),
+		)
+	}
+
+	return violations
+}
+
+func validateRetryPolicy(policy *workflowv1.RetryPolicy, field string, violations *apierrors.FieldViolations) {
+	if policy.MaxAttempts < 0 {
+		violations.Add(field+".max_attempts", "max attempts must not be negative")
+	}
+
+	var initial, maxBackoff time.Duration
+	if policy.InitialBackoff != nil {
+		if err := policy.InitialBackoff.CheckValid(); err != nil {
+			violations.Add(field+".initial_backoff", err.Error())
+		} else if initial = policy.InitialBackoff.AsDuration(); initial <= 0 {
+			violations.Add(field+".initial_backoff", "initial backoff must be positive")
+		}
+	}
+
+	if policy.MaxBackoff != nil {
+		if err := policy.MaxBackoff.CheckValid(); err != nil {
+			violations.Add(field+".max_backoff", err.Error())
+		} else if maxBackoff = policy.MaxBackoff.AsDuration(); maxBackoff <= 0 {
+			violations.Add(field+".max_backoff", "max backoff must be positive")
+		} else if initial > 0 && maxBackoff < initial {
+			violations.Add(field+".max_backoff", "max backoff must not be less than initial backoff")
+		}
+	}
+
+	if policy.BackoffMultiplier != 0 && policy.BackoffMultiplier < 1 {
+		violations.Add(field+".backoff_multiplier", "backoff multiplier must be at least 1")
+	}
+
+	if policy.Jitter < 0 || policy.Jitter > 1 {
+		violations.Add(field+".jitter", "jitter must be between 0 and 1")
+	}
+
+	for i, code := range policy.NonRetryableErrorCodes {
+		if code == "" {
+			violations.Add(fmt.Sprintf("%s.non_retryable_error_codes[%d]", field, i), "error code must not be empty")
+		}
+	}
+}
+
+func findCycle(def *workflowv1.WorkflowDefinition, index map[string]int) []string {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+
+	state := make(map[string]int, len(index))
+	var path []string
+
+	var visit func(id string) []string
+	visit = func(id string) []string {
+		state[id] = visiting
+		path = append(path, id)
+
+		for _, dep := range def.Steps[index[id]].DependsOn {
+			if _, ok := index[dep]; !ok || dep == id {
+				continue
+			}
+
+			switch state[dep] {
+			case visiting:
+				for k, p := range path {
+					if p == dep {
+						return append(append([]string{}, path[k:]...), dep)
+					}
+				}
+			case unvisited:
+				if cycle := visit(dep); cycle != nil {
+					return cycle
+				}
+			}
+		}
+
+		path = path[:len(path)-1]
+		state[id] = visited
+		return nil
+	}
+
+	for _, st := range def.Steps {
+		if _, ok := index[st.Id]; ok && state[st.Id] == unvisited {
+			if cycle := visit(st.Id); cycle != nil {
+				return cycle
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,122 @@
+package workflow
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestValidateDefinition(t *testing.T) {
+	tests := []struct {
+		name       string
+		definition string
+		// Fields of the expected violations, in order.
+		want []string
+	}{
+		{
+			name: "valid",
+			definition: `{"steps": [
+				{"id": "fetch", "type": "task", "queue": "default", "outputs": ["url"]},
+				{"id": "log", "type": "echo", "depends_on": ["fetch"], "timeout": "30s"},
+				{"id": "done", "type": "noop", "depends_on": ["fetch", "log"]}
+			]}`,
+		},
+		{
+			name:       "no steps",
+			definition: `{"steps": []}`,
+			want:       []string{"definition.steps"},
+		},
+		{
+			name:       "empty step id",
+			definition: `{"steps": [{"type": "noop"}]}`,
+			want:       []string{"definition.steps[0].id"},
+		},
+		{
+			name:       "invalid step id",
+			definition: `{"steps": [{"id": "1st", "type": "noop"}]}`,
+			want:       []string{"definition.steps[0].id"},
+		},
+		{
+			name:       "duplicate step id",
+			definition: `{"steps": [{"id": "a", "type": "noop"}, {"id": "a", "type": "noop"}]}`,
+			want:       []string{"definition.steps[1].id"},
+		},
+		{
+			name:       "unknown step type",
+			definition: `{"steps": [{"id": "a", "type": "shell"}]}`,
+			want:       []string{"definition.steps[0].type"},
+		},
+		{
+			name:       "task without queue",
+			definition: `{"steps": [{"id": "a", "type": "task"}]}`,
+			want:       []string{"definition.steps[0].queue"},
+		},
+		{
+			name:       "queue on builtin step",
+			definition: `{"steps": [{"id": "a", "type": "noop", "queue": "default"}]}`,
+			want:       []string{"definition.steps[0].queue"},
+		},
+		{
+			name:       "empty and duplicate outputs",
+			definition: `{"steps": [{"id": "a", "type": "noop", "outputs": ["", "x", "x"]}]}`,
+			want:       []string{"definition.steps[0].outputs[0]", "definition.steps[0].outputs[2]"},
+		},
+		{
+			name:       "non positive timeout",
+			definition: `{"steps": [{"id": "a", "type": "noop", "timeout": "0s"}]}`,
+			want:       []string{"definition.steps[0].timeout"},
+		},
+		{
+			name:       "self dependency",
+			definition: `{"steps": [{"id": "a", "type": "noop", "depends_on": ["a"]}]}`,
+			want:       []string{"definition.steps[0].depends_on[0]"},
+		},
+		{
+			name:       "duplicate dependency",
+			definition: `{"steps": [{"id": "a", "type": "noop"}, {"id": "b", "type": "noop", "depends_on": ["a", "a"]}]}`,
+			want:       []string{"definition.steps[1].depends_on[1]"},
+		},
+		{
+			name:       "unknown dependency",
+			definition: `{"steps": [{"id": "a", "type": "noop", "depends_on": ["b"]}]}`,
+			want:       []string{"definition.steps[0].depends_on[0]"},
+		},
+		{
+			name: "dependency cycle",
+			definition: `{"steps": [
+				{"id": "a", "type": "noop", "depends_on": ["c"]},
+				{"id": "b", "type": "noop", "depends_on": ["a"]},
+				{"id": "c", "type": "noop", "depends_on": ["b"]}
+			]}`,
+			want: []string{"definition.steps[0].depends_on"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			def, err := parseDefinition([]byte(tt.definition), true)
+			if err != nil {
+				t.Fatalf("parseDefinition: %v", err)
+			}
+
+			var got []string
+			for _, v := range validateDefinition(def) {
+				got = append(got, v.Field)
+			}
+
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("got violations %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseDefinitionStrict(t *testing.T) {
+	raw := []byte(`{"steps": [{"id": "a", "type": "noop", "unknown": true}]}`)
+
+	if _, err := parseDefinition(raw, true); err == nil {
+		t.Error("strict parsing accepted an unknown field")
+	}
+	if _, err := parseDefinition(raw, false); err != nil {
+		t.Errorf("lenient parsing rejected an unknown field: %v", err)
+	}
+}
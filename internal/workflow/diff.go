@@ -0,0 +1,82 @@
+package workflow
+
+import (
+	"sort"
+
+	workflowv1 "github.com/vantutran2k1/rwe/gen/go/workflow/v1"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+const (
+	stepChangeAdded    = "added"
+	stepChangeRemoved  = "removed"
+	stepChangeModified = "modified"
+)
+
+func diffDefinitions(from, to *workflowv1.WorkflowDefinition) []*workflowv1.StepDiff {
+	fromSteps := indexSteps(from)
+	toSteps := indexSteps(to)
+
+	diffs := make([]*workflowv1.StepDiff, 0)
+	for id, st := range toSteps {
+		prev, ok := fromSteps[id]
+		if !ok {
+			diffs = append(diffs, &workflowv1.StepDiff{StepId: id, Change: stepChangeAdded})
+			continue
+		}
+
+		if changed := changedFields(prev, st); len(changed) > 0 {
+			diffs = append(diffs, &workflowv1.StepDiff{
+				StepId:        id,
+				Change:        stepChangeModified,
+				ChangedFields: changed,
+			})
+		}
+	}
+
+	for id := range fromSteps {
+		if _, ok := toSteps[id]; !ok {
+			diffs = append(diffs, &workflowv1.StepDiff{StepId: id, Change: stepChangeRemoved})
+		}
+	}
+
+	sort.Slice(diffs, func(i, j int) bool {
+		return diffs[i].StepId < diffs[j].StepId
+	})
+
+	return diffs
+}
+
+func indexSteps(def *workflowv1.WorkflowDefinition) map[string]*workflowv1.Step {
+	steps := make(map[string]*workflowv1.Step, len(def.Steps))
+	for _, st := range def.Steps {
+		steps[st.Id] = st
+	}
+
+	return steps
+}
+
+func changedFields(a, b *workflowv1.Step) []string {
+	ra, rb := a.ProtoReflect(), b.ProtoReflect()
+	fields := ra.Descriptor().Fields()
+
+	var changed []string
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		if !proto.Equal(fieldOnly(ra, fd), fieldOnly(rb, fd)) {
+			changed = append(changed, string(fd.Name()))
+		}
+	}
+
+	return changed
+}
+
+func fieldOnly(m protoreflect.Message, fd protoreflect.FieldDescriptor) proto.Message {
+	out := m.New()
+	if m.Has(fd) {
+		out.Set(fd, m.Get(fd))
+	}
+
+	return out.Interface()
+}
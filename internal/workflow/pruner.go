@@ -0,0 +1,65 @@
+package workflow
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/vantutran2k1/rwe/internal/common/utils"
+	sqlc "github.com/vantutran2k1/rwe/internal/workflow/db"
+)
+
+// DeliveryPruner deletes webhook deliveries older than the retention, after
+// which a redelivery starts a new run.
+type DeliveryPruner struct {
+	querier   sqlc.Querier
+	retention time.Duration
+	interval  time.Duration
+	batchSize int32
+}
+
+func NewDeliveryPruner(pool *pgxpool.Pool, retention, interval time.Duration, batchSize int32) *DeliveryPruner {
+	return &DeliveryPruner{
+		querier:   sqlc.New(pool),
+		retention: retention,
+		interval:  interval,
+		batchSize: batchSize,
+	}
+}
+
+func (p *DeliveryPruner) Run(ctx context.Context) {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := p.prune(ctx); err != nil && ctx.Err() == nil {
+			slog.Error("webhook delivery pruning failed", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// prune deletes in batches so that a large backlog does not hold locks for
+// long.
+func (p *DeliveryPruner) prune(ctx context.Context) error {
+	before := utils.TimeToPgTimestamptz(time.Now().Add(-p.retention))
+
+	for {
+		deleted, err := p.querier.DeleteExpiredWebhookDeliveries(ctx, sqlc.DeleteExpiredWebhookDeliveriesParams{
+			ReceivedBefore: before,
+			BatchSize:      p.batchSize,
+		})
+		if err != nil {
+			return err
+		}
+		if deleted < int64(p.batchSize) {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package workflow
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	apierrors "github.com/vantutran2k1/rwe/internal/common/errors"
+	"github.com/vantutran2k1/rwe/internal/common/utils"
+	"github.com/vantutran2k1/rwe/internal/quota"
+	sqlc "github.com/vantutran2k1/rwe/internal/workflow/db"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (s *Service) limits(ctx context.Context, tenantID pgtype.UUID) (quota.Limits, error) {
+	limits, err := s.quotas.Limits(ctx, uuid.UUID(tenantID.Bytes))
+	if err != nil {
+		return limits, status.Errorf(codes.Internal, "error getting quotas: %v", err)
+	}
+
+	return limits, nil
+}
+
+func checkDefinitionSize(tenantID pgtype.UUID, limits quota.Limits, definition []byte) error {
+	if quota.Allows(limits.MaxDefinitionBytes, 0, int64(len(definition))) {
+		return nil
+	}
+
+	return quotaExceeded(tenantID, "max_definition_bytes", limits.MaxDefinitionBytes, int64(len(definition)))
+}
+
+// checkWorkflowQuota must run in the transaction creating the workflow. It
+// serializes quota checks of the tenant so concurrent requests cannot both
+// take the last slot.
+func checkWorkflowQuota(ctx context.Context, querier sqlc.Querier, tenantID pgtype.UUID, limits quota.Limits) error {
+	if err := querier.LockTenantQuota(ctx, utils.PgUUIDToString(tenantID)); err != nil {
+		return err
+	}
+
+	count, err := querier.CountActiveWorkflows(ctx, tenantID)
+	if err != nil {
+		return err
+	}
+	if !quota.Allows(limits.MaxWorkflows, count, 1) {
+		return quotaExceeded(tenantID, "max_workflows", limits.MaxWorkflows, count)
+	}
+
+	return nil
+}
+
+// checkRunQuota must run in the transaction creating the run, see
+// checkWorkflowQuota.
+func checkRunQuota(ctx context.Context, querier sqlc.Querier, tenantID pgtype.UUID, limits quota.Limits) error {
+	if err := querier.LockTenantQuota(ctx, utils.PgUUIDToString(tenantID)); err != nil {
+		return err
+	}
+
+	active, err := querier.CountActiveRuns(ctx, tenantID)
+	if err != nil {
+		return err
+	}
+	if !quota.Allows(limits.MaxConcurrentRuns, active, 1) {
+		return quotaExceeded(tenantID, "max_concurrent_runs", limits.MaxConcurrentRuns, active)
+	}
+
+	now := time.Now().UTC()
+	monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	started, err := querier.CountRunsSince(ctx, sqlc.CountRunsSinceParams{
+		TenantID:  tenantID,
+		StartedAt: utils.TimeToPgTimestamptz(monthStart),
+	})
+	if err != nil {
+		return err
+	}
+	if !quota.Allows(limits.RunsPerMonth, started, 1) {
+		return quotaExceeded(tenantID, "runs_per_month", limits.RunsPerMonth, started)
+	}
+
+	return nil
+}
+
+func quotaExceeded(tenantID pgtype.UUID, name string, limit, used int64) error {
+	var violations apierrors.QuotaViolations
+	violations.Add("tenant:"+utils.PgUUIDToString(tenantID), fmt.Sprintf("%s limit of %d exceeded (current: %d)", name, limit, used))
+
+	return apierrors.QuotaExceeded(fmt.Sprintf("quota %s exceeded", name), violations, 0)
+}
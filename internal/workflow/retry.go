@@ -0,0 +1,72 @@
+package workflow
+
+import (
+	"math"
+	"math/rand/v2"
+	"slices"
+	"time"
+
+	workflowv1 "github.com/vantutran2k1/rwe/gen/go/workflow/v1"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+const (
+	defaultMaxAttempts       = 1
+	defaultInitialBackoff    = time.Second
+	defaultMaxBackoff        = 5 * time.Minute
+	defaultBackoffMultiplier = 2.0
+)
+
+func parseRetryPolicy(raw []byte) (*workflowv1.RetryPolicy, error) {
+	policy := &workflowv1.RetryPolicy{}
+	if len(raw) == 0 {
+		return policy, nil
+	}
+
+	if err := (protojson.UnmarshalOptions{DiscardUnknown: true}).Unmarshal(raw, policy); err != nil {
+		return nil, err
+	}
+
+	return policy, nil
+}
+
+// retryDelay reports whether a task that failed its attempts-th attempt with
+// errorCode may be retried, and how long to wait before the next attempt.
+func retryDelay(policy *workflowv1.RetryPolicy, attempts int32, errorCode string) (time.Duration, bool) {
+	maxAttempts := policy.GetMaxAttempts()
+	if maxAttempts <= 0 {
+		maxAttempts = defaultMaxAttempts
+	}
+
+	if attempts >= maxAttempts {
+		return 0, false
+	}
+
+	if errorCode != "" && slices.Contains(policy.GetNonRetryableErrorCodes(), errorCode) {
+		return 0, false
+	}
+
+	initial := defaultInitialBackoff
+	if policy.GetInitialBackoff() != nil {
+		initial = policy.GetInitialBackoff().AsDuration()
+	}
+
+	maxBackoff := defaultMaxBackoff
+	if policy.GetMaxBackoff() != nil {
+		maxBackoff = policy.GetMaxBackoff().AsDuration()
+	}
+
+	multiplier := policy.GetBackoffMultiplier()
+	if multiplier == 0 {
+		multiplier = defaultBackoffMultiplier
+	}
+
+	delay := float64(initial) * math.Pow(multiplier, float64(max(attempts-1, 0)))
+	delay = math.Min(delay, float64(maxBackoff))
+
+	if jitter := policy.GetJitter(); jitter > 0 {
+		delay += delay * jitter * (2*rand.Float64() - 1)
+	}
+
+	return time.Duration(delay), true
+}
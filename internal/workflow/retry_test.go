@@ -0,0 +1,191 @@
+package workflow
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	workflowv1 "github.com/vantutran2k1/rwe/gen/go/workflow/v1"
+	apierrors "github.com/vantutran2k1/rwe/internal/common/errors"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+func TestRetryDelay(t *testing.T) {
+	tests := []struct {
+		name      string
+		policy    *workflowv1.RetryPolicy
+		attempts  int32
+		errorCode string
+		wantDelay time.Duration
+		wantRetry bool
+	}{
+		{
+			name:     "default policy does not retry",
+			policy:   &workflowv1.RetryPolicy{},
+			attempts: 1,
+		},
+		{
+			name:      "first retry uses the default initial backoff",
+			policy:    &workflowv1.RetryPolicy{MaxAttempts: 3},
+			attempts:  1,
+			wantDelay: time.Second,
+			wantRetry: true,
+		},
+		{
+			name:      "backoff grows by the default multiplier",
+			policy:    &workflowv1.RetryPolicy{MaxAttempts: 5},
+			attempts:  3,
+			wantDelay: 4 * time.Second,
+			wantRetry: true,
+		},
+		{
+			name:     "attempts exhausted",
+			policy:   &workflowv1.RetryPolicy{MaxAttempts: 3},
+			attempts: 3,
+		},
+		{
+			name: "custom backoff and multiplier",
+			policy: &workflowv1.RetryPolicy{
+				MaxAttempts:       5,
+				InitialBackoff:    durationpb.New(100 * time.Millisecond),
+				BackoffMultiplier: 3,
+			},
+			attempts:  3,
+			wantDelay: 900 * time.Millisecond,
+			wantRetry: true,
+		},
+		{
+			name: "backoff is capped by max backoff",
+			policy: &workflowv1.RetryPolicy{
+				MaxAttempts:    20,
+				InitialBackoff: durationpb.New(time.Second),
+				MaxBackoff:     durationpb.New(10 * time.Second),
+			},
+			attempts:  10,
+			wantDelay: 10 * time.Second,
+			wantRetry: true,
+		},
+		{
+			name:      "default max backoff",
+			policy:    &workflowv1.RetryPolicy{MaxAttempts: 30},
+			attempts:  20,
+			wantDelay: defaultMaxBackoff,
+			wantRetry: true,
+		},
+		{
+			name: "non retryable error code",
+			policy: &workflowv1.RetryPolicy{
+				MaxAttempts:            3,
+				NonRetryableErrorCodes: []string{"invalid_input"},
+			},
+			attempts:  1,
+			errorCode: "invalid_input",
+		},
+		{
+			name: "other error codes are retried",
+			policy: &workflowv1.RetryPolicy{
+				MaxAttempts:            3,
+				NonRetryableErrorCodes: []string{"invalid_input"},
+			},
+			attempts:  1,
+			errorCode: "timeout",
+			wantDelay: time.Second,
+			wantRetry: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			delay, retry := retryDelay(tt.policy, tt.attempts, tt.errorCode)
+			if retry != tt.wantRetry || delay != tt.wantDelay {
+				t.Errorf("retryDelay() = %v, %v, want %v, %v", delay, retry, tt.wantDelay, tt.wantRetry)
+			}
+		})
+	}
+}
+
+func TestRetryDelayJitter(t *testing.T) {
+	policy := &workflowv1.RetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: durationpb.New(10 * time.Second),
+		Jitter:         0.5,
+	}
+
+	for range 100 {
+		delay, retry := retryDelay(policy, 1, "")
+		if !retry {
+			t.Fatal("retryDelay() did not retry")
+		}
+		if delay < 5*time.Second || delay > 15*time.Second {
+			t.Fatalf("retryDelay() = %v, want within 50%% of 10s", delay)
+		}
+	}
+}
+
+func TestValidateRetryPolicy(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy *workflowv1.RetryPolicy
+		want   []string
+	}{
+		{
+			name: "valid",
+			policy: &workflowv1.RetryPolicy{
+				MaxAttempts:       3,
+				InitialBackoff:    durationpb.New(time.Second),
+				MaxBackoff:        durationpb.New(time.Minute),
+				BackoffMultiplier: 2,
+				Jitter:            0.2,
+			},
+		},
+		{
+			name:   "negative max attempts",
+			policy: &workflowv1.RetryPolicy{MaxAttempts: -1},
+			want:   []string{"retry.max_attempts"},
+		},
+		{
+			name:   "non positive initial backoff",
+			policy: &workflowv1.RetryPolicy{InitialBackoff: durationpb.New(0)},
+			want:   []string{"retry.initial_backoff"},
+		},
+		{
+			name: "max backoff below initial backoff",
+			policy: &workflowv1.RetryPolicy{
+				InitialBackoff: durationpb.New(time.Minute),
+				MaxBackoff:     durationpb.New(time.Second),
+			},
+			want: []string{"retry.max_backoff"},
+		},
+		{
+			name:   "multiplier below one",
+			policy: &workflowv1.RetryPolicy{BackoffMultiplier: 0.5},
+			want:   []string{"retry.backoff_multiplier"},
+		},
+		{
+			name:   "jitter out of range",
+			policy: &workflowv1.RetryPolicy{Jitter: 1.5},
+			want:   []string{"retry.jitter"},
+		},
+		{
+			name:   "empty error code",
+			policy: &workflowv1.RetryPolicy{NonRetryableErrorCodes: []string{"a", ""}},
+			want:   []string{"retry.non_retryable_error_codes[1]"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var violations apierrors.FieldViolations
+			validateRetryPolicy(tt.policy, "retry", &violations)
+
+			var got []string
+			for _, v := range violations {
+				got = append(got, v.Field)
+			}
+
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("got violations %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
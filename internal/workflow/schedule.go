@@ -0,0 +1,411 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	workflowv1 "github.com/vantutran2k1/rwe/gen/go/workflow/v1"
+	apierrors "github.com/vantutran2k1/rwe/internal/common/errors"
+	"github.com/vantutran2k1/rwe/internal/common/events"
+	"github.com/vantutran2k1/rwe/internal/common/utils"
+	sqlc "github.com/vantutran2k1/rwe/internal/workflow/db"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+type ScheduleService struct {
+	pool    *pgxpool.Pool
+	querier sqlc.Querier
+	workflowv1.UnimplementedScheduleServiceServer
+}
+
+func NewScheduleService(pool *pgxpool.Pool) *ScheduleService {
+	return &ScheduleService{
+		pool:    pool,
+		querier: sqlc.New(pool),
+	}
+}
+
+func (s *ScheduleService) CreateSchedule(ctx context.Context, req *workflowv1.CreateScheduleRequest) (*workflowv1.CreateScheduleResponse, error) {
+	tenantID, err := tenantFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	workflowID, err := uuid.Parse(req.WorkflowId)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid workflow id: %v", err)
+	}
+
+	params := sqlc.CreateScheduleParams{
+		TenantID:      tenantID,
+		WorkflowID:    utils.UUIDToPgUUID(workflowID),
+		Name:          strings.TrimSpace(req.Name),
+		Timezone:      req.Timezone,
+		OverlapPolicy: req.OverlapPolicy,
+		Paused:        req.Paused,
+	}
+	if params.Timezone == "" {
+		params.Timezone = "UTC"
+	}
+	if params.OverlapPolicy == "" {
+		params.OverlapPolicy = overlapSkip
+	}
+	if req.CatchupWindowSeconds != nil {
+		params.CatchupWindowSeconds = *req.CatchupWindowSeconds
+	} else {
+		params.CatchupWindowSeconds = int32(defaultCatchupWindow / time.Second)
+	}
+
+	var violations apierrors.FieldViolations
+	if params.Name == "" {
+		violations.Add("name", "name is required")
+	}
+	if !validOverlapPolicy(params.OverlapPolicy) {
+		violations.Add("overlap_policy", "must be one of skip, buffer or cancel")
+	}
+	if params.CatchupWindowSeconds < 0 || time.Duration(params.CatchupWindowSeconds)*time.Second > maxCatchupWindow {
+		violations.Add("catchup_window_seconds", fmt.Sprintf("must be between 0 and %d", int(maxCatchupWindow/time.Second)))
+	}
+	if req.WorkflowVersion < 0 {
+		violations.Add("workflow_version", "must not be negative")
+	}
+
+	now := time.Now()
+	switch {
+	case req.CronExpression != "" && req.IntervalSeconds != 0:
+		violations.Add("cron_expression", "cron_expression and interval_seconds are mutually exclusive")
+	case req.CronExpression != "":
+		spec, err := parseCron(req.CronExpression, params.Timezone)
+		if err != nil {
+			violations.Add("cron_expression", err.Error())
+			break
+		}
+
+		next := spec.next(now)
+		if next.IsZero() {
+			violations.Add("cron_expression", "expression never fires")
+			break
+		}
+
+		params.CronExpression = utils.StringToPgText(strings.TrimSpace(req.CronExpression))
+		params.NextRunAt = utils.TimeToPgTimestamptz(next)
+	case req.IntervalSeconds != 0:
+		interval := time.Duration(req.IntervalSeconds) * time.Second
+		if interval < minScheduleInterval {
+			violations.Add("interval_seconds", fmt.Sprintf("must be at least %d", int(minScheduleInterval/time.Second)))
+			break
+		}
+
+		params.IntervalSeconds = pgtype.Int4{Int32: req.IntervalSeconds, Valid: true}
+		params.NextRunAt = utils.TimeToPgTimestamptz(now.Add(interval))
+	default:
+		violations.Add("cron_expression", "one of cron_expression and interval_seconds is required")
+	}
+
+	if len(violations) > 0 {
+		return nil, apierrors.BadRequest("invalid schedule", violations)
+	}
+
+	wf, err := s.querier.GetWorkflowByID(ctx, sqlc.GetWorkflowByIDParams{
+		ID:       params.WorkflowID,
+		TenantID: tenantID,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, status.Errorf(codes.NotFound, "workflow with id %s not found", workflowID)
+		}
+
+		return nil, status.Errorf(codes.Internal, "error getting workflow: %v", err)
+	}
+
+	if wf.Archived.Bool {
+		return nil, status.Errorf(codes.FailedPrecondition, "workflow %s is archived", workflowID)
+	}
+
+	if req.WorkflowVersion != 0 {
+		if _, err := s.querier.GetWorkflowVersion(ctx, sqlc.GetWorkflowVersionParams{
+			WorkflowID: wf.ID,
+			Version:    req.WorkflowVersion,
+			TenantID:   tenantID,
+		}); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return nil, status.Errorf(codes.NotFound, "version %d of workflow %s not found", req.WorkflowVersion, workflowID)
+			}
+
+			return nil, status.Errorf(codes.Internal, "error getting workflow version: %v", err)
+		}
+
+		params.WorkflowVersion = pgtype.Int4{Int32: req.WorkflowVersion, Valid: true}
+	}
+
+	if req.Payload != nil {
+		params.Payload, err = protojson.Marshal(req.Payload)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid payload: %v", err)
+		}
+	}
+
+	var reqErr error
+	var sched sqlc.WorkflowSchedule
+	if err := s.execTx(ctx, func(querier sqlc.Querier) error {
+		sched, err = querier.CreateSchedule(ctx, params)
+		if err != nil {
+			var pgErr *pgconn.PgError
+			if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+				reqErr = status.Errorf(codes.AlreadyExists, "schedule %q already exists", params.Name)
+			}
+			return err
+		}
+
+		return appendEvent(ctx, querier, sched.TenantID, sched.ID, events.ScheduleCreated, map[string]any{
+			"workflow_id": utils.PgUUIDToString(sched.WorkflowID),
+			"name":        sched.Name,
+		})
+	}); err != nil {
+		if reqErr != nil {
+			return nil, reqErr
+		}
+
+		return nil, status.Errorf(codes.Internal, "error creating schedule: %v", err)
+	}
+
+	schedule, err := toSchedule(sched)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "error converting schedule: %v", err)
+	}
+
+	return &workflowv1.CreateScheduleResponse{Schedule: schedule}, nil
+}
+
+func (s *ScheduleService) GetSchedule(ctx context.Context, req *workflowv1.GetScheduleRequest) (*workflowv1.GetScheduleResponse, error) {
+	tenantID, err := tenantFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	scheduleID, err := uuid.Parse(req.Id)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid schedule id: %v", err)
+	}
+
+	sched, err := s.querier.GetSchedule(ctx, sqlc.GetScheduleParams{
+		ID:       utils.UUIDToPgUUID(scheduleID),
+		TenantID: tenantID,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, status.Errorf(codes.NotFound, "schedule with id %s not found", scheduleID)
+		}
+
+		return nil, status.Errorf(codes.Internal, "error getting schedule: %v", err)
+	}
+
+	schedule, err := toSchedule(sched)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "error converting schedule: %v", err)
+	}
+
+	return &workflowv1.GetScheduleResponse{Schedule: schedule}, nil
+}
+
+func (s *ScheduleService) ListSchedules(ctx context.Context, req *workflowv1.ListSchedulesRequest) (*workflowv1.ListSchedulesResponse, error) {
+	tenantID, err := tenantFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var workflowID pgtype.UUID
+	if req.WorkflowId != "" {
+		id, err := uuid.Parse(req.WorkflowId)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid workflow id: %v", err)
+		}
+		workflowID = utils.UUIDToPgUUID(id)
+	}
+
+	pageSize := int32(50)
+	if req.PageSize > 0 && req.PageSize < pageSize {
+		pageSize = req.PageSize
+	}
+
+	rows, err := s.querier.ListSchedules(ctx, sqlc.ListSchedulesParams{
+		TenantID:   tenantID,
+		WorkflowID: workflowID,
+		MaxResults: pageSize,
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "error listing schedules: %v", err)
+	}
+
+	schedules := make([]*workflowv1.Schedule, 0, len(rows))
+	for _, row := range rows {
+		schedule, err := toSchedule(row)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "error converting schedule: %v", err)
+		}
+		schedules = append(schedules, schedule)
+	}
+
+	return &workflowv1.ListSchedulesResponse{Schedules: schedules}, nil
+}
+
+func (s *ScheduleService) PauseSchedule(ctx context.Context, req *workflowv1.PauseScheduleRequest) (*workflowv1.PauseScheduleResponse, error) {
+	sched, err := s.setPaused(ctx, req.Id, true, req.Reason)
+	if err != nil {
+		return nil, err
+	}
+
+	return &workflowv1.PauseScheduleResponse{Schedule: sched}, nil
+}
+
+func (s *ScheduleService) ResumeSchedule(ctx context.Context, req *workflowv1.ResumeScheduleRequest) (*workflowv1.ResumeScheduleResponse, error) {
+	sched, err := s.setPaused(ctx, req.Id, false, "")
+	if err != nil {
+		return nil, err
+	}
+
+	return &workflowv1.ResumeScheduleResponse{Schedule: sched}, nil
+}
+
+func (s *ScheduleService) DeleteSchedule(ctx context.Context, req *workflowv1.DeleteScheduleRequest) (*workflowv1.DeleteScheduleResponse, error) {
+	tenantID, err := tenantFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	scheduleID, err := uuid.Parse(req.Id)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid schedule id: %v", err)
+	}
+
+	var reqErr error
+	if err := s.execTx(ctx, func(querier sqlc.Querier) error {
+		deleted, err := querier.DeleteSchedule(ctx, sqlc.DeleteScheduleParams{
+			ID:       utils.UUIDToPgUUID(scheduleID),
+			TenantID: tenantID,
+		})
+		if err != nil {
+			return err
+		}
+		if deleted == 0 {
+			reqErr = status.Errorf(codes.NotFound, "schedule with id %s not found", scheduleID)
+			return reqErr
+		}
+
+		return appendEvent(ctx, querier, tenantID, utils.UUIDToPgUUID(scheduleID), events.ScheduleDeleted, nil)
+	}); err != nil {
+		if reqErr != nil {
+			return nil, reqErr
+		}
+
+		return nil, status.Errorf(codes.Internal, "error deleting schedule: %v", err)
+	}
+
+	return &workflowv1.DeleteScheduleResponse{Success: true}, nil
+}
+
+// setPaused pauses or resumes a schedule. Resuming skips the ticks missed
+// while paused. Requests that do not change the state are no-ops.
+func (s *ScheduleService) setPaused(ctx context.Context, id string, paused bool, reason string) (*workflowv1.Schedule, error) {
+	tenantID, err := tenantFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	scheduleID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid schedule id: %v", err)
+	}
+
+	var reqErr error
+	var sched sqlc.WorkflowSchedule
+	if err := s.execTx(ctx, func(querier sqlc.Querier) error {
+		sched, err = querier.GetScheduleForUpdate(ctx, sqlc.GetScheduleForUpdateParams{
+			ID:       utils.UUIDToPgUUID(scheduleID),
+			TenantID: tenantID,
+		})
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				reqErr = status.Errorf(codes.NotFound, "schedule with id %s not found", scheduleID)
+			}
+			return err
+		}
+
+		if sched.Paused == paused {
+			return nil
+		}
+
+		nextRunAt := sched.NextRunAt
+		eventType := events.SchedulePaused
+		if !paused {
+			spec, err := specOf(sched)
+			if err != nil {
+				reqErr = status.Errorf(codes.FailedPrecondition, "schedule %s is invalid: %v", scheduleID, err)
+				return reqErr
+			}
+
+			next := spec.next(time.Now())
+			if next.IsZero() {
+				reqErr = status.Errorf(codes.FailedPrecondition, "schedule %s never fires again", scheduleID)
+				return reqErr
+			}
+
+			nextRunAt = utils.TimeToPgTimestamptz(next)
+			eventType = events.ScheduleResumed
+		}
+
+		sched, err = querier.SetSchedulePaused(ctx, sqlc.SetSchedulePausedParams{
+			ID:          sched.ID,
+			TenantID:    sched.TenantID,
+			Paused:      paused,
+			PauseReason: pgtype.Text{String: reason, Valid: paused && reason != ""},
+			NextRunAt:   nextRunAt,
+		})
+		if err != nil {
+			return err
+		}
+
+		return appendEvent(ctx, querier, sched.TenantID, sched.ID, eventType, map[string]any{
+			"reason": reason,
+		})
+	}); err != nil {
+		if reqErr != nil {
+			return nil, reqErr
+		}
+
+		return nil, status.Errorf(codes.Internal, "error updating schedule: %v", err)
+	}
+
+	schedule, err := toSchedule(sched)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "error converting schedule: %v", err)
+	}
+
+	return schedule, nil
+}
+
+func (s *ScheduleService) execTx(ctx context.Context, fn func(sqlc.Querier) error) error {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := sqlc.New(tx)
+
+	if err := fn(qtx); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
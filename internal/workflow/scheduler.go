@@ -0,0 +1,393 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/vantutran2k1/rwe/internal/common/events"
+	"github.com/vantutran2k1/rwe/internal/common/utils"
+	sqlc "github.com/vantutran2k1/rwe/internal/workflow/db"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+type StepHandler func(ctx context.Context, input []byte) ([]byte, error)
+
+type Scheduler struct {
+	pool         *pgxpool.Pool
+	querier      sqlc.Querier
+	handlers     map[string]StepHandler
+	interval     time.Duration
+	batchSize    int32
+	leaseSeconds int32
+}
+
+func NewScheduler(pool *pgxpool.Pool, interval time.Duration, batchSize int32, leaseDuration time.Duration) *Scheduler {
+	return &Scheduler{
+		pool:         pool,
+		querier:      sqlc.New(pool),
+		handlers:     builtinHandlers(),
+		interval:     interval,
+		batchSize:    batchSize,
+		leaseSeconds: int32(leaseDuration.Seconds()),
+	}
+}
+
+func (s *Scheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.tick(ctx); err != nil && ctx.Err() == nil {
+			slog.Error("scheduler tick failed", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *Scheduler) tick(ctx context.Context) error {
+	if err := s.startPendingRuns(ctx); err != nil {
+		return fmt.Errorf("error starting runs: %w", err)
+	}
+
+	if _, err := s.querier.RequeueExpiredTasks(ctx); err != nil {
+		return fmt.Errorf("error requeueing expired tasks: %w", err)
+	}
+
+	if _, err := s.querier.FailTimedOutTasks(ctx); err != nil {
+		return fmt.Errorf("error failing timed out tasks: %w", err)
+	}
+
+	if err := s.retryFailedTasks(ctx); err != nil {
+		return fmt.Errorf("error retrying failed tasks: %w", err)
+	}
+
+	if _, err := s.querier.PromoteReadyTasks(ctx); err != nil {
+		return fmt.Errorf("error promoting tasks: %w", err)
+	}
+
+	if err := s.executeQueuedTasks(ctx); err != nil {
+		return fmt.Errorf("error executing tasks: %w", err)
+	}
+
+	if err := s.finalizeRuns(ctx); err != nil {
+		return fmt.Errorf("error finalizing runs: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Scheduler) startPendingRuns(ctx context.Context) error {
+	return s.execTx(ctx, func(querier sqlc.Querier) error {
+		runs, err := querier.ClaimPendingRuns(ctx, s.batchSize)
+		if err != nil {
+			return err
+		}
+
+		tenantIDs := make([]pgtype.UUID, 0, len(runs))
+		for _, run := range runs {
+			tenantIDs = append(tenantIDs, run.TenantID)
+		}
+
+		if err := querier.LockEventStreams(ctx, tenantIDs); err != nil {
+			return err
+		}
+
+		for _, run := range runs {
+			if err := s.expandRun(ctx, querier, run); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+func (s *Scheduler) expandRun(ctx context.Context, querier sqlc.Querier, run sqlc.WorkflowRun) error {
+	raw, err := querier.GetRunDefinition(ctx, run.ID)
+	if err != nil {
+		return err
+	}
+
+	def, err := parseDefinition(raw, false)
+	if err != nil {
+		return s.failRun(ctx, querier, run, err.Error())
+	}
+
+	if violations := validateDefinition(def); len(violations) > 0 {
+		v := violations[0]
+		return s.failRun(ctx, querier, run, fmt.Sprintf("invalid definition: %s: %s", v.Field, v.Description))
+	}
+
+	for _, st := range def.Steps {
+		var input []byte
+		if st.Input != nil {
+			input, err = protojson.Marshal(st.Input)
+			if err != nil {
+				return err
+			}
+		}
+
+		var retryPolicy []byte
+		if st.Retry != nil {
+			retryPolicy, err = protojson.Marshal(st.Retry)
+			if err != nil {
+				return err
+			}
+		}
+
+		var timeout pgtype.Int4
+		if st.Timeout != nil {
+			timeout = pgtype.Int4{Int32: int32(math.Ceil(st.Timeout.AsDuration().Seconds())), Valid: true}
+		}
+
+		dependsOn := st.DependsOn
+		if dependsOn == nil {
+			dependsOn = []string{}
+		}
+
+		if err := querier.CreateTask(ctx, sqlc.CreateTaskParams{
+			RunID:          run.ID,
+			StepID:         st.Id,
+			StepType:       st.Type,
+			Queue:          pgtype.Text{String: st.Queue, Valid: st.Queue != ""},
+			Input:          input,
+			DependsOn:      dependsOn,
+			TimeoutSeconds: timeout,
+			RetryPolicy:    retryPolicy,
+		}); err != nil {
+			return err
+		}
+	}
+
+	if err := querier.MarkRunRunning(ctx, run.ID); err != nil {
+		return err
+	}
+
+	return appendEvent(ctx, querier, run.TenantID, run.ID, events.RunStarted, map[string]any{
+		"workflow_id":      utils.PgUUIDToString(run.WorkflowID),
+		"workflow_version": run.WorkflowVersion.Int32,
+		"tasks":            len(def.Steps),
+	})
+}
+
+func (s *Scheduler) failRun(ctx context.Context, querier sqlc.Querier, run sqlc.WorkflowRun, reason string) error {
+	slog.Warn("workflow run failed", "run_id", utils.PgUUIDToString(run.ID), "reason", reason)
+
+	if err := querier.FailRun(ctx, sqlc.FailRunParams{
+		ID:    run.ID,
+		Error: utils.StringToPgText(reason),
+	}); err != nil {
+		return err
+	}
+
+	return appendEvent(ctx, querier, run.TenantID, run.ID, events.RunFailed, map[string]any{
+		"workflow_id": utils.PgUUIDToString(run.WorkflowID),
+		"error":       reason,
+	})
+}
+
+func (s *Scheduler) retryFailedTasks(ctx context.Context) error {
+	return s.execTx(ctx, func(querier sqlc.Querier) error {
+		tasks, err := querier.ClaimFailedTasks(ctx, s.batchSize)
+		if err != nil {
+			return err
+		}
+
+		tenantIDs := make([]pgtype.UUID, 0, len(tasks))
+		for _, task := range tasks {
+			tenantIDs = append(tenantIDs, task.TenantID)
+		}
+
+		if err := querier.LockEventStreams(ctx, tenantIDs); err != nil {
+			return err
+		}
+
+		for _, task := range tasks {
+			policy, err := parseRetryPolicy(task.RetryPolicy)
+			if err != nil {
+				return err
+			}
+
+			if delay, ok := retryDelay(policy, task.Attempts.Int32, task.ErrorCode.String); ok {
+				if err := querier.ScheduleTaskRetry(ctx, sqlc.ScheduleTaskRetryParams{
+					ID:          task.ID,
+					AvailableAt: utils.TimeToPgTimestamptz(time.Now().Add(delay)),
+				}); err != nil {
+					return err
+				}
+				continue
+			}
+
+			if err := querier.DeadLetterTask(ctx, task.ID); err != nil {
+				return err
+			}
+
+			if err := appendEvent(ctx, querier, task.TenantID, task.ID, events.TaskDeadLettered, map[string]any{
+				"run_id":     utils.PgUUIDToString(task.RunID),
+				"step_id":    task.StepID,
+				"attempts":   task.Attempts.Int32,
+				"error":      task.LastError.String,
+				"error_code": task.ErrorCode.String,
+			}); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+func (s *Scheduler) executeQueuedTasks(ctx context.Context) error {
+	stepTypes := make([]string, 0, len(s.handlers))
+	for stepType := range s.handlers {
+		stepTypes = append(stepTypes, stepType)
+	}
+
+	tasks, err := s.querier.ClaimQueuedTasks(ctx, sqlc.ClaimQueuedTasksParams{
+		LeaseSeconds: s.leaseSeconds,
+		StepTypes:    stepTypes,
+		BatchSize:    s.batchSize,
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, task := range tasks {
+		result, execErr := s.execute(ctx, task)
+		if execErr != nil {
+			err = s.querier.FailTask(ctx, sqlc.FailTaskParams{
+				ID:        task.ID,
+				LastError: utils.StringToPgText(execErr.Error()),
+				ErrorCode: pgtype.Text{String: errorCode(execErr), Valid: true},
+			})
+		} else {
+			err = s.querier.CompleteTask(ctx, sqlc.CompleteTaskParams{
+				ID:     task.ID,
+				Result: result,
+			})
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *Scheduler) execute(ctx context.Context, task sqlc.Task) ([]byte, error) {
+	if task.TimeoutSeconds.Valid {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(task.TimeoutSeconds.Int32)*time.Second)
+		defer cancel()
+	}
+
+	return s.handlers[task.StepType](ctx, task.Input)
+}
+
+func (s *Scheduler) finalizeRuns(ctx context.Context) error {
+	return s.execTx(ctx, func(querier sqlc.Querier) error {
+		failed, err := querier.FinishFailedRuns(ctx)
+		if err != nil {
+			return err
+		}
+
+		succeeded, err := querier.FinishSucceededRuns(ctx)
+		if err != nil {
+			return err
+		}
+
+		// Both batches are finished up front, so that the tenants they append
+		// events to are locked together.
+		tenantIDs := make([]pgtype.UUID, 0, len(failed)+len(succeeded))
+		for _, run := range failed {
+			tenantIDs = append(tenantIDs, run.TenantID)
+		}
+		for _, run := range succeeded {
+			tenantIDs = append(tenantIDs, run.TenantID)
+		}
+
+		if err := querier.LockEventStreams(ctx, tenantIDs); err != nil {
+			return err
+		}
+
+		if len(failed) > 0 {
+			runIDs := make([]pgtype.UUID, 0, len(failed))
+			for _, run := range failed {
+				runIDs = append(runIDs, run.ID)
+			}
+
+			if err := querier.CancelRunTasks(ctx, runIDs); err != nil {
+				return err
+			}
+		}
+
+		for _, run := range failed {
+			if err := appendEvent(ctx, querier, run.TenantID, run.ID, events.RunFailed, map[string]any{
+				"workflow_id": utils.PgUUIDToString(run.WorkflowID),
+				"error":       run.Error.String,
+			}); err != nil {
+				return err
+			}
+		}
+
+		for _, run := range succeeded {
+			if err := appendEvent(ctx, querier, run.TenantID, run.ID, events.RunSucceeded, map[string]any{
+				"workflow_id": utils.PgUUIDToString(run.WorkflowID),
+			}); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+func (s *Scheduler) execTx(ctx context.Context, fn func(sqlc.Querier) error) error {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := sqlc.New(tx)
+
+	if err := fn(qtx); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func errorCode(err error) string {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return "timeout"
+	}
+
+	return "error"
+}
+
+func builtinHandlers() map[string]StepHandler {
+	return map[string]StepHandler{
+		"noop": func(ctx context.Context, input []byte) ([]byte, error) {
+			return []byte("{}"), nil
+		},
+		"echo": func(ctx context.Context, input []byte) ([]byte, error) {
+			if len(input) == 0 {
+				return []byte("null"), nil
+			}
+
+			return input, nil
+		},
+	}
+}
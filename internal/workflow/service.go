@@ -6,10 +6,18 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/jackc/pgx/v5/pgxpool"
+	authv1 "github.com/vantutran2k1/rwe/gen/go/auth/v1"
 	workflowv1 "github.com/vantutran2k1/rwe/gen/go/workflow/v1"
+	"github.com/vantutran2k1/rwe/internal/auth"
 	"github.com/vantutran2k1/rwe/internal/common/db"
+	apierrors "github.com/vantutran2k1/rwe/internal/common/errors"
+	"github.com/vantutran2k1/rwe/internal/common/events"
+	"github.com/vantutran2k1/rwe/internal/common/token"
 	"github.com/vantutran2k1/rwe/internal/common/utils"
+	"github.com/vantutran2k1/rwe/internal/quota"
+	"github.com/vantutran2k1/rwe/internal/usage"
 	sqlc "github.com/vantutran2k1/rwe/internal/workflow/db"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -21,42 +29,79 @@ import (
 type Service struct {
 	pool    *pgxpool.Pool
 	querier sqlc.Querier
+	quotas  *quota.Store
 	workflowv1.UnimplementedWorkflowServiceServer
 }
 
-func NewService(pool *pgxpool.Pool) *Service {
+func NewService(pool *pgxpool.Pool, quotas *quota.Store) *Service {
 	return &Service{
 		pool:    pool,
 		querier: sqlc.New(pool),
+		quotas:  quotas,
 	}
 }
 
 func (s *Service) CreateWorkflow(ctx context.Context, req *workflowv1.CreateWorkflowRequest) (*workflowv1.CreateWorkflowResponse, error) {
-	// TODO: get tenant id from auth
-	tenantID, err := uuid.Parse(req.TenantId)
+	tenantID, err := tenantFromContext(ctx)
 	if err != nil {
-		return nil, status.Errorf(codes.InvalidArgument, "invalid tenant id: %v", err)
+		return nil, err
 	}
 
 	// TODO: check for unique name per tenant
 
-	definition, err := protojson.Marshal(req.Definition)
+	definition, err := marshalDefinition(req.Definition)
 	if err != nil {
-		return nil, status.Errorf(codes.InvalidArgument, "invalid definition: %v", err)
+		return nil, err
 	}
 
-	// TODO: validate definition
+	limits, err := s.limits(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkDefinitionSize(tenantID, limits, definition); err != nil {
+		return nil, err
+	}
 
+	var reqErr error
 	var row sqlc.CreateWorkflowRow
 	if err := s.execTx(ctx, func(querier sqlc.Querier) error {
+		if err := checkWorkflowQuota(ctx, querier, tenantID, limits); err != nil {
+			if _, ok := status.FromError(err); ok {
+				reqErr = err
+			}
+			return err
+		}
+
 		row, err = querier.CreateWorkflow(ctx, sqlc.CreateWorkflowParams{
-			TenantID:   utils.UUIDToPgUUID(tenantID),
+			TenantID:   tenantID,
 			Name:       req.Name,
 			Definition: definition,
 		})
+		if err != nil {
+			return err
+		}
 
-		return err
+		if err := querier.CreateWorkflowVersion(ctx, sqlc.CreateWorkflowVersionParams{
+			WorkflowID: row.ID,
+			Version:    row.Version.Int32,
+			Definition: definition,
+		}); err != nil {
+			return err
+		}
+
+		if err := recordUsage(ctx, querier, row.TenantID, usage.MetricDefinitionBytes, float64(row.DefinitionBytes)); err != nil {
+			return err
+		}
+
+		return appendEvent(ctx, querier, row.TenantID, row.ID, events.WorkflowCreated, map[string]any{
+			"name":    row.Name,
+			"version": row.Version.Int32,
+		})
 	}); err != nil {
+		if reqErr != nil {
+			return nil, reqErr
+		}
+
 		return nil, status.Errorf(codes.Internal, "error creating workflow: %v", err)
 	}
 
@@ -64,16 +109,25 @@ func (s *Service) CreateWorkflow(ctx context.Context, req *workflowv1.CreateWork
 		Id:       utils.PgUUIDToString(row.ID),
 		TenantId: utils.PgUUIDToString(row.TenantID),
 		Name:     row.Name,
+		Version:  row.Version.Int32,
 	}, nil
 }
 
 func (s *Service) GetWorkflow(ctx context.Context, req *workflowv1.GetWorkflowRequest) (*workflowv1.GetWorkflowResponse, error) {
+	tenantID, err := tenantFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	workflowID, err := uuid.Parse(req.Id)
 	if err != nil {
 		return nil, status.Errorf(codes.InvalidArgument, "invalid workflow id: %v", err)
 	}
 
-	row, err := s.querier.GetWorkflowByID(ctx, utils.UUIDToPgUUID(workflowID))
+	row, err := s.querier.GetWorkflowByID(ctx, sqlc.GetWorkflowByIDParams{
+		ID:       utils.UUIDToPgUUID(workflowID),
+		TenantID: tenantID,
+	})
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, status.Errorf(codes.NotFound, "workflow with id %s not found", workflowID)
@@ -82,8 +136,6 @@ func (s *Service) GetWorkflow(ctx context.Context, req *workflowv1.GetWorkflowRe
 		return nil, status.Errorf(codes.Internal, "error getting workflow: %v", err)
 	}
 
-	// TODO: check for tenant id
-
 	var definition structpb.Struct
 	if err := protojson.Unmarshal(row.Definition, &definition); err != nil {
 		return nil, status.Errorf(codes.Internal, "error parsing definition: %v", err)
@@ -102,6 +154,11 @@ func (s *Service) GetWorkflow(ctx context.Context, req *workflowv1.GetWorkflowRe
 }
 
 func (s *Service) GetWorkflows(ctx context.Context, req *workflowv1.GetWorkflowsRequest) (*workflowv1.GetWorkflowsResponse, error) {
+	tenantID, err := tenantFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	c, err := db.DecodeCursor(req.Token)
 	if err != nil {
 		return nil, status.Errorf(codes.InvalidArgument, "invalid token: %v", err)
@@ -112,21 +169,31 @@ func (s *Service) GetWorkflows(ctx context.Context, req *workflowv1.GetWorkflows
 		req.PageSize = pageSize
 	}
 
-	// TODO: get tenant id from auth
-	uid, _ := uuid.Parse("22222222-2222-2222-2222-222222222222")
+	tenantIDs := []pgtype.UUID{tenantID}
+	if req.IncludeDescendants {
+		if scope := token.GetTenantScope(ctx); auth.ParseRole(scope.Role) < authv1.Role_ROLE_ADMIN {
+			return nil, status.Error(codes.PermissionDenied, "listing workflows of descendant tenants requires the admin role")
+		}
+
+		tenantIDs, err = s.querier.ListSubtreeTenantIDs(ctx, tenantID)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "error listing descendant tenants: %v", err)
+		}
+	}
+
 	rows, err := s.querier.ListWorkflowsByTenantID(ctx, sqlc.ListWorkflowsByTenantIDParams{
-		TenantID:  utils.UUIDToPgUUID(uid),
-		UpdatedAt: utils.TimeToPgTimestamptz(c.LastUpdatedAt),
-		ID:        utils.UUIDToPgUUID(c.LastID),
-		Limit:     pageSize,
+		TenantIds:  tenantIDs,
+		UpdatedAt:  utils.TimeToPgTimestamptz(c.LastUpdatedAt),
+		ID:         utils.UUIDToPgUUID(c.LastID),
+		MaxResults: pageSize,
 	})
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "error getting workflows: %v", err)
 	}
 
 	wfs := make([]*workflowv1.Workflow, 0, len(rows))
-	var definition structpb.Struct
 	for _, row := range rows {
+		var definition structpb.Struct
 		if err := protojson.Unmarshal(row.Definition, &definition); err != nil {
 			return nil, status.Errorf(codes.Internal, "error parsing definition: %v", err)
 		}
@@ -147,6 +214,482 @@ func (s *Service) GetWorkflows(ctx context.Context, req *workflowv1.GetWorkflows
 	return &workflowv1.GetWorkflowsResponse{Workflows: wfs}, nil
 }
 
+func (s *Service) StartWorkflowRun(ctx context.Context, req *workflowv1.StartWorkflowRunRequest) (*workflowv1.StartWorkflowRunResponse, error) {
+	tenantID, err := tenantFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	workflowID, err := uuid.Parse(req.WorkflowId)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid workflow id: %v", err)
+	}
+
+	wf, err := s.querier.GetWorkflowByID(ctx, sqlc.GetWorkflowByIDParams{
+		ID:       utils.UUIDToPgUUID(workflowID),
+		TenantID: tenantID,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, status.Errorf(codes.NotFound, "workflow with id %s not found", workflowID)
+		}
+
+		return nil, status.Errorf(codes.Internal, "error getting workflow: %v", err)
+	}
+
+	if wf.Archived.Bool {
+		return nil, status.Errorf(codes.FailedPrecondition, "workflow %s is archived", workflowID)
+	}
+
+	version := wf.Version.Int32
+	if req.Version != 0 {
+		v, err := s.querier.GetWorkflowVersion(ctx, sqlc.GetWorkflowVersionParams{
+			WorkflowID: wf.ID,
+			Version:    req.Version,
+			TenantID:   wf.TenantID,
+		})
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return nil, status.Errorf(codes.NotFound, "version %d of workflow %s not found", req.Version, workflowID)
+			}
+
+			return nil, status.Errorf(codes.Internal, "error getting workflow version: %v", err)
+		}
+		version = v.Version
+	}
+
+	var payload []byte
+	if req.Payload != nil {
+		payload, err = protojson.Marshal(req.Payload)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid payload: %v", err)
+		}
+	}
+
+	limits, err := s.limits(ctx, wf.TenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	var reqErr error
+	var run sqlc.WorkflowRun
+	if err := s.execTx(ctx, func(querier sqlc.Querier) error {
+		if err := checkRunQuota(ctx, querier, wf.TenantID, limits); err != nil {
+			if _, ok := status.FromError(err); ok {
+				reqErr = err
+			}
+			return err
+		}
+
+		run, err = querier.CreateWorkflowRun(ctx, sqlc.CreateWorkflowRunParams{
+			TenantID:        wf.TenantID,
+			WorkflowID:      wf.ID,
+			WorkflowVersion: pgtype.Int4{Int32: version, Valid: true},
+			Payload:         payload,
+		})
+		if err != nil {
+			return err
+		}
+
+		if err := recordUsage(ctx, querier, run.TenantID, usage.MetricRunsStarted, 1); err != nil {
+			return err
+		}
+
+		return appendEvent(ctx, querier, run.TenantID, run.ID, events.RunCreated, map[string]any{
+			"workflow_id":      utils.PgUUIDToString(run.WorkflowID),
+			"workflow_version": run.WorkflowVersion.Int32,
+		})
+	}); err != nil {
+		if reqErr != nil {
+			return nil, reqErr
+		}
+
+		return nil, status.Errorf(codes.Internal, "error starting workflow run: %v", err)
+	}
+
+	return &workflowv1.StartWorkflowRunResponse{
+		Id:              utils.PgUUIDToString(run.ID),
+		WorkflowId:      utils.PgUUIDToString(run.WorkflowID),
+		Status:          run.Status,
+		WorkflowVersion: run.WorkflowVersion.Int32,
+	}, nil
+}
+
+func (s *Service) GetWorkflowRun(ctx context.Context, req *workflowv1.GetWorkflowRunRequest) (*workflowv1.GetWorkflowRunResponse, error) {
+	tenantID, err := tenantFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	runID, err := uuid.Parse(req.Id)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid run id: %v", err)
+	}
+
+	run, err := s.querier.GetWorkflowRunByID(ctx, sqlc.GetWorkflowRunByIDParams{
+		ID:       utils.UUIDToPgUUID(runID),
+		TenantID: tenantID,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, status.Errorf(codes.NotFound, "workflow run with id %s not found", runID)
+		}
+
+		return nil, status.Errorf(codes.Internal, "error getting workflow run: %v", err)
+	}
+
+	tasks, err := s.querier.ListTasksByRunID(ctx, run.ID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "error getting tasks: %v", err)
+	}
+
+	r, err := toWorkflowRun(run, tasks)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "error parsing workflow run: %v", err)
+	}
+
+	return &workflowv1.GetWorkflowRunResponse{Run: r}, nil
+}
+
+func (s *Service) UpdateWorkflow(ctx context.Context, req *workflowv1.UpdateWorkflowRequest) (*workflowv1.UpdateWorkflowResponse, error) {
+	tenantID, err := tenantFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	workflowID, err := uuid.Parse(req.Id)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid workflow id: %v", err)
+	}
+
+	definition, err := marshalDefinition(req.Definition)
+	if err != nil {
+		return nil, err
+	}
+
+	limits, err := s.limits(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkDefinitionSize(tenantID, limits, definition); err != nil {
+		return nil, err
+	}
+
+	var reqErr error
+	var row sqlc.UpdateWorkflowDefinitionRow
+	if err := s.execTx(ctx, func(querier sqlc.Querier) error {
+		row, err = querier.UpdateWorkflowDefinition(ctx, sqlc.UpdateWorkflowDefinitionParams{
+			ID:         utils.UUIDToPgUUID(workflowID),
+			TenantID:   tenantID,
+			Name:       pgtype.Text{String: req.Name, Valid: req.Name != ""},
+			Definition: definition,
+		})
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				reqErr = status.Errorf(codes.NotFound, "workflow with id %s not found", workflowID)
+			}
+			return err
+		}
+
+		if row.Archived.Bool {
+			reqErr = status.Errorf(codes.FailedPrecondition, "workflow %s is archived", workflowID)
+			return reqErr
+		}
+
+		if req.ExpectedVersion != 0 && row.Version.Int32-1 != req.ExpectedVersion {
+			reqErr = status.Errorf(codes.Aborted, "workflow %s is at version %d, expected %d", workflowID, row.Version.Int32-1, req.ExpectedVersion)
+			return reqErr
+		}
+
+		if err := querier.CreateWorkflowVersion(ctx, sqlc.CreateWorkflowVersionParams{
+			WorkflowID: row.ID,
+			Version:    row.Version.Int32,
+			Definition: definition,
+		}); err != nil {
+			return err
+		}
+
+		if err := recordUsage(ctx, querier, row.TenantID, usage.MetricDefinitionBytes, float64(row.DefinitionBytesDelta)); err != nil {
+			return err
+		}
+
+		return appendEvent(ctx, querier, row.TenantID, row.ID, events.WorkflowUpdated, map[string]any{
+			"name":    row.Name,
+			"version": row.Version.Int32,
+		})
+	}); err != nil {
+		if reqErr != nil {
+			return nil, reqErr
+		}
+
+		return nil, status.Errorf(codes.Internal, "error updating workflow: %v", err)
+	}
+
+	return &workflowv1.UpdateWorkflowResponse{
+		Id:      utils.PgUUIDToString(row.ID),
+		Name:    row.Name,
+		Version: row.Version.Int32,
+	}, nil
+}
+
+func (s *Service) ListWorkflowVersions(ctx context.Context, req *workflowv1.ListWorkflowVersionsRequest) (*workflowv1.ListWorkflowVersionsResponse, error) {
+	tenantID, err := tenantFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	workflowID, err := uuid.Parse(req.Id)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid workflow id: %v", err)
+	}
+
+	rows, err := s.querier.ListWorkflowVersions(ctx, sqlc.ListWorkflowVersionsParams{
+		WorkflowID: utils.UUIDToPgUUID(workflowID),
+		TenantID:   tenantID,
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "error listing workflow versions: %v", err)
+	}
+
+	if len(rows) == 0 {
+		return nil, status.Errorf(codes.NotFound, "workflow with id %s not found", workflowID)
+	}
+
+	versions := make([]*workflowv1.WorkflowVersion, 0, len(rows))
+	for _, row := range rows {
+		v, err := toWorkflowVersion(row)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "error parsing definition: %v", err)
+		}
+		versions = append(versions, v)
+	}
+
+	return &workflowv1.ListWorkflowVersionsResponse{Versions: versions}, nil
+}
+
+func (s *Service) GetWorkflowVersion(ctx context.Context, req *workflowv1.GetWorkflowVersionRequest) (*workflowv1.GetWorkflowVersionResponse, error) {
+	tenantID, err := tenantFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	workflowID, err := uuid.Parse(req.Id)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid workflow id: %v", err)
+	}
+
+	row, err := s.getVersion(ctx, tenantID, workflowID, req.Version)
+	if err != nil {
+		return nil, err
+	}
+
+	v, err := toWorkflowVersion(row)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "error parsing definition: %v", err)
+	}
+
+	return &workflowv1.GetWorkflowVersionResponse{Version: v}, nil
+}
+
+func (s *Service) DiffWorkflowVersions(ctx context.Context, req *workflowv1.DiffWorkflowVersionsRequest) (*workflowv1.DiffWorkflowVersionsResponse, error) {
+	tenantID, err := tenantFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	workflowID, err := uuid.Parse(req.Id)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid workflow id: %v", err)
+	}
+
+	from, err := s.getVersion(ctx, tenantID, workflowID, req.FromVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	to, err := s.getVersion(ctx, tenantID, workflowID, req.ToVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	fromDef, err := parseDefinition(from.Definition, false)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "error parsing definition: %v", err)
+	}
+
+	toDef, err := parseDefinition(to.Definition, false)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "error parsing definition: %v", err)
+	}
+
+	return &workflowv1.DiffWorkflowVersionsResponse{
+		FromVersion: from.Version,
+		ToVersion:   to.Version,
+		Steps:       diffDefinitions(fromDef, toDef),
+	}, nil
+}
+
+func (s *Service) ListDeadLetterTasks(ctx context.Context, req *workflowv1.ListDeadLetterTasksRequest) (*workflowv1.ListDeadLetterTasksResponse, error) {
+	tenantID, err := tenantFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var workflowID pgtype.UUID
+	if req.WorkflowId != "" {
+		id, err := uuid.Parse(req.WorkflowId)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid workflow id: %v", err)
+		}
+		workflowID = utils.UUIDToPgUUID(id)
+	}
+
+	pageSize := int32(20)
+	if req.PageSize > 0 && req.PageSize < pageSize {
+		pageSize = req.PageSize
+	}
+
+	rows, err := s.querier.ListDeadLetterTasks(ctx, sqlc.ListDeadLetterTasksParams{
+		TenantID:   tenantID,
+		WorkflowID: workflowID,
+		MaxResults: pageSize,
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "error listing dead-lettered tasks: %v", err)
+	}
+
+	tasks := make([]*workflowv1.Task, 0, len(rows))
+	for _, row := range rows {
+		task, err := toTask(row)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "error parsing task: %v", err)
+		}
+		tasks = append(tasks, task)
+	}
+
+	return &workflowv1.ListDeadLetterTasksResponse{Tasks: tasks}, nil
+}
+
+func (s *Service) RedriveTask(ctx context.Context, req *workflowv1.RedriveTaskRequest) (*workflowv1.RedriveTaskResponse, error) {
+	tenantID, err := tenantFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	taskID, err := uuid.Parse(req.Id)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid task id: %v", err)
+	}
+
+	var reqErr error
+	var task sqlc.Task
+	if err := s.execTx(ctx, func(querier sqlc.Querier) error {
+		row, err := querier.GetTaskForUpdate(ctx, sqlc.GetTaskForUpdateParams{
+			ID:       utils.UUIDToPgUUID(taskID),
+			TenantID: tenantID,
+		})
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				reqErr = status.Errorf(codes.NotFound, "task with id %s not found", taskID)
+			}
+			return err
+		}
+
+		if row.Status != "dead_lettered" {
+			reqErr = status.Errorf(codes.FailedPrecondition, "task %s is %s, only dead-lettered tasks can be redriven", taskID, row.Status)
+			return reqErr
+		}
+
+		task, err = querier.RedriveTask(ctx, sqlc.RedriveTaskParams{
+			ID:            row.ID,
+			ResetAttempts: req.ResetAttempts,
+		})
+		if err != nil {
+			return err
+		}
+
+		if row.RunStatus == "failed" {
+			if err := querier.ReopenRun(ctx, row.RunID); err != nil {
+				return err
+			}
+
+			if err := querier.ResumeCancelledTasks(ctx, row.RunID); err != nil {
+				return err
+			}
+		}
+
+		return appendEvent(ctx, querier, row.TenantID, row.ID, events.TaskRedriven, map[string]any{
+			"run_id":         utils.PgUUIDToString(row.RunID),
+			"step_id":        row.StepID,
+			"reset_attempts": req.ResetAttempts,
+		})
+	}); err != nil {
+		if reqErr != nil {
+			return nil, reqErr
+		}
+
+		return nil, status.Errorf(codes.Internal, "error redriving task: %v", err)
+	}
+
+	t, err := toTask(task)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "error parsing task: %v", err)
+	}
+
+	return &workflowv1.RedriveTaskResponse{Task: t}, nil
+}
+
+func (s *Service) getVersion(ctx context.Context, tenantID pgtype.UUID, workflowID uuid.UUID, version int32) (sqlc.WorkflowVersion, error) {
+	row, err := s.querier.GetWorkflowVersion(ctx, sqlc.GetWorkflowVersionParams{
+		WorkflowID: utils.UUIDToPgUUID(workflowID),
+		Version:    version,
+		TenantID:   tenantID,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return sqlc.WorkflowVersion{}, status.Errorf(codes.NotFound, "version %d of workflow %s not found", version, workflowID)
+		}
+
+		return sqlc.WorkflowVersion{}, status.Errorf(codes.Internal, "error getting workflow version: %v", err)
+	}
+
+	return row, nil
+}
+
+func marshalDefinition(in *structpb.Struct) ([]byte, error) {
+	var violations apierrors.FieldViolations
+	if in == nil {
+		violations.Add("definition", "definition is required")
+		return nil, apierrors.BadRequest("invalid workflow definition", violations)
+	}
+
+	definition, err := protojson.Marshal(in)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid definition: %v", err)
+	}
+
+	def, err := parseDefinition(definition, true)
+	if err != nil {
+		violations.Add("definition", err.Error())
+		return nil, apierrors.BadRequest("invalid workflow definition", violations)
+	}
+
+	if violations = validateDefinition(def); len(violations) > 0 {
+		return nil, apierrors.BadRequest("invalid workflow definition", violations)
+	}
+
+	return definition, nil
+}
+
+func tenantFromContext(ctx context.Context) (pgtype.UUID, error) {
+	scope := token.GetTenantScope(ctx)
+	if scope == nil {
+		return pgtype.UUID{}, status.Error(codes.PermissionDenied, "missing tenant scope")
+	}
+
+	return utils.UUIDToPgUUID(scope.TenantID), nil
+}
+
 func (s *Service) execTx(ctx context.Context, fn func(sqlc.Querier) error) error {
 	tx, err := s.pool.Begin(ctx)
 	if err != nil {
@@ -162,3 +705,27 @@ func (s *Service) execTx(ctx context.Context, fn func(sqlc.Querier) error) error
 
 	return tx.Commit(ctx)
 }
+
+func appendEvent(ctx context.Context, querier sqlc.Querier, tenantID, aggregateID pgtype.UUID, eventType string, payload any) error {
+	data, err := events.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	_, err = querier.AppendEvent(ctx, sqlc.AppendEventParams{
+		TenantID:    tenantID,
+		EventType:   eventType,
+		AggregateID: aggregateID,
+		Payload:     data,
+	})
+
+	return err
+}
+
+func recordUsage(ctx context.Context, querier sqlc.Querier, tenantID pgtype.UUID, metric string, value float64) error {
+	return querier.RecordUsage(ctx, sqlc.RecordUsageParams{
+		TenantID: tenantID,
+		Metric:   metric,
+		Value:    value,
+	})
+}
@@ -0,0 +1,320 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/vantutran2k1/rwe/internal/common/events"
+	"github.com/vantutran2k1/rwe/internal/common/utils"
+	"github.com/vantutran2k1/rwe/internal/quota"
+	"github.com/vantutran2k1/rwe/internal/usage"
+	sqlc "github.com/vantutran2k1/rwe/internal/workflow/db"
+	"google.golang.org/grpc/status"
+)
+
+// maxTicksPerPass bounds the ticks a schedule evaluates per pass so that a
+// large backlog cannot hold its lock for long; the rest follow next pass.
+const maxTicksPerPass = 100
+
+// ScheduleTicker starts runs for due schedules. Replicas elect a leader by
+// holding a Postgres advisory lock on a dedicated connection, and only the
+// leader evaluates ticks.
+type ScheduleTicker struct {
+	pool      *pgxpool.Pool
+	quotas    *quota.Store
+	interval  time.Duration
+	batchSize int32
+}
+
+func NewScheduleTicker(pool *pgxpool.Pool, quotas *quota.Store, interval time.Duration, batchSize int32) *ScheduleTicker {
+	return &ScheduleTicker{
+		pool:      pool,
+		quotas:    quotas,
+		interval:  interval,
+		batchSize: batchSize,
+	}
+}
+
+func (t *ScheduleTicker) Run(ctx context.Context) {
+	ticker := time.NewTicker(t.interval)
+	defer ticker.Stop()
+
+	var leader *pgxpool.Conn
+	defer func() {
+		if leader != nil {
+			resign(leader)
+		}
+	}()
+
+	for {
+		var err error
+		leader, err = t.elect(ctx, leader)
+		if err != nil && ctx.Err() == nil {
+			slog.Error("schedule leader election failed", "error", err)
+		}
+
+		if leader != nil {
+			if err := t.tick(ctx); err != nil && ctx.Err() == nil {
+				slog.Error("schedule tick failed", "error", err)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// elect returns the connection holding the leader lock, or nil if another
+// replica holds it. A leader whose connection broke has lost the lock.
+func (t *ScheduleTicker) elect(ctx context.Context, leader *pgxpool.Conn) (*pgxpool.Conn, error) {
+	if leader != nil {
+		if err := leader.Ping(ctx); err == nil {
+			return leader, nil
+		}
+
+		slog.Warn("lost schedule leadership")
+		resign(leader)
+	}
+
+	conn, err := t.pool.Acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	locked, err := sqlc.New(conn).TryLockScheduler(ctx)
+	if err != nil {
+		resign(conn)
+		return nil, err
+	}
+	if !locked {
+		conn.Release()
+		return nil, nil
+	}
+
+	slog.Info("acquired schedule leadership")
+	return conn, nil
+}
+
+// resign closes the connection instead of returning it to the pool, which
+// releases the session level lock even if unlocking would fail.
+func resign(conn *pgxpool.Conn) {
+	_ = conn.Conn().Close(context.Background())
+	conn.Release()
+}
+
+func (t *ScheduleTicker) tick(ctx context.Context) error {
+	tx, err := t.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	querier := sqlc.New(tx)
+	schedules, err := querier.ClaimDueSchedules(ctx, t.batchSize)
+	if err != nil {
+		return fmt.Errorf("error claiming schedules: %w", err)
+	}
+
+	tenantIDs := make([]pgtype.UUID, 0, len(schedules))
+	for _, sched := range schedules {
+		tenantIDs = append(tenantIDs, sched.TenantID)
+	}
+
+	if err := querier.LockEventStreams(ctx, tenantIDs); err != nil {
+		return fmt.Errorf("error locking event streams: %w", err)
+	}
+
+	now := time.Now()
+	for _, sched := range schedules {
+		// Each schedule fires in its own savepoint, so that a failing one is
+		// retried next pass without holding back the others.
+		if err := t.fireInSavepoint(ctx, tx, sched, now); err != nil {
+			if ctx.Err() != nil {
+				return err
+			}
+			slog.Error("error firing schedule", "schedule_id", utils.PgUUIDToString(sched.ID), "error", err)
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (t *ScheduleTicker) fireInSavepoint(ctx context.Context, tx pgx.Tx, sched sqlc.WorkflowSchedule, now time.Time) error {
+	savepoint, err := tx.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer savepoint.Rollback(ctx)
+
+	if err := t.fire(ctx, sqlc.New(savepoint), sched, now); err != nil {
+		return err
+	}
+
+	return savepoint.Commit(ctx)
+}
+
+// fire starts the runs of all due ticks of a schedule, oldest first, and
+// moves next_run_at past them.
+func (t *ScheduleTicker) fire(ctx context.Context, querier sqlc.Querier, sched sqlc.WorkflowSchedule, now time.Time) error {
+	spec, err := specOf(sched)
+	if err != nil {
+		return pauseSchedule(ctx, querier, sched, fmt.Sprintf("invalid schedule: %v", err))
+	}
+
+	window := time.Duration(sched.CatchupWindowSeconds) * time.Second
+	advance := sqlc.AdvanceScheduleParams{ID: sched.ID}
+	next := sched.NextRunAt.Time
+
+	if window == 0 {
+		// Without catching up only the most recent due tick runs.
+		for following := spec.next(next); !following.IsZero() && !following.After(now); following = spec.next(next) {
+			next = following
+		}
+	}
+
+	for i := 0; i < maxTicksPerPass && !next.IsZero() && !next.After(now); i++ {
+		if window > 0 && now.Sub(next) > window {
+			slog.Warn("skipping missed schedule ticks", "schedule_id", utils.PgUUIDToString(sched.ID), "since", next)
+			next = spec.next(now.Add(-window))
+			continue
+		}
+
+		active, err := querier.ListActiveScheduleRuns(ctx, sched.ID)
+		if err != nil {
+			return err
+		}
+
+		if len(active) > 0 {
+			switch sched.OverlapPolicy {
+			case overlapBuffer:
+				// Leave the tick due until the active run finished.
+				return advanceSchedule(ctx, querier, advance, next)
+			case overlapCancel:
+				if err := cancelRuns(ctx, querier, sched, active); err != nil {
+					return err
+				}
+			default:
+				next = spec.next(next)
+				continue
+			}
+		}
+
+		run, err := t.startRun(ctx, querier, sched, next)
+		if err != nil {
+			return err
+		}
+		if run.ID.Valid {
+			advance.LastRunAt = run.StartedAt
+			advance.LastRunID = run.ID
+		}
+
+		next = spec.next(next)
+	}
+
+	if next.IsZero() {
+		return pauseSchedule(ctx, querier, sched, "schedule never fires again")
+	}
+
+	return advanceSchedule(ctx, querier, advance, next)
+}
+
+// startRun creates the run of a tick. Ticks over the run quota of the tenant
+// or that already have a run are skipped and return a zero run.
+func (t *ScheduleTicker) startRun(ctx context.Context, querier sqlc.Querier, sched sqlc.WorkflowSchedule, tick time.Time) (sqlc.WorkflowRun, error) {
+	limits, err := t.quotas.Limits(ctx, uuid.UUID(sched.TenantID.Bytes))
+	if err != nil {
+		return sqlc.WorkflowRun{}, err
+	}
+
+	if err := checkRunQuota(ctx, querier, sched.TenantID, limits); err != nil {
+		if st, ok := status.FromError(err); ok {
+			slog.Warn("skipping schedule tick", "schedule_id", utils.PgUUIDToString(sched.ID), "tick", tick, "reason", st.Message())
+			return sqlc.WorkflowRun{}, nil
+		}
+		return sqlc.WorkflowRun{}, err
+	}
+
+	run, err := querier.CreateScheduledRun(ctx, sqlc.CreateScheduledRunParams{
+		WorkflowVersion: sched.WorkflowVersion,
+		Payload:         sched.Payload,
+		ScheduleID:      sched.ID,
+		ScheduledAt:     utils.TimeToPgTimestamptz(tick),
+		WorkflowID:      sched.WorkflowID,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return sqlc.WorkflowRun{}, nil
+		}
+		return sqlc.WorkflowRun{}, err
+	}
+
+	if err := recordUsage(ctx, querier, run.TenantID, usage.MetricRunsStarted, 1); err != nil {
+		return sqlc.WorkflowRun{}, err
+	}
+
+	return run, appendEvent(ctx, querier, run.TenantID, run.ID, events.RunCreated, map[string]any{
+		"workflow_id":      utils.PgUUIDToString(run.WorkflowID),
+		"workflow_version": run.WorkflowVersion.Int32,
+		"schedule_id":      utils.PgUUIDToString(sched.ID),
+		"scheduled_at":     tick,
+	})
+}
+
+func cancelRuns(ctx context.Context, querier sqlc.Querier, sched sqlc.WorkflowSchedule, runIDs []pgtype.UUID) error {
+	reason := "cancelled by a newer run of schedule " + sched.Name
+	if err := querier.CancelRuns(ctx, sqlc.CancelRunsParams{
+		Reason: reason,
+		RunIds: runIDs,
+	}); err != nil {
+		return err
+	}
+
+	if err := querier.CancelRunTasks(ctx, runIDs); err != nil {
+		return err
+	}
+
+	for _, id := range runIDs {
+		if err := appendEvent(ctx, querier, sched.TenantID, id, events.RunCancelled, map[string]any{
+			"workflow_id": utils.PgUUIDToString(sched.WorkflowID),
+			"schedule_id": utils.PgUUIDToString(sched.ID),
+			"error":       reason,
+		}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func advanceSchedule(ctx context.Context, querier sqlc.Querier, params sqlc.AdvanceScheduleParams, next time.Time) error {
+	params.NextRunAt = utils.TimeToPgTimestamptz(next)
+	return querier.AdvanceSchedule(ctx, params)
+}
+
+func pauseSchedule(ctx context.Context, querier sqlc.Querier, sched sqlc.WorkflowSchedule, reason string) error {
+	slog.Warn("pausing schedule", "schedule_id", utils.PgUUIDToString(sched.ID), "reason", reason)
+
+	if _, err := querier.SetSchedulePaused(ctx, sqlc.SetSchedulePausedParams{
+		ID:          sched.ID,
+		TenantID:    sched.TenantID,
+		Paused:      true,
+		PauseReason: utils.StringToPgText(reason),
+		NextRunAt:   sched.NextRunAt,
+	}); err != nil {
+		return err
+	}
+
+	return appendEvent(ctx, querier, sched.TenantID, sched.ID, events.SchedulePaused, map[string]any{
+		"reason": reason,
+	})
+}
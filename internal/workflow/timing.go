@@ -0,0 +1,91 @@
+package workflow
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	sqlc "github.com/vantutran2k1/rwe/internal/workflow/db"
+)
+
+const (
+	overlapSkip   = "skip"
+	overlapBuffer = "buffer"
+	overlapCancel = "cancel"
+
+	defaultCatchupWindow = time.Minute
+	maxCatchupWindow     = 7 * 24 * time.Hour
+	minScheduleInterval  = 10 * time.Second
+)
+
+// scheduleSpec computes the ticks of a schedule.
+type scheduleSpec interface {
+	// next returns the first tick strictly after t, or the zero time if
+	// there is none.
+	next(t time.Time) time.Time
+}
+
+type cronSpec struct {
+	schedule cron.Schedule
+}
+
+func (c cronSpec) next(t time.Time) time.Time {
+	return c.schedule.Next(t)
+}
+
+// intervalSpec ticks every interval from anchor, which can be any past tick.
+type intervalSpec struct {
+	anchor   time.Time
+	interval time.Duration
+}
+
+func (s intervalSpec) next(t time.Time) time.Time {
+	if t.Before(s.anchor) {
+		return s.anchor
+	}
+
+	return s.anchor.Add((t.Sub(s.anchor)/s.interval + 1) * s.interval)
+}
+
+func parseCron(expression, timezone string) (scheduleSpec, error) {
+	if _, err := time.LoadLocation(timezone); err != nil {
+		return nil, fmt.Errorf("unknown time zone %q", timezone)
+	}
+
+	expression = strings.TrimSpace(expression)
+	if strings.HasPrefix(expression, "@every") {
+		return nil, errors.New("use interval_seconds for fixed intervals")
+	}
+	if strings.HasPrefix(expression, "TZ=") || strings.HasPrefix(expression, "CRON_TZ=") {
+		return nil, errors.New("set the time zone with the timezone field")
+	}
+
+	schedule, err := cron.ParseStandard("CRON_TZ=" + timezone + " " + expression)
+	if err != nil {
+		return nil, err
+	}
+
+	return cronSpec{schedule: schedule}, nil
+}
+
+func specOf(sched sqlc.WorkflowSchedule) (scheduleSpec, error) {
+	if sched.IntervalSeconds.Valid {
+		return intervalSpec{
+			anchor:   sched.NextRunAt.Time,
+			interval: time.Duration(sched.IntervalSeconds.Int32) * time.Second,
+		}, nil
+	}
+
+	return parseCron(sched.CronExpression.String, sched.Timezone)
+}
+
+func validOverlapPolicy(policy string) bool {
+	switch policy {
+	case overlapSkip, overlapBuffer, overlapCancel:
+		return true
+	}
+
+	return false
+}
@@ -0,0 +1,217 @@
+package workflow
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	schemeHmacSha256 = "hmac-sha256"
+	schemeStripe     = "stripe"
+
+	defaultSignatureHeader = "x-signature-256"
+	stripeSignatureHeader  = "stripe-signature"
+
+	// stripeTolerance rejects replays of old signed requests.
+	stripeTolerance = 5 * time.Minute
+
+	maxDeliveryIDLength = 200
+)
+
+var errInvalidSignature = errors.New("invalid webhook signature")
+
+func validSignatureScheme(scheme string) bool {
+	return scheme == schemeHmacSha256 || scheme == schemeStripe
+}
+
+// verifySignature checks the signature header of a webhook request against
+// the HMAC-SHA256 of its body.
+func verifySignature(scheme string, secret []byte, header string, body []byte, now time.Time) error {
+	if header == "" {
+		return errInvalidSignature
+	}
+
+	switch scheme {
+	case schemeStripe:
+		return verifyStripeSignature(secret, header, body, now)
+	default:
+		return verifyHmacSignature(secret, header, body)
+	}
+}
+
+func verifyHmacSignature(secret []byte, header string, body []byte) error {
+	value := strings.TrimPrefix(strings.TrimSpace(header), "sha256=")
+
+	signature, err := hex.DecodeString(value)
+	if err != nil {
+		signature, err = base64.StdEncoding.DecodeString(value)
+		if err != nil {
+			return errInvalidSignature
+		}
+	}
+
+	if !hmac.Equal(signature, sign(secret, body)) {
+		return errInvalidSignature
+	}
+
+	return nil
+}
+
+// verifyStripeSignature checks headers of the form t=<unix>,v1=<hex>,... where
+// the signed payload is "<t>.<body>".
+func verifyStripeSignature(secret []byte, header string, body []byte, now time.Time) error {
+	var timestamp string
+	var signatures [][]byte
+	for _, part := range strings.Split(header, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			continue
+		}
+
+		switch key {
+		case "t":
+			timestamp = value
+		case "v1":
+			if sig, err := hex.DecodeString(value); err == nil {
+				signatures = append(signatures, sig)
+			}
+		}
+	}
+
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil || len(signatures) == 0 {
+		return errInvalidSignature
+	}
+	if math.Abs(now.Sub(time.Unix(ts, 0)).Seconds()) > stripeTolerance.Seconds() {
+		return errors.New("webhook signature timestamp is outside the tolerance")
+	}
+
+	expected := sign(secret, append([]byte(timestamp+"."), body...))
+	for _, sig := range signatures {
+		if hmac.Equal(sig, expected) {
+			return nil
+		}
+	}
+
+	return errInvalidSignature
+}
+
+func sign(secret, data []byte) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(data)
+
+	return mac.Sum(nil)
+}
+
+// validSource reports whether source is "body", "body.<path>" or
+// "headers.<name>".
+func validSource(source string) bool {
+	if source == "body" {
+		return true
+	}
+
+	if path, ok := strings.CutPrefix(source, "body."); ok {
+		return path != ""
+	}
+
+	name, ok := strings.CutPrefix(source, "headers.")
+	return ok && name != ""
+}
+
+// resolveSource looks up a source in the request. Body paths are dot
+// separated object keys and array indexes; missing values resolve to nil.
+func resolveSource(source string, body any, headers map[string]string) any {
+	if name, ok := strings.CutPrefix(source, "headers."); ok {
+		if value, ok := headers[strings.ToLower(name)]; ok {
+			return value
+		}
+		return nil
+	}
+
+	value := body
+	path, _ := strings.CutPrefix(source, "body")
+	for _, key := range strings.Split(strings.TrimPrefix(path, "."), ".") {
+		if key == "" {
+			continue
+		}
+
+		switch v := value.(type) {
+		case map[string]any:
+			value = v[key]
+		case []any:
+			i, err := strconv.Atoi(key)
+			if err != nil || i < 0 || i >= len(v) {
+				return nil
+			}
+			value = v[i]
+		default:
+			return nil
+		}
+	}
+
+	return value
+}
+
+// mapPayload builds the run payload from a webhook request. Without a
+// mapping the body is used as is, wrapped in {"body": ...} unless it is an
+// object.
+func mapPayload(mapping map[string]string, body any, headers map[string]string) ([]byte, error) {
+	if len(mapping) == 0 {
+		if _, ok := body.(map[string]any); ok {
+			return json.Marshal(body)
+		}
+
+		return json.Marshal(map[string]any{"body": body})
+	}
+
+	payload := make(map[string]any, len(mapping))
+	for field, source := range mapping {
+		payload[field] = resolveSource(source, body, headers)
+	}
+
+	return json.Marshal(payload)
+}
+
+// deliveryID identifies a delivery for deduplication. It is empty when no
+// source is configured or the request lacks it, and such deliveries are not
+// deduplicated: identical bodies are not necessarily the same delivery.
+func deliveryID(source string, parsed any, headers map[string]string) (string, error) {
+	if source != "" {
+		switch v := resolveSource(source, parsed, headers).(type) {
+		case nil:
+		case string:
+			if v != "" {
+				return shortenID(v), nil
+			}
+		case float64:
+			return strconv.FormatFloat(v, 'f', -1, 64), nil
+		default:
+			return "", fmt.Errorf("delivery id at %s must be a string or number", source)
+		}
+	}
+
+	return "", nil
+}
+
+// shortenID hashes IDs too long to be stored as is.
+func shortenID(id string) string {
+	if len(id) > maxDeliveryIDLength {
+		return hashID([]byte(id))
+	}
+
+	return id
+}
+
+func hashID(data []byte) string {
+	sum := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
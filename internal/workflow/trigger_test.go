@@ -0,0 +1,134 @@
+package workflow
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+var (
+	testSecret = []byte("webhook-secret")
+	testBody   = []byte(`{"id":"evt_1","type":"ping"}`)
+)
+
+func TestVerifyHmacSignature(t *testing.T) {
+	signature := sign(testSecret, testBody)
+
+	tests := []struct {
+		name   string
+		header string
+		body   []byte
+		valid  bool
+	}{
+		{name: "hex", header: hex.EncodeToString(signature), body: testBody, valid: true},
+		{name: "hex with prefix", header: "sha256=" + hex.EncodeToString(signature), body: testBody, valid: true},
+		{name: "base64", header: base64.StdEncoding.EncodeToString(signature), body: testBody, valid: true},
+		{name: "base64 with prefix", header: "sha256=" + base64.StdEncoding.EncodeToString(signature), body: testBody, valid: true},
+		{name: "surrounding whitespace", header: " sha256=" + hex.EncodeToString(signature) + " ", body: testBody, valid: true},
+		{name: "tampered body", header: hex.EncodeToString(signature), body: []byte(`{"id":"evt_2"}`), valid: false},
+		{name: "other secret", header: hex.EncodeToString(sign([]byte("other"), testBody)), body: testBody, valid: false},
+		{name: "truncated", header: hex.EncodeToString(signature[:16]), body: testBody, valid: false},
+		{name: "not encoded", header: "not a signature", body: testBody, valid: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := verifyHmacSignature(testSecret, tt.header, tt.body)
+			if (err == nil) != tt.valid {
+				t.Errorf("verifyHmacSignature(%q) = %v, want valid %v", tt.header, err, tt.valid)
+			}
+		})
+	}
+}
+
+func TestVerifyStripeSignature(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+
+	stripeHeader := func(ts time.Time, secret []byte, extra ...string) string {
+		timestamp := strconv.FormatInt(ts.Unix(), 10)
+		signature := sign(secret, append([]byte(timestamp+"."), testBody...))
+		parts := append([]string{"t=" + timestamp}, extra...)
+		return strings.Join(append(parts, "v1="+hex.EncodeToString(signature)), ",")
+	}
+
+	tests := []struct {
+		name   string
+		header string
+		valid  bool
+	}{
+		{name: "valid", header: stripeHeader(now, testSecret), valid: true},
+		{name: "within tolerance", header: stripeHeader(now.Add(-stripeTolerance), testSecret), valid: true},
+		{name: "clock skew within tolerance", header: stripeHeader(now.Add(stripeTolerance), testSecret), valid: true},
+		{name: "too old", header: stripeHeader(now.Add(-stripeTolerance-time.Second), testSecret), valid: false},
+		{name: "too far in the future", header: stripeHeader(now.Add(stripeTolerance+time.Second), testSecret), valid: false},
+		{name: "other secret", header: stripeHeader(now, []byte("other")), valid: false},
+		{name: "any of several signatures", header: stripeHeader(now, testSecret, "v1=deadbeef", "v0=ignored"), valid: true},
+		{
+			name:   "timestamp not covered by signature",
+			header: strings.Replace(stripeHeader(now, testSecret), "t="+strconv.FormatInt(now.Unix(), 10), "t="+strconv.FormatInt(now.Unix()+1, 10), 1),
+			valid:  false,
+		},
+		{name: "missing timestamp", header: "v1=" + hex.EncodeToString(sign(testSecret, testBody)), valid: false},
+		{name: "missing signature", header: "t=" + strconv.FormatInt(now.Unix(), 10), valid: false},
+		{name: "malformed", header: "garbage", valid: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := verifyStripeSignature(testSecret, tt.header, testBody, now)
+			if (err == nil) != tt.valid {
+				t.Errorf("verifyStripeSignature(%q) = %v, want valid %v", tt.header, err, tt.valid)
+			}
+		})
+	}
+}
+
+func TestVerifySignatureRequiresHeader(t *testing.T) {
+	for _, scheme := range []string{schemeHmacSha256, schemeStripe} {
+		if err := verifySignature(scheme, testSecret, "", testBody, time.Now()); err == nil {
+			t.Errorf("verifySignature(%s) accepted an empty header", scheme)
+		}
+	}
+}
+
+func TestDeliveryID(t *testing.T) {
+	body := map[string]any{
+		"id":    "evt_1",
+		"seq":   float64(42),
+		"empty": "",
+		"long":  strings.Repeat("x", maxDeliveryIDLength+1),
+		"obj":   map[string]any{"a": "b"},
+	}
+	headers := map[string]string{"x-delivery-id": "delivery-1"}
+
+	tests := []struct {
+		name    string
+		source  string
+		want    string
+		wantErr bool
+	}{
+		{name: "no source", source: "", want: ""},
+		{name: "body string", source: "body.id", want: "evt_1"},
+		{name: "body number", source: "body.seq", want: "42"},
+		{name: "header", source: "headers.X-Delivery-Id", want: "delivery-1"},
+		{name: "missing value", source: "body.missing", want: ""},
+		{name: "empty string", source: "body.empty", want: ""},
+		{name: "long id is hashed", source: "body.long", want: hashID([]byte(body["long"].(string)))},
+		{name: "object", source: "body.obj", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := deliveryID(tt.source, body, headers)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("deliveryID(%q) error = %v, want error %v", tt.source, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("deliveryID(%q) = %q, want %q", tt.source, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,473 @@
+package workflow
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	workflowv1 "github.com/vantutran2k1/rwe/gen/go/workflow/v1"
+	"github.com/vantutran2k1/rwe/internal/auth"
+	apierrors "github.com/vantutran2k1/rwe/internal/common/errors"
+	"github.com/vantutran2k1/rwe/internal/common/events"
+	"github.com/vantutran2k1/rwe/internal/common/utils"
+	"github.com/vantutran2k1/rwe/internal/quota"
+	"github.com/vantutran2k1/rwe/internal/usage"
+	sqlc "github.com/vantutran2k1/rwe/internal/workflow/db"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	webhookSecretPrefix = "rwe_whsec_"
+	minSecretLength     = 16
+	maxSecretLength     = 256
+	maxPayloadMappings  = 50
+)
+
+type WebhookService struct {
+	pool    *pgxpool.Pool
+	querier sqlc.Querier
+	quotas  *quota.Store
+	secrets *auth.SecretBox
+	baseURL string
+	workflowv1.UnimplementedWebhookServiceServer
+}
+
+func NewWebhookService(pool *pgxpool.Pool, quotas *quota.Store, secrets *auth.SecretBox, baseURL string) *WebhookService {
+	return &WebhookService{
+		pool:    pool,
+		querier: sqlc.New(pool),
+		quotas:  quotas,
+		secrets: secrets,
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+	}
+}
+
+func (s *WebhookService) CreateWebhook(ctx context.Context, req *workflowv1.CreateWebhookRequest) (*workflowv1.CreateWebhookResponse, error) {
+	tenantID, err := tenantFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	workflowID, err := uuid.Parse(req.WorkflowId)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid workflow id: %v", err)
+	}
+
+	params := sqlc.CreateWebhookParams{
+		TenantID:         tenantID,
+		WorkflowID:       utils.UUIDToPgUUID(workflowID),
+		Name:             strings.TrimSpace(req.Name),
+		SignatureHeader:  strings.ToLower(strings.TrimSpace(req.SignatureHeader)),
+		SignatureScheme:  req.SignatureScheme,
+		DeliveryIDSource: pgtype.Text{String: req.DeliveryIdSource, Valid: req.DeliveryIdSource != ""},
+	}
+	if params.SignatureScheme == "" {
+		params.SignatureScheme = schemeHmacSha256
+	}
+	if params.SignatureHeader == "" {
+		params.SignatureHeader = defaultSignatureHeader
+		if params.SignatureScheme == schemeStripe {
+			params.SignatureHeader = stripeSignatureHeader
+		}
+	}
+
+	var violations apierrors.FieldViolations
+	if params.Name == "" {
+		violations.Add("name", "name is required")
+	}
+	if !validSignatureScheme(params.SignatureScheme) {
+		violations.Add("signature_scheme", "must be one of hmac-sha256 or stripe")
+	}
+	if strings.ContainsAny(params.SignatureHeader, " \t:") {
+		violations.Add("signature_header", "invalid header name")
+	}
+	if req.Secret != "" && (len(req.Secret) < minSecretLength || len(req.Secret) > maxSecretLength) {
+		violations.Add("secret", fmt.Sprintf("must be between %d and %d characters", minSecretLength, maxSecretLength))
+	}
+	if req.DeliveryIdSource != "" && !validSource(req.DeliveryIdSource) {
+		violations.Add("delivery_id_source", "must be body.<path> or headers.<name>")
+	}
+	if len(req.PayloadMapping) > maxPayloadMappings {
+		violations.Add("payload_mapping", fmt.Sprintf("must not have more than %d fields", maxPayloadMappings))
+	}
+	for field, source := range req.PayloadMapping {
+		if field == "" || !validSource(source) {
+			violations.Add("payload_mapping."+field, "must map to body, body.<path> or headers.<name>")
+		}
+	}
+	if req.WorkflowVersion < 0 {
+		violations.Add("workflow_version", "must not be negative")
+	}
+
+	if len(violations) > 0 {
+		return nil, apierrors.BadRequest("invalid webhook", violations)
+	}
+
+	wf, err := s.querier.GetWorkflowByID(ctx, sqlc.GetWorkflowByIDParams{
+		ID:       params.WorkflowID,
+		TenantID: tenantID,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, status.Errorf(codes.NotFound, "workflow with id %s not found", workflowID)
+		}
+
+		return nil, status.Errorf(codes.Internal, "error getting workflow: %v", err)
+	}
+
+	if wf.Archived.Bool {
+		return nil, status.Errorf(codes.FailedPrecondition, "workflow %s is archived", workflowID)
+	}
+
+	if req.WorkflowVersion != 0 {
+		if _, err := s.querier.GetWorkflowVersion(ctx, sqlc.GetWorkflowVersionParams{
+			WorkflowID: wf.ID,
+			Version:    req.WorkflowVersion,
+			TenantID:   tenantID,
+		}); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return nil, status.Errorf(codes.NotFound, "version %d of workflow %s not found", req.WorkflowVersion, workflowID)
+			}
+
+			return nil, status.Errorf(codes.Internal, "error getting workflow version: %v", err)
+		}
+
+		params.WorkflowVersion = pgtype.Int4{Int32: req.WorkflowVersion, Valid: true}
+	}
+
+	mapping := req.PayloadMapping
+	if mapping == nil {
+		mapping = map[string]string{}
+	}
+	params.PayloadMapping, err = json.Marshal(mapping)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "error encoding payload mapping: %v", err)
+	}
+
+	rawToken, tokenHash, err := auth.GenerateWebhookToken()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "error generating webhook token: %v", err)
+	}
+	params.TokenHash = tokenHash
+
+	secret, generated := req.Secret, ""
+	if secret == "" {
+		secret, err = generateWebhookSecret()
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "error generating webhook secret: %v", err)
+		}
+		generated = secret
+	}
+
+	// Binding the secret to the token hash keeps it from being moved to
+	// another webhook.
+	params.Secret, err = s.secrets.Seal([]byte(secret), []byte(tokenHash))
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "error encrypting webhook secret: %v", err)
+	}
+
+	var reqErr error
+	var hook sqlc.WorkflowWebhook
+	if err := s.execTx(ctx, func(querier sqlc.Querier) error {
+		hook, err = querier.CreateWebhook(ctx, params)
+		if err != nil {
+			var pgErr *pgconn.PgError
+			if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+				reqErr = status.Errorf(codes.AlreadyExists, "webhook %q already exists", params.Name)
+			}
+			return err
+		}
+
+		return appendEvent(ctx, querier, hook.TenantID, hook.ID, events.WebhookCreated, map[string]any{
+			"workflow_id": utils.PgUUIDToString(hook.WorkflowID),
+			"name":        hook.Name,
+		})
+	}); err != nil {
+		if reqErr != nil {
+			return nil, reqErr
+		}
+
+		return nil, status.Errorf(codes.Internal, "error creating webhook: %v", err)
+	}
+
+	webhook, err := toWebhook(hook)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "error converting webhook: %v", err)
+	}
+
+	return &workflowv1.CreateWebhookResponse{
+		Webhook: webhook,
+		Url:     s.baseURL + "/v1/hooks/" + rawToken,
+		Secret:  generated,
+	}, nil
+}
+
+func (s *WebhookService) ListWebhooks(ctx context.Context, req *workflowv1.ListWebhooksRequest) (*workflowv1.ListWebhooksResponse, error) {
+	tenantID, err := tenantFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var workflowID pgtype.UUID
+	if req.WorkflowId != "" {
+		id, err := uuid.Parse(req.WorkflowId)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid workflow id: %v", err)
+		}
+		workflowID = utils.UUIDToPgUUID(id)
+	}
+
+	pageSize := int32(50)
+	if req.PageSize > 0 && req.PageSize < pageSize {
+		pageSize = req.PageSize
+	}
+
+	rows, err := s.querier.ListWebhooks(ctx, sqlc.ListWebhooksParams{
+		TenantID:   tenantID,
+		WorkflowID: workflowID,
+		MaxResults: pageSize,
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "error listing webhooks: %v", err)
+	}
+
+	webhooks := make([]*workflowv1.Webhook, 0, len(rows))
+	for _, row := range rows {
+		webhook, err := toWebhook(row)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "error converting webhook: %v", err)
+		}
+		webhooks = append(webhooks, webhook)
+	}
+
+	return &workflowv1.ListWebhooksResponse{Webhooks: webhooks}, nil
+}
+
+func (s *WebhookService) DeleteWebhook(ctx context.Context, req *workflowv1.DeleteWebhookRequest) (*workflowv1.DeleteWebhookResponse, error) {
+	tenantID, err := tenantFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	webhookID, err := uuid.Parse(req.Id)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid webhook id: %v", err)
+	}
+
+	var reqErr error
+	if err := s.execTx(ctx, func(querier sqlc.Querier) error {
+		deleted, err := querier.DeleteWebhook(ctx, sqlc.DeleteWebhookParams{
+			ID:       utils.UUIDToPgUUID(webhookID),
+			TenantID: tenantID,
+		})
+		if err != nil {
+			return err
+		}
+		if deleted == 0 {
+			reqErr = status.Errorf(codes.NotFound, "webhook with id %s not found", webhookID)
+			return reqErr
+		}
+
+		return appendEvent(ctx, querier, tenantID, utils.UUIDToPgUUID(webhookID), events.WebhookDeleted, nil)
+	}); err != nil {
+		if reqErr != nil {
+			return nil, reqErr
+		}
+
+		return nil, status.Errorf(codes.Internal, "error deleting webhook: %v", err)
+	}
+
+	return &workflowv1.DeleteWebhookResponse{Success: true}, nil
+}
+
+// DeliverWebhook starts a run for a signed webhook request. Deliveries are
+// deduplicated per webhook, so retries by the sender return the first run.
+func (s *WebhookService) DeliverWebhook(ctx context.Context, req *workflowv1.DeliverWebhookRequest) (*workflowv1.DeliverWebhookResponse, error) {
+	if req.Token == "" {
+		return nil, status.Error(codes.NotFound, "webhook not found")
+	}
+
+	hook, err := s.querier.GetWebhookByTokenHash(ctx, auth.HashKey(req.Token))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, status.Error(codes.NotFound, "webhook not found")
+		}
+
+		return nil, status.Errorf(codes.Internal, "error getting webhook: %v", err)
+	}
+
+	if hook.TenantStatus.Valid && hook.TenantStatus.TenantStatus != sqlc.TenantStatusActive {
+		return nil, status.Errorf(codes.FailedPrecondition, "tenant %s is %s", utils.PgUUIDToString(hook.TenantID), hook.TenantStatus.TenantStatus)
+	}
+	if hook.WorkflowArchived.Bool {
+		return nil, status.Errorf(codes.FailedPrecondition, "workflow %s is archived", utils.PgUUIDToString(hook.WorkflowID))
+	}
+
+	secret, err := s.secrets.Open(hook.Secret, []byte(hook.TokenHash))
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "error decrypting webhook secret: %v", err)
+	}
+
+	if err := verifySignature(hook.SignatureScheme, secret, req.Headers[hook.SignatureHeader], req.Body, time.Now()); err != nil {
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+
+	var body any
+	if len(bytes.TrimSpace(req.Body)) > 0 {
+		if err := json.Unmarshal(req.Body, &body); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "webhook body must be JSON: %v", err)
+		}
+	}
+
+	var mapping map[string]string
+	if err := json.Unmarshal(hook.PayloadMapping, &mapping); err != nil {
+		return nil, status.Errorf(codes.Internal, "error decoding payload mapping: %v", err)
+	}
+
+	payload, err := mapPayload(mapping, body, req.Headers)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "error mapping payload: %v", err)
+	}
+
+	delivery, err := deliveryID(hook.DeliveryIDSource.String, body, req.Headers)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	source := map[string]string{"webhook_id": utils.PgUUIDToString(hook.ID)}
+	if delivery != "" {
+		source["delivery_id"] = delivery
+	}
+
+	metadata, err := json.Marshal(source)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "error encoding run metadata: %v", err)
+	}
+
+	limits, err := s.quotas.Limits(ctx, uuid.UUID(hook.TenantID.Bytes))
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "error getting quotas: %v", err)
+	}
+
+	var reqErr error
+	var runID pgtype.UUID
+	var duplicate bool
+	if err := s.execTx(ctx, func(querier sqlc.Querier) error {
+		if delivery != "" {
+			// A concurrent delivery with the same ID blocks here until the
+			// first one commits, then sees it as a duplicate.
+			inserted, err := querier.CreateWebhookDelivery(ctx, sqlc.CreateWebhookDeliveryParams{
+				WebhookID:  hook.ID,
+				DeliveryID: delivery,
+			})
+			if err != nil {
+				return err
+			}
+			if inserted == 0 {
+				existing, err := querier.GetWebhookDelivery(ctx, sqlc.GetWebhookDeliveryParams{
+					WebhookID:  hook.ID,
+					DeliveryID: delivery,
+				})
+				if err != nil {
+					return err
+				}
+
+				runID, duplicate = existing.RunID, true
+				return nil
+			}
+		}
+
+		if err := checkRunQuota(ctx, querier, hook.TenantID, limits); err != nil {
+			if _, ok := status.FromError(err); ok {
+				reqErr = err
+			}
+			return err
+		}
+
+		run, err := querier.CreateTriggeredRun(ctx, sqlc.CreateTriggeredRunParams{
+			WorkflowVersion: hook.WorkflowVersion,
+			Payload:         payload,
+			Metadata:        metadata,
+			WorkflowID:      hook.WorkflowID,
+		})
+		if err != nil {
+			return err
+		}
+		runID = run.ID
+
+		if delivery != "" {
+			if err := querier.SetWebhookDeliveryRun(ctx, sqlc.SetWebhookDeliveryRunParams{
+				WebhookID:  hook.ID,
+				DeliveryID: delivery,
+				RunID:      run.ID,
+			}); err != nil {
+				return err
+			}
+		}
+
+		if err := querier.TouchWebhook(ctx, hook.ID); err != nil {
+			return err
+		}
+
+		if err := recordUsage(ctx, querier, run.TenantID, usage.MetricRunsStarted, 1); err != nil {
+			return err
+		}
+
+		return appendEvent(ctx, querier, run.TenantID, run.ID, events.RunCreated, map[string]any{
+			"workflow_id":      utils.PgUUIDToString(run.WorkflowID),
+			"workflow_version": run.WorkflowVersion.Int32,
+			"webhook_id":       utils.PgUUIDToString(hook.ID),
+			"delivery_id":      delivery,
+		})
+	}); err != nil {
+		if reqErr != nil {
+			return nil, reqErr
+		}
+
+		return nil, status.Errorf(codes.Internal, "error starting workflow run: %v", err)
+	}
+
+	resp := &workflowv1.DeliverWebhookResponse{Duplicate: duplicate}
+	if runID.Valid {
+		resp.RunId = utils.PgUUIDToString(runID)
+	}
+
+	return resp, nil
+}
+
+func (s *WebhookService) execTx(ctx context.Context, fn func(sqlc.Querier) error) error {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := sqlc.New(tx)
+
+	if err := fn(qtx); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func generateWebhookSecret() (string, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+
+	return webhookSecretPrefix + hex.EncodeToString(raw), nil
+}
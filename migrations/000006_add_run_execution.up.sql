@@ -0,0 +1,21 @@
+ALTER TABLE workflow_runs
+    ADD COLUMN result JSONB;
+ALTER TABLE workflow_runs
+    ADD COLUMN error TEXT;
+ALTER TABLE workflow_runs
+    ADD COLUMN updated_at timestamptz DEFAULT now();
+
+ALTER TABLE tasks
+    ADD COLUMN step_type TEXT NOT NULL DEFAULT '';
+ALTER TABLE tasks
+    ADD COLUMN input JSONB;
+ALTER TABLE tasks
+    ADD COLUMN depends_on TEXT[] NOT NULL DEFAULT '{}';
+ALTER TABLE tasks
+    ADD COLUMN created_at timestamptz DEFAULT now();
+ALTER TABLE tasks
+    ADD COLUMN updated_at timestamptz DEFAULT now();
+
+CREATE INDEX idx_workflow_runs_status ON workflow_runs (status);
+CREATE INDEX idx_tasks_status ON tasks (status);
+CREATE UNIQUE INDEX idx_tasks_run_step ON tasks (run_id, step_id);
@@ -0,0 +1,13 @@
+ALTER TABLE workers
+    ADD COLUMN tenant_id UUID REFERENCES tenants (id) ON DELETE CASCADE;
+ALTER TABLE workers
+    ADD COLUMN created_at timestamptz DEFAULT now();
+
+ALTER TABLE tasks
+    ADD COLUMN queue TEXT;
+ALTER TABLE tasks
+    ADD COLUMN lease_expires_at timestamptz;
+
+CREATE INDEX idx_workers_tenant ON workers (tenant_id);
+CREATE INDEX idx_tasks_queue ON tasks (queue, status);
+CREATE INDEX idx_tasks_lease ON tasks (lease_expires_at) WHERE status = 'running';
@@ -0,0 +1,6 @@
+ALTER TABLE tasks
+    ADD COLUMN timeout_seconds INT;
+ALTER TABLE tasks
+    ADD COLUMN deadline_at timestamptz;
+
+CREATE INDEX idx_tasks_deadline ON tasks (deadline_at) WHERE status = 'running';
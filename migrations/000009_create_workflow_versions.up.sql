@@ -0,0 +1,34 @@
+CREATE TABLE IF NOT EXISTS workflow_versions
+(
+    workflow_id UUID  NOT NULL REFERENCES workflows (id) ON DELETE CASCADE,
+    version     INT   NOT NULL,
+    definition  JSONB NOT NULL,
+    created_at  timestamptz DEFAULT now(),
+    PRIMARY KEY (workflow_id, version)
+);
+
+INSERT INTO workflow_versions (workflow_id, version, definition, created_at)
+SELECT id, coalesce(version, 1), definition, created_at
+FROM workflows
+ON CONFLICT DO NOTHING;
+
+CREATE OR REPLACE FUNCTION reject_workflow_version_update() RETURNS trigger AS
+$$
+BEGIN
+    RAISE EXCEPTION 'workflow versions are immutable';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trg_workflow_versions_immutable
+    BEFORE UPDATE
+    ON workflow_versions
+    FOR EACH ROW
+EXECUTE FUNCTION reject_workflow_version_update();
+
+ALTER TABLE workflow_runs
+    ADD COLUMN workflow_version INT;
+
+UPDATE workflow_runs r
+SET workflow_version = w.version
+FROM workflows w
+WHERE w.id = r.workflow_id;
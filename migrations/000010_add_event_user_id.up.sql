@@ -0,0 +1,10 @@
+-- Account events belong to no tenant; user_id lets their user stream them.
+ALTER TABLE events
+    ADD COLUMN user_id UUID;
+
+UPDATE events
+SET user_id = aggregate_id
+WHERE tenant_id IS NULL
+  AND event_type LIKE 'user.%';
+
+CREATE INDEX idx_events_user_id ON events (user_id, id) WHERE user_id IS NOT NULL;
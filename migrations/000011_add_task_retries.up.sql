@@ -0,0 +1,10 @@
+ALTER TABLE tasks
+    ADD COLUMN retry_policy JSONB;
+ALTER TABLE tasks
+    ADD COLUMN error_code TEXT;
+ALTER TABLE tasks
+    ADD COLUMN available_at timestamptz;
+ALTER TABLE tasks
+    ADD COLUMN dead_lettered_at timestamptz;
+
+CREATE INDEX idx_tasks_dead_lettered ON tasks (dead_lettered_at) WHERE status = 'dead_lettered';
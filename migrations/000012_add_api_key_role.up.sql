@@ -0,0 +1,2 @@
+ALTER TABLE api_keys
+    ADD COLUMN role TEXT DEFAULT 'member';
@@ -0,0 +1,26 @@
+CREATE TABLE sessions
+(
+    id                      UUID PRIMARY KEY,
+    user_id                 UUID        NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    user_agent              TEXT,
+    ip_address              TEXT,
+    access_token_id         UUID,
+    access_token_expires_at timestamptz,
+    created_at              timestamptz DEFAULT now(),
+    last_used_at            timestamptz DEFAULT now(),
+    expires_at              timestamptz NOT NULL,
+    revoked_at              timestamptz
+);
+
+CREATE INDEX idx_sessions_user ON sessions (user_id);
+
+CREATE TABLE refresh_tokens
+(
+    id         UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    session_id UUID NOT NULL REFERENCES sessions (id) ON DELETE CASCADE,
+    token_hash TEXT NOT NULL UNIQUE,
+    created_at timestamptz DEFAULT now(),
+    used_at    timestamptz
+);
+
+CREATE INDEX idx_refresh_tokens_session ON refresh_tokens (session_id);
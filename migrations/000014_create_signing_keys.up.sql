@@ -0,0 +1,10 @@
+CREATE TABLE signing_keys
+(
+    kid                   TEXT PRIMARY KEY,
+    algorithm             TEXT  NOT NULL DEFAULT 'v4.public',
+    public_key            BYTEA NOT NULL,
+    encrypted_private_key BYTEA NOT NULL,
+    created_at            timestamptz    DEFAULT now(),
+    retired_at            timestamptz,
+    expires_at            timestamptz
+);
@@ -0,0 +1,25 @@
+ALTER TABLE users
+    ADD COLUMN email_verified_at timestamptz;
+
+CREATE TABLE user_tokens
+(
+    id         UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id    UUID        NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    purpose    TEXT        NOT NULL,
+    token_hash TEXT        NOT NULL UNIQUE,
+    created_at timestamptz DEFAULT now(),
+    expires_at timestamptz NOT NULL,
+    used_at    timestamptz
+);
+
+CREATE INDEX idx_user_tokens_user_id ON user_tokens (user_id, purpose);
+
+-- Emails are stored trimmed and lowercased. Accounts whose normalized email
+-- would collide with another account are left as they are.
+UPDATE users u
+SET email = lower(trim(u.email))
+WHERE u.email <> lower(trim(u.email))
+  AND NOT EXISTS (SELECT 1
+                  FROM users o
+                  WHERE o.id <> u.id
+                    AND lower(trim(o.email)) = lower(trim(u.email)));
@@ -0,0 +1,22 @@
+CREATE TABLE user_mfa
+(
+    user_id          UUID PRIMARY KEY REFERENCES users (id) ON DELETE CASCADE,
+    encrypted_secret BYTEA NOT NULL,
+    confirmed_at     timestamptz,
+    last_used_step   BIGINT NOT NULL DEFAULT 0,
+    created_at       timestamptz     DEFAULT now()
+);
+
+CREATE TABLE mfa_recovery_codes
+(
+    id         UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id    UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    code_hash  TEXT NOT NULL,
+    created_at timestamptz DEFAULT now(),
+    used_at    timestamptz
+);
+
+CREATE INDEX idx_mfa_recovery_codes_user_id ON mfa_recovery_codes (user_id);
+
+ALTER TABLE user_tokens
+    ADD COLUMN attempts INT NOT NULL DEFAULT 0;
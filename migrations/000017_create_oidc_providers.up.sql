@@ -0,0 +1,49 @@
+CREATE TABLE oidc_providers
+(
+    tenant_id               UUID PRIMARY KEY REFERENCES tenants (id) ON DELETE CASCADE,
+    issuer                  TEXT    NOT NULL,
+    client_id               TEXT    NOT NULL,
+    encrypted_client_secret BYTEA   NOT NULL,
+    allowed_domains         TEXT[]  NOT NULL DEFAULT '{}',
+    default_role            TEXT    NOT NULL DEFAULT 'member',
+    enabled                 BOOLEAN NOT NULL DEFAULT true,
+    created_at              timestamptz      DEFAULT now(),
+    updated_at              timestamptz      DEFAULT now()
+);
+
+CREATE TABLE oidc_login_states
+(
+    state_hash    TEXT PRIMARY KEY,
+    tenant_id     UUID        NOT NULL REFERENCES tenants (id) ON DELETE CASCADE,
+    code_verifier TEXT        NOT NULL,
+    nonce         TEXT        NOT NULL,
+    -- Set when a signed-in user links the identity to their account.
+    link_user_id  UUID REFERENCES users (id) ON DELETE CASCADE,
+    created_at    timestamptz DEFAULT now(),
+    expires_at    timestamptz NOT NULL,
+    used_at       timestamptz
+);
+
+CREATE TABLE user_identities
+(
+    issuer     TEXT NOT NULL,
+    subject    TEXT NOT NULL,
+    user_id    UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    created_at timestamptz DEFAULT now(),
+    PRIMARY KEY (issuer, subject)
+);
+
+CREATE INDEX idx_user_identities_user_id ON user_identities (user_id);
+
+CREATE TABLE tenant_domains
+(
+    tenant_id          UUID NOT NULL REFERENCES tenants (id) ON DELETE CASCADE,
+    domain             TEXT NOT NULL,
+    verification_token TEXT NOT NULL,
+    created_at         timestamptz DEFAULT now(),
+    verified_at        timestamptz,
+    PRIMARY KEY (tenant_id, domain)
+);
+
+-- A domain can be proven by one tenant only.
+CREATE UNIQUE INDEX idx_tenant_domains_verified_domain ON tenant_domains (domain) WHERE verified_at IS NOT NULL;
@@ -0,0 +1,6 @@
+ALTER TABLE api_keys
+    ADD COLUMN scopes TEXT[] NOT NULL DEFAULT '{}';
+ALTER TABLE api_keys
+    ADD COLUMN allowed_cidrs TEXT[] NOT NULL DEFAULT '{}';
+ALTER TABLE api_keys
+    ADD COLUMN rotated_to UUID REFERENCES api_keys (id);
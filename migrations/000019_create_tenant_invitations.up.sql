@@ -0,0 +1,16 @@
+CREATE TABLE tenant_invitations
+(
+    id          UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id   UUID        NOT NULL REFERENCES tenants (id) ON DELETE CASCADE,
+    email       TEXT        NOT NULL,
+    role        TEXT        NOT NULL,
+    token_hash  TEXT        NOT NULL UNIQUE,
+    invited_by  UUID REFERENCES users (id) ON DELETE SET NULL,
+    created_at  timestamptz DEFAULT now(),
+    expires_at  timestamptz NOT NULL,
+    accepted_at timestamptz,
+    accepted_by UUID REFERENCES users (id) ON DELETE SET NULL,
+    revoked_at  timestamptz
+);
+
+CREATE INDEX idx_tenant_invitations_tenant_id ON tenant_invitations (tenant_id, email);
@@ -0,0 +1,13 @@
+ALTER TABLE tenants
+    ADD COLUMN status_reason TEXT;
+ALTER TABLE tenants
+    ADD COLUMN status_changed_at timestamptz;
+ALTER TABLE tenants
+    ADD COLUMN delete_after timestamptz;
+-- Who suspended the tenant: 'tenant' when its owner did, 'platform' when the
+-- operators did. Platform suspensions are set outside of the API and cannot
+-- be lifted by members of the tenant.
+ALTER TABLE tenants
+    ADD COLUMN suspended_by TEXT CHECK (suspended_by IN ('tenant', 'platform'));
+
+CREATE INDEX idx_tenants_delete_after ON tenants (delete_after) WHERE delete_after IS NOT NULL;
@@ -0,0 +1,19 @@
+CREATE INDEX idx_usage_records_sample_at ON usage_records (sample_at);
+CREATE INDEX idx_usage_records_tenant_id ON usage_records (tenant_id, metric, sample_at);
+
+CREATE TABLE usage_rollups
+(
+    tenant_id    UUID        NOT NULL,
+    metric       TEXT        NOT NULL,
+    granularity  TEXT        NOT NULL,
+    bucket_start timestamptz NOT NULL,
+    value        NUMERIC     NOT NULL,
+    updated_at   timestamptz DEFAULT now(),
+    PRIMARY KEY (tenant_id, metric, granularity, bucket_start)
+);
+
+CREATE TABLE usage_watermarks
+(
+    name      TEXT PRIMARY KEY,
+    watermark timestamptz NOT NULL
+);
@@ -0,0 +1,34 @@
+CREATE TABLE workflow_schedules
+(
+    id                     UUID PRIMARY KEY     DEFAULT gen_random_uuid(),
+    tenant_id              UUID        NOT NULL REFERENCES tenants (id) ON DELETE CASCADE,
+    workflow_id            UUID        NOT NULL REFERENCES workflows (id) ON DELETE CASCADE,
+    name                   TEXT        NOT NULL,
+    cron_expression        TEXT,
+    timezone               TEXT        NOT NULL DEFAULT 'UTC',
+    interval_seconds       INT,
+    workflow_version       INT,
+    payload                JSONB,
+    overlap_policy         TEXT        NOT NULL DEFAULT 'skip',
+    catchup_window_seconds INT         NOT NULL DEFAULT 60,
+    paused                 BOOLEAN     NOT NULL DEFAULT false,
+    pause_reason           TEXT,
+    next_run_at            timestamptz NOT NULL,
+    last_run_at            timestamptz,
+    last_run_id            UUID,
+    created_at             timestamptz          DEFAULT now(),
+    updated_at             timestamptz          DEFAULT now(),
+    CHECK ((cron_expression IS NULL) <> (interval_seconds IS NULL))
+);
+
+CREATE UNIQUE INDEX idx_workflow_schedules_tenant_name ON workflow_schedules (tenant_id, name);
+CREATE INDEX idx_workflow_schedules_next_run_at ON workflow_schedules (next_run_at) WHERE NOT paused;
+
+ALTER TABLE workflow_runs
+    ADD COLUMN schedule_id UUID REFERENCES workflow_schedules (id) ON DELETE SET NULL;
+ALTER TABLE workflow_runs
+    ADD COLUMN scheduled_at timestamptz;
+
+-- A tick can only ever start one run, even if two schedulers race.
+CREATE UNIQUE INDEX idx_workflow_runs_schedule_tick ON workflow_runs (schedule_id, scheduled_at);
+CREATE INDEX idx_workflow_runs_schedule_status ON workflow_runs (schedule_id, status) WHERE schedule_id IS NOT NULL;
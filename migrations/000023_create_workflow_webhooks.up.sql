@@ -0,0 +1,31 @@
+CREATE TABLE workflow_webhooks
+(
+    id                 UUID PRIMARY KEY     DEFAULT gen_random_uuid(),
+    tenant_id          UUID        NOT NULL REFERENCES tenants (id) ON DELETE CASCADE,
+    workflow_id        UUID        NOT NULL REFERENCES workflows (id) ON DELETE CASCADE,
+    name               TEXT        NOT NULL,
+    token_hash         TEXT        NOT NULL UNIQUE,
+    secret             BYTEA       NOT NULL,
+    signature_header   TEXT        NOT NULL,
+    signature_scheme   TEXT        NOT NULL,
+    delivery_id_source TEXT,
+    payload_mapping    JSONB       NOT NULL DEFAULT '{}',
+    workflow_version   INT,
+    created_at         timestamptz DEFAULT now(),
+    updated_at         timestamptz DEFAULT now(),
+    last_delivery_at   timestamptz
+);
+
+CREATE UNIQUE INDEX idx_workflow_webhooks_tenant_name ON workflow_webhooks (tenant_id, name);
+
+CREATE TABLE webhook_deliveries
+(
+    webhook_id  UUID NOT NULL REFERENCES workflow_webhooks (id) ON DELETE CASCADE,
+    delivery_id TEXT NOT NULL,
+    run_id      UUID REFERENCES workflow_runs (id) ON DELETE SET NULL,
+    received_at timestamptz DEFAULT now(),
+    PRIMARY KEY (webhook_id, delivery_id)
+);
+
+-- Deliveries are pruned by age.
+CREATE INDEX idx_webhook_deliveries_received_at ON webhook_deliveries (received_at);